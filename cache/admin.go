@@ -0,0 +1,134 @@
+package cache
+
+import (
+	"crypto/subtle"
+	"dbcache/conf"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+//管理接口(rpc和grpc的AddTable):运行中增加缓存表,表配置写入cache.conf,并用于拼接从数据库加载的sql.
+//管理接口默认关闭,在config.conf的[RpcServer],[GrpcServer]中配置admin_token后开启,请求需带相同的token.
+//请求的表配置比cache.conf更严格(见AddRemoteTable()):只能是标识符,不能有where,other只能是order by;
+//同一个表的其它别名隐藏,脱敏和只读的列,新增的别名也必须隐藏,脱敏(不能显示更多字符)和只读,不能通过别名绕过列的访问策略.
+
+var identRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)                              //列名,分段名,视图名
+var tableRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)   //表名,可以带库名(schema.table)
+var orderRegexp = regexp.MustCompile(`(?i)^\s*order\s+by\s+(.+)$`)                            //other:order by 排序列...
+var orderKeyRegexp = regexp.MustCompile(`(?i)^\s*[A-Za-z_][A-Za-z0-9_]*(\s+(asc|desc))?\s*$`) //一个排序键:排序列 [asc|desc]
+
+//检查管理接口的token.adminToken(服务端配置)为空时管理接口关闭.
+func CheckAdminToken(adminToken string, token string) error {
+	if adminToken == "" {
+		return fmt.Errorf("CheckAdminToken(),管理接口未开启(未配置admin_token)")
+	}
+	if subtle.ConstantTimeCompare([]byte(adminToken), []byte(token)) != 1 {
+		return fmt.Errorf("CheckAdminToken(),token错误")
+	}
+	return nil
+}
+
+//管理接口运行中增加缓存表:先检查表配置(见checkRemoteTable()),再与同一个表的其它别名比较列的访问策略,
+//然后与AddTable()一样写入cache.conf并加载.
+func (in *Instance) AddRemoteTable(name string, table conf.CacheTable) (dbCache *DBcache, err error) {
+	if err = checkRemoteTable(name, table); err != nil {
+		return nil, err
+	}
+	return in.addTable(in.db, name, table, in.checkAliasPolicy)
+}
+
+//检查管理接口请求的表配置:表名,分段名,列名,视图名只能是标识符,不能有where,other只能是order by 排序列 [asc|desc],...
+func checkRemoteTable(name string, table conf.CacheTable) error {
+	if name != "" && !identRegexp.MatchString(name) {
+		return fmt.Errorf("checkRemoteTable(),名称格式错误: %s", name)
+	}
+	if !tableRegexp.MatchString(table.TableName) {
+		return fmt.Errorf("checkRemoteTable(),表名格式错误: %s", table.TableName)
+	}
+	columns := append([]string{table.Pkey}, table.GetColumns()...)
+	columns = append(columns, table.GetHiddenColumns()...)
+	columns = append(columns, table.GetReadonlyColumns()...)
+	masks, err := table.GetMaskedColumns()
+	if err != nil {
+		return fmt.Errorf("checkRemoteTable(), err: %s", err)
+	}
+	for column := range masks {
+		columns = append(columns, column)
+	}
+	for _, column := range columns {
+		if !identRegexp.MatchString(column) {
+			return fmt.Errorf("checkRemoteTable(),列名格式错误: %s", column)
+		}
+	}
+	if strings.TrimSpace(table.Where) != "" {
+		return fmt.Errorf("checkRemoteTable(),不能配置where")
+	}
+	if strings.TrimSpace(table.Other) != "" {
+		match := orderRegexp.FindStringSubmatch(table.Other)
+		if match == nil {
+			return fmt.Errorf("checkRemoteTable(),other只能是order by: %s", table.Other)
+		}
+		for _, key := range strings.Split(match[1], ",") {
+			if !orderKeyRegexp.MatchString(key) {
+				return fmt.Errorf("checkRemoteTable(),other排序格式错误: %s", table.Other)
+			}
+		}
+	}
+	views, err := table.GetSortViews()
+	if err != nil {
+		return fmt.Errorf("checkRemoteTable(), err: %s", err)
+	}
+	for _, view := range views {
+		if !identRegexp.MatchString(view.Name) {
+			return fmt.Errorf("checkRemoteTable(),排序视图名格式错误: %s", view.Name)
+		}
+		for _, key := range view.Keys {
+			if !identRegexp.MatchString(key.Column) {
+				return fmt.Errorf("checkRemoteTable(),排序视图[%s]的列名格式错误: %s", view.Name, key.Column)
+			}
+		}
+	}
+	//配置项的值写入cache.conf的一行,不能换行
+	if strings.ContainsAny(table.SortViews+table.CacheType+table.MaskedColumns, "\r\n") {
+		return fmt.Errorf("checkRemoteTable(),配置项的值不能换行")
+	}
+	return nil
+}
+
+//同一个表的其它别名隐藏,脱敏和只读的列,新增的表如果缓存了,也必须隐藏,脱敏(不能显示更多字符)和只读.调用者需持有tableMutex.
+func (in *Instance) checkAliasPolicy(table conf.CacheTable) error {
+	p, err := newColumnPolicy(table)
+	if err != nil {
+		return err
+	}
+	isColumn := make(map[string]bool)
+	for _, column := range table.GetColumns() {
+		isColumn[strings.ToLower(column)] = true
+	}
+	for _, c := range in.GetCacheObjs() {
+		if !strings.EqualFold(c.TableConfig.GetTableName(), table.TableName) {
+			continue
+		}
+		other := c.Policy()
+		if other == nil {
+			continue
+		}
+		for column := range other.hidden {
+			if isColumn[column] && !p.hidden[column] {
+				return fmt.Errorf("checkAliasPolicy(),列[%s]在[%s]中隐藏,也必须配置在hidden_columns中", column, c.Name())
+			}
+		}
+		for column, keep := range other.masked {
+			if n, ok := p.masked[column]; isColumn[column] && !p.hidden[column] && (!ok || n > keep) {
+				return fmt.Errorf("checkAliasPolicy(),列[%s]在[%s]中脱敏(显示末尾%d个字符),也必须隐藏或脱敏", column, c.Name(), keep)
+			}
+		}
+		for column := range other.readonly {
+			if isColumn[column] && !p.readonly[column] {
+				return fmt.Errorf("checkAliasPolicy(),列[%s]在[%s]中只读,也必须配置在readonly_columns中", column, c.Name())
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"dbcache/conf"
+	"testing"
+)
+
+//管理接口的token:未配置时关闭,不同时拒绝
+func TestCheckAdminToken(t *testing.T) {
+	tests := []struct {
+		adminToken string
+		token      string
+		ok         bool
+	}{
+		{"", "", false},
+		{"", "x", false},
+		{"secret", "", false},
+		{"secret", "Secret", false},
+		{"secret", "secret", true},
+	}
+	for _, test := range tests {
+		if err := CheckAdminToken(test.adminToken, test.token); (err == nil) != test.ok {
+			t.Errorf("CheckAdminToken(%q, %q) = %v, want ok %t", test.adminToken, test.token, err, test.ok)
+		}
+	}
+}
+
+//管理接口请求的表配置只能是标识符,不能有where,other只能是order by
+func TestCheckRemoteTable(t *testing.T) {
+	base := conf.CacheTable{TableName: "users", Columns: "id,name,age", Pkey: "id", Other: "order by age desc, id", CacheType: "tree"}
+	if err := checkRemoteTable("active_users", base); err != nil {
+		t.Fatalf("checkRemoteTable() = %v", err)
+	}
+	tests := []struct {
+		name   string
+		modify func(table *conf.CacheTable)
+	}{
+		{"users]\n[admin", func(table *conf.CacheTable) {}},
+		{"active_users", func(table *conf.CacheTable) { table.TableName = "users where 1=1" }},
+		{"active_users", func(table *conf.CacheTable) { table.TableName = "users;drop table users" }},
+		{"active_users", func(table *conf.CacheTable) { table.Columns = "id,name,(select password from admin)" }},
+		{"active_users", func(table *conf.CacheTable) { table.Pkey = "id or 1" }},
+		{"active_users", func(table *conf.CacheTable) { table.Where = "status=1" }},
+		{"active_users", func(table *conf.CacheTable) { table.Other = "limit 1" }},
+		{"active_users", func(table *conf.CacheTable) { table.Other = "order by age union select 1" }},
+		{"active_users", func(table *conf.CacheTable) { table.Other = "order by age\nwhere=1=1" }},
+		{"active_users", func(table *conf.CacheTable) { table.HiddenColumns = "name,a b" }},
+		{"active_users", func(table *conf.CacheTable) { table.MaskedColumns = "na-me:2" }},
+		{"active_users", func(table *conf.CacheTable) { table.SortViews = "v-1:age asc" }},
+		{"active_users", func(table *conf.CacheTable) { table.CacheType = "tree\nwhere=1=1" }},
+	}
+	for i, test := range tests {
+		table := base
+		test.modify(&table)
+		if err := checkRemoteTable(test.name, table); err == nil {
+			t.Errorf("%d: checkRemoteTable(%q, %+v) want error", i, test.name, table)
+		}
+	}
+	table := base
+	table.TableName = "shop.users"
+	table.Other = ""
+	if err := checkRemoteTable("", table); err != nil {
+		t.Errorf("checkRemoteTable(shop.users) = %v", err)
+	}
+}
+
+//新增的别名不能绕过同一个表其它别名的列访问策略
+func TestCheckAliasPolicy(t *testing.T) {
+	d := newTestCache("slice", "order by age asc")
+	table := d.TableConfig
+	table.HiddenColumns = "score"
+	table.MaskedColumns = "name:2"
+	table.ReadonlyColumns = "price"
+	if err := d.applyLiveConfig(table); err != nil {
+		t.Fatal(err)
+	}
+	in := NewInstance(nil, "", "", "")
+	in.cacheObj["test"] = d
+	tests := []struct {
+		columns  string
+		hidden   string
+		masked   string
+		readonly string
+		ok       bool
+	}{
+		{"id,age", "", "", "", true},
+		{"id,age,score", "", "", "", false},
+		{"id,age,score", "score", "", "", true},
+		{"id,name", "", "", "", false},
+		{"id,name", "", "name:4", "", false},
+		{"id,name", "", "name:1", "", true},
+		{"id,name", "name", "", "", true},
+		{"id,price", "", "", "", false},
+		{"id,price", "", "", "price", true},
+	}
+	for _, test := range tests {
+		alias := conf.CacheTable{TableName: "TEST", Columns: test.columns, Pkey: "id",
+			HiddenColumns: test.hidden, MaskedColumns: test.masked, ReadonlyColumns: test.readonly}
+		if err := in.checkAliasPolicy(alias); (err == nil) != test.ok {
+			t.Errorf("checkAliasPolicy(%+v) = %v, want ok %t", alias, err, test.ok)
+		}
+	}
+	//其它表不比较
+	other := conf.CacheTable{TableName: "goods", Columns: "id,score", Pkey: "id"}
+	if err := in.checkAliasPolicy(other); err != nil {
+		t.Errorf("checkAliasPolicy(goods) = %v", err)
+	}
+}
@@ -0,0 +1,187 @@
+package cache
+
+import (
+	"database/sql"
+	"dbcache/logs"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+//同一个表的多个别名:cache.conf中多个分段配置同一个table_name(例如where不同),每个分段是一个缓存表对象,按分段名访问.
+//通过一个别名写入后,同一实例中同一个表的其它别名从数据库重新读取写入的行(按各自的columns和where):
+//符合where的行插入或更新到缓存,不符合的行从缓存中删除.
+//实时更新或等待返回结果时,写入后立即读取;异步更新不等待结果时,读取发送到写入sql的同步协程,在写入的sql执行后读取.
+
+//获取缓存表的名称(cache.conf中的分段名).
+func (d *DBcache) Name() string {
+	if d.name == "" {
+		return d.TableConfig.GetTableName()
+	}
+	return d.name
+}
+
+//异步sql文件名的前缀:名称与表名相同(不区分大小写)时是表名,与以前的文件名一致;别名时是名称,同一个表的多个别名不写同一个文件.
+func (d *DBcache) asyncFileName() string {
+	if strings.EqualFold(d.Name(), d.TableConfig.GetTableName()) {
+		return d.TableConfig.GetTableName()
+	}
+	return d.Name()
+}
+
+//获取同一实例中同一个表的其它别名.
+func (in *Instance) getAliases(dbCache *DBcache) (caches []*DBcache) {
+	in.cacheObjMutex.RLock()
+	defer in.cacheObjMutex.RUnlock()
+	for _, c := range in.cacheObj {
+		if c != dbCache && strings.EqualFold(c.TableConfig.GetTableName(), dbCache.TableConfig.GetTableName()) {
+			caches = append(caches, c)
+		}
+	}
+	return caches
+}
+
+//写入后,其它别名重新读取写入的行.workerKey是写入时分配同步协程的主键值.
+func (d *DBcache) syncAliases(workerKey string, pkeys []string) {
+	if len(pkeys) == 0 || d.instance == nil {
+		return
+	}
+	aliases := d.instance.getAliases(d)
+	if len(aliases) == 0 {
+		return
+	}
+	refresh := func() {
+		for _, alias := range aliases {
+			if err := alias.refreshRows(pkeys); err != nil {
+				logs.Error("a", "syncAliases(),别名[%s]重新读取[%s]写入的行失败, err: %s", alias.Name(), d.Name(), err)
+			}
+		}
+	}
+	if d.TableConfig.GetIsRealtime() || d.isWaitResult() {
+		refresh()
+		return
+	}
+	if err := d.dataAsync.sendFuncToAsyncChan(workerKey, refresh); err != nil {
+		logs.Error("a", "syncAliases(),[%s]同步别名失败, err: %s", d.Name(), err)
+	}
+}
+
+//从数据库重新读取主键值为pkeys的行(按缓存表的columns和where),读取到的行插入或更新缓存,未读取到的行从缓存中删除.
+func (d *DBcache) refreshRows(pkeys []string) (err error) {
+	//已关闭的不再读取.不加closeMutex:在同步协程中执行时,重新加载(见reload.go)持有closeMutex并等待同步协程
+	select {
+	case <-d.stopChan:
+		return nil
+	default:
+	}
+	for start := 0; start < len(pkeys); start += BATCH_SIZE {
+		end := start + BATCH_SIZE
+		if end > len(pkeys) {
+			end = len(pkeys)
+		}
+		rows, err := d.readRows(pkeys[start:end])
+		if err != nil {
+			return fmt.Errorf("refreshRows(), err: %s", err)
+		}
+		d.applyRows(pkeys[start:end], rows)
+	}
+	return nil
+}
+
+//从数据库读取主键值为pkeys的行(按缓存表的columns和where),返回主键值 -> 行数据.
+func (d *DBcache) readRows(pkeys []string) (rows map[string]map[string]string, err error) {
+	index := make([]int, len(pkeys))
+	for i := range index {
+		index[i] = i
+	}
+	args := make([]interface{}, 0, len(pkeys))
+	where, err := d.pkeyInSql(&args, pkeys, index)
+	if err != nil {
+		return nil, err
+	}
+	if d.TableConfig.GetWhere() != "" {
+		where = "(" + d.TableConfig.GetWhere() + ") and " + where
+	}
+	return d.queryRows(selectColumnsSql(d.dialect, &d.TableConfig)+" where "+where, args...)
+}
+
+//把读取到的行应用到缓存:rows中有的行插入或更新,pkeys中其它已缓存的行删除.与Upsert()串行.
+func (d *DBcache) applyRows(pkeys []string, rows map[string]map[string]string) {
+	d.upsertMutex.Lock()
+	defer d.upsertMutex.Unlock()
+	d.applyRowsLocked(pkeys, rows)
+}
+
+//同applyRows(),调用者已持有upsertMutex(Upsert()中).
+func (d *DBcache) applyRowsLocked(pkeys []string, rows map[string]map[string]string) {
+	deleted := make(map[string]bool)
+	for _, pkey := range pkeys {
+		row, ok := rows[pkey]
+		if ok {
+			d.putRow(pkey, row)
+		} else if _, isCached := d.DbCache.Load(pkey); isCached {
+			deleted[pkey] = true
+		}
+	}
+	d.deleteCaches(deleted)
+}
+
+//插入或更新缓存中的一行.已缓存时只更新值改变的列.
+func (d *DBcache) putRow(pkey string, row map[string]string) {
+	v, ok := d.DbCache.Load(pkey)
+	if ok {
+		rowMap := v.(*sync.Map)
+		changes := make([][]string, 0, len(row))
+		for column, value := range row {
+			if old, ok := rowMap.Load(column); ok && old.(string) == value {
+				continue
+			}
+			rowMap.Store(column, value)
+			changes = append(changes, []string{column, "=", value})
+		}
+		if len(changes) > 0 {
+			d.updatePageCache(pkey, changes)
+			d.updateViews(pkey, changes)
+		}
+		return
+	}
+	rowMap := new(sync.Map)
+	for column, value := range row {
+		rowMap.Store(column, value)
+	}
+	d.DbCache.Store(pkey, rowMap)
+	d.insertPageCache(pkey, rowMap)
+}
+
+//执行查询(值是占位符,args是参数),返回主键值 -> 行数据.空值保存为空字符串.
+func (d *DBcache) queryRows(query string, args ...interface{}) (result map[string]map[string]string, err error) {
+	rows, err := d.DbConn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("queryRows(),查询失败.语句:%s, err: %s", query, err)
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("queryRows(),获取列名失败, err: %s", err)
+	}
+	values := make([]sql.RawBytes, len(columns))
+	scanArgs := make([]interface{}, len(columns))
+	for i := range values {
+		scanArgs[i] = &values[i]
+	}
+	result = make(map[string]map[string]string)
+	for rows.Next() {
+		if err = rows.Scan(scanArgs...); err != nil {
+			return nil, fmt.Errorf("queryRows(),读取行数据失败, err: %s", err)
+		}
+		row := make(map[string]string, len(columns))
+		for i, value := range values {
+			row[columns[i]] = string(value)
+		}
+		result[row[d.TableConfig.GetPkey()]] = row
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("queryRows(),读取行数据失败, err: %s", err)
+	}
+	return result, nil
+}
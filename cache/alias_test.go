@@ -0,0 +1,49 @@
+package cache
+
+import (
+	"testing"
+)
+
+//同一个表的多个别名,按名称(不区分大小写)注册,别名从数据库读取的行应用到缓存
+func TestAlias(t *testing.T) {
+	in := NewInstance(nil, "", "", "")
+	d := newTestCache("slice", "order by age asc")
+	d.instance = in
+	in.register(d)
+	young := newTestCache("link", "order by age asc")
+	young.name = "YoungTest"
+	young.instance = in
+	in.register(young)
+	other := newTestCache("slice", "")
+	other.name = "other"
+	other.TableConfig.TableName = "other"
+	other.instance = in
+	in.register(other)
+
+	if got, ok := in.GetCacheObj("youngtest"); !ok || got != young {
+		t.Errorf("GetCacheObj(youngtest) = %v, %t", got, ok)
+	}
+	if aliases := in.getAliases(d); len(aliases) != 1 || aliases[0] != young {
+		t.Errorf("getAliases() = %v, want [YoungTest]", aliases)
+	}
+	if d.asyncFileName() != "test" || young.asyncFileName() != "YoungTest" {
+		t.Errorf("asyncFileName() = %s, %s", d.asyncFileName(), young.asyncFileName())
+	}
+
+	insertTestRow(young, map[string]string{"id": "1", "age": "10", "name": "a"})
+	insertTestRow(young, map[string]string{"id": "2", "age": "20", "name": "b"})
+	//1更新,2不再符合where(删除),3新增
+	young.applyRows([]string{"1", "2", "3"}, map[string]map[string]string{
+		"1": {"id": "1", "age": "30", "name": "a"},
+		"3": {"id": "3", "age": "15", "name": "c"},
+	})
+	if got := testPkeys(young); got != "3,1" {
+		t.Errorf("pkeys = %s, want 3,1", got)
+	}
+	if row, err := young.GetRow("1"); err != nil || row["age"] != "30" {
+		t.Errorf("GetRow(1) = %v, %v", row, err)
+	}
+	if _, err := young.GetRow("2"); err == nil || young.GetRowCount() != 2 {
+		t.Errorf("row 2 not deleted, row count %d", young.GetRowCount())
+	}
+}
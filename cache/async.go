@@ -1,53 +1,96 @@
 package cache
 
 import (
+	"context"
 	"database/sql"
 	"dbcache/conf"
+	"dbcache/dialect"
 	"dbcache/logs"
 	"fmt"
+	"hash/fnv"
 	"os"
 	"path"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
 type DataAsync struct {
-	DataAsyncConf      conf.DataAsync //[配置文件cache.conf]保存数据库数据异步信息.
-	AsyncSqlchan       chan *AsyncSql //异步数据库同步管道
-	AsyncFileObj       *os.File       //异步数据库同步,保存需要更新的SQL语句文件对象.
-	AsyncFailedFileObj *os.File       //异步数据库同步,保存失败的需要更新的SQL语句文件对象.
+	DataAsyncConf      conf.DataAsync  //[配置文件cache.conf]保存数据库数据异步信息.
+	AsyncFileObj       *os.File        //异步数据库同步,保存需要更新的SQL语句文件对象.
+	AsyncFailedFileObj *os.File        //异步数据库同步,保存失败的需要更新的SQL语句文件对象.
+	workers            []*asyncWorker  //异步同步协程,按主键分片,同一主键的sql按顺序执行,不同主键并发执行.
+	fileMutex          sync.Mutex      //多个协程写同一个异步sql文件,需加锁
+	stopChan           chan struct{}   //通知后台同步协程退出的管道
+	wg                 sync.WaitGroup  //等待后台同步协程退出
+	mutex              sync.RWMutex    //保护isClosed,关闭时等待正在发送的语句完成
+	isClosed           bool            //是否已关闭,关闭后不再接收新的sql语句
+	dialect            dialect.Dialect //数据库方言,等待返回执行结果时按方言取得自增主键值
+}
+
+//异步同步协程,每个协程有自己的管道.
+type asyncWorker struct {
+	id         int            //协程编号
+	sqlChan    chan *AsyncSql //异步数据库同步管道
+	executed   int64          //已执行的sql数
+	failed     int64          //执行失败的sql数
+	lastLag    int64          //最近一条sql从进入管道到开始执行的等待时间(纳秒)
+	busySince  int64          //正在执行的sql开始执行的时间(纳秒时间戳),0表示空闲
+}
+
+//异步同步协程的状态,用于查看每个协程的延迟.
+type AsyncWorkerStat struct {
+	Id       int           //协程编号
+	Pending  int           //管道中等待执行的sql数
+	Executed int64         //已执行的sql数
+	Failed   int64         //执行失败的sql数
+	Lag      time.Duration //最近一条sql从进入管道到开始执行的等待时间
+	Busy     time.Duration //正在执行的sql已执行的时间,0表示空闲
 }
 
 //异步更新数据库
 type AsyncSql struct {
 	isWaitResult bool             //是否等待返回执行结果
 	result       chan *WaitResult //等待返回执行结果的管道
-	exeSql       string           //异步数据更新SQL语句
+	pkey         string           //主键值,用于分配到同步协程,同一主键的sql按顺序执行
+	exeSql       string           //异步数据更新SQL语句,值是占位符
+	args         []interface{}    //SQL语句的参数
 	timestamp    string           //执行语句的时间
+	enqueueTime  time.Time        //进入管道的时间,用于计算延迟
 	isFinish     bool             //是否完成.
+	fn           func()           //不为空时不是sql,在同步协程中执行此函数(排在同一主键之前的sql之后,见alias.go)
 }
 
 //等待数据库返回执行结果.
 type WaitResult struct {
-	n   int64 //执行行数
-	err error //执行是否有错误.
+	result dialect.Result //执行行数,插入时数据库生成的自增主键值
+	err    error          //执行是否有错误.
 }
 
 func NewDatAsync() *DataAsync {
 	return &DataAsync{
 		DataAsyncConf:      conf.DataAsync{},
-		AsyncSqlchan:       nil,
 		AsyncFileObj:       nil,
 		AsyncFailedFileObj: nil,
+		workers:            nil,
+		stopChan:           make(chan struct{}),
+		dialect:            dialect.Default,
 	}
 }
 
-//初始化异步同步信息.
-func (d *DataAsync) InitAsync(db *sql.DB, tableName string) (err error) {
+//初始化异步同步信息.tablesConf是cache.conf,dataDir不为空时,异步sql文件的相对路径(async_file_path)相对于dataDir.
+func (d *DataAsync) InitAsync(db *sql.DB, tablesConf string, dataDir string, tableName string) (err error) {
+	d.dialect = dialect.Of(db)
 	//读取配置文件,数据库异步同步数据的信息.
-	err = conf.ParseConf(conf.TABLES_CONF, &d.DataAsyncConf)
+	err = conf.ParseConf(tablesConf, &d.DataAsyncConf)
 	if err != nil {
 		return err
 	}
+	if dataDir != "" && !filepath.IsAbs(d.DataAsyncConf.AsyncFilePath) {
+		//文件名直接加在路径后面,路径需以分隔符结尾
+		d.DataAsyncConf.AsyncFilePath = filepath.Join(dataDir, d.DataAsyncConf.AsyncFilePath) + string(filepath.Separator)
+	}
 	//初始化文件对象
 	d.AsyncFileObj, err = os.OpenFile(d.DataAsyncConf.AsyncFilePath+tableName+"_"+d.DataAsyncConf.AsyncFileName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
@@ -59,83 +102,194 @@ func (d *DataAsync) InitAsync(db *sql.DB, tableName string) (err error) {
 		err = fmt.Errorf("open async sql failed file failed, file name:%s, err:%v\n", d.DataAsyncConf.AsyncFilePath+tableName+"_"+d.DataAsyncConf.AsyncFailedFileName, err)
 		return err
 	}
-	//初始化管道.
-	d.AsyncSqlchan = make(chan *AsyncSql, d.DataAsyncConf.AsyncMaxChan)
-	//后台异步同步数据
-	go d.backSyncSql(db)
+	//初始化同步协程及管道.未配置协程数时,默认1个.
+	workerNum := d.DataAsyncConf.GetAsyncWorkers()
+	d.workers = make([]*asyncWorker, workerNum)
+	for i := 0; i < workerNum; i++ {
+		d.workers[i] = &asyncWorker{
+			id:      i,
+			sqlChan: make(chan *AsyncSql, d.DataAsyncConf.AsyncMaxChan),
+		}
+		//后台异步同步数据
+		d.wg.Add(1)
+		go d.backSyncSql(db, d.workers[i])
+	}
 
 	return nil
 }
 
+//根据主键值,分配同步协程.同一主键总是分配到同一个协程,保证执行顺序.
+func (d *DataAsync) getWorker(pkey string) *asyncWorker {
+	if len(d.workers) == 1 {
+		return d.workers[0]
+	}
+	h := fnv.New32a()
+	h.Write([]byte(pkey))
+	return d.workers[h.Sum32()%uint32(len(d.workers))]
+}
+
+//按同步协程把主键分组,返回每组主键在pkeys中的下标.同一组的主键由同一个协程执行.
+//未初始化同步协程(实时更新)时,只有一组.
+func (d *DataAsync) groupByWorker(pkeys []string) (groups [][]int) {
+	if len(d.workers) <= 1 {
+		group := make([]int, len(pkeys))
+		for i := range pkeys {
+			group[i] = i
+		}
+		return [][]int{group}
+	}
+	index := make(map[*asyncWorker]int, len(d.workers))
+	for i, pkey := range pkeys {
+		w := d.getWorker(pkey)
+		k, ok := index[w]
+		if !ok {
+			k = len(groups)
+			index[w] = k
+			groups = append(groups, nil)
+		}
+		groups[k] = append(groups[k], i)
+	}
+	return groups
+}
+
 //后台同步数据库
-func (d *DataAsync) backSyncSql(db *sql.DB) {
-	sqlTmp := &AsyncSql{}
+func (d *DataAsync) backSyncSql(db *sql.DB, w *asyncWorker) {
+	defer d.wg.Done()
 	for {
 		//从管道中取出要执行的sql
 		select {
-		case sqlTmp = <-d.AsyncSqlchan:
-		default:
-			time.Sleep(time.Millisecond * 100)
-			continue
+		case sqlTmp := <-w.sqlChan:
+			d.workerExec(db, w, sqlTmp)
+		case <-d.stopChan:
+			//收到退出通知,先把管道中剩余的sql全部执行完,再退出.
+			for {
+				select {
+				case sqlTmp := <-w.sqlChan:
+					d.workerExec(db, w, sqlTmp)
+				default:
+					return
+				}
+			}
 		}
+	}
+}
 
-		//检查是否等待返回执行结果.
-		if sqlTmp.isWaitResult {
-			//等待返回执行结果.
-			//执行SQL语句
-			rs, err := db.Exec(sqlTmp.exeSql)
-			if err != nil {
-				t := &WaitResult{0, err}
-				sqlTmp.result <- t
-				continue
-			}
-			n, err := rs.RowsAffected()
-			if err != nil {
-				t := &WaitResult{0, err}
-				sqlTmp.result <- t
-				continue
-			}
-			t := &WaitResult{n, err}
+//同步协程执行一条sql,并记录状态.
+func (d *DataAsync) workerExec(db *sql.DB, w *asyncWorker, sqlTmp *AsyncSql) {
+	//不是sql,不记录状态
+	if sqlTmp.fn != nil {
+		sqlTmp.fn()
+		return
+	}
+	now := time.Now()
+	atomic.StoreInt64(&w.lastLag, int64(now.Sub(sqlTmp.enqueueTime)))
+	atomic.StoreInt64(&w.busySince, now.UnixNano())
+	err := d.execAsyncSql(db, sqlTmp)
+	atomic.StoreInt64(&w.busySince, 0)
+	atomic.AddInt64(&w.executed, 1)
+	if err != nil {
+		atomic.AddInt64(&w.failed, 1)
+	}
+}
+
+//执行一条异步sql语句
+func (d *DataAsync) execAsyncSql(db *sql.DB, sqlTmp *AsyncSql) (err error) {
+	//检查是否等待返回执行结果.
+	if sqlTmp.isWaitResult {
+		//等待返回执行结果.
+		//执行SQL语句.不是插入语句时,没有自增主键值.
+		result, err := d.dialect.Exec(db, sqlTmp.exeSql, sqlTmp.args...)
+		if err != nil {
+			t := &WaitResult{dialect.Result{}, err}
 			sqlTmp.result <- t
+			return err
+		}
+		t := &WaitResult{result, nil}
+		sqlTmp.result <- t
 
-		} else { //不等待返回执行结果.
-			//检查是否完成.
-			if sqlTmp.isFinish == true {
-				continue
-			}
+	} else { //不等待返回执行结果.
+		//检查是否完成.
+		if sqlTmp.isFinish == true {
+			return nil
+		}
 
-			//检查文件容量大小
-			if d.checkFileSize(d.AsyncFileObj) {
-				newFile, err := d.splitFile(d.AsyncFileObj)
-				if err != nil {
-					logs.Error("a", "backSyncSql(),splitFile() faild. err: %v", err)
-				}
-				d.AsyncFileObj = newFile
-			}
-			//检查文件容量大小
-			if d.checkFileSize(d.AsyncFailedFileObj) {
-				newFile, err := d.splitFile(d.AsyncFailedFileObj)
-				if err != nil {
-					logs.Error("a", "backSyncSql(),splitFile() faild. err: %v", err)
-				}
-				d.AsyncFailedFileObj = newFile
-			}
+		//需执行的sql,首先保存于文件
+		sqlMsg := fmt.Sprintf("/* %s */  %s;%s\n", sqlTmp.timestamp, sqlTmp.exeSql, formatArgs(sqlTmp.args))
+		d.writeFile(false, sqlMsg)
 
-			//需执行的sql,首先保存于文件
-			sqlMsg := fmt.Sprintf("/* %s */  %s;\n", sqlTmp.timestamp, sqlTmp.exeSql)
-			fmt.Fprintf(d.AsyncFileObj, sqlMsg)
+		//执行SQL语句
+		_, err = db.Exec(sqlTmp.exeSql, sqlTmp.args...)
+		if err != nil {
+			//将执行失败的语句保存于失败日志文件.
+			sqlMsg := fmt.Sprintf("/* [%s][%s] */  %s;%s\n", sqlTmp.timestamp, err, sqlTmp.exeSql, formatArgs(sqlTmp.args))
+			d.writeFile(true, sqlMsg)
+			sqlTmp.isFinish = false
+		}
+		sqlTmp.isFinish = true
+	}
+	return err
+}
 
-			//执行SQL语句
-			_, err := db.Exec(sqlTmp.exeSql)
+//异步sql文件中语句的参数,写在语句后面的注释中.没有参数时为空.
+func formatArgs(args []interface{}) string {
+	if len(args) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("  /* args: %q */", args)
+}
+
+//写异步sql文件,isFailed为true时写失败文件.多个同步协程共用文件,需加锁.
+func (d *DataAsync) writeFile(isFailed bool, sqlMsg string) {
+	d.fileMutex.Lock()
+	defer d.fileMutex.Unlock()
+	if isFailed {
+		//检查文件容量大小
+		if d.checkFileSize(d.AsyncFailedFileObj) {
+			newFile, err := d.splitFile(d.AsyncFailedFileObj)
 			if err != nil {
-				//将执行失败的语句保存于失败日志文件.
-				sqlMsg := fmt.Sprintf("/* [%s][%s] */  %s;\n", sqlTmp.timestamp, err, sqlTmp.exeSql)
-				fmt.Fprintf(d.AsyncFailedFileObj, sqlMsg)
-				sqlTmp.isFinish = false
+				logs.Error("a", "backSyncSql(),splitFile() faild. err: %v", err)
 			}
-			sqlTmp.isFinish = true
+			d.AsyncFailedFileObj = newFile
 		}
+		fmt.Fprint(d.AsyncFailedFileObj, sqlMsg)
+	} else {
+		//检查文件容量大小
+		if d.checkFileSize(d.AsyncFileObj) {
+			newFile, err := d.splitFile(d.AsyncFileObj)
+			if err != nil {
+				logs.Error("a", "backSyncSql(),splitFile() faild. err: %v", err)
+			}
+			d.AsyncFileObj = newFile
+		}
+		fmt.Fprint(d.AsyncFileObj, sqlMsg)
+	}
+}
+
+//获取每个同步协程的状态(管道中等待数,已执行数,失败数,延迟).
+func (d *DataAsync) WorkerStats() (stats []AsyncWorkerStat) {
+	stats = make([]AsyncWorkerStat, 0, len(d.workers))
+	for _, w := range d.workers {
+		stat := AsyncWorkerStat{
+			Id:       w.id,
+			Pending:  len(w.sqlChan),
+			Executed: atomic.LoadInt64(&w.executed),
+			Failed:   atomic.LoadInt64(&w.failed),
+			Lag:      time.Duration(atomic.LoadInt64(&w.lastLag)),
+		}
+		if busySince := atomic.LoadInt64(&w.busySince); busySince != 0 {
+			stat.Busy = time.Since(time.Unix(0, busySince))
+		}
+		stats = append(stats, stat)
 	}
+	return stats
+}
+
+//管道中等待执行的sql总数
+func (d *DataAsync) pendingCount() (n int) {
+	for _, w := range d.workers {
+		n += len(w.sqlChan)
+	}
+	return n
 }
 
 //检查文件大小
@@ -175,37 +329,146 @@ func (d *DataAsync) splitFile(file *os.File) (newFile *os.File, err error) {
 	return fileObj, nil
 }
 
-//发送要执行的sql语句到管道.
-func (d *DataAsync) sendToAsyncChan(exeSql string) {
+//发送要执行的sql语句及参数到管道.pkey为主键值,同一主键的sql按顺序执行.
+func (d *DataAsync) sendToAsyncChan(pkey string, exeSql string, args ...interface{}) (err error) {
+	now := time.Now()
+	sqlTmp := &AsyncSql{
+		pkey:        pkey,
+		exeSql:      exeSql,
+		args:        args,
+		timestamp:   now.Format("2006-01-02 15-04-05"),
+		enqueueTime: now,
+		isFinish:    false,
+	}
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+	if d.isClosed {
+		return fmt.Errorf("sendToAsyncChan(),异步同步已关闭,不再接收sql语句: %s", exeSql)
+	}
+	if len(d.workers) == 0 {
+		return fmt.Errorf("sendToAsyncChan(),异步同步未初始化: %s", exeSql)
+	}
+	select {
+	case d.getWorker(pkey).sqlChan <- sqlTmp:
+	default:
+		logs.Error("a", "sendToAsyncChan(),异步同步管道已满,sql未执行: %s", exeSql)
+		return fmt.Errorf("sendToAsyncChan(),异步同步管道已满: %s", exeSql)
+	}
+	return nil
+}
+
+//发送要执行的函数到管道,在pkey分配的同步协程中,管道中已有的sql执行完后执行.
+func (d *DataAsync) sendFuncToAsyncChan(pkey string, fn func()) (err error) {
 	sqlTmp := &AsyncSql{
-		exeSql:    exeSql,
-		timestamp: time.Now().Format("2006-01-02 15-04-05"),
-		isFinish:  false,
+		pkey:        pkey,
+		fn:          fn,
+		enqueueTime: time.Now(),
+	}
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+	if d.isClosed {
+		return fmt.Errorf("sendFuncToAsyncChan(),异步同步已关闭")
+	}
+	if len(d.workers) == 0 {
+		return fmt.Errorf("sendFuncToAsyncChan(),异步同步未初始化")
 	}
 	select {
-	case d.AsyncSqlchan <- sqlTmp:
+	case d.getWorker(pkey).sqlChan <- sqlTmp:
 	default:
-		fmt.Println("Async sql output File,channel blocked")
+		return fmt.Errorf("sendFuncToAsyncChan(),异步同步管道已满")
 	}
+	return nil
 }
 
-//发送要执行的sql语句到管道.等待执行结果.
-func (d *DataAsync) sendToAsyncChanResult(isWaitResult bool, result chan *WaitResult, exeSql string) {
+//发送要执行的sql语句及参数到管道.等待执行结果.pkey为主键值,同一主键的sql按顺序执行.
+func (d *DataAsync) sendToAsyncChanResult(isWaitResult bool, result chan *WaitResult, pkey string, exeSql string, args ...interface{}) (err error) {
 	sqlTmp := &AsyncSql{
 		isWaitResult: isWaitResult,
 		result:       result,
+		pkey:         pkey,
 		exeSql:       exeSql,
+		args:         args,
+		enqueueTime:  time.Now(),
+	}
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+	if d.isClosed {
+		return fmt.Errorf("sendToAsyncChanResult(),异步同步已关闭,不再接收sql语句: %s", exeSql)
+	}
+	if len(d.workers) == 0 {
+		return fmt.Errorf("sendToAsyncChanResult(),异步同步未初始化: %s", exeSql)
 	}
 	select {
-	case d.AsyncSqlchan <- sqlTmp:
+	case d.getWorker(pkey).sqlChan <- sqlTmp:
 	default:
-		fmt.Println("Async sql output File,channel blocked")
+		logs.Error("a", "sendToAsyncChanResult(),异步同步管道已满,sql未执行: %s", exeSql)
+		return fmt.Errorf("sendToAsyncChanResult(),异步同步管道已满: %s", exeSql)
 	}
+	return nil
 }
 
-//关闭打开的对象
-func (d *DataAsync) Close() {
+//等待管道中已有的sql执行完,不关闭.向每个同步协程发送一个函数,都执行后返回.如果ctx先超时,返回错误.
+func (d *DataAsync) flush(ctx context.Context) (err error) {
+	var wg sync.WaitGroup
+	d.mutex.RLock()
+	if d.isClosed {
+		d.mutex.RUnlock()
+		return nil
+	}
+	for _, w := range d.workers {
+		wg.Add(1)
+		select {
+		case w.sqlChan <- &AsyncSql{fn: wg.Done, enqueueTime: time.Now()}:
+		case <-ctx.Done():
+			d.mutex.RUnlock()
+			return fmt.Errorf("DataAsync.flush(),发送到同步协程超时, err: %s", ctx.Err())
+		}
+	}
+	d.mutex.RUnlock()
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return fmt.Errorf("DataAsync.flush(),等待异步sql执行完超时,剩余%d条未执行, err: %s", d.pendingCount(), ctx.Err())
+	}
+	return nil
+}
+
+//关闭打开的对象.不再接收新的sql语句,等待管道中剩余的sql执行完,再关闭文件.
+//如果ctx先超时,则返回ctx的错误,剩余未执行的sql语句会丢失.
+func (d *DataAsync) Close(ctx context.Context) (err error) {
+	d.mutex.Lock()
+	if d.isClosed {
+		d.mutex.Unlock()
+		return nil
+	}
+	d.isClosed = true
+	d.mutex.Unlock()
+
+	//通知后台同步协程退出,并等待管道中的sql执行完.
+	close(d.stopChan)
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("DataAsync.Close(),等待异步sql执行完超时,剩余%d条未执行, err: %s", d.pendingCount(), ctx.Err())
+		return err
+	}
+
 	//关闭异步同步文件对象.
-	d.AsyncFileObj.Close()
-	d.AsyncFailedFileObj.Close()
+	if d.AsyncFileObj != nil {
+		d.AsyncFileObj.Close()
+	}
+	if d.AsyncFailedFileObj != nil {
+		d.AsyncFailedFileObj.Close()
+	}
+	return nil
 }
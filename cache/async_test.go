@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"dbcache/logs"
+	"testing"
+	"time"
+)
+
+//新建有n个同步协程(不启动)的异步同步对象,每个协程的管道长度为size
+func newTestAsync(n int, size int) *DataAsync {
+	d := NewDatAsync()
+	for i := 0; i < n; i++ {
+		d.workers = append(d.workers, &asyncWorker{id: i, sqlChan: make(chan *AsyncSql, size)})
+	}
+	return d
+}
+
+//同一主键总是分配到同一个协程,按协程分组的主键不重复不遗漏
+func TestGetWorker(t *testing.T) {
+	d := newTestAsync(4, 10)
+	pkeys := []string{"1", "2", "3", "4", "5", "6", "7", "8", "1", "abc"}
+	used := make(map[int]bool)
+	for _, pkey := range pkeys {
+		w := d.getWorker(pkey)
+		used[w.id] = true
+		for i := 0; i < 3; i++ {
+			if d.getWorker(pkey) != w {
+				t.Fatalf("getWorker(%s) returned different workers", pkey)
+			}
+		}
+	}
+	if len(used) < 2 {
+		t.Errorf("getWorker() used %d workers, want more than 1", len(used))
+	}
+	count := 0
+	for _, group := range d.groupByWorker(pkeys) {
+		w := d.getWorker(pkeys[group[0]])
+		for _, i := range group {
+			if d.getWorker(pkeys[i]) != w {
+				t.Errorf("groupByWorker() group has pkeys of different workers")
+			}
+		}
+		count += len(group)
+	}
+	if count != len(pkeys) {
+		t.Errorf("groupByWorker() has %d pkeys, want %d", count, len(pkeys))
+	}
+	if groups := newTestAsync(1, 10).groupByWorker(pkeys); len(groups) != 1 || len(groups[0]) != len(pkeys) {
+		t.Errorf("groupByWorker() with one worker = %v", groups)
+	}
+}
+
+//协程状态:等待执行数,已执行数,延迟.管道已满时返回错误
+func TestWorkerStats(t *testing.T) {
+	//管道已满时写日志
+	logs.Flog, logs.Slog, logs.Elog = &logs.FileLog{}, &logs.StdoutLog{}, &logs.EmailLog{}
+	d := newTestAsync(1, 1)
+	if err := d.sendToAsyncChan("1", "update t set a=1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.sendToAsyncChan("2", "update t set a=2"); err == nil {
+		t.Errorf("sendToAsyncChan() to a full channel want error")
+	}
+	if stats := d.WorkerStats(); stats[0].Pending != 1 || stats[0].Executed != 0 {
+		t.Errorf("WorkerStats() = %+v, want 1 pending", stats[0])
+	}
+	w := d.workers[0]
+	sqlTmp := <-w.sqlChan
+	//已完成的sql不再执行数据库
+	sqlTmp.isFinish = true
+	sqlTmp.enqueueTime = time.Now().Add(-time.Second)
+	d.workerExec(nil, w, sqlTmp)
+	stats := d.WorkerStats()
+	if stats[0].Pending != 0 || stats[0].Executed != 1 || stats[0].Failed != 0 || stats[0].Busy != 0 {
+		t.Errorf("WorkerStats() = %+v", stats[0])
+	}
+	if stats[0].Lag < time.Second {
+		t.Errorf("WorkerStats() lag = %s, want at least 1s", stats[0].Lag)
+	}
+}
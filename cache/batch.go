@@ -0,0 +1,471 @@
+package cache
+
+import (
+	"dbcache/conf"
+	"dbcache/dialect"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+//批量操作:批量插入,批量删除,按where条件更新,按where条件删除.
+//数据库按批执行sql语句,缓存(map,slice,link)一次加锁更新.
+//sql语句中的值都用占位符,值作为参数传给数据库(见sqlValue()).
+
+const (
+	BATCH_SIZE   = 500   //批量执行sql时,每条sql语句最多包含的行数
+	MAX_SQL_ARGS = 32766 //每条sql语句最多的参数个数(sqlite的上限,mysql,postgres是65535)
+)
+
+//批量插入多行数据.rows中每个元素是一行数据,格式与InsertRow()相同.
+//每BATCH_SIZE行生成一条INSERT语句.返回插入的行数.
+//如果某批插入数据库失败,之前成功的批次仍会插入缓存,并返回错误.
+func (d *DBcache) InsertRows(rows []string) (n int64, err error) {
+	if err = d.beginWrite(); err != nil {
+		return 0, fmt.Errorf("InsertRows(),err: %s", err)
+	}
+	defer d.endWrite()
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	if d.dialect.HasDefault() {
+		return d.insertRows(rows)
+	}
+	//数据库不支持VALUES中的DEFAULT(sqlite)时,按行中的列分组插入,同一组的行有相同的列.
+	for _, group := range d.groupRowsByColumns(rows) {
+		i, err := d.insertRows(group)
+		n += i
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+//按行中的列(按顺序)分组,保持行的先后顺序.行的格式错误时,放在单独的组中(插入时返回错误).
+func (d *DBcache) groupRowsByColumns(rows []string) (groups [][]string) {
+	index := make(map[string]int)
+	for _, row := range rows {
+		key := "\x00" + row
+		if whereCondition, err := d.GetCondition(row, ","); err == nil {
+			columns := make([]string, len(whereCondition))
+			for i, condition := range whereCondition {
+				columns[i] = condition[0]
+			}
+			key = strings.Join(columns, ",")
+		}
+		k, ok := index[key]
+		if !ok {
+			k = len(groups)
+			index[key] = k
+			groups = append(groups, nil)
+		}
+		groups[k] = append(groups[k], row)
+	}
+	return groups
+}
+
+//批量插入多行数据,见InsertRows().
+func (d *DBcache) insertRows(rows []string) (n int64, err error) {
+	Pkey := d.TableConfig.GetPkey()
+	sortMode := d.TableConfig.GetSortMode()
+
+	items := make([]*SliceCache, 0, len(rows)) //要插入的行
+	columns := make([]string, 0)               //所有行中出现的列,按出现顺序
+	isColumn := make(map[string]bool)
+	pkeys := make([]string, 0, len(rows)) //有主键的行的主键值
+	pkeyItems := make([]int, 0, len(rows)) //有主键的行在items中的下标
+	autoItems := make([]int, 0)            //没有主键(自增列)的行在items中的下标
+	for _, row := range rows {
+		whereCondition, err := d.GetCondition(row, ",")
+		if err != nil {
+			err = fmt.Errorf("InsertRows(),获取条件错误.行: %s, err: %v", row, err)
+			return 0, err
+		}
+		item := &SliceCache{
+			SortMode: sortMode,
+			RowMap:   new(sync.Map),
+		}
+		isPkey := false
+		for _, condition := range whereCondition {
+			//判断该列在数据库中是否可为空
+			colInfo, ok := d.ColumnInfo[condition[0]]
+			if ok {
+				if colInfo.isNullable == true && colInfo.nullable == false {
+					if strings.TrimSpace(condition[2]) == "" {
+						err = fmt.Errorf("InsertRows(),该列%s不能为空.行: %s", condition[0], row)
+						return 0, err
+					}
+				}
+			}
+			if !isColumn[condition[0]] {
+				isColumn[condition[0]] = true
+				columns = append(columns, condition[0])
+			}
+			if condition[0] == Pkey {
+				item.Pkey = condition[2]
+				isPkey = true
+			}
+			item.RowMap.Store(condition[0], condition[2])
+		}
+		//不是自增列,必须要有主键.自增列可以不要
+		if d.TableConfig.PkeyIsIncrement() == false && isPkey != true {
+			err = fmt.Errorf("InsertRows(),插入行中,没有主键.行: %s, 主键: %s", row, Pkey)
+			return 0, err
+		}
+		if isPkey {
+			pkeys = append(pkeys, item.Pkey)
+			pkeyItems = append(pkeyItems, len(items))
+		} else {
+			autoItems = append(autoItems, len(items))
+		}
+		items = append(items, item)
+	}
+
+	//生成一批行的INSERT语句及参数,行中没有的列用DEFAULT.
+	insertSql := func(index []int) (string, []interface{}, error) {
+		values := make([][]string, 0, len(index))
+		args := make([]interface{}, 0, len(index)*len(columns))
+		for _, i := range index {
+			value := make([]string, 0, len(columns))
+			for _, column := range columns {
+				v, ok := items[i].RowMap.Load(column)
+				if ok {
+					sqlValue, err := d.sqlValue(&args, column, v.(string))
+					if err != nil {
+						return "", nil, err
+					}
+					value = append(value, sqlValue)
+				} else {
+					value = append(value, "DEFAULT")
+				}
+			}
+			values = append(values, value)
+		}
+		return d.dialect.Insert(d.TableConfig.GetTableName(), columns, values), args, nil
+	}
+	//每批的行数,参数个数不超过MAX_SQL_ARGS
+	batchSize := BATCH_SIZE
+	if size := MAX_SQL_ARGS / len(columns); size < batchSize {
+		batchSize = size
+	}
+
+	okItems := make([]*SliceCache, 0, len(items)) //成功插入数据库的行
+	//有主键的行
+	if len(pkeys) > 0 {
+		i, okIndex, err := d.execBatch(pkeys, batchSize, func(index []int) (string, []interface{}, error) {
+			itemIndex := make([]int, len(index))
+			for k, v := range index {
+				itemIndex[k] = pkeyItems[v]
+			}
+			return insertSql(itemIndex)
+		})
+		n += i
+		for _, v := range okIndex {
+			okItems = append(okItems, items[pkeyItems[v]])
+		}
+		if err != nil {
+			d.insertCaches(okItems)
+			return n, fmt.Errorf("InsertRows(),err: %s", err)
+		}
+	}
+	//没有主键的行(自增列),需等待返回执行结果,取得数据库生成的主键值.
+	//postgres用RETURNING返回每行的主键值,其它数据库按第一行的主键值和自增步长推算(同一条INSERT语句插入的多行,自增主键值是连续分配的).
+	for start := 0; start < len(autoItems); start += batchSize {
+		end := start + batchSize
+		if end > len(autoItems) {
+			end = len(autoItems)
+		}
+		batch := autoItems[start:end]
+		sqlString, args, err := insertSql(batch)
+		if err != nil {
+			d.insertCaches(okItems)
+			return n, fmt.Errorf("InsertRows(),err: %s", err)
+		}
+		result, _, err := d.execDbSql("", sqlString+d.dialect.Returning(Pkey), true, args...)
+		if err != nil {
+			d.insertCaches(okItems)
+			return n, fmt.Errorf("InsertRows(),err: %s", err)
+		}
+		if len(result.InsertIds) == 0 {
+			d.insertCaches(okItems)
+			return n, fmt.Errorf("InsertRows(),获取自增主键值失败.语句:%s", sqlString)
+		}
+		n += result.RowsAffected
+		autoPkeys := make([]string, 0, len(batch))
+		for k, v := range batch {
+			item := items[v]
+			item.Pkey = strconv.FormatInt(result.InsertId(k, d.incrementStep), 10)
+			item.RowMap.Store(Pkey, item.Pkey)
+			okItems = append(okItems, item)
+			autoPkeys = append(autoPkeys, item.Pkey)
+		}
+		d.syncAliases("", autoPkeys)
+	}
+	d.insertCaches(okItems)
+	return n, nil
+}
+
+//批量删除多行数据,根据主键值.每BATCH_SIZE个主键生成一条DELETE语句.返回删除的行数.
+func (d *DBcache) DelRows(pkeys []string) (n int64, err error) {
+	if err = d.beginWrite(); err != nil {
+		return 0, fmt.Errorf("DelRows(),err: %s", err)
+	}
+	defer d.endWrite()
+	n, err = d.delRows(pkeys)
+	if err != nil {
+		return n, fmt.Errorf("DelRows(),err: %s", err)
+	}
+	return n, nil
+}
+
+//根据where条件(格式与GetWhere()相同),更新所有符合条件的行.changes是要更新的多列表达式,格式与UpdateColumns()相同.
+//先在缓存中查找符合条件的行,再按主键批量更新数据库.返回更新的行数.
+func (d *DBcache) UpdateWhere(where string, changes string) (n int64, err error) {
+	if err = d.beginWrite(); err != nil {
+		return 0, fmt.Errorf("UpdateWhere(),err: %s", err)
+	}
+	defer d.endWrite()
+	changeCondition, err := d.GetCondition(changes, ",")
+	if err != nil {
+		err = fmt.Errorf("UpdateWhere(),条件错误: %s. err: %s", changes, err)
+		return 0, err
+	}
+	columns := d.TableConfig.GetColumns()
+	for _, condition := range changeCondition {
+		if condition[0] == d.TableConfig.GetPkey() {
+			err = fmt.Errorf("UpdateWhere(),不能更新主键列: %s", changes)
+			return 0, err
+		}
+		isExist := false
+		for _, column := range columns {
+			if condition[0] == column {
+				isExist = true
+				break
+			}
+		}
+		if isExist == false {
+			err = fmt.Errorf("UpdateWhere(),该列未缓存,列名: %s", condition[0])
+			return 0, err
+		}
+	}
+	pkeys, err := d.getWherePkeys(where)
+	if err != nil {
+		return 0, fmt.Errorf("UpdateWhere(),err: %s", err)
+	}
+	if len(pkeys) == 0 {
+		return 0, nil
+	}
+	n, okIndex, err := d.execBatch(pkeys, BATCH_SIZE, func(index []int) (string, []interface{}, error) {
+		args := make([]interface{}, 0, len(changeCondition)+len(index))
+		SqlStr, err := d.GetSqlStr(&args, changes)
+		if err != nil {
+			return "", nil, err
+		}
+		where, err := d.pkeyInSql(&args, pkeys, index)
+		return "UPDATE " + d.dialect.Quote(d.TableConfig.GetTableName()) + " SET " + SqlStr + " WHERE " + where, args, err
+	})
+	//更新缓存,一次加锁.
+	d.RwMutex.Lock()
+	for _, i := range okIndex {
+		v, ok := d.DbCache.Load(pkeys[i])
+		if !ok {
+			continue
+		}
+		rowMap := v.(*sync.Map)
+		for _, condition := range changeCondition {
+			rowMap.Store(condition[0], condition[2])
+		}
+		d.updateViews(pkeys[i], changeCondition)
+	}
+	d.RwMutex.Unlock()
+	//更新了排序列时,移动分页缓存中的行(切片缓存会加d.RwMutex,需在解锁后).
+	for _, i := range okIndex {
+		d.updatePageCache(pkeys[i], changeCondition)
+	}
+	if err != nil {
+		return n, fmt.Errorf("UpdateWhere(),err: %s", err)
+	}
+	return n, nil
+}
+
+//根据where条件(格式与GetWhere()相同),删除所有符合条件的行.返回删除的行数.
+func (d *DBcache) DeleteWhere(where string) (n int64, err error) {
+	if err = d.beginWrite(); err != nil {
+		return 0, fmt.Errorf("DeleteWhere(),err: %s", err)
+	}
+	defer d.endWrite()
+	pkeys, err := d.getWherePkeys(where)
+	if err != nil {
+		return 0, fmt.Errorf("DeleteWhere(),err: %s", err)
+	}
+	n, err = d.delRows(pkeys)
+	if err != nil {
+		return n, fmt.Errorf("DeleteWhere(),err: %s", err)
+	}
+	return n, nil
+}
+
+//批量删除数据库和缓存中的行.
+func (d *DBcache) delRows(pkeys []string) (n int64, err error) {
+	if len(pkeys) == 0 {
+		return 0, nil
+	}
+	n, okIndex, err := d.execBatch(pkeys, BATCH_SIZE, func(index []int) (string, []interface{}, error) {
+		args := make([]interface{}, 0, len(index))
+		where, err := d.pkeyInSql(&args, pkeys, index)
+		return "DELETE FROM " + d.dialect.Quote(d.TableConfig.GetTableName()) + " WHERE " + where, args, err
+	})
+	okPkeys := make(map[string]bool, len(okIndex))
+	for _, i := range okIndex {
+		okPkeys[pkeys[i]] = true
+	}
+	d.deleteCaches(okPkeys)
+	return n, err
+}
+
+//根据where条件,在缓存中查找符合条件的行的主键值.
+func (d *DBcache) getWherePkeys(where string) (pkeys []string, err error) {
+	result, err := d.GetWhere(where)
+	if err != nil {
+		return nil, err
+	}
+	Pkey := d.TableConfig.GetPkey()
+	pkeys = make([]string, 0, len(result))
+	for _, row := range result {
+		pkeys = append(pkeys, row[Pkey])
+	}
+	return pkeys, nil
+}
+
+//批量执行sql.按同步协程把主键分组(保证同一主键的sql按顺序执行),每组再按batchSize分批.
+//buildSql根据一批主键在pkeys中的下标生成sql语句及参数(值不合法时返回错误).返回受影响的行数,执行成功的主键下标.
+//每批执行成功后,同步同一个表的其它别名(见alias.go).
+func (d *DBcache) execBatch(pkeys []string, batchSize int, buildSql func(index []int) (string, []interface{}, error)) (n int64, okIndex []int, err error) {
+	okIndex = make([]int, 0, len(pkeys))
+	for _, group := range d.dataAsync.groupByWorker(pkeys) {
+		for start := 0; start < len(group); start += batchSize {
+			end := start + batchSize
+			if end > len(group) {
+				end = len(group)
+			}
+			batch := group[start:end]
+			sqlString, args, err := buildSql(batch)
+			if err != nil {
+				return n, okIndex, err
+			}
+			result, _, err := d.execDbSql(pkeys[batch[0]], sqlString, false, args...)
+			if err != nil {
+				return n, okIndex, err
+			}
+			n += result.RowsAffected
+			okIndex = append(okIndex, batch...)
+			batchPkeys := make([]string, len(batch))
+			for k, v := range batch {
+				batchPkeys[k] = pkeys[v]
+			}
+			d.syncAliases(pkeys[batch[0]], batchPkeys)
+		}
+	}
+	return n, okIndex, nil
+}
+
+//根据主键值,生成 主键 IN (...) 条件,主键值加入参数args.
+func (d *DBcache) pkeyInSql(args *[]interface{}, pkeys []string, index []int) (string, error) {
+	Pkey := d.TableConfig.GetPkey()
+	values := make([]string, 0, len(index))
+	for _, i := range index {
+		value, err := d.sqlValue(args, Pkey, pkeys[i])
+		if err != nil {
+			return "", err
+		}
+		values = append(values, value)
+	}
+	return d.dialect.Quote(Pkey) + " IN (" + strings.Join(values, ",") + ")", nil
+}
+
+//根据主键值,生成 主键=占位符 条件,主键值加入参数args.
+func (d *DBcache) pkeySql(args *[]interface{}, pkeyValue string) (string, error) {
+	value, err := d.sqlValue(args, d.TableConfig.GetPkey(), pkeyValue)
+	if err != nil {
+		return "", err
+	}
+	return d.dialect.Quote(d.TableConfig.GetPkey()) + "=" + value, nil
+}
+
+//数值:可带符号,小数点,指数.不含inf,nan,十六进制
+var numberRegexp = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)([eE][+-]?\d+)?$`)
+
+//把列的值加入sql语句的参数args,返回sql语句中按数据库方言的占位符.值不拼接到sql语句中,不依赖数据库的字符串转义规则.
+//整型,浮点型列的值必须是数值(去掉首尾空格).
+func (d *DBcache) sqlValue(args *[]interface{}, column string, value string) (string, error) {
+	switch getCompareKind(d.GetColumnType(column)) {
+	case compareDecimal, compareFloat:
+		number := strings.TrimSpace(value)
+		//超出int64的定点数,按字符串检查格式,不转换为浮点数(避免丢失精度)
+		if _, err := strconv.ParseInt(number, 10, 64); err != nil {
+			if _, err := strconv.ParseFloat(number, 64); !(err == nil || errors.Is(err, strconv.ErrRange)) || !numberRegexp.MatchString(number) {
+				return "", fmt.Errorf("sqlValue(),列%s的值不是数值: %s", column, value)
+			}
+		}
+		value = number
+	}
+	*args = append(*args, value)
+	return d.dialect.Placeholder(len(*args)), nil
+}
+
+//按列表达式(列,=,值)得到列名和sql语句中的占位符,值加入参数args,用于INSERT语句.
+func (d *DBcache) columnValues(args *[]interface{}, whereCondition [][]string) (columns []string, values []string, err error) {
+	columns = make([]string, 0, len(whereCondition))
+	values = make([]string, 0, len(whereCondition))
+	for _, condition := range whereCondition {
+		value, err := d.sqlValue(args, condition[0], condition[2])
+		if err != nil {
+			return nil, nil, err
+		}
+		columns = append(columns, condition[0])
+		values = append(values, value)
+	}
+	return columns, values, nil
+}
+
+//生成查询表配置中的列的select语句(不带where),表名和列名按数据库方言引用.
+func selectColumnsSql(sqlDialect dialect.Dialect, table *conf.CacheTable) string {
+	columns := table.GetColumns()
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = sqlDialect.Quote(column)
+	}
+	return "select " + strings.Join(quoted, ",") + " from " + sqlDialect.Quote(table.GetTableName())
+}
+
+//批量插入缓存(map及用于分页查询的缓存),一次加锁.
+func (d *DBcache) insertCaches(items []*SliceCache) {
+	if len(items) == 0 {
+		return
+	}
+	for _, item := range items {
+		d.DbCache.Store(item.Pkey, item.RowMap)
+		//取出所有排序列的值
+		item.SortValues = d.sortOrder.values(item.RowMap)
+		item.SortColumn = firstValue(item.SortValues)
+	}
+	atomic.AddInt64(&d.RowCount, d.pageStore.Insert(items))
+	d.insertViews(items)
+}
+
+//批量删除缓存(map及用于分页查询的缓存),一次加锁.
+func (d *DBcache) deleteCaches(pkeys map[string]bool) {
+	if len(pkeys) == 0 {
+		return
+	}
+	for pkey := range pkeys {
+		d.DbCache.Delete(pkey)
+	}
+	atomic.AddInt64(&d.RowCount, -d.pageStore.Delete(pkeys))
+	d.deleteViews(pkeys)
+}
@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"dbcache/dialect"
+	"reflect"
+	"testing"
+)
+
+//值作为参数,不拼接到sql语句中:整型,浮点型列的值必须是数值,其它列原样传入
+func TestSqlValue(t *testing.T) {
+	d := newTestCache("slice", "")
+	tests := []struct {
+		column string
+		value  string
+		want   string
+		ok     bool
+	}{
+		{"id", "12", "12", true},
+		{"id", " -3 ", "-3", true},
+		{"price", "12345678901234567890.123", "12345678901234567890.123", true},
+		{"score", "1.5e3", "1.5e3", true},
+		{"score", ".5", ".5", true},
+		{"id", "1) OR (1=1", "", false},
+		{"id", "", "", false},
+		{"score", "NaN", "", false},
+		{"score", "inf", "", false},
+		{"price", "0x1p4", "", false},
+		{"name", `it's \`, `it's \`, true},
+	}
+	for _, test := range tests {
+		var args []interface{}
+		holder, err := d.sqlValue(&args, test.column, test.value)
+		if (err == nil) != test.ok {
+			t.Errorf("sqlValue(%s, %q) = %v, want ok %t", test.column, test.value, err, test.ok)
+			continue
+		}
+		if test.ok && (holder != "?" || len(args) != 1 || args[0] != test.want) {
+			t.Errorf("sqlValue(%s, %q) = %s, %q, want ?, %s", test.column, test.value, holder, args, test.want)
+		}
+	}
+	var args []interface{}
+	if where, err := d.pkeyInSql(&args, []string{"1", "1) OR (1=1"}, []int{0, 1}); err == nil {
+		t.Errorf("pkeyInSql() = %s, want error", where)
+	}
+	args = nil
+	if where, err := d.pkeyInSql(&args, []string{"1", "2"}, []int{0, 1}); err != nil || where != "`id` IN (?,?)" || !reflect.DeepEqual(args, []interface{}{"1", "2"}) {
+		t.Errorf("pkeyInSql() = %s, %q, %v", where, args, err)
+	}
+	args = []interface{}{"x"}
+	if set, err := d.GetSqlStr(&args, "name=a,age=3"); err != nil || set != "`name`=?,`age`=?" || !reflect.DeepEqual(args, []interface{}{"x", "a", "3"}) {
+		t.Errorf("GetSqlStr() = %s, %q, %v", set, args, err)
+	}
+	//postgres的占位符按参数的顺序编号
+	d.dialect = dialect.PostgreSQL{}
+	args = []interface{}{"x"}
+	set, _ := d.GetSqlStr(&args, "name=a")
+	if where, err := d.pkeySql(&args, "7"); err != nil || set+" "+where != `"name"=$2 "id"=$3` {
+		t.Errorf("postgres GetSqlStr(), pkeySql() = %s %s, %v", set, where, err)
+	}
+}
+
+//没有主键值的行,按第一行的自增主键值和自增步长推算每行的主键值
+func TestInsertRowsIncrementStep(t *testing.T) {
+	d, f := newFakeCache(t)
+	d.TableConfig.PkeyAutoIncrement = true
+	d.incrementStep = 2
+	f.setResult(3, 10)
+	n, err := d.InsertRows([]string{"age=3,name=a", "age=1,name=b", "age=2,name=c"})
+	if err != nil || n != 3 {
+		t.Fatalf("InsertRows() = %d, %v, want 3", n, err)
+	}
+	checkRow(t, d, "10", map[string]string{"id": "10", "age": "3", "name": "a"})
+	checkRow(t, d, "12", map[string]string{"id": "12", "age": "1", "name": "b"})
+	checkRow(t, d, "14", map[string]string{"id": "14", "age": "2", "name": "c"})
+	if got := testPkeys(d); got != "12,14,10" {
+		t.Errorf("page cache = %s, want 12,14,10", got)
+	}
+
+	//sqlite的LastInsertId()是最后一行的值,步长总是1
+	d.dialect = dialect.SQLite{}
+	d.incrementStep = 1
+	f.setResult(2, 21)
+	if n, err = d.InsertRows([]string{"age=4,name=d", "age=5,name=e"}); err != nil || n != 2 {
+		t.Fatalf("sqlite InsertRows() = %d, %v, want 2", n, err)
+	}
+	checkRow(t, d, "20", map[string]string{"age": "4", "name": "d"})
+	checkRow(t, d, "21", map[string]string{"age": "5", "name": "e"})
+}
@@ -1,24 +1,20 @@
 package cache
 
 import (
+	"context"
 	"database/sql"
 	"dbcache/conf"
+	"dbcache/dialect"
 	"dbcache/logs"
 	"fmt"
 	_ "github.com/go-sql-driver/mysql"
-	"math"
 	"reflect"
-	"runtime"
-	"runtime/debug"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
-	"time"
 )
 
-var (
-	CacheObj = map[string]*DBcache{} //缓存表对象,用于rpc,grpc
-)
 
 //数据库缓存表中列的信息
 type columnInfo struct {
@@ -36,14 +32,18 @@ type columnInfo struct {
 
 type DBcache struct {
 	//基础配置信息
+	name        string                 //缓存表的名称(cache.conf中的分段名),同一个表可以有多个名称(别名,见alias.go)
 	DbConn      *sql.DB                //数据库对象
 	TableConfig conf.CacheTable      //[配置文件cache.conf]保存缓存数据表信息
 	ColumnInfo  map[string]*columnInfo //数据库缓存表中列的信息
-	CacheType   string                 //用于分页查询,缓存类型:一.slice切片,二.sliceNotDel切片(不删除,只记录),三.link链表
+	CacheType   string                 //用于分页查询,缓存类型:一.slice切片,二.sliceNotDel切片(不删除,只记录),三.link链表,四.tree顺序统计树
 	dataAsync   *DataAsync             //异步同步数据库对象
+	dialect     dialect.Dialect        //数据库方言,生成sql语句
+	//主键是自增列时,自增列的步长(mysql的auto_increment_increment),批量插入时推算主键值
+	incrementStep int64
 
 	//map数据缓存对象[主缓存对象]
-	DbCache sync.Map //用来缓存的表数据
+	DbCache sync.Map //用来缓存的表数据,主键值=>*sync.Map(一行数据,与分页缓存共用)
 	//链表缓存对象
 	LinkDbCache LinkCache //链表保存缓存数据[用于页面分页显示](链表,插入和删除快,但查找,修改没切片数据快.适用于插入删除多.数据量大)
 	//切片缓存对象(切片数组优点,因为内存是连续的,查找,修改快,但是插入和删除慢.适用于查询多.数据量少)
@@ -52,69 +52,115 @@ type DBcache struct {
 	DelRowNum    map[int]bool  //(缓存是切片SliceDbCache,并且缓存类型是[sliceNotDel])保存已删除行的行号,当有删除行时,只是把删除的行号保存.未进行切片的删除,因为切片的删除会影响性能.但是这样的缺点是未排序.
 	RowCount     int64         //总行数
 	RwMutex      sync.RWMutex  //读写锁
+	//顺序统计树缓存对象(插入,删除,按行号取行,取行号都是O(log n).适用于数据量大,插入删除多)
+	TreeDbCache *TreeCache //缓存类型是[tree]时,用来根据行号查询缓存,[用于页面分页显示]
+	//用于分页查询的缓存,根据缓存类型是上面的切片,链表或树
+	pageStore PageStore
+	//命名排序视图[用于页面分页显示,按不同的列排序]
+	views map[string]*viewCache
+	//分页缓存的排序方式(order by中的多列,最后按主键)
+	sortOrder *sortOrder
+
+	//生命周期管理
+	stopChan   chan struct{}  //通知后台协程退出的管道
+	wg         sync.WaitGroup //等待后台协程退出
+	closeMutex sync.RWMutex   //写操作持有读锁,关闭时持有写锁,等待正在执行的写操作完成
+	isClosed   bool           //是否已关闭,关闭后不再接收写操作
+
+	upsertMutex sync.Mutex //Upsert时,判断缓存是否存在和插入缓存需串行
+
+	//sliceNotDel切片整理(见compact.go)
+	sortedRows   int        //切片中已排序的行数(加载或整理后),之后的行是追加的,未排序.由RwMutex保护
+	compactMutex sync.Mutex //整理需串行
+	compactStat  compactStat //最近一次整理的信息
+
+	configMutex sync.RWMutex //保护TableConfig中可热加载的配置项和policy(见reload.go)
+	policy      *ColumnPolicy //列的访问策略(见policy.go)
+
+	instance *Instance //所属的缓存实例(见registry.go)
 }
 
 //切片缓存数据
 type SliceCache struct {
 	Pkey       string    //主键值
-	SortColumn string    //排序列值
+	SortColumn string    //排序列值(多列排序时为第一列的值)
 	SortMode   string    //排列方式
+	SortValues []string  //所有排序列的值,按排序键顺序
 	RowMap     *sync.Map //数据库中行的数据
 }
-//新建缓存对象,根据配置文件中,配置的数据库表名.
-func NewDBcache(db *sql.DB, tableName string) (dbCache *DBcache, err error) {
-	//获取配置文件中缓存的所有表
-	result, err := conf.GetCacheTable()
+//新建缓存对象,根据配置文件中的分段名(缓存表的名称,见alias.go).注册到默认实例(见registry.go).
+func NewDBcache(db *sql.DB, name string) (dbCache *DBcache, err error) {
+	return newDBcache(defaultInstance, db, name)
+}
+
+//新建缓存对象,按实例的cache.conf和数据目录,并注册到实例.
+func newDBcache(in *Instance, db *sql.DB, name string) (dbCache *DBcache, err error) {
+	//获取配置文件中所有缓存表的分段名
+	result, err := conf.GetCacheTableSections(in.TablesConf())
 	if err!=nil{
 		return nil,err
 	}
 	isConfTable:=false
-	tableName=strings.TrimSpace(tableName)
+	name=strings.TrimSpace(name)
 	for _,v:=range result{
-		if v==tableName{
+		//分段名不区分大小写,使用配置文件中的分段名
+		if strings.EqualFold(v,name){
+			name=v
 			isConfTable=true
 		}
 	}
 	if isConfTable==false{
-		err = fmt.Errorf("InitCache(),the table [%s] is not in cache config. ", tableName)
+		err = fmt.Errorf("InitCache(),the table [%s] is not in cache config. ", name)
 		return nil,err
 	}
 	cacheTable:=conf.CacheTable{}
 	//读取配置文件,初始化配置信息
-	err = conf.ParseConfTable(conf.TABLES_CONF,tableName, &cacheTable)
+	err = conf.ParseConfTable(in.TablesConf(),name, &cacheTable)
 	if err != nil {
 		return nil, err
 	}
 	dbCache=&DBcache{
+			name:         name,
 			DbConn:       db,
 			TableConfig:  cacheTable,
 			ColumnInfo:   nil,
 			CacheType:    "link",
 			dataAsync:    NewDatAsync(),
+			dialect:      dialect.Of(db),
 			DbCache:      sync.Map{},
 			LinkDbCache:  NewLinkCache(),
 			SliceDbCache: nil,
 			DelRowNum:    make(map[int]bool),
 			RowCount:     0,
 			RwMutex:      sync.RWMutex{},
+			stopChan:     make(chan struct{}),
+			instance:     in,
 		}
-	//根据配置文件生成select查询语句
+	dbCache.policy, err = newColumnPolicy(cacheTable)
+	if err != nil {
+		return nil, err
+	}
+	dbCache.sortOrder = newSortOrder(dbCache.TableConfig.GetSortKeys())
+	dbCache.pageStore = dbCache.newPageStore(dbCache.TableConfig.GetCacheType())
+	//初始化命名排序视图
+	err = dbCache.initViews()
+	if err != nil {
+		return nil, err
+	}
+	//根据配置文件生成select查询语句.表名和列名按数据库方言引用.
+	//查询总行数不加other(order by),postgres中聚合查询不能按非聚合列排序.
 	var selectSql string
 	var countSql string
 	if dbCache.TableConfig.GetWhere() == "" {
-		selectSql = "select " + dbCache.TableConfig.GetColumn() +
-			" from " + dbCache.TableConfig.GetTableName() + " " +
-			dbCache.TableConfig.GetOther()
-		countSql = "select count(1) from " + dbCache.TableConfig.GetTableName() + " " +
+		selectSql = selectColumnsSql(dbCache.dialect, &dbCache.TableConfig) + " " +
 			dbCache.TableConfig.GetOther()
+		countSql = "select count(1) from " + dbCache.dialect.Quote(dbCache.TableConfig.GetTableName())
 	} else {
-		selectSql = "select " + dbCache.TableConfig.GetColumn() +
-			" from " + dbCache.TableConfig.GetTableName() +
-			" where " + dbCache.TableConfig.GetWhere() + " " +
-			dbCache.TableConfig.GetOther()
-		countSql = "select count(1) from " + dbCache.TableConfig.GetTableName() +
+		selectSql = selectColumnsSql(dbCache.dialect, &dbCache.TableConfig) +
 			" where " + dbCache.TableConfig.GetWhere() + " " +
 			dbCache.TableConfig.GetOther()
+		countSql = "select count(1) from " + dbCache.dialect.Quote(dbCache.TableConfig.GetTableName()) +
+			" where " + dbCache.TableConfig.GetWhere()
 	}
 	//查询总行数
 	var count int64
@@ -130,11 +176,17 @@ func NewDBcache(db *sql.DB, tableName string) (dbCache *DBcache, err error) {
 		}
 	}
 
-	//判断用于分页查询的缓存类型.
-	if dbCache.TableConfig.GetCacheType() == "slice" || dbCache.TableConfig.GetCacheType() == "sliceNotDel" {
-		dbCache.RowCount = count
-		dbCache.SliceDbCache = make([]*SliceCache, count, count+count/2)
+	//主键是自增列时,读取自增步长.同一条INSERT语句插入的多行,按此步长推算主键值(见InsertRows())
+	if dbCache.TableConfig.PkeyIsIncrement() {
+		dbCache.incrementStep, err = dbCache.dialect.AutoIncrementStep(db)
+		if err != nil {
+			err = fmt.Errorf("InitCache(),读取自增列的步长失败, err: %s", err)
+			return nil, err
+		}
 	}
+
+	//用于分页查询的行,全部加载后再排序.
+	pageRows := make([]*SliceCache, 0, count)
 	// 执行select查询,检索缓存数据
 	rows, err := db.Query(selectSql)
 	if err != nil {
@@ -176,6 +228,8 @@ func NewDBcache(db *sql.DB, tableName string) (dbCache *DBcache, err error) {
 		}
 		dbCache.ColumnInfo[name] = &column
 	}
+	//排序时按列的类型比较
+	dbCache.initSortTypes()
 
 	//每行的数据,行中每列保存在[]sql.RawBytes字节切片
 	values := make([]sql.RawBytes, len(columns))
@@ -185,7 +239,6 @@ func NewDBcache(db *sql.DB, tableName string) (dbCache *DBcache, err error) {
 		scanArgs[i] = &values[i]
 	}
 	var rowNum int64
-	sortColumn := dbCache.TableConfig.GetSortColumn()
 	sortMode := dbCache.TableConfig.GetSortMode()
 	if sortMode == "" {
 		sortMode = "asc"
@@ -204,7 +257,6 @@ func NewDBcache(db *sql.DB, tableName string) (dbCache *DBcache, err error) {
 		//var RowMap sync.Map
 		RowMap := new(sync.Map)
 		PkeyValue := ""
-		sortColumnValue := ""
 		for i, columnValue := range values {
 			//columnType := GetColumnType(columns[i])
 			//如果数据库类型是字符串
@@ -227,45 +279,25 @@ func NewDBcache(db *sql.DB, tableName string) (dbCache *DBcache, err error) {
 			if dbCache.TableConfig.GetPkey() == columns[i] {
 				PkeyValue = value
 			}
-			//取出排序列值
-			if sortColumn == columns[i] {
-				sortColumnValue = value
-			}
 			RowMap.Store(columns[i], value)
 		}
 		//主缓存
-		dbCache.DbCache.Store(PkeyValue, *RowMap)
-
-		//判断用于分页查询的缓存类型.
-		switch dbCache.TableConfig.GetCacheType() {
-		case "slice": //数据保存于切片
-			SliceData := &SliceCache{
-				Pkey:       PkeyValue,
-				SortColumn: sortColumnValue,
-				SortMode:   sortMode,
-				RowMap:     RowMap,
-			}
-			dbCache.SliceDbCache[rowNum] = SliceData
-		case "sliceNotDel": //数据保存于切片,但删除记录未真的删除,只是记录.
-			SliceData := &SliceCache{
-				Pkey:       PkeyValue,
-				SortColumn: sortColumnValue,
-				SortMode:   sortMode,
-				RowMap:     RowMap,
-			}
-			dbCache.SliceDbCache[rowNum] = SliceData
-			go dbCache.backCheckDelRowRecord() //后台检查删除记录是否达到需要重新初始化
-		case "link": //数据保存于链表
-			node := &Node{
-				rowNum:     rowNum,
-				pkey:       PkeyValue,
-				sortColumn: sortColumnValue,
-				row:        RowMap,
-				pre:        nil,
-				next:       nil,
-			}
-			dbCache.LinkDbCache.InsertTail(node)
+		dbCache.DbCache.Store(PkeyValue, RowMap)
+		//命名排序视图,全部加载后再排序.
+		for _, view := range dbCache.views {
+			view.appendRow(PkeyValue, RowMap)
 		}
+
+		//取出所有排序列的值
+		sortValues := dbCache.sortOrder.values(RowMap)
+
+		pageRows = append(pageRows, &SliceCache{
+			Pkey:       PkeyValue,
+			SortColumn: firstValue(sortValues),
+			SortMode:   sortMode,
+			SortValues: sortValues,
+			RowMap:     RowMap,
+		})
 		rowNum++ //行计数.
 	}
 	if err = rows.Err(); err != nil {
@@ -273,24 +305,27 @@ func NewDBcache(db *sql.DB, tableName string) (dbCache *DBcache, err error) {
 		return nil, err
 	}
 	dbCache.RowCount = rowNum
+	for _, view := range dbCache.views {
+		view.sortRows()
+	}
 	//判断是实时更新,还是后台异步同步数据库数据.
 	if dbCache.TableConfig.GetIsRealtime() == false {
-		err := dbCache.dataAsync.InitAsync(db, dbCache.TableConfig.GetTableName())
+		err := dbCache.dataAsync.InitAsync(db, in.TablesConf(), in.DataDir(), dbCache.asyncFileName())
 		if err != nil {
 			err = fmt.Errorf("InitAsync(),初始化异步同步数据库失败: %s", err)
 			return nil, err
 		}
 	}
 
-	//判断配置表中,是否指定了排序方式,如果没指定,则按主键升序排序
-	switch dbCache.TableConfig.GetCacheType() {
-	case "slice", "sliceNotDel": //数据保存于切片
-		if dbCache.TableConfig.GetSortMode() == "" { //如果未排序,按升序排序
-			dbCache.SliceDbCache = QuickSortGoAsc(dbCache.SliceDbCache)
-		}
+	//加载分页缓存,按order by中的多列排序,排序列都相同时按主键升序.没指定排序时,按主键升序排序
+	dbCache.pageStore.Load(pageRows)
+	//后台检查删除记录是否达到需要重新初始化
+	if dbCache.TableConfig.GetCacheType() == "sliceNotDel" {
+		dbCache.wg.Add(1)
+		go dbCache.backCheckDelRowRecord()
 	}
 	//用于rpc和grpc,保存缓存表对象.
-	CacheObj[dbCache.TableConfig.GetTableName()] = dbCache
+	in.register(dbCache)
 	return dbCache, nil
 }
 
@@ -300,7 +335,7 @@ func (d *DBcache) GetRow(Pkey string) (result map[string]string, err error) {
 	result = map[string]string{}
 	v, ok := d.DbCache.Load(Pkey)
 	if ok {
-		rowMap := v.(sync.Map)
+		rowMap := v.(*sync.Map)
 		rowMap.Range(func(column, value interface{}) bool {
 			result[column.(string)] = value.(string)
 			return true
@@ -331,6 +366,10 @@ func (d *DBcache) GetColumn(Pkey string, column string) (result string, err erro
 
 //根据主键值,删除该行数据.
 func (d *DBcache) DelRow(Pkey string) (n int64, err error) {
+	if err = d.beginWrite(); err != nil {
+		return 0, fmt.Errorf("DelRow(),err: %s", err)
+	}
+	defer d.endWrite()
 	//删除数据库对应主键的行
 	n, err = d.DelDbRow(Pkey)
 	if err != nil {
@@ -341,49 +380,41 @@ func (d *DBcache) DelRow(Pkey string) (n int64, err error) {
 	d.DbCache.Delete(Pkey)
 
 	//删除用于分页缓存中的数据
-	switch d.TableConfig.GetCacheType() {
-	case "slice": //缓存数据保存于切片
-		d.DelSliceDbCache(Pkey)
-	case "sliceNotDel": //缓存数据保存于切片,但不真的删除,只记录行号
-		d.DelSliceDbCacheRecord(Pkey)
-	case "link": //缓存数据保存于链表
-		ok := d.LinkDbCache.DeleteNodePkey(Pkey)
-		if ok {
-			atomic.AddInt64(&d.RowCount, -1)
-		}
-	}
+	atomic.AddInt64(&d.RowCount, -d.pageStore.Delete(map[string]bool{Pkey: true}))
+	//删除命名排序视图中的数据
+	d.deleteViews(map[string]bool{Pkey: true})
+	//同步同一个表的其它别名
+	d.syncAliases(Pkey, []string{Pkey})
 	return n, err
 }
 
 //根据主键值,删除数据库中该行数据.
 func (d *DBcache) DelDbRow(key string) (n int64, err error) {
 	//删除数据库中对应的行.通过主键查找.
-	columnType := d.GetColumnType(key)
-	var sqlString string
-	if strings.Contains(columnType, "INT") || columnType == "FLOAT" || columnType == "DOUBLE" || columnType == "DECIMAL" {
-		sqlString = "DELETE from " + d.TableConfig.GetTableName() + " where " + d.TableConfig.GetPkey() + "=" + key
-	} else {
-		sqlString = "DELETE from " + d.TableConfig.GetTableName() + " where " + d.TableConfig.GetPkey() + "='" + key + "'"
+	args := make([]interface{}, 0, 1)
+	where, err := d.pkeySql(&args, key)
+	if err != nil {
+		return 0, fmt.Errorf("DelDbRow(),err: %s", err)
 	}
+	sqlString := "DELETE FROM " + d.dialect.Quote(d.TableConfig.GetTableName()) + " WHERE " + where
 
 	//判断是实时更新,还是异步更新
 	if d.TableConfig.GetIsRealtime() == true {
-		rs, err := d.DbConn.Exec(sqlString)
+		result, err := d.dialect.Exec(d.DbConn, sqlString, args...)
+		n = result.RowsAffected
 		if err != nil {
 			err = fmt.Errorf("DelDbRow(),删除行数据失败,行主键(%s),err : %s", key, err)
 			return 0, err
 		}
-		n, err = rs.RowsAffected()
-		if err != nil {
-			err = fmt.Errorf("DelDbRow(),获取受影响的行失败, err : %s", err)
-			return 0, err
-		}
 		return n, err
 	} else {
 		//异步更新数据库时,是否需要等待返回执行结果.
-		if d.TableConfig.GetIsWaitResult() {
+		if d.isWaitResult() {
 			result := make(chan *WaitResult, 1)
-			d.dataAsync.sendToAsyncChanResult(true, result, sqlString)
+			err = d.dataAsync.sendToAsyncChanResult(true, result, key, sqlString, args...)
+			if err != nil {
+				return 0, err
+			}
 			waitResult := &WaitResult{}
 			waitResult = <-result
 			if waitResult.err != nil {
@@ -393,7 +424,10 @@ func (d *DBcache) DelDbRow(key string) (n int64, err error) {
 			return n, err
 		} else {
 			//不返回结果.
-			d.dataAsync.sendToAsyncChan(sqlString)
+			err = d.dataAsync.sendToAsyncChan(key, sqlString, args...)
+			if err != nil {
+				return 0, err
+			}
 		}
 	}
 	return 0, nil
@@ -420,82 +454,16 @@ func (d *DBcache) DelSliceDbCache(pkeyValue string) {
 //(该函数仅于分页查询),获取用于分页缓存中数据的行号
 func (d *DBcache) GetRowNum(pkeyValue string) (i int) {
 	pkeyValue = strings.TrimSpace(pkeyValue)
-	//如果是按主键列排序,则使用二分查找
-	if d.TableConfig.GetSortColumn() == d.TableConfig.GetPkey() {
-		switch d.TableConfig.GetSortMode() {
-		case "asc":
-			return BinarySearchAsc(d.SliceDbCache, pkeyValue)
-		case "desc":
-			return BinarySearchDesc(d.SliceDbCache, pkeyValue)
-		default:
-			return BinarySearchAsc(d.SliceDbCache, pkeyValue)
-		}
-	} else {
-		for i, sliceData := range d.SliceDbCache {
-			if sliceData.Pkey == pkeyValue {
-				return i
-			}
-		}
+	//先按排序列值二分查找,找不到时再遍历查找
+	if i = d.searchRowNum(pkeyValue); i != -1 {
+		return i
 	}
-	return -1
-}
-
-//后台检查当保存删除的行容量,重新初始化SliceDbCache
-func (d *DBcache) backCheckDelRowRecord() {
-	for {
-		time.Sleep(time.Second * 3600) //一小时检查一次.
-		hour := time.Now().Hour()
-		//晚上1点到5点之间检查和重新初始化
-		if hour >= 1 && hour <= 5 {
-			if len(d.DelRowNum) > 10000 || len(d.DelRowNum) > len(d.SliceDbCache)/3 {
-				d.RwMutex.Lock()
-				size := len(d.SliceDbCache)
-				d.SliceDbCache = nil
-				d.DelRowNum = nil
-				d.SliceDbCache = make([]*SliceCache, 0, size)
-				d.DelRowNum = make(map[int]bool, size)
-				pkey := d.TableConfig.GetPkey()
-				sortColumn := d.TableConfig.GetSortColumn()
-				sortMode := d.TableConfig.GetSortMode()
-				d.DbCache.Range(func(k, v interface{}) bool {
-					rowMap := v.(sync.Map)
-					pkeyValue := k.(string)
-					var sortColumnValue string
-					//如果主键是排序列
-					if pkey == sortColumn {
-						sortColumnValue = pkeyValue
-					} else { //如果不是主键为排序列,则还要在rowMap中取排序列的值
-						Value, ok := rowMap.Load(sortColumn)
-						if ok {
-							sortColumnValue = Value.(string)
-						} else {
-							sortColumnValue = pkeyValue
-						}
-					}
-					SliceData := &SliceCache{
-						Pkey:       pkeyValue,
-						SortColumn: sortColumnValue,
-						SortMode:   sortMode,
-						RowMap:     &rowMap,
-					}
-					d.SliceDbCache = append(d.SliceDbCache, SliceData)
-					return true
-				})
-				//排序.
-				switch sortMode{
-				case "asc":
-					d.SliceDbCache = QuickSortGoAsc(d.SliceDbCache)
-				case "desc":
-					d.SliceDbCache = QuickSortGoDesc(d.SliceDbCache)
-				default:
-					d.SliceDbCache = QuickSortGoAsc(d.SliceDbCache)
-				}
-				d.RwMutex.Unlock()
-				runtime.GC()
-				debug.FreeOSMemory()
-			}
+	for i, sliceData := range d.SliceDbCache {
+		if sliceData.Pkey == pkeyValue {
+			return i
 		}
 	}
+	return -1
 }
 
 //缓存类型是sliceNotDel切片(不删除,只记录)
@@ -505,11 +473,14 @@ func (d *DBcache) DelSliceDbCacheRecord(pkeyValue string) {
 		return
 	}
 
+	//查找行号和记录删除在同一写锁中,防止期间整理切片(Compact)使行号改变.
+	d.RwMutex.Lock()
 	n := d.GetRowNumRecord(pkeyValue)
 	if n != -1 {
-		d.RwMutex.RLock()
 		d.DelRowNum[n] = true
-		d.RwMutex.RUnlock()
+	}
+	d.RwMutex.Unlock()
+	if n != -1 {
 		atomic.AddInt64(&d.RowCount, -1)
 	}
 }
@@ -518,33 +489,19 @@ func (d *DBcache) DelSliceDbCacheRecord(pkeyValue string) {
 //(该函数仅于分页查询),获取用于分页缓存中数据的行号
 func (d *DBcache) GetRowNumRecord(pkeyValue string) (i int) {
 	pkeyValue = strings.TrimSpace(pkeyValue)
-	i = -1
-	//如果是按主键列排序,则使用二分查找
-	if d.TableConfig.GetSortColumn() == d.TableConfig.GetPkey() {
-		switch d.TableConfig.GetSortMode() {
-		case "asc":
-			i = BinarySearchAsc(d.SliceDbCache, pkeyValue)
-		case "desc":
-			i = BinarySearchDesc(d.SliceDbCache, pkeyValue)
-		default:
-			i = BinarySearchAsc(d.SliceDbCache, pkeyValue)
+	//先按排序列值二分查找(后插入的行未排序,可能找不到),找不到时再遍历查找
+	if i = d.searchRowNum(pkeyValue); i != -1 {
+		//找到,还要在保存删除行号记录中查找.已删除时(更新排序列后,原行只记录为删除),再遍历查找
+		if !d.DelRowNum[i] {
+			return i
 		}
-		//找到,还要在保存删除行号记录中查找
-		if i != -1 {
-			if d.DelRowNum[i] {
-				return -1
-			} else {
-				return i
-			}
+	}
+	for i, sliceData := range d.SliceDbCache {
+		if d.DelRowNum[i] {
+			continue
 		}
-	} else {
-		for i, sliceData := range d.SliceDbCache {
-			if d.DelRowNum[i] {
-				continue
-			}
-			if sliceData.Pkey == pkeyValue {
-				return i
-			}
+		if sliceData.Pkey == pkeyValue {
+			return i
 		}
 	}
 	return -1
@@ -607,120 +564,82 @@ func (d *DBcache) GetWhereCondition(where string, isAnd, isOr bool) (whereCondit
 
 //根据where条件表达式,检查表达式是否成立,并得到数据.
 func (d *DBcache) GetWhereValue(isAnd, isOr bool, whereCondition [][]string, rowMap *sync.Map, result *[]map[string]string) {
+	if !d.isWhereMatch(isAnd, isOr, whereCondition, rowMap) {
+		return
+	}
+	//从该行数据(sny.map),保存在row(map)中.再把该行数据追加到结果切片
 	row := map[string]string{}
+	rowMap.Range(func(k, v interface{}) bool {
+		row[k.(string)] = v.(string)
+		return true
+	})
+	*result = append(*result, row)
+}
+
+//根据where条件表达式,检查该行数据是否符合条件.
+func (d *DBcache) isWhereMatch(isAnd, isOr bool, whereCondition [][]string, rowMap *sync.Map) bool {
+	isMatch := false
 	//从条件表达式中,获取各个表达式.并判断各表达式是否成立.
-	for i, condition := range whereCondition {
+	for _, condition := range whereCondition {
+		isTrue := false
 		//根据条件表式中,column中的值,在该行缓存中查找该值.再做比较(=或!=)判断
 		keyValue, ok := rowMap.Load(condition[0])
 		if ok {
-			//当条件表达式中是=时.
 			if condition[1] == "=" {
-				//判断条件表达中的值与缓存是否相等
-				if condition[2] == keyValue.(string) {
-					//相等,则在条件表达中,设置条件成立
-					whereCondition[i][3] = "true"
-				}
-				//当条件表达式中是!=时.
+				isTrue = condition[2] == keyValue.(string)
 			} else if condition[1] == "!=" {
-				//判断条件表达中的值与缓存是否不相等
-				if condition[2] != keyValue.(string) {
-					//不相等,则在条件表达中,设置条件成立
-					whereCondition[i][3] = "true"
-				}
-			}
-		}
-	}
-
-	switch {
-	//当条件表达式是and时.如果有一个条件不成立,则整个表达式不成立.不成立则true
-	case isAnd == true && isOr == false:
-		isMatch := false
-		for i, condition := range whereCondition {
-			if condition[3] == "false" {
-				isMatch = true
-			} else {
-				whereCondition[i][3] = "false"
+				isTrue = condition[2] != keyValue.(string)
 			}
 		}
-		//条件表达中只要有一个条件(不成立)则执行以下.
-		if isMatch == false {
-			//从该行数据(sny.map),保存在*row(map)中.
-			rowMap.Range(func(k, v interface{}) bool {
-				//(*row)[k.(string)] = v.(string)
-				(row)[k.(string)] = v.(string)
-				return true
-			})
-			//再把该行数据追加到结果切片
-			*result = append(*result, row)
-		}
-
-	//当条件表达式是or时.只要有一个为真.则条件成立
-	case isAnd == false && isOr == true:
-		isMatch := false
-		for i, condition := range whereCondition {
-			if condition[3] == "true" {
-				isMatch = true
-				whereCondition[i][3] = "false"
+		//当条件表达式是and时.如果有一个条件不成立,则整个表达式不成立.
+		if isAnd == true && isOr == false {
+			if !isTrue {
+				return false
 			}
+			isMatch = true
+			continue
 		}
-		//条件表达中只要有一个条件(成立)则执行以下.
-		if isMatch == true {
-			rowMap.Range(func(k, v interface{}) bool {
-				//(*row)[k.(string)] = v.(string)
-				(row)[k.(string)] = v.(string)
-				return true
-			})
-			*result = append(*result, row)
-		}
-
-	//当条件是=时.无and或or
-	case isAnd == false && isOr == false:
-		isMatch := false
-		for i, condition := range whereCondition {
-			if condition[3] == "true" {
-				isMatch = true
-				whereCondition[i][3] = "false"
-			}
-		}
-		//判断是否条件成立
-		if isMatch == true {
-			rowMap.Range(func(k, v interface{}) bool {
-				//(*row)[k.(string)] = v.(string)
-				(row)[k.(string)] = v.(string)
-				return true
-			})
-			*result = append(*result, row)
+		//当条件表达式是or或单条件时.只要有一个为真.则条件成立
+		if isTrue {
+			return true
 		}
 	}
+	return isMatch
 }
 
 //根据where条件,获取多行数据.
 func (d *DBcache) GetWhere(where string) (result []map[string]string, err error) {
-	//存储各条件表达式
-	whereCondition := [][]string{}
+	isAnd, isOr, whereCondition, err := d.parseWhere(where)
+	if err != nil {
+		return nil, fmt.Errorf("GetWhere(), err: %s", err)
+	}
+
+	//从sync.map中取得每行数据,存储于result(MAP类型)
+	d.DbCache.Range(func(k, v interface{}) bool {
+		rowMap := v.(*sync.Map)
+		d.GetWhereValue(isAnd, isOr, whereCondition, rowMap, &result)
+		return true
+	})
+	return result, err
+}
 
+//解析where条件字符串,返回是否and,or组合及各条件表达式.
+func (d *DBcache) parseWhere(where string) (isAnd, isOr bool, whereCondition [][]string, err error) {
 	where = strings.TrimSpace(where)
 	if len(where) == 0 {
-		return nil, fmt.Errorf("GetWhere(),where条件不能为空")
+		return false, false, nil, fmt.Errorf("where条件不能为空")
 	}
 
 	//检查是否包含and 或 or
 	where = strings.ToLower(where)
-	isAnd := strings.Contains(where, " and ")
-	isOr := strings.Contains(where, " or ")
+	isAnd = strings.Contains(where, " and ")
+	isOr = strings.Contains(where, " or ")
 	//根据and 或 or得到表达式
 	whereCondition, err = d.GetWhereCondition(where, isAnd, isOr)
 	if err != nil {
-		return nil, fmt.Errorf("GetWhere(), err: %s", err)
+		return false, false, nil, err
 	}
-
-	//从sync.map中取得每行数据,存储于result(MAP类型)
-	d.DbCache.Range(func(k, v interface{}) bool {
-		rowMap := v.(sync.Map)
-		d.GetWhereValue(isAnd, isOr, whereCondition, &rowMap, &result)
-		return true
-	})
-	return result, err
+	return isAnd, isOr, whereCondition, nil
 }
 
 //获取and或or二边的条件表达式
@@ -781,7 +700,7 @@ func (d *DBcache) SplitString(str string, operator string) (slices []string) {
 func (d *DBcache) SplitCondition(str string, operator string) (result []string, err error) {
 	if i := strings.Index(str, operator); i != -1 {
 		column := str[:i]
-		value := str[i+1:]
+		value := str[i+len(operator):]
 		column = strings.TrimSpace(column)
 		value = strings.TrimSpace(value)
 		operator = strings.TrimSpace(operator)
@@ -798,6 +717,10 @@ func (d *DBcache) SplitCondition(str string, operator string) (result []string,
 
 //根据主键,更新一列的数据.
 func (d *DBcache) UpdateColumn(Pkey string, column string, value string) (n int64, err error) {
+	if err = d.beginWrite(); err != nil {
+		return 0, fmt.Errorf("UpdateColumn(),err: %s", err)
+	}
+	defer d.endWrite()
 	Columns := d.TableConfig.GetColumns()
 	isExist := false
 	for _, v := range Columns {
@@ -820,8 +743,11 @@ func (d *DBcache) UpdateColumn(Pkey string, column string, value string) (n int6
 			return 0, err
 		}
 		//更新缓存
-		rowMap := v.(sync.Map)
+		rowMap := v.(*sync.Map)
 		rowMap.Store(column, value)
+		d.updatePageCache(Pkey, [][]string{{column, "=", value}})
+		d.updateViews(Pkey, [][]string{{column, "=", value}})
+		d.syncAliases(Pkey, []string{Pkey})
 		return i, nil
 	} else {
 		err = fmt.Errorf("UpdateColumn(),数据未找到,主键: %s ", Pkey)
@@ -832,44 +758,46 @@ func (d *DBcache) UpdateColumn(Pkey string, column string, value string) (n int6
 
 //根据主键,更新数据库中一列.
 func (d *DBcache) UpdateDbcolumn(Pkey string, column string, value string) (n int64, err error) {
-	columnType := d.GetColumnType(column)
-	if columnType == "" {
-		columnType = "VARCHAR"
-	}
-	var sqlString string
-	//如果在数据库中,列类型是整型,浮点型
-	if strings.Contains(columnType, "INT") || columnType == "FLOAT" || columnType == "DOUBLE" || columnType == "DECIMAL" {
-		sqlString = "UPDATE " + d.TableConfig.GetTableName() + " SET " + column + "=" + value + " WHERE " + d.TableConfig.GetPkey() + "='" + Pkey + "'"
-	} else {
-		sqlString = "UPDATE " + d.TableConfig.GetTableName() + " SET " + column + "='" + value + "' WHERE " + d.TableConfig.GetPkey() + "='" + Pkey + "'"
+	//值作为参数,列类型是整型,浮点型时,值必须是数值
+	args := make([]interface{}, 0, 2)
+	sqlValue, err := d.sqlValue(&args, column, value)
+	if err != nil {
+		return 0, fmt.Errorf("UpdateDbcolumn(),err: %s", err)
 	}
+	where, err := d.pkeySql(&args, Pkey)
+	if err != nil {
+		return 0, fmt.Errorf("UpdateDbcolumn(),err: %s", err)
+	}
+	sqlString := "UPDATE " + d.dialect.Quote(d.TableConfig.GetTableName()) + " SET " + d.dialect.Quote(column) + "=" + sqlValue + " WHERE " + where
 	//判断是实时更新,还是异步更新
 	if d.TableConfig.GetIsRealtime() == true {
-		rs, err := d.DbConn.Exec(sqlString)
+		result, err := d.dialect.Exec(d.DbConn, sqlString, args...)
+		n = result.RowsAffected
 		if err != nil {
-			err = fmt.Errorf("UpdateDbcolumn(),更新行数据失败,主键: %s 列名: %s 列值: %s ", Pkey, column, value)
-			return 0, err
-		}
-		n, err = rs.RowsAffected()
-		if err != nil {
-			err = fmt.Errorf("UpdateDbcolumn(),获取受影响的行失败. err : %s", err)
+			err = fmt.Errorf("UpdateDbcolumn(),更新行数据失败,主键: %s 列名: %s 列值: %s, err: %s", Pkey, column, value, err)
 			return 0, err
 		}
 		return n, err
 	} else {
 		//异步更新数据库时,是否需要等待返回执行结果.
-		if d.TableConfig.GetIsWaitResult() {
+		if d.isWaitResult() {
 			result := make(chan *WaitResult, 1)
-			d.dataAsync.sendToAsyncChanResult(true, result, sqlString)
+			err = d.dataAsync.sendToAsyncChanResult(true, result, Pkey, sqlString, args...)
+			if err != nil {
+				return 0, err
+			}
 			waitResult := &WaitResult{}
 			waitResult = <-result
 			if waitResult.err != nil {
 				err = fmt.Errorf("UpdateDbcolumn(),更新行数据失败,主键: %s 列名: %s 列值: %s ", Pkey, column, value)
 				return 0, err
 			}
-			return waitResult.n, err
+			return waitResult.result.RowsAffected, err
 		} else {
-			d.dataAsync.sendToAsyncChan(sqlString)
+			err = d.dataAsync.sendToAsyncChan(Pkey, sqlString, args...)
+			if err != nil {
+				return 0, err
+			}
 		}
 	}
 	return 0, nil
@@ -877,6 +805,10 @@ func (d *DBcache) UpdateDbcolumn(Pkey string, column string, value string) (n in
 
 //根据主键,更新多列数据.
 func (d *DBcache) UpdateColumns(Pkey string, where string) (n int64, err error) {
+	if err = d.beginWrite(); err != nil {
+		return 0, fmt.Errorf("UpdateColumns(),err: %s", err)
+	}
+	defer d.endWrite()
 	whereCondition, err := d.GetCondition(where, ",")
 	if err != nil {
 		err = fmt.Errorf("UpdateColumns(),条件错误: %s. err: %s", where, err)
@@ -900,7 +832,7 @@ func (d *DBcache) UpdateColumns(Pkey string, where string) (n int64, err error)
 	//更新缓存
 	v, ok := d.DbCache.Load(Pkey)
 	if ok {
-		rowMap := v.(sync.Map)
+		rowMap := v.(*sync.Map)
 		//更新数据库
 		n, err = d.UpdateDbcolumns(Pkey, where)
 		if err != nil {
@@ -912,6 +844,9 @@ func (d *DBcache) UpdateColumns(Pkey string, where string) (n int64, err error)
 		for _, condition := range whereCondition {
 			rowMap.Store(condition[0], condition[2])
 		}
+		d.updatePageCache(Pkey, whereCondition)
+		d.updateViews(Pkey, whereCondition)
+		d.syncAliases(Pkey, []string{Pkey})
 
 		return n, nil
 	} else {
@@ -924,25 +859,32 @@ func (d *DBcache) UpdateColumns(Pkey string, where string) (n int64, err error)
 //根据主键,更新数据库中多列.
 func (d *DBcache) UpdateDbcolumns(Pkey string, condition string) (n int64, err error) {
 
-	SqlStr := d.GetSqlStr(condition)
-	sqlString := "UPDATE " + d.TableConfig.GetTableName() + " SET " + SqlStr + " WHERE " + d.TableConfig.GetPkey() + "='" + Pkey + "'"
+	args := make([]interface{}, 0)
+	SqlStr, err := d.GetSqlStr(&args, condition)
+	if err != nil {
+		return 0, fmt.Errorf("UpdateDbcolumns(),err: %s", err)
+	}
+	where, err := d.pkeySql(&args, Pkey)
+	if err != nil {
+		return 0, fmt.Errorf("UpdateDbcolumns(),err: %s", err)
+	}
+	sqlString := "UPDATE " + d.dialect.Quote(d.TableConfig.GetTableName()) + " SET " + SqlStr + " WHERE " + where
 	if d.TableConfig.GetIsRealtime() == true {
-		rs, err := d.DbConn.Exec(sqlString)
+		result, err := d.dialect.Exec(d.DbConn, sqlString, args...)
+		n = result.RowsAffected
 		if err != nil {
 			err = fmt.Errorf("UpdateDbcolumns(),更新行数据失败,行主键: %s, err: %s", Pkey, err)
 			return 0, err
 		}
-		n, err = rs.RowsAffected()
-		if err != nil {
-			err = fmt.Errorf("UpdateDbcolumns(),获取受影响的行失败. err: %s", err)
-			return 0, err
-		}
 		return n, err
 	} else {
 		//异步更新数据库时,是否需要等待返回执行结果.
-		if d.TableConfig.GetIsWaitResult() {
+		if d.isWaitResult() {
 			result := make(chan *WaitResult, 1)
-			d.dataAsync.sendToAsyncChanResult(true, result, sqlString)
+			err = d.dataAsync.sendToAsyncChanResult(true, result, Pkey, sqlString, args...)
+			if err != nil {
+				return 0, err
+			}
 			waitResult := &WaitResult{}
 			waitResult = <-result
 			if waitResult.err != nil {
@@ -951,32 +893,33 @@ func (d *DBcache) UpdateDbcolumns(Pkey string, condition string) (n int64, err e
 			}
 			return n, err
 		} else {
-			d.dataAsync.sendToAsyncChan(sqlString)
+			err = d.dataAsync.sendToAsyncChan(Pkey, sqlString, args...)
+			if err != nil {
+				return 0, err
+			}
 		}
 	}
 	return 0, nil
 }
 
-//根据表达式,得到SQL语句的字符串.
-func (d *DBcache) GetSqlStr(condition string) (SqlStr string) {
+//根据表达式,得到SQL语句的字符串(列=占位符,...),值加入参数args.
+func (d *DBcache) GetSqlStr(args *[]interface{}, condition string) (SqlStr string, err error) {
 	whereCondition, err := d.GetCondition(condition, ",")
 	if err != nil {
-		logs.Error("a", "GetSqlStr(),条件错误: %s, err: %s", condition, err)
-		return ""
+		return "", fmt.Errorf("GetSqlStr(),条件错误: %s, err: %s", condition, err)
 	}
 	var columns string
 
 	for _, condition := range whereCondition {
-		//判断列是否是INT,FLOAT,DOUBLE,DECIMAL.
-		columnType := d.GetColumnType(condition[0])
-		if strings.Contains(columnType, "INT") || columnType == "FLOAT" || columnType == "DOUBLE" || columnType == "DECIMAL" {
-			columns = columns + condition[0] + "=" + condition[2] + ","
-		} else {
-			columns = columns + condition[0] + "='" + condition[2] + "',"
+		//列类型是整型,浮点型时,值必须是数值.
+		sqlValue, err := d.sqlValue(args, condition[0], condition[2])
+		if err != nil {
+			return "", fmt.Errorf("GetSqlStr(),err: %s", err)
 		}
+		columns = columns + d.dialect.Quote(condition[0]) + "=" + sqlValue + ","
 	}
 	SqlStr = columns[:len(columns)-1]
-	return SqlStr
+	return SqlStr, nil
 }
 
 //判断是否存在主键.
@@ -996,21 +939,36 @@ func (d *DBcache) isExistPkey(condition string) (isTrue bool) {
 	return isTrue
 }
 
-//插入一行数据.
-func (d *DBcache) InsertRow(condition string) (n int64, err error) {
+//从条件中取出主键值,没有主键返回空字符串.
+func (d *DBcache) getPkeyValue(condition string) (pkeyValue string) {
+	whereCondition, err := d.GetCondition(condition, ",")
+	if err != nil {
+		return ""
+	}
+	for _, v := range whereCondition {
+		if v[0] == d.TableConfig.GetPkey() {
+			return v[2]
+		}
+	}
+	return ""
+}
+
+//插入一行数据.返回插入的行数和自增主键值(主键不是自增列时,lastInsertId为数据库返回值,可忽略).
+func (d *DBcache) InsertRow(condition string) (n int64, lastInsertId int64, err error) {
+	if err = d.beginWrite(); err != nil {
+		return 0, 0, fmt.Errorf("InsertRow(),err: %s", err)
+	}
+	defer d.endWrite()
 	rowMap := new(sync.Map)
-	sortColumn := d.TableConfig.GetSortColumn()
-	sortMode := d.TableConfig.GetSortMode()
 	whereCondition, err := d.GetCondition(condition, ",")
 	if err != nil {
 		err = fmt.Errorf("InsertRow(),获取条件错误. err: %v", err)
-		return 0, err
+		return 0, 0, err
 	}
 	//判断插入一行数据中，有没有主键．这只是需要主键．
 	//如果是自增列，则不需要主键．
 	Pkey := d.TableConfig.GetPkey()
 	var PkeyValue string
-	var sortColumnValue string
 	isPkey := false
 	for _, condition := range whereCondition {
 		//判断该列在数据库中是否可为空
@@ -1019,7 +977,7 @@ func (d *DBcache) InsertRow(condition string) (n int64, err error) {
 			if colInfo.isNullable == true && colInfo.nullable == false {
 				if strings.TrimSpace(condition[2]) == "" {
 					err = fmt.Errorf("InsertRow(),该列%s不能为空. err: %v", condition[0], err)
-					return 0, err
+					return 0, 0, err
 				}
 			}
 		}
@@ -1029,275 +987,366 @@ func (d *DBcache) InsertRow(condition string) (n int64, err error) {
 			PkeyValue = condition[2]
 			isPkey = true
 		}
-		//取排序列的值
-		if condition[0] == sortColumn {
-			sortColumnValue = condition[2]
-		}
-
 		//将插入的行数据保存于map中
 		rowMap.Store(condition[0], condition[2])
 	}
 	//不是自增列,必须要有主键.自增列可以不要
 	if d.TableConfig.PkeyIsIncrement() == false && isPkey != true {
 		err = fmt.Errorf("InsertRow(),插入行中,没有主键.条件: %s, 主键: %s, err: %v", condition, Pkey, err)
-		return 0, err
+		return 0, 0, err
 	}
 	//插入数据库
-	i, err := d.InsertDbRow(condition)
+	i, lastInsertId, err := d.InsertDbRow(condition)
 	if err != nil {
-		return 0, err
+		return 0, 0, err
+	}
+	//自增列没有主键值时,用数据库生成的主键值保存缓存.同步协程按插入时的主键值(空)分配.
+	workerKey := PkeyValue
+	if isPkey == false {
+		PkeyValue = strconv.FormatInt(lastInsertId, 10)
+		rowMap.Store(Pkey, PkeyValue)
 	}
 	//插入缓存
-	d.DbCache.Store(PkeyValue, *rowMap)
-
+	d.DbCache.Store(PkeyValue, rowMap)
 	//插入用于分页查询的缓存
-	switch d.TableConfig.GetCacheType() {
-	case "slice": //数据保存于切片.
-		//插入用于分页查询缓存
-		d.RwMutex.Lock()
-		SliceData := &SliceCache{
-			Pkey:       PkeyValue,
-			SortColumn: sortColumnValue,
-			SortMode:   sortMode,
-			RowMap:     rowMap,
-		}
-		index := -1
-		switch d.TableConfig.GetSortMode() {
-		case "asc":
-			index = BinarySearchAsc(d.SliceDbCache, sortColumnValue)
-			if index == -1 {
-				//当是升序排序时,如果没找到,判断是不是比第1个还小,如果还小,则插入到最前面
-				if sortColumnValue < d.SliceDbCache[0].SortColumn {
-					d.SliceDbCache = append(d.SliceDbCache[:0], append([]*SliceCache{SliceData}, d.SliceDbCache[0:]...)...)
-				} else {
-					d.SliceDbCache = append(d.SliceDbCache, SliceData)
-				}
-			} else {
-				d.SliceDbCache = append(d.SliceDbCache[:n], append([]*SliceCache{SliceData}, d.SliceDbCache[n:]...)...)
-			}
-		case "desc":
-			index = BinarySearchDesc(d.SliceDbCache, sortColumnValue)
-			if index == -1 {
-				//当是降序排序时,如果没找到,判断是不是比第1个还大,如果还大,则插入到最前面
-				if sortColumnValue > d.SliceDbCache[0].SortColumn {
-					d.SliceDbCache = append(d.SliceDbCache[:0], append([]*SliceCache{SliceData}, d.SliceDbCache[0:]...)...)
-				} else {
-					d.SliceDbCache = append(d.SliceDbCache, SliceData)
-				}
-			} else {
-				d.SliceDbCache = append(d.SliceDbCache[:index], append([]*SliceCache{SliceData}, d.SliceDbCache[index:]...)...)
-			}
-		default:
-			//如果未排序,则追加到最后.
-			d.SliceDbCache = append(d.SliceDbCache, SliceData)
-		}
-		d.RwMutex.Unlock()
-		atomic.AddInt64(&d.RowCount, 1)
-	case "sliceNotDel": //数据保存于切片,但不删除
-		//插入用于分页查询缓存
-		d.RwMutex.Lock()
-		SliceData := &SliceCache{
-			Pkey:       PkeyValue,
-			SortColumn: sortColumnValue,
-			SortMode:   sortMode,
-			RowMap:     rowMap,
-		}
-		//只是追加,保证切片的行号不变.
-		d.SliceDbCache = append(d.SliceDbCache, SliceData)
-		d.RwMutex.Unlock()
-		atomic.AddInt64(&d.RowCount, 1)
-	case "link": //数据保存于链表
-		node := &Node{
-			rowNum:     d.LinkDbCache.length + 1,
-			pkey:       PkeyValue,
-			sortColumn: sortColumnValue,
-			row:        rowMap,
-			pre:        nil,
-			next:       nil,
+	d.insertPageCache(PkeyValue, rowMap)
+	d.syncAliases(workerKey, []string{PkeyValue})
+	return i, lastInsertId, nil
+}
+
+//插入一行到用于分页查询的缓存(slice,sliceNotDel,link,tree).排序列的值从行数据中取.
+func (d *DBcache) insertPageCache(PkeyValue string, rowMap *sync.Map) {
+	sortValues := d.sortOrder.values(rowMap)
+	SliceData := &SliceCache{
+		Pkey:       PkeyValue,
+		SortColumn: firstValue(sortValues),
+		SortMode:   d.TableConfig.GetSortMode(),
+		SortValues: sortValues,
+		RowMap:     rowMap,
+	}
+	atomic.AddInt64(&d.RowCount, d.pageStore.Insert([]*SliceCache{SliceData}))
+	//插入命名排序视图
+	d.insertViews([]*SliceCache{{Pkey: PkeyValue, RowMap: rowMap}})
+}
+
+//更新行时,如果更新了排序列,在分页缓存中移动该行到新的位置.changes是更新的列表达式(列,=,值).
+func (d *DBcache) updatePageCache(PkeyValue string, changes [][]string) {
+	if !d.sortOrder.hasColumn(changes) {
+		return
+	}
+	d.pageStore.Update(PkeyValue, func(old *SliceCache) *SliceCache {
+		sortValues, isChange := d.sortOrder.changeValues(old.SortValues, changes)
+		if isChange == false {
+			return nil
 		}
-		switch d.TableConfig.GetSortMode() {
-		case "asc":
-			d.LinkDbCache.InsertNodeAsc(node)
-		case "desc":
-			d.LinkDbCache.InsertNodeDesc(node)
-		default: //插入到最后.
-			d.LinkDbCache.InsertTail(node)
+		return &SliceCache{
+			Pkey:       old.Pkey,
+			SortColumn: firstValue(sortValues),
+			SortMode:   old.SortMode,
+			SortValues: sortValues,
+			RowMap:     old.RowMap,
 		}
-		atomic.AddInt64(&d.RowCount, 1)
-	}
-	return i, nil
+	})
 }
 
-//插入一行数据到数据库.
-func (d *DBcache) InsertDbRow(condition string) (n int64, err error) {
-	SqlStr := d.GetSqlStr(condition)
-	sqlString := "INSERT INTO " + d.TableConfig.GetTableName() + " SET " + SqlStr
+//插入一行数据到数据库.返回插入的行数和自增主键值.
+//自增列没有主键值时,异步更新也会等待返回执行结果,用于取得数据库生成的主键值.
+func (d *DBcache) InsertDbRow(condition string) (n int64, lastInsertId int64, err error) {
+	whereCondition, err := d.GetCondition(condition, ",")
+	if err != nil {
+		err = fmt.Errorf("InsertDbRow(),条件错误: %s, err: %v", condition, err)
+		return 0, 0, err
+	}
+	args := make([]interface{}, 0, len(whereCondition))
+	columns, values, err := d.columnValues(&args, whereCondition)
+	if err != nil {
+		return 0, 0, fmt.Errorf("InsertDbRow(),err: %s", err)
+	}
+	sqlString := d.dialect.Insert(d.TableConfig.GetTableName(), columns, [][]string{values})
+	//主键值,用于异步同步时分配同步协程.自增列没有主键值时,都分配到同一个协程.
+	pkeyValue := d.getPkeyValue(condition)
+	isAutoPkey := d.TableConfig.PkeyIsIncrement() && pkeyValue == ""
+	isWaitResult := d.isWaitResult() || isAutoPkey
+	if isAutoPkey {
+		//postgres用RETURNING返回数据库生成的主键值
+		sqlString = sqlString + d.dialect.Returning(d.TableConfig.GetPkey())
+	}
 
 	if d.TableConfig.GetIsRealtime() == true {
-		rs, err := d.DbConn.Exec(sqlString)
+		result, err := d.dialect.Exec(d.DbConn, sqlString, args...)
 		if err != nil {
 			err = fmt.Errorf("InsertDbRow(),插入行到数据库失败.语句:%s, err: %v", sqlString, err)
-			return 0, err
+			return 0, 0, err
 		}
-		n, err = rs.RowsAffected()
-		if err != nil {
-			err = fmt.Errorf("InsertDbRow(),获取受影响的行失败. err: %v", err)
-			return 0, err
+		n, lastInsertId = result.RowsAffected, result.InsertId(0, 1)
+		//只有自增列没有主键值时,才需要数据库生成的主键值.
+		if isAutoPkey && lastInsertId == 0 {
+			err = fmt.Errorf("InsertDbRow(),获取自增主键值失败.语句:%s", sqlString)
+			return 0, 0, err
 		}
-		return n, err
+		return n, lastInsertId, nil
 	} else {
 		//异步更新数据库时,是否需要等待返回执行结果.
-		if d.TableConfig.GetIsWaitResult() {
+		if isWaitResult {
 			result := make(chan *WaitResult, 1)
-			d.dataAsync.sendToAsyncChanResult(true, result, sqlString)
+			err = d.dataAsync.sendToAsyncChanResult(true, result, pkeyValue, sqlString, args...)
+			if err != nil {
+				return 0, 0, err
+			}
 			waitResult := &WaitResult{}
 			waitResult = <-result
 			if waitResult.err != nil {
 				err = fmt.Errorf("InsertDbRow(),插入行到数据库失败.语句:%s, err: %v", sqlString, waitResult.err)
-				return 0, err
+				return 0, 0, err
 			}
-			return waitResult.n, err
+			return waitResult.result.RowsAffected, waitResult.result.InsertId(0, 1), nil
 		} else {
-			d.dataAsync.sendToAsyncChan(sqlString)
+			err = d.dataAsync.sendToAsyncChan(pkeyValue, sqlString, args...)
+			if err != nil {
+				return 0, 0, err
+			}
 		}
 	}
-	return 0, nil
+	return 0, 0, nil
 }
 
-//(该函数仅于分页显示,提取数据)从缓存中,获取指定的行,开始行-结束行.(不包括结束行)并不是与数据库中行号一致.
-//因为从数据库中检索数据时,数据先后不一定.这只是缓存的行号.目的是一样.不影响使用.
-func (d *DBcache) GetRowBetween(start int, end int) (result []map[string]string) {
-	switch d.TableConfig.GetCacheType() {
-	case "slice": //数据保存于slice切片.
-		if start > len(d.SliceDbCache) {
-			start = len(d.SliceDbCache) - 1
+//插入或更新一行数据(主键存在则更新,不存在则插入).row是多列的组合表达式,必须包含主键.
+//返回受影响的行数,是插入还是更新,以及isInsert是否确定(isKnown).
+//已缓存的行是更新;未缓存的行,数据库中可能已有(例如不符合where),只有能根据受影响的行数判断时(mysql)才确定.
+//未缓存的行写入后从数据库重新读取(按where),不按写入的列插入缓存;异步更新不等待结果时,在sql执行后读取.
+func (d *DBcache) Upsert(row string) (n int64, isInsert bool, isKnown bool, err error) {
+	if err = d.beginWrite(); err != nil {
+		return 0, false, false, fmt.Errorf("Upsert(),err: %s", err)
+	}
+	defer d.endWrite()
+	whereCondition, err := d.GetCondition(row, ",")
+	if err != nil {
+		err = fmt.Errorf("Upsert(),获取条件错误. err: %v", err)
+		return 0, false, false, err
+	}
+	Pkey := d.TableConfig.GetPkey()
+	columns := d.TableConfig.GetColumns()
+	var PkeyValue string
+	isPkey := false
+	for _, condition := range whereCondition {
+		//检查该列是否存在于缓存表中.
+		isExist := false
+		for _, column := range columns {
+			if condition[0] == column {
+				isExist = true
+				break
+			}
 		}
-		if end > len(d.SliceDbCache) {
-			end = len(d.SliceDbCache)
+		if isExist == false {
+			err = fmt.Errorf("Upsert(),该列未缓存,列名: %s", condition[0])
+			return 0, false, false, err
 		}
-		for i := start; i < end; i++ {
-			row := make(map[string]string, end-start)
-			rowMap := *d.SliceDbCache[i].RowMap
-			rowMap.Range(func(column, value interface{}) bool {
-				row[column.(string)] = value.(string)
-				return true
-			})
-			result = append(result, row)
+		//判断该列在数据库中是否可为空
+		colInfo, ok := d.ColumnInfo[condition[0]]
+		if ok {
+			if colInfo.isNullable == true && colInfo.nullable == false {
+				if strings.TrimSpace(condition[2]) == "" {
+					err = fmt.Errorf("Upsert(),该列%s不能为空.", condition[0])
+					return 0, false, false, err
+				}
+			}
+		}
+		if condition[0] == Pkey {
+			PkeyValue = condition[2]
+			isPkey = true
 		}
+	}
+	if isPkey == false {
+		err = fmt.Errorf("Upsert(),没有主键.条件: %s, 主键: %s", row, Pkey)
+		return 0, false, false, err
+	}
+	//主键存在时更新其它列,只有主键时不做任何更新.
+	args := make([]interface{}, 0, len(whereCondition))
+	upsertColumns, values, err := d.columnValues(&args, whereCondition)
+	if err != nil {
+		return 0, false, false, fmt.Errorf("Upsert(),err: %s", err)
+	}
+	sqlString := d.dialect.Upsert(d.TableConfig.GetTableName(), Pkey, upsertColumns, values)
 
-	case "sliceNotDel": //数据保存于sliceNotDel切片(不删除,只记录)
-		if start > len(d.SliceDbCache) {
-			start = len(d.SliceDbCache) - 1
+	//同步同一个表的其它别名,在释放upsertMutex之后(别名同步时会加别名的upsertMutex).
+	defer func() {
+		if err == nil {
+			d.syncAliases(PkeyValue, []string{PkeyValue})
 		}
-		if end > len(d.SliceDbCache) {
-			end = len(d.SliceDbCache)
+	}()
+	//同一主键的插入或更新需串行,防止二个Upsert同时插入缓存.
+	d.upsertMutex.Lock()
+	defer d.upsertMutex.Unlock()
+
+	v, isCached := d.DbCache.Load(PkeyValue)
+	result, isWait, err := d.execDbSql(PkeyValue, sqlString, false, args...)
+	if err != nil {
+		err = fmt.Errorf("Upsert(),err: %s", err)
+		return 0, false, false, err
+	}
+	n = result.RowsAffected
+
+	if isCached {
+		//更新缓存
+		rowMap := v.(*sync.Map)
+		for _, condition := range whereCondition {
+			rowMap.Store(condition[0], condition[2])
 		}
-		//获取start到end之间删除的行
-		var delRowCount int
-		for k, _ := range d.DelRowNum {
-			if k >= start && k < end {
-				delRowCount++
-			}
+		d.updatePageCache(PkeyValue, whereCondition)
+		d.updateViews(PkeyValue, whereCondition)
+		return n, false, true, nil
+	}
+	//未缓存的行:等待了数据库返回结果,且能根据受影响的行数判断时(mysql:1插入,2更新,0数据未变化),按行数判断.否则不能确定(isInsert为true).
+	isInsert = true
+	if isWait {
+		if insert, ok := d.dialect.UpsertIsInsert(n); ok {
+			isInsert, isKnown = insert, true
 		}
-
-		for i := start; i < end+delRowCount; i++ {
-			if d.DelRowNum[i] {
-				continue
+	}
+	//从数据库重新读取该行(按where),符合where的行插入缓存.
+	if !isWait {
+		refresh := func() {
+			if err := d.refreshRows([]string{PkeyValue}); err != nil {
+				logs.Error("a", "Upsert(),[%s]重新读取写入的行失败,主键: %s, err: %s", d.Name(), PkeyValue, err)
 			}
-
-			row := make(map[string]string, end-start)
-			rowMap := *d.SliceDbCache[i].RowMap
-			rowMap.Range(func(column, value interface{}) bool {
-				row[column.(string)] = value.(string)
-				return true
-			})
-			result = append(result, row)
 		}
-	case "link": //数据保存于链表
-		startInt64 := int64(start)
-		endInt64 := int64(end)
-		length := d.LinkDbCache.length
-		if startInt64 > length {
-			startInt64 = length
+		if err = d.dataAsync.sendFuncToAsyncChan(PkeyValue, refresh); err != nil {
+			logs.Error("a", "Upsert(),[%s]重新读取写入的行失败,主键: %s, err: %s", d.Name(), PkeyValue, err)
+			err = nil
 		}
-		if endInt64 > length {
-			endInt64 = length
+		return n, isInsert, isKnown, nil
+	}
+	rows, err := d.readRows([]string{PkeyValue})
+	if err != nil {
+		logs.Error("a", "Upsert(),[%s]重新读取写入的行失败,主键: %s, err: %s", d.Name(), PkeyValue, err)
+		return n, isInsert, isKnown, nil
+	}
+	d.applyRowsLocked([]string{PkeyValue}, rows)
+	return n, isInsert, isKnown, nil
+}
+
+//执行一条sql语句(值是占位符,args是参数),根据配置实时更新,或异步更新.mustWait为true时,异步更新也等待返回执行结果.
+//返回受影响的行数,插入时的自增主键值,以及是否等待了数据库返回结果.
+func (d *DBcache) execDbSql(pkeyValue string, sqlString string, mustWait bool, args ...interface{}) (result dialect.Result, isWait bool, err error) {
+	if d.TableConfig.GetIsRealtime() == true {
+		//不是插入语句时,没有自增主键值.
+		result, err = d.dialect.Exec(d.DbConn, sqlString, args...)
+		if err != nil {
+			err = fmt.Errorf("execDbSql(),执行失败.语句:%s, err: %v", sqlString, err)
+			return dialect.Result{}, true, err
 		}
-		nodes := d.LinkDbCache.GetNodeBetween(startInt64, endInt64)
-		for _, node := range nodes {
-			row := make(map[string]string, len(nodes))
-			rowMap := node.row
-			rowMap.Range(func(column, value interface{}) bool {
-				row[column.(string)] = value.(string)
-				return true
-			})
-			result = append(result, row)
+		return result, true, nil
+	}
+	//异步更新数据库时,是否需要等待返回执行结果.
+	if mustWait || d.isWaitResult() {
+		resultChan := make(chan *WaitResult, 1)
+		err = d.dataAsync.sendToAsyncChanResult(true, resultChan, pkeyValue, sqlString, args...)
+		if err != nil {
+			return dialect.Result{}, true, err
+		}
+		waitResult := <-resultChan
+		if waitResult.err != nil {
+			err = fmt.Errorf("execDbSql(),执行失败.语句:%s, err: %v", sqlString, waitResult.err)
+			return dialect.Result{}, true, err
 		}
+		return waitResult.result, true, nil
 	}
+	err = d.dataAsync.sendToAsyncChan(pkeyValue, sqlString, args...)
+	if err != nil {
+		return dialect.Result{}, false, err
+	}
+	return dialect.Result{}, false, nil
+}
 
+//(该函数仅于分页显示,提取数据)从缓存中,获取指定的行,开始行-结束行.(不包括结束行)并不是与数据库中行号一致.
+//因为从数据库中检索数据时,数据先后不一定.这只是缓存的行号.目的是一样.不影响使用.
+func (d *DBcache) GetRowBetween(start int, end int) (result []map[string]string) {
+	for _, row := range d.pageStore.Between(start, end) {
+		result = append(result, rowMapToMap(row.RowMap))
+	}
 	return result
 }
+
+//获取行在分页缓存中的排序位置(从0开始),不存在返回-1.用于计算该行在第几页.
+func (d *DBcache) GetRowRank(pkeyValue string) int {
+	return d.pageStore.Rank(strings.TrimSpace(pkeyValue))
+}
+
 //用于分页,获取总页数.pageSize参数是每页行数大小
 func (d *DBcache) GetPageCount(pageSize int) (result int) {
-	if pageSize <= 0{
-		return 0
-	}
-	result = int(math.Ceil(float64(d.RowCount) / float64(pageSize)))
-	return result
+	return d.tableView().GetPageCount(pageSize)
 }
 
 //用于分页,根据指定开始页,获取多少页,每页行数.返回数据.参数说明:startPage,开始页,pageNum多少页,pageSize参数是每页行数大小
 func (d *DBcache) GetMultipageRows(startPage int,pageNum int,pageSize int) (result []map[string]string) {
-	if pageSize <= 0{
-		return nil
-	}
-	//获取总页数
-	pageCount := d.GetPageCount(pageSize)
-	if startPage <= 0 {
-		startPage = 1
-	}
-	if startPage > pageCount {
-		startPage = pageCount
-	}
-	var startRow,endRow int
-	if startPage==1 {
-		startRow=0
-		endRow=pageNum*pageSize -1
-	}else{
-		startRow =(startPage-1)*pageSize -1
-		endRow =(startPage+pageNum)*pageSize  -1
-	}
-	result = d.GetRowBetween(startRow, endRow)
-	return result
+	return d.tableView().GetMultipageRows(startPage, pageNum, pageSize)
 }
 //用于分页,根据页码和每页行数大小,返回数据.参数说明:page参数是页码,pageSize参数是每页行数大小
 func (d *DBcache) GetOnePageRows(page int,pageSize int) (result []map[string]string) {
-	if pageSize <= 0{
+	return d.tableView().GetOnePageRows(page, pageSize)
+}
+
+//获取总行数.
+func (d *DBcache) GetRowCount() int64 {
+	return atomic.LoadInt64(&d.RowCount)
+}
+
+//获取异步同步协程的状态(管道中等待数,已执行数,延迟等).实时更新时返回nil.
+func (d *DBcache) AsyncStats() (stats []AsyncWorkerStat) {
+	if d.TableConfig.GetIsRealtime() {
 		return nil
 	}
-	//获取总页数
-	pageCount := d.GetPageCount(pageSize)
-	if page <= 0 {
-		page = 1
-	}
-	if page > pageCount {
-		page = pageCount
+	return d.dataAsync.WorkerStats()
+}
+
+//开始写操作,如果缓存已关闭,返回错误.成功时,调用者必须调用endWrite()
+func (d *DBcache) beginWrite() (err error) {
+	d.closeMutex.RLock()
+	if d.isClosed {
+		d.closeMutex.RUnlock()
+		return fmt.Errorf("缓存表[%s]已关闭,不再接收写操作", d.Name())
 	}
+	return nil
+}
+
+//结束写操作
+func (d *DBcache) endWrite() {
+	d.closeMutex.RUnlock()
+}
 
-	var startRow,endRow int
-	if page==1 {
-		startRow=0
-		endRow=pageSize
-	}else{
-		startRow =(page-1)*pageSize -1
-		endRow =page*pageSize  -1
+//关闭缓存对象.
+//不再接收新的写操作,等待正在执行的写操作完成,把异步管道中的sql同步到数据库,停止后台协程,关闭文件.
+//如果ctx先超时,返回错误.
+func (d *DBcache) Close(ctx context.Context) (err error) {
+	d.closeMutex.Lock()
+	if d.isClosed {
+		d.closeMutex.Unlock()
+		return nil
 	}
-	result = d.GetRowBetween(startRow, endRow)
-	return result
+	d.isClosed = true
+	d.closeMutex.Unlock()
+	return d.shutdown(ctx)
 }
 
-//关闭打开的对象
-func (d *DBcache) Close() {
-	//关闭异步同步文件对象.
-	d.dataAsync.Close()
+//已不再接收写操作(isClosed)时,停止后台协程,把异步管道中的sql同步到数据库,关闭文件.见Close().
+func (d *DBcache) shutdown(ctx context.Context) (err error) {
+	//停止后台协程
+	close(d.stopChan)
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return fmt.Errorf("Close(),等待后台协程退出超时, err: %s", ctx.Err())
+	}
+
+	//同步异步管道中剩余的sql,关闭异步同步文件对象.
+	err = d.dataAsync.Close(ctx)
+	if err != nil {
+		return fmt.Errorf("Close(),err: %s", err)
+	}
+	return nil
 }
@@ -0,0 +1,308 @@
+package cache
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"dbcache/dialect"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+//测试用的数据库:记录执行的sql及参数,Exec返回预设的结果,Query按参数(主键值)返回预设的行.
+type fakeDB struct {
+	mutex        sync.Mutex
+	execs        []string            //执行的sql及参数
+	rowsAffected int64               //Exec返回的受影响的行数
+	lastInsertId int64               //Exec返回的LastInsertId()
+	columns      []string            //Query返回的列
+	rows         map[string][]string //Query返回的行,主键值 -> 各列的值
+}
+
+//设置Exec返回的结果
+func (f *fakeDB) setResult(rowsAffected int64, lastInsertId int64) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.rowsAffected, f.lastInsertId = rowsAffected, lastInsertId
+}
+
+//取出执行的sql及参数
+func (f *fakeDB) takeExecs() []string {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	execs := f.execs
+	f.execs = nil
+	return execs
+}
+
+var fakeDBs sync.Map //数据源名 -> *fakeDB
+
+//测试用的数据库驱动,数据源名对应fakeDBs中的数据库
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	f, ok := fakeDBs.Load(name)
+	if !ok {
+		return nil, fmt.Errorf("fake database %s not found", name)
+	}
+	return &fakeConn{f.(*fakeDB)}, nil
+}
+
+func init() {
+	sql.Register("dbcache_fake", fakeDriver{})
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("fakeConn: Prepare not supported")
+}
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return nil, errors.New("fakeConn: Begin not supported") }
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.db.mutex.Lock()
+	defer c.db.mutex.Unlock()
+	c.db.execs = append(c.db.execs, query+" "+fmt.Sprint(namedValues(args)))
+	return fakeResult{c.db.rowsAffected, c.db.lastInsertId}, nil
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.db.mutex.Lock()
+	defer c.db.mutex.Unlock()
+	rows := &fakeRows{columns: c.db.columns}
+	for _, pkey := range namedValues(args) {
+		if row, ok := c.db.rows[fmt.Sprint(pkey)]; ok {
+			rows.rows = append(rows.rows, row)
+		}
+	}
+	return rows, nil
+}
+
+//参数的值
+func namedValues(args []driver.NamedValue) []interface{} {
+	values := make([]interface{}, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	return values
+}
+
+type fakeResult struct {
+	rowsAffected int64
+	lastInsertId int64
+}
+
+func (r fakeResult) LastInsertId() (int64, error) { return r.lastInsertId, nil }
+func (r fakeResult) RowsAffected() (int64, error) { return r.rowsAffected, nil }
+
+type fakeRows struct {
+	columns []string
+	rows    [][]string
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	for i, value := range r.rows[0] {
+		dest[i] = value
+	}
+	r.rows = r.rows[1:]
+	return nil
+}
+
+//新建使用测试数据库的缓存表(实时更新,按age排序),注册到新的实例.
+func newFakeCache(t *testing.T) (*DBcache, *fakeDB) {
+	f := &fakeDB{
+		rowsAffected: 1,
+		columns:      []string{"id", "age", "price", "score", "name", "create_date"},
+		rows:         map[string][]string{},
+	}
+	fakeDBs.Store(t.Name(), f)
+	t.Cleanup(func() { fakeDBs.Delete(t.Name()) })
+	db, err := sql.Open("dbcache_fake", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	d := newTestCache("slice", "order by age asc")
+	d.DbConn = db
+	d.TableConfig.IsRealtime = true
+	d.incrementStep = 1
+	d.stopChan = make(chan struct{})
+	d.instance = NewInstance(db, "", "", "")
+	d.instance.register(d)
+	return d, f
+}
+
+//检查缓存中一行的列的值
+func checkRow(t *testing.T, d *DBcache, pkey string, want map[string]string) {
+	t.Helper()
+	row, err := d.GetRow(pkey)
+	if err != nil {
+		t.Errorf("GetRow(%s): %s", pkey, err)
+		return
+	}
+	for column, value := range want {
+		if row[column] != value {
+			t.Errorf("GetRow(%s)[%s] = %q, want %q", pkey, column, row[column], value)
+		}
+	}
+}
+
+//已缓存的行是更新;未缓存的行按受影响的行数判断插入或更新,从数据库重新读取后缓存(不按写入的列)
+func TestUpsert(t *testing.T) {
+	d, f := newFakeCache(t)
+	insertTestRow(d, map[string]string{"id": "1", "age": "10", "name": "a"})
+
+	//已缓存:更新缓存
+	n, isInsert, isKnown, err := d.Upsert("id=1,name=b")
+	if err != nil || n != 1 || isInsert || !isKnown {
+		t.Errorf("Upsert(cached) = %d, %t, %t, %v, want 1, false, true", n, isInsert, isKnown, err)
+	}
+	checkRow(t, d, "1", map[string]string{"name": "b", "age": "10"})
+	want := "INSERT INTO `test` (`id`,`name`) VALUES (?,?) ON DUPLICATE KEY UPDATE `name`=VALUES(`name`) [1 b]"
+	if execs := f.takeExecs(); len(execs) != 1 || execs[0] != want {
+		t.Errorf("Upsert(cached) executed %q, want %q", execs, want)
+	}
+
+	//未缓存,mysql受影响的行数1:插入.按数据库中的行缓存
+	f.rows["2"] = []string{"2", "20", "", "", "c", ""}
+	n, isInsert, isKnown, err = d.Upsert("id=2,name=c")
+	if err != nil || n != 1 || !isInsert || !isKnown {
+		t.Errorf("Upsert(insert) = %d, %t, %t, %v, want 1, true, true", n, isInsert, isKnown, err)
+	}
+	checkRow(t, d, "2", map[string]string{"id": "2", "age": "20", "name": "c"})
+	if got := testPkeys(d); got != "1,2" {
+		t.Errorf("page cache = %s, want 1,2", got)
+	}
+
+	//未缓存,受影响的行数2:更新.数据库中的行不符合where(读取不到),不缓存
+	f.setResult(2, 0)
+	n, isInsert, isKnown, err = d.Upsert("id=3,name=d")
+	if err != nil || n != 2 || isInsert || !isKnown {
+		t.Errorf("Upsert(update) = %d, %t, %t, %v, want 2, false, true", n, isInsert, isKnown, err)
+	}
+	if _, ok := d.DbCache.Load("3"); ok {
+		t.Errorf("Upsert(update) cached a row not read from the database")
+	}
+
+	//postgres不能按受影响的行数判断
+	d.dialect = dialect.PostgreSQL{}
+	f.setResult(1, 0)
+	f.rows["4"] = []string{"4", "5", "", "", "e", ""}
+	n, isInsert, isKnown, err = d.Upsert("id=4,name=e")
+	if err != nil || n != 1 || !isInsert || isKnown {
+		t.Errorf("postgres Upsert() = %d, %t, %t, %v, want 1, true, false", n, isInsert, isKnown, err)
+	}
+	checkRow(t, d, "4", map[string]string{"age": "5", "name": "e"})
+	if got := testPkeys(d); got != "4,1,2" {
+		t.Errorf("page cache = %s, want 4,1,2", got)
+	}
+	f.takeExecs()
+
+	//异步更新不等待结果:sql执行后(同一个同步协程中)再读取
+	d.dialect = dialect.Default
+	d.TableConfig.IsRealtime = false
+	d.dataAsync = newTestAsync(1, 10)
+	file, err := os.Create(filepath.Join(t.TempDir(), "async.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	d.dataAsync.AsyncFileObj = file
+	d.dataAsync.DataAsyncConf.MaxAsyncFileSize = 1
+	f.rows["5"] = []string{"5", "50", "", "", "f", ""}
+	n, isInsert, isKnown, err = d.Upsert("id=5,name=f")
+	if err != nil || n != 0 || !isInsert || isKnown {
+		t.Errorf("async Upsert() = %d, %t, %t, %v, want 0, true, false", n, isInsert, isKnown, err)
+	}
+	if _, ok := d.DbCache.Load("5"); ok {
+		t.Errorf("async Upsert() cached the row before the sql was executed")
+	}
+	w := d.dataAsync.workers[0]
+	for len(w.sqlChan) > 0 {
+		d.dataAsync.workerExec(d.DbConn, w, <-w.sqlChan)
+	}
+	want = "INSERT INTO `test` (`id`,`name`) VALUES (?,?) ON DUPLICATE KEY UPDATE `name`=VALUES(`name`) [5 f]"
+	if execs := f.takeExecs(); len(execs) != 1 || execs[0] != want {
+		t.Errorf("async Upsert() executed %q, want %q", execs, want)
+	}
+	checkRow(t, d, "5", map[string]string{"age": "50", "name": "f"})
+}
+
+//主键是自增列时,没有主键值的行按数据库返回的自增主键值缓存
+func TestInsertRowAutoIncrement(t *testing.T) {
+	d, f := newFakeCache(t)
+	d.TableConfig.PkeyAutoIncrement = true
+	f.setResult(1, 42)
+	n, lastInsertId, err := d.InsertRow("age=5,name=x")
+	if err != nil || n != 1 || lastInsertId != 42 {
+		t.Fatalf("InsertRow() = %d, %d, %v, want 1, 42", n, lastInsertId, err)
+	}
+	want := "INSERT INTO `test` (`age`,`name`) VALUES (?,?) [5 x]"
+	if execs := f.takeExecs(); len(execs) != 1 || execs[0] != want {
+		t.Errorf("InsertRow() executed %q, want %q", execs, want)
+	}
+	checkRow(t, d, "42", map[string]string{"id": "42", "age": "5", "name": "x"})
+
+	//有主键值时,按插入的主键值缓存
+	f.setResult(1, 43)
+	if _, _, err = d.InsertRow("id=7,age=1"); err != nil {
+		t.Fatalf("InsertRow(id=7): %s", err)
+	}
+	checkRow(t, d, "7", map[string]string{"id": "7", "age": "1"})
+	if _, ok := d.DbCache.Load("43"); ok {
+		t.Errorf("InsertRow(id=7) cached the row under LastInsertId()")
+	}
+	if got := testPkeys(d); got != "7,42" {
+		t.Errorf("page cache = %s, want 7,42", got)
+	}
+
+	//数据库没有返回自增主键值时,返回错误,不缓存
+	f.setResult(1, 0)
+	if _, _, err = d.InsertRow("age=9"); err == nil {
+		t.Errorf("InsertRow() without insert id want error")
+	}
+	if got := d.GetRowCount(); got != 2 {
+		t.Errorf("GetRowCount() = %d, want 2", got)
+	}
+}
+
+//!=按整个运算符分割,值中不保留=;where条件中的!=只匹配不相等的行
+func TestSplitConditionNotEqual(t *testing.T) {
+	d := newTestCache("slice", "order by age asc")
+	tests := []struct {
+		str      string
+		operator string
+		want     []string
+	}{
+		{"name != a", "!=", []string{"name", "!=", "a", "false"}},
+		{"name!=a", "!=", []string{"name", "!=", "a", "false"}},
+		{"name = a", "=", []string{"name", "=", "a", "false"}},
+	}
+	for _, test := range tests {
+		got, err := d.SplitCondition(test.str, test.operator)
+		if err != nil || !reflect.DeepEqual(got, test.want) {
+			t.Errorf("SplitCondition(%q, %q) = %q, %v, want %q", test.str, test.operator, got, err, test.want)
+		}
+	}
+	for i := 1; i <= 3; i++ {
+		insertTestRow(d, map[string]string{"id": fmt.Sprint(i), "age": fmt.Sprint(i), "name": fmt.Sprint("n", i)})
+	}
+	rows, total, err := d.GetWhereRowBetween("name!=n2", 0, 10)
+	if got := rowIds(rows); err != nil || got != "1,3" || total != 2 {
+		t.Errorf("GetWhereRowBetween(name!=n2) = %s, %d, %v, want 1,3, 2", got, total, err)
+	}
+}
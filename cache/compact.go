@@ -0,0 +1,167 @@
+package cache
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"sync/atomic"
+	"time"
+)
+
+//整理sliceNotDel切片:删除时只记录行号,插入的行追加到最后(未排序),整理时清除已删除的行,并把追加的行排序.
+//整理分三步,只有最后一步加写锁:
+//1.加读锁,取切片的长度和已删除的行号.sliceNotDel只追加,不修改已有的行,所以前面的行在整理期间不会改变.
+//2.不加锁,取出未删除的行并排序(最耗时的一步),期间查询和写操作都不受影响.
+//3.加写锁,合并整理期间的变化:过滤掉期间删除的行,期间追加的行排序后归并,再替换切片.
+
+//sliceNotDel切片的整理状态
+type CompactStat struct {
+	Rows         int           //切片中的行数(包括已删除的行)
+	DeletedRows  int           //已删除,未整理的行数
+	Unsorted     int           //追加到最后,未排序的行数(包括已删除的)
+	Compactions  int64         //整理次数
+	LastCompact  time.Time     //最近一次整理的时间,未整理过为零值
+	LastDuration time.Duration //最近一次整理的用时
+	LastRemoved  int64         //最近一次整理清除的已删除行数
+}
+
+//最近一次整理的信息,原子操作读写
+type compactStat struct {
+	compactions  int64
+	lastCompact  int64 //UnixNano
+	lastDuration int64
+	lastRemoved  int64
+}
+
+//立即整理sliceNotDel切片,清除已删除的行,并对追加的行排序.返回清除的已删除行数.
+//整理期间不阻塞查询和写操作,只在最后替换切片时加写锁.
+func (d *DBcache) Compact() (n int64, err error) {
+	if d.TableConfig.GetCacheType() != "sliceNotDel" {
+		err = fmt.Errorf("Compact(),缓存类型不是sliceNotDel,不需要整理: %s", d.TableConfig.GetCacheType())
+		return 0, err
+	}
+	return d.compact(), nil
+}
+
+//获取sliceNotDel切片的整理状态.
+func (d *DBcache) CompactStats() (stat CompactStat) {
+	d.RwMutex.RLock()
+	stat.Rows = len(d.SliceDbCache)
+	stat.DeletedRows = len(d.DelRowNum)
+	stat.Unsorted = len(d.SliceDbCache) - d.sortedRows
+	d.RwMutex.RUnlock()
+	stat.Compactions = atomic.LoadInt64(&d.compactStat.compactions)
+	if lastCompact := atomic.LoadInt64(&d.compactStat.lastCompact); lastCompact != 0 {
+		stat.LastCompact = time.Unix(0, lastCompact)
+	}
+	stat.LastDuration = time.Duration(atomic.LoadInt64(&d.compactStat.lastDuration))
+	stat.LastRemoved = atomic.LoadInt64(&d.compactStat.lastRemoved)
+	return stat
+}
+
+//整理切片,返回清除的已删除行数.
+func (d *DBcache) compact() (n int64) {
+	d.compactMutex.Lock()
+	defer d.compactMutex.Unlock()
+	begin := time.Now()
+
+	//1.取快照
+	d.RwMutex.RLock()
+	rows := d.SliceDbCache
+	size := len(rows)
+	deleted := make(map[int]bool, len(d.DelRowNum))
+	for i := range d.DelRowNum {
+		deleted[i] = true
+	}
+	d.RwMutex.RUnlock()
+
+	//2.排序未删除的行
+	sorted := make([]*SliceCache, 0, size-len(deleted))
+	for i := 0; i < size; i++ {
+		if !deleted[i] {
+			sorted = append(sorted, rows[i])
+		}
+	}
+	sorted = d.sortOrder.sortSlice(sorted)
+
+	//3.合并整理期间的变化,替换切片
+	d.RwMutex.Lock()
+	removed := make(map[*SliceCache]bool)
+	for i := range d.DelRowNum {
+		if i < size && !deleted[i] {
+			removed[d.SliceDbCache[i]] = true
+		}
+	}
+	if len(removed) > 0 {
+		result := sorted[:0]
+		for _, row := range sorted {
+			if !removed[row] {
+				result = append(result, row)
+			}
+		}
+		sorted = result
+	}
+	var appended []*SliceCache
+	for i := size; i < len(d.SliceDbCache); i++ {
+		if !d.DelRowNum[i] {
+			appended = append(appended, d.SliceDbCache[i])
+		}
+	}
+	if len(appended) > 0 {
+		sorted = d.sortOrder.mergeSlice(sorted, d.sortOrder.sortSlice(appended))
+	}
+	n = int64(len(d.DelRowNum))
+	d.SliceDbCache = sorted
+	d.DelRowNum = make(map[int]bool)
+	d.sortedRows = len(sorted)
+	d.RwMutex.Unlock()
+
+	atomic.AddInt64(&d.compactStat.compactions, 1)
+	atomic.StoreInt64(&d.compactStat.lastCompact, time.Now().UnixNano())
+	atomic.StoreInt64(&d.compactStat.lastDuration, int64(time.Since(begin)))
+	atomic.StoreInt64(&d.compactStat.lastRemoved, n)
+	return n
+}
+
+//是否需要整理:已删除的行和追加的未排序行,超过配置的行数或占切片的比例.
+func (d *DBcache) needCompact() bool {
+	d.RwMutex.RLock()
+	defer d.RwMutex.RUnlock()
+	dirty := len(d.DelRowNum) + len(d.SliceDbCache) - d.sortedRows
+	if dirty == 0 {
+		return false
+	}
+	config := d.liveConfig()
+	return dirty > config.GetCompactMaxDeleted() || float64(dirty) > float64(len(d.SliceDbCache))*config.GetCompactRatio()
+}
+
+//小时是否在允许后台整理的时间段内.
+func (d *DBcache) isCompactHour(hour int) bool {
+	config := d.liveConfig()
+	start, end := config.GetCompactHours()
+	if start <= end {
+		return hour >= start && hour <= end
+	}
+	//跨过0点,例如22-4
+	return hour >= start || hour <= end
+}
+
+//后台按配置的间隔检查,在允许的时间段内,需要整理时整理切片.
+func (d *DBcache) backCheckDelRowRecord() {
+	defer d.wg.Done()
+	ticker := time.NewTicker(d.TableConfig.GetCompactInterval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-d.stopChan:
+			return
+		}
+		if !d.isCompactHour(time.Now().Hour()) || !d.needCompact() {
+			continue
+		}
+		d.compact()
+		runtime.GC()
+		debug.FreeOSMemory()
+	}
+}
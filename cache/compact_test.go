@@ -0,0 +1,95 @@
+package cache
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+//整理sliceNotDel切片:清除已删除的行,追加的行排序
+func TestCompact(t *testing.T) {
+	d := newTestCache("sliceNotDel", "order by age asc")
+	rows := make([]*SliceCache, 0, 6)
+	for _, age := range []string{"50", "10", "30", "20", "60", "40"} {
+		rowMap := new(sync.Map)
+		rowMap.Store("id", age)
+		rowMap.Store("age", age)
+		d.DbCache.Store(age, rowMap)
+		rows = append(rows, &SliceCache{Pkey: age, SortValues: d.sortOrder.values(rowMap), RowMap: rowMap})
+	}
+	d.pageStore.Load(rows)
+	insertTestRow(d, map[string]string{"id": "25", "age": "25"})
+	insertTestRow(d, map[string]string{"id": "5", "age": "5"})
+	d.pageStore.Delete(map[string]bool{"30": true, "5": true})
+	if got := testPkeys(d); got != "10,20,40,50,60,25" {
+		t.Errorf("before Compact: got %s, want 10,20,40,50,60,25", got)
+	}
+	stat := d.CompactStats()
+	if stat.Rows != 8 || stat.DeletedRows != 2 || stat.Unsorted != 2 || stat.Compactions != 0 || !stat.LastCompact.IsZero() {
+		t.Errorf("CompactStats() before Compact = %+v", stat)
+	}
+	n, err := d.Compact()
+	if err != nil || n != 2 {
+		t.Errorf("Compact() = %d, %v, want 2", n, err)
+	}
+	if got := testPkeys(d); got != "10,20,25,40,50,60" {
+		t.Errorf("after Compact: got %s, want 10,20,25,40,50,60", got)
+	}
+	for i, pkey := range []string{"10", "20", "25", "40", "50", "60"} {
+		if got := d.GetRowNumRecord(pkey); got != i {
+			t.Errorf("GetRowNumRecord(%s) = %d, want %d", pkey, got, i)
+		}
+	}
+	stat = d.CompactStats()
+	if stat.Rows != 6 || stat.DeletedRows != 0 || stat.Unsorted != 0 || stat.Compactions != 1 || stat.LastRemoved != 2 || stat.LastCompact.IsZero() {
+		t.Errorf("CompactStats() after Compact = %+v", stat)
+	}
+	if _, err := newTestCache("slice", "").Compact(); err == nil {
+		t.Errorf("Compact() on slice cache: want error")
+	}
+}
+
+//整理期间插入和删除,整理后不丢失插入的行,删除的行不再出现
+func TestCompactConcurrent(t *testing.T) {
+	d := newTestCache("sliceNotDel", "order by age desc")
+	for i := 0; i < 2000; i++ {
+		pkey := fmt.Sprint(i)
+		insertTestRow(d, map[string]string{"id": pkey, "age": fmt.Sprint(i % 97)})
+	}
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			d.Compact()
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 2000; i < 3000; i++ {
+			pkey := fmt.Sprint(i)
+			insertTestRow(d, map[string]string{"id": pkey, "age": fmt.Sprint(i % 97)})
+			d.pageStore.Delete(map[string]bool{fmt.Sprint(i - 2000): true})
+		}
+	}()
+	wg.Wait()
+	d.Compact()
+	var last *SliceCache
+	count := 0
+	d.pageStore.Range(func(row *SliceCache) bool {
+		var id int
+		fmt.Sscan(row.Pkey, &id)
+		if id < 1000 {
+			t.Errorf("deleted row %s still in cache", row.Pkey)
+		}
+		if last != nil && !d.sortOrder.less(last, row) {
+			t.Errorf("rows out of order: %s before %s", last.Pkey, row.Pkey)
+		}
+		last = row
+		count++
+		return true
+	})
+	if count != 2000 || d.pageStore.Len() != 2000 {
+		t.Errorf("rows = %d, Len() = %d, want 2000", count, d.pageStore.Len())
+	}
+}
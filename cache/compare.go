@@ -0,0 +1,225 @@
+package cache
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+//按列在数据库中的类型比较缓存中的值.缓存中的值都保存为字符串,直接按字符串比较时,
+//"100"会排在"9"前面,所以排序时要根据列的类型(整型,浮点,日期,字符串)比较.
+
+//比较方式
+type compareKind int
+
+const (
+	compareBytes    compareKind = iota //按字节比较(二进制,未知类型)
+	compareText                        //字符串,按不区分大小写比较(与数据库默认的_ci排序规则一致)
+	compareDecimal                     //整型,定点数,按十进制精确比较
+	compareFloat                       //浮点数
+	compareDatetime                    //日期,日期时间,时间戳
+	compareTime                        //时间(时:分:秒,可以为负数或超过24小时)
+)
+
+//日期时间的格式
+var datetimeLayouts = []string{
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999 -0700 MST",
+	"2006-01-02",
+}
+
+//根据列在数据库中的类型,获取比较方式.
+func getCompareKind(databaseTypeName string) compareKind {
+	typeName := strings.ToUpper(databaseTypeName)
+	typeName = strings.TrimSpace(strings.TrimPrefix(typeName, "UNSIGNED"))
+	//INT2,INT4,INT8,FLOAT4,FLOAT8,TIMESTAMPTZ,TIMETZ,BPCHAR,JSONB是postgres的类型名
+	switch typeName {
+	case "TINYINT", "SMALLINT", "MEDIUMINT", "INT", "INTEGER", "BIGINT", "YEAR", "DECIMAL", "NUMERIC", "INT2", "INT4", "INT8":
+		return compareDecimal
+	case "FLOAT", "DOUBLE", "REAL", "FLOAT4", "FLOAT8":
+		return compareFloat
+	case "DATE", "DATETIME", "TIMESTAMP", "TIMESTAMPTZ":
+		return compareDatetime
+	case "TIME", "TIMETZ":
+		return compareTime
+	case "CHAR", "VARCHAR", "TINYTEXT", "TEXT", "MEDIUMTEXT", "LONGTEXT", "ENUM", "SET", "JSON", "BPCHAR", "JSONB":
+		return compareText
+	}
+	return compareBytes
+}
+
+//按比较方式比较二个值.a<b返回-1,相等返回0,a>b返回1.
+//值不能按类型解析时(如空值),排在能解析的值前面,二个都不能解析时按字节比较.
+func compareValue(kind compareKind, a, b string) int {
+	if a == b {
+		return 0
+	}
+	switch kind {
+	case compareText:
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	case compareDecimal:
+		x, okA := parseDecimal(a)
+		y, okB := parseDecimal(b)
+		if okA && okB {
+			return compareDecimalValue(x, y)
+		}
+		return compareInvalid(okA, okB, a, b)
+	case compareFloat:
+		x, errA := strconv.ParseFloat(strings.TrimSpace(a), 64)
+		y, errB := strconv.ParseFloat(strings.TrimSpace(b), 64)
+		if errA == nil && errB == nil {
+			return compareFloat64(x, y)
+		}
+		return compareInvalid(errA == nil, errB == nil, a, b)
+	case compareDatetime:
+		x, okA := parseDatetime(a)
+		y, okB := parseDatetime(b)
+		if okA && okB {
+			switch {
+			case x.Before(y):
+				return -1
+			case x.After(y):
+				return 1
+			}
+			return 0
+		}
+		return compareInvalid(okA, okB, a, b)
+	case compareTime:
+		x, okA := parseClock(a)
+		y, okB := parseClock(b)
+		if okA && okB {
+			return compareFloat64(x, y)
+		}
+		return compareInvalid(okA, okB, a, b)
+	}
+	return strings.Compare(a, b)
+}
+
+//有值不能按类型解析时的比较:不能解析的排在前面.
+func compareInvalid(okA, okB bool, a, b string) int {
+	switch {
+	case okA && !okB:
+		return 1
+	case !okA && okB:
+		return -1
+	}
+	return strings.Compare(a, b)
+}
+
+func compareFloat64(x, y float64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+//十进制数,整数部分去掉前导0,小数部分去掉末尾的0.
+type decimal struct {
+	negative bool
+	integer  string
+	fraction string
+}
+
+//解析十进制数字符串.例如:-012.340 -> {true 12 34}
+func parseDecimal(s string) (d decimal, ok bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return d, false
+	}
+	if s[0] == '-' || s[0] == '+' {
+		d.negative = s[0] == '-'
+		s = s[1:]
+	}
+	i := strings.Index(s, ".")
+	if i == -1 {
+		d.integer = s
+	} else {
+		d.integer, d.fraction = s[:i], s[i+1:]
+	}
+	if d.integer == "" && d.fraction == "" {
+		return d, false
+	}
+	for _, c := range d.integer + d.fraction {
+		if c < '0' || c > '9' {
+			return d, false
+		}
+	}
+	d.integer = strings.TrimLeft(d.integer, "0")
+	d.fraction = strings.TrimRight(d.fraction, "0")
+	//-0和0相等
+	if d.integer == "" && d.fraction == "" {
+		d.negative = false
+	}
+	return d, true
+}
+
+//比较二个十进制数
+func compareDecimalValue(x, y decimal) int {
+	if x.negative != y.negative {
+		if x.negative {
+			return -1
+		}
+		return 1
+	}
+	//先比较整数部分的长度,再比较整数部分,最后比较小数部分
+	c := len(x.integer) - len(y.integer)
+	if c == 0 {
+		c = strings.Compare(x.integer, y.integer)
+	}
+	if c == 0 {
+		c = strings.Compare(x.fraction, y.fraction)
+	}
+	switch {
+	case c < 0:
+		c = -1
+	case c > 0:
+		c = 1
+	}
+	if x.negative {
+		return -c
+	}
+	return c
+}
+
+//解析日期时间字符串
+func parseDatetime(s string) (t time.Time, ok bool) {
+	s = strings.TrimSpace(s)
+	for _, layout := range datetimeLayouts {
+		t, err := time.Parse(layout, s)
+		if err == nil {
+			return t, true
+		}
+	}
+	return t, false
+}
+
+//解析时间字符串,返回秒数.格式:[-]时:分:秒[.小数]
+func parseClock(s string) (seconds float64, ok bool) {
+	s = strings.TrimSpace(s)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	parts := strings.Split(s, ":")
+	if s == "" || len(parts) > 3 {
+		return 0, false
+	}
+	for _, part := range parts {
+		v, err := strconv.ParseFloat(part, 64)
+		if err != nil || v < 0 {
+			return 0, false
+		}
+		seconds = seconds*60 + v
+	}
+	//只有时:分时,补上秒.只有一个数时是秒数
+	if len(parts) == 2 {
+		seconds *= 60
+	}
+	if negative {
+		seconds = -seconds
+	}
+	return seconds, true
+}
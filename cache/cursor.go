@@ -0,0 +1,149 @@
+package cache
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+//游标分页(keyset).游标是不透明的字符串,由一行的所有排序列值和主键值编码而成.
+//行的先后顺序按(排序列值...,主键值)比较,与行在缓存中的位置无关,所以插入,删除行时,翻页不会重复或遗漏.
+
+//一行在分页中的位置:所有排序列值和主键值
+type pageKey struct {
+	sortValues []string
+	pkey       string
+}
+
+//把行的位置编码成游标.格式:排序列个数:(排序列值长度:排序列值)...主键值,再base64编码.
+func encodeCursor(key pageKey) string {
+	s := strconv.Itoa(len(key.sortValues)) + ":"
+	for _, value := range key.sortValues {
+		s += strconv.Itoa(len(value)) + ":" + value
+	}
+	s += key.pkey
+	return base64.RawURLEncoding.EncodeToString([]byte(s))
+}
+
+//把游标解码成行的位置.
+func decodeCursor(cursor string) (key pageKey, err error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return key, fmt.Errorf("decodeCursor(),游标格式错误: %s, err: %s", cursor, err)
+	}
+	s := string(b)
+	//取出一个"长度:"前缀
+	nextLen := func() (n int, ok bool) {
+		i := strings.Index(s, ":")
+		if i == -1 {
+			return 0, false
+		}
+		n, err := strconv.Atoi(s[:i])
+		if err != nil || n < 0 {
+			return 0, false
+		}
+		s = s[i+1:]
+		return n, true
+	}
+	count, ok := nextLen()
+	if !ok || count > len(s) {
+		return key, fmt.Errorf("decodeCursor(),游标格式错误: %s", cursor)
+	}
+	key.sortValues = make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		n, ok := nextLen()
+		if !ok || n > len(s) {
+			return key, fmt.Errorf("decodeCursor(),游标格式错误: %s", cursor)
+		}
+		key.sortValues = append(key.sortValues, s[:n])
+		s = s[n:]
+	}
+	key.pkey = s
+	return key, nil
+}
+
+//游标分页,获取游标之后的pageSize行.按表的默认视图排序.
+func (d *DBcache) GetPageAfter(cursor string, pageSize int) (result []map[string]string, next string, prev string, err error) {
+	return d.tableView().GetPageAfter(cursor, pageSize)
+}
+
+//游标分页,获取游标之前的pageSize行.按表的默认视图排序.
+func (d *DBcache) GetPageBefore(cursor string, pageSize int) (result []map[string]string, next string, prev string, err error) {
+	return d.tableView().GetPageBefore(cursor, pageSize)
+}
+
+//游标分页,获取游标之后的pageSize行.cursor为空时从第一行开始.
+//返回数据,下一页游标next(传给GetPageAfter),上一页游标prev(传给GetPageBefore).没有下一页或上一页时,游标为空.
+func (v *SortView) GetPageAfter(cursor string, pageSize int) (result []map[string]string, next string, prev string, err error) {
+	if pageSize <= 0 {
+		return nil, "", "", fmt.Errorf("GetPageAfter(),pageSize必须大于0")
+	}
+	key, err := cursorKey(cursor)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("GetPageAfter(), err: %s", err)
+	}
+	//游标之后最前面的pageSize+1行,多取一行用于判断是否有下一页.
+	rows, hasPrev := v.src.seekRows(key, true, pageSize+1)
+	hasNext := len(rows) > pageSize
+	if hasNext {
+		rows = rows[:pageSize]
+	}
+	return cursorPage(rows, hasNext, hasPrev, cursor, true)
+}
+
+//游标分页,获取游标之前的pageSize行(按正常顺序返回).cursor为空时获取最后一页.
+//返回数据,下一页游标next(传给GetPageAfter),上一页游标prev(传给GetPageBefore).没有下一页或上一页时,游标为空.
+func (v *SortView) GetPageBefore(cursor string, pageSize int) (result []map[string]string, next string, prev string, err error) {
+	if pageSize <= 0 {
+		return nil, "", "", fmt.Errorf("GetPageBefore(),pageSize必须大于0")
+	}
+	key, err := cursorKey(cursor)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("GetPageBefore(), err: %s", err)
+	}
+	//游标之前最后面的pageSize+1行,多取一行用于判断是否有上一页.
+	rows, hasNext := v.src.seekRows(key, false, pageSize+1)
+	hasPrev := len(rows) > pageSize
+	if hasPrev {
+		rows = rows[1:]
+	}
+	return cursorPage(rows, hasNext, hasPrev, cursor, false)
+}
+
+//把游标解码成用于比较位置的行.cursor为空时返回nil.
+func cursorKey(cursor string) (*SliceCache, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	key, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	return &SliceCache{Pkey: key.pkey, SortValues: key.sortValues}, nil
+}
+
+//生成游标分页的结果.当前页没有数据时,isAfter为true则上一页游标是cursor,否则下一页游标是cursor.
+func cursorPage(rows []*SliceCache, hasNext bool, hasPrev bool, cursor string, isAfter bool) (result []map[string]string, next string, prev string, err error) {
+	if len(rows) == 0 {
+		if cursor != "" && isAfter && hasPrev {
+			prev = cursor
+		}
+		if cursor != "" && !isAfter && hasNext {
+			next = cursor
+		}
+		return nil, next, prev, nil
+	}
+	result = make([]map[string]string, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, rowMapToMap(row.RowMap))
+	}
+	if hasNext {
+		last := rows[len(rows)-1]
+		next = encodeCursor(pageKey{last.SortValues, last.Pkey})
+	}
+	if hasPrev {
+		prev = encodeCursor(pageKey{rows[0].SortValues, rows[0].Pkey})
+	}
+	return result, next, prev, nil
+}
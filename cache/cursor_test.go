@@ -0,0 +1,111 @@
+package cache
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+//游标分页取一页,返回主键值(逗号隔开)及下一页,上一页游标
+func cursorIds(t *testing.T, v *SortView, isAfter bool, cursor string) (ids string, next string, prev string) {
+	var rows []map[string]string
+	var err error
+	if isAfter {
+		rows, next, prev, err = v.GetPageAfter(cursor, 3)
+	} else {
+		rows, next, prev, err = v.GetPageBefore(cursor, 3)
+	}
+	if err != nil {
+		t.Fatalf("view %q cursor page: %s", v.Name, err)
+	}
+	var pkeys []string
+	for _, row := range rows {
+		pkeys = append(pkeys, row["id"])
+	}
+	return strings.Join(pkeys, ","), next, prev
+}
+
+//游标分页往后,往前翻页,翻页之间插入,删除行:游标前面的变化不影响后面的页,不重复不遗漏.
+func TestCursorRoundTrip(t *testing.T) {
+	for _, cacheType := range []string{"slice", "sliceNotDel", "link", "tree"} {
+		d := newTestCache(cacheType, "order by age asc")
+		d.TableConfig.SortViews = "agev:age asc"
+		if err := d.initViews(); err != nil {
+			t.Fatalf("initViews(): %s", err)
+		}
+		d.initSortTypes()
+		//加载1-10,age为1,1,2,2,3,3,4,4,5,5.sliceNotDel加载的行是已排序部分,之后插入的行追加在后面.
+		var rows []*SliceCache
+		for i := 1; i <= 10; i++ {
+			rowMap := new(sync.Map)
+			rowMap.Store("id", fmt.Sprint(i))
+			rowMap.Store("age", fmt.Sprint((i+1)/2))
+			rows = append(rows, &SliceCache{Pkey: fmt.Sprint(i), SortValues: d.sortOrder.values(rowMap), RowMap: rowMap})
+		}
+		d.pageStore.Load(append([]*SliceCache(nil), rows...))
+		d.insertViews(rows)
+		insert := func(id int, age int) {
+			insertTestRow(d, map[string]string{"id": fmt.Sprint(id), "age": fmt.Sprint(age)})
+		}
+		remove := func(pkey string) {
+			d.pageStore.Delete(map[string]bool{pkey: true})
+			d.deleteViews(map[string]bool{pkey: true})
+		}
+		for _, name := range []string{"", "agev"} {
+			v, err := d.View(name)
+			if err != nil {
+				t.Fatalf("View(%q): %s", name, err)
+			}
+			check := func(step string, got string, next string, prev string, want string, hasNext bool, hasPrev bool) {
+				t.Helper()
+				if got != want || (next != "") != hasNext || (prev != "") != hasPrev {
+					t.Errorf("%s view %q %s = %s next %v prev %v, want %s next %v prev %v",
+						cacheType, name, step, got, next != "", prev != "", want, hasNext, hasPrev)
+				}
+			}
+			if name == "agev" {
+				//恢复到与默认视图翻页前一样的数据
+				remove("11")
+				remove("12")
+				remove("13")
+				insert(2, 1)
+				insert(5, 3)
+				insert(8, 4)
+			}
+			//往后翻页
+			got, next, prev := cursorIds(t, v, true, "")
+			check("after page 1", got, next, prev, "1,2,3", true, false)
+			insert(11, 1) //游标前面,不出现
+			insert(12, 3) //游标后面,排在6后面
+			remove("2")   //已取的行
+			remove("5")   //未取的行,不出现
+			got, next, prev = cursorIds(t, v, true, next)
+			check("after page 2", got, next, prev, "4,6,12", true, true)
+			got, next, prev = cursorIds(t, v, true, next)
+			check("after page 3", got, next, prev, "7,8,9", true, true)
+			got, next, prev = cursorIds(t, v, true, next)
+			check("after page 4", got, next, prev, "10", false, true)
+			//往前翻页
+			got, next, prev = cursorIds(t, v, false, prev)
+			check("before page 3", got, next, prev, "7,8,9", true, true)
+			remove("8")   //已取的行
+			insert(13, 2) //游标前面,排在4后面
+			got, next, prev = cursorIds(t, v, false, prev)
+			check("before page 2", got, next, prev, "13,6,12", true, true)
+			before := prev
+			got, next, prev = cursorIds(t, v, false, prev)
+			check("before page 1", got, next, prev, "11,3,4", true, true)
+			//下一页游标与往后翻页一致
+			prevCursor := prev
+			got, next, prev = cursorIds(t, v, true, next)
+			check("after page 1 next", got, next, prev, "13,6,12", true, true)
+			got, next, prev = cursorIds(t, v, false, prevCursor)
+			check("before page 0", got, next, prev, "1", true, false)
+			got, next, prev = cursorIds(t, v, false, "")
+			check("before last page", got, next, prev, "7,9,10", false, true)
+			got, next, prev = cursorIds(t, v, true, before)
+			check("after before cursor", got, next, prev, "6,12,7", true, true)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"database/sql"
+	"dbcache/conf"
+	"dbcache/dialect"
+	"fmt"
+	"strings"
+)
+
+//根据数据库中表的结构生成缓存表的配置(dbcache gen-config):列,主键,主键是否自增.表结构按数据库方言读取(见dialect包).
+//where,other,cache_type等由调用者设置.
+
+//读取数据库中表tableName的结构,生成缓存表的配置.exclude是不缓存的列(不区分大小写),不能排除主键.
+func GenCacheTable(db *sql.DB, tableName string, exclude []string) (table conf.CacheTable, err error) {
+	columns, err := dialect.Of(db).Columns(db, tableName)
+	if err != nil {
+		return table, fmt.Errorf("GenCacheTable(),查询数据库表结构失败, err: %s", err)
+	}
+	return genCacheTable(tableName, columns, exclude)
+}
+
+//按表的列生成缓存表的配置.缓存只支持一列的主键.
+func genCacheTable(tableName string, columns []dialect.Column, exclude []string) (table conf.CacheTable, err error) {
+	if len(columns) == 0 {
+		return table, fmt.Errorf("genCacheTable(),数据库中不存在表[%s]", tableName)
+	}
+	excluded := make(map[string]bool, len(exclude))
+	for _, column := range exclude {
+		column = strings.TrimSpace(column)
+		if column != "" {
+			excluded[strings.ToLower(column)] = true
+		}
+	}
+	var pkeys []dialect.Column
+	names := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if column.IsPkey {
+			pkeys = append(pkeys, column)
+		}
+		if excluded[strings.ToLower(column.Name)] {
+			delete(excluded, strings.ToLower(column.Name))
+			if column.IsPkey {
+				return table, fmt.Errorf("genCacheTable(),表[%s]的主键[%s]不能排除", tableName, column.Name)
+			}
+			continue
+		}
+		names = append(names, column.Name)
+	}
+	for column := range excluded {
+		return table, fmt.Errorf("genCacheTable(),表[%s]中不存在排除的列[%s]", tableName, column)
+	}
+	switch len(pkeys) {
+	case 0:
+		return table, fmt.Errorf("genCacheTable(),表[%s]没有主键", tableName)
+	case 1:
+	default:
+		return table, fmt.Errorf("genCacheTable(),表[%s]的主键有%d列,缓存只支持一列的主键", tableName, len(pkeys))
+	}
+	table.TableName = tableName
+	table.Columns = strings.Join(names, ",")
+	table.Pkey = pkeys[0].Name
+	table.PkeyAutoIncrement = pkeys[0].AutoIncrement
+	return table, nil
+}
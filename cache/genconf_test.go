@@ -0,0 +1,36 @@
+package cache
+
+import (
+	"dbcache/dialect"
+	"testing"
+)
+
+func TestGenCacheTable(t *testing.T) {
+	columns := []dialect.Column{
+		{Name: "goods_id", IsPkey: true, AutoIncrement: true},
+		{Name: "goods_name"},
+		{Name: "price"},
+		{Name: "description"},
+	}
+	table, err := genCacheTable("goods", columns, []string{"Description", " "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.TableName != "goods" || table.Columns != "goods_id,goods_name,price" || table.Pkey != "goods_id" || !table.PkeyAutoIncrement {
+		t.Errorf("genCacheTable() = %+v", table)
+	}
+	for _, c := range []struct {
+		columns []dialect.Column
+		exclude []string
+	}{
+		{nil, nil},                      //表不存在
+		{columns, []string{"goods_id"}}, //排除主键
+		{columns, []string{"qty"}},      //排除的列不存在
+		{columns[1:], nil},              //没有主键
+		{append(columns, dialect.Column{Name: "type_id", IsPkey: true}), nil}, //多列主键
+	} {
+		if _, err := genCacheTable("goods", c.columns, c.exclude); err == nil {
+			t.Errorf("genCacheTable(%v, %v) want error", c.columns, c.exclude)
+		}
+	}
+}
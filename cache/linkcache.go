@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"sort"
 	"strings"
 	"sync"
 )
@@ -109,86 +110,6 @@ func (l *LinkCache) InsertTailFromHead(node *Node) {
 
 }
 
-//插入一个节点,根据按sortColumn升序排列的数据.
-func (l *LinkCache) InsertNodeAsc(node *Node) bool {
-	l.mutex.Lock()
-	defer l.mutex.Unlock()
-	t := l.head
-	//循环查找与小于sortColumn的节点,只到下一节点大于则退出查找
-	for t.next != nil && t.next.sortColumn < node.sortColumn {
-		t = t.next
-	}
-	//如果查找到
-	if t.next != nil && t.next.sortColumn > node.sortColumn {
-		if t.next != nil {
-			t.next.pre = node
-		}
-
-		node.next = t.next
-		node.pre = t
-		t.next = node
-
-		l.length++
-		return true
-	} else {
-		if t.next == nil { //当找到最后一个节点时
-			t.next = node
-			node.pre = t
-
-			l.length++
-			return true
-		}else{ //当比所有节点都小的时候,要插在最前面.
-			t.next.pre = node
-			node.next = t.next
-			node.pre = t
-			t.next = node
-
-			l.length++
-			return true
-		}
-	}
-	return false
-}
-//插入一个节点,根据按sortColumn降序排列的数据.
-func (l *LinkCache) InsertNodeDesc(node *Node) bool {
-	l.mutex.Lock()
-	defer l.mutex.Unlock()
-	t := l.head
-	//循环查找与大于sortColumn的节点,只到下一节点小于则退出查找
-	for t.next != nil && t.next.sortColumn > node.sortColumn {
-		t = t.next
-	}
-	//如果查找到
-	if t.next != nil && t.next.sortColumn < node.sortColumn {
-		if t.next != nil {
-			t.next.pre = node
-		}
-
-		node.next = t.next
-		node.pre = t
-		t.next = node
-
-		l.length++
-		return true
-	} else {
-		if t.next == nil {//当找到最后一个节点时
-			t.next = node
-			node.pre = t
-
-			l.length++
-			return true
-		}else{//当比所有节点都大的时候,要插在最前面.
-			t.next.pre = node
-			node.next = t.next
-			node.pre = t
-			t.next = node
-
-			l.length++
-			return true
-		}
-	}
-	return false
-}
 //删除一个节点,根据主键.
 func (l *LinkCache) DeleteNodePkey(Pkey string) bool {
 	l.mutex.Lock()
@@ -252,6 +173,21 @@ func (l *LinkCache) GetNodeBetween(start int64, end int64) []*Node {
 	return nil
 }
 
+//在一次加锁中取链表长度,及按bounds(长度)计算的开始到结束(不包括结束)的节点.
+func (l *LinkCache) GetNodePage(bounds func(length int64) (start int64, end int64)) (nodes []*Node, length int64) {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	start, end := bounds(l.length)
+	var index int64
+	for t := l.head.next; t != nil && index < end; t = t.next {
+		if index >= start {
+			nodes = append(nodes, t)
+		}
+		index++
+	}
+	return nodes, l.length
+}
+
 //得到链表中所有节点
 func (l *LinkCache) GetAllNode() []*Node {
 	l.mutex.RLock()
@@ -458,3 +394,124 @@ func (l *LinkCache) FindString(Pkey string) (out string) {
 	}
 	return out
 }
+
+//插入一个节点,按less排序,less(a,b)为true时a排在b前面.
+func (l *LinkCache) InsertNodeOrder(node *Node, less func(a, b *Node) bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	t := l.head
+	//循环查找排在node前面的节点,直到下一节点排在node后面则退出查找
+	for t.next != nil && less(t.next, node) {
+		t = t.next
+	}
+	node.next = t.next
+	node.pre = t
+	if t.next != nil {
+		t.next.pre = node
+	} else {
+		l.tail = node
+	}
+	t.next = node
+	l.length++
+}
+
+//批量插入节点,按less排序,只加一次锁.
+func (l *LinkCache) InsertNodesOrder(nodes []*Node, less func(a, b *Node) bool) {
+	if len(nodes) == 0 {
+		return
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	//先把要插入的节点排序,再从头到尾遍历一次,依次插入.
+	sort.Slice(nodes, func(i, j int) bool { return less(nodes[i], nodes[j]) })
+	t := l.head
+	for _, node := range nodes {
+		for t.next != nil && less(t.next, node) {
+			t = t.next
+		}
+		node.next = t.next
+		node.pre = t
+		if t.next != nil {
+			t.next.pre = node
+		}
+		t.next = node
+		t = node
+		l.length++
+	}
+	//重新确定尾节点
+	for t.next != nil {
+		t = t.next
+	}
+	l.tail = t
+}
+
+//根据主键更新节点,并按less移到新的位置,只加一次锁.update修改节点的排序值,返回false时不移动.
+func (l *LinkCache) UpdateNodeOrder(Pkey string, update func(node *Node) bool, less func(a, b *Node) bool) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	t := l.head
+	//循环查找与Pkey相等的节点
+	for t.next != nil && t.next.pkey != Pkey {
+		t = t.next
+	}
+	node := t.next
+	if node == nil || !update(node) {
+		return false
+	}
+	//从链表中取下该节点
+	t.next = node.next
+	if node.next != nil {
+		node.next.pre = t
+	} else {
+		l.tail = t
+	}
+	//从头查找新的位置
+	t = l.head
+	for t.next != nil && less(t.next, node) {
+		t = t.next
+	}
+	node.next = t.next
+	node.pre = t
+	if t.next != nil {
+		t.next.pre = node
+	} else {
+		l.tail = node
+	}
+	t.next = node
+	return true
+}
+
+//批量删除节点,根据主键.只加一次锁,遍历一次链表.返回删除的节点数.
+func (l *LinkCache) DeleteNodesPkey(pkeys map[string]bool) (n int64) {
+	if len(pkeys) == 0 {
+		return 0
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	t := l.head
+	for t.next != nil {
+		if pkeys[t.next.pkey] {
+			if t.next.next != nil {
+				t.next.next.pre = t
+			}
+			t.next = t.next.next
+			l.length--
+			n++
+			continue
+		}
+		t = t.next
+	}
+	l.tail = t
+	return n
+}
+
+//从头到尾遍历链表中所有节点,f返回false时停止遍历.
+func (l *LinkCache) RangeNode(f func(node *Node) bool) {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	for t := l.head.next; t != nil; t = t.next {
+		if !f(t) {
+			return
+		}
+	}
+}
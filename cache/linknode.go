@@ -7,6 +7,7 @@ type Node struct {
 	rowNum int64
 	pkey   string
 	sortColumn string
+	sortValues []string //所有排序列的值
 	row    *sync.Map
 	pre    *Node
 	next   *Node
@@ -0,0 +1,201 @@
+package cache
+
+import (
+	"fmt"
+	"math"
+	"sync"
+)
+
+//按where条件分页.与GetRowBetween()等分页函数一样,按排序视图的顺序返回数据,
+//只是先过滤掉不符合条件的行.默认视图未配置分页缓存时,按DbCache顺序(无序)过滤.
+
+//分页查询的结果.数据,总行数和总页数在同一次加锁中取得,是一致的.
+type PageResult struct {
+	Rows      []map[string]string //当前页的数据
+	Total     int                 //总行数(有where条件时是符合条件的总行数)
+	PageCount int                 //总页数
+	Page      int                 //当前页码(小于1时为第1页,大于总页数时为最后一页)
+	PageSize  int                 //每页行数
+	HasNext   bool                //是否有下一页
+	HasPrev   bool                //是否有上一页
+}
+
+//根据总行数和请求的页码,计算总页数,当前页码,是否有上一页和下一页.
+func (r *PageResult) setPage(total int, page int) {
+	r.Total = total
+	r.PageCount = whereCountPage(total, r.PageSize)
+	if page > r.PageCount {
+		page = r.PageCount
+	}
+	if page < 1 {
+		page = 1
+	}
+	r.Page = page
+	r.HasPrev = page > 1
+	r.HasNext = page < r.PageCount
+}
+
+//获取一页数据及总行数,总页数等分页信息.按表的默认视图排序.
+func (d *DBcache) GetPage(page int, pageSize int) (result *PageResult, err error) {
+	return d.tableView().GetPage(page, pageSize)
+}
+
+//根据where条件,获取一页数据及符合条件的总行数,总页数等分页信息.按表的默认视图排序.
+func (d *DBcache) GetWherePage(where string, page int, pageSize int) (result *PageResult, err error) {
+	return d.tableView().GetWherePage(where, page, pageSize)
+}
+
+//获取一页数据及总行数,总页数等分页信息.总行数和数据在同一次加锁中取得,并发写时也不会不一致.
+//page参数是页码,pageSize参数是每页行数大小
+func (v *SortView) GetPage(page int, pageSize int) (result *PageResult, err error) {
+	if pageSize <= 0 {
+		return nil, fmt.Errorf("GetPage(),pageSize必须大于0")
+	}
+	result = &PageResult{PageSize: pageSize}
+	result.Rows, _ = v.src.page(func(total int) (start int, end int) {
+		result.setPage(total, page)
+		start = (result.Page - 1) * pageSize
+		return start, start + pageSize
+	})
+	return result, nil
+}
+
+//根据where条件,获取一页数据及符合条件的总行数,总页数等分页信息.
+func (v *SortView) GetWherePage(where string, page int, pageSize int) (result *PageResult, err error) {
+	if pageSize <= 0 {
+		return nil, fmt.Errorf("GetWherePage(),pageSize必须大于0")
+	}
+	rows, err := v.getWhereRowMaps(where)
+	if err != nil {
+		return nil, fmt.Errorf("GetWherePage(), err: %s", err)
+	}
+	result = &PageResult{PageSize: pageSize}
+	result.setPage(len(rows), page)
+	start := (result.Page - 1) * pageSize
+	result.Rows = rowMapsBetween(rows, start, start+pageSize)
+	return result, nil
+}
+
+//根据where条件,获取符合条件的行,从开始行到结束行(不包括结束行).按表的默认视图排序.
+func (d *DBcache) GetWhereRowBetween(where string, start int, end int) (result []map[string]string, total int, err error) {
+	return d.tableView().GetWhereRowBetween(where, start, end)
+}
+
+//根据where条件,获取符合条件的总页数及总行数.
+func (d *DBcache) GetWherePageCount(where string, pageSize int) (pageCount int, total int, err error) {
+	return d.tableView().GetWherePageCount(where, pageSize)
+}
+
+//根据where条件分页,从开始页,获取多少页.按表的默认视图排序.
+func (d *DBcache) GetWhereMultipageRows(where string, startPage int, pageNum int, pageSize int) (result []map[string]string, total int, pageCount int, err error) {
+	return d.tableView().GetWhereMultipageRows(where, startPage, pageNum, pageSize)
+}
+
+//根据where条件分页,获取一页数据.按表的默认视图排序.
+func (d *DBcache) GetWhereOnePageRows(where string, page int, pageSize int) (result []map[string]string, total int, pageCount int, err error) {
+	return d.tableView().GetWhereOnePageRows(where, page, pageSize)
+}
+
+//根据where条件,获取符合条件的行,从开始行到结束行(不包括结束行).total是符合条件的总行数.
+func (v *SortView) GetWhereRowBetween(where string, start int, end int) (result []map[string]string, total int, err error) {
+	rows, err := v.getWhereRowMaps(where)
+	if err != nil {
+		return nil, 0, fmt.Errorf("GetWhereRowBetween(), err: %s", err)
+	}
+	return rowMapsBetween(rows, start, end), len(rows), nil
+}
+
+//根据where条件,获取符合条件的总页数及总行数.pageSize参数是每页行数大小
+func (v *SortView) GetWherePageCount(where string, pageSize int) (pageCount int, total int, err error) {
+	if pageSize <= 0 {
+		return 0, 0, fmt.Errorf("GetWherePageCount(),pageSize必须大于0")
+	}
+	rows, err := v.getWhereRowMaps(where)
+	if err != nil {
+		return 0, 0, fmt.Errorf("GetWherePageCount(), err: %s", err)
+	}
+	return whereCountPage(len(rows), pageSize), len(rows), nil
+}
+
+//根据where条件分页,从开始页,获取多少页.返回数据,符合条件的总行数及总页数.
+//参数说明:startPage,开始页,pageNum多少页,pageSize参数是每页行数大小
+func (v *SortView) GetWhereMultipageRows(where string, startPage int, pageNum int, pageSize int) (result []map[string]string, total int, pageCount int, err error) {
+	if pageSize <= 0 || pageNum <= 0 {
+		return nil, 0, 0, fmt.Errorf("GetWhereMultipageRows(),pageNum,pageSize必须大于0")
+	}
+	rows, err := v.getWhereRowMaps(where)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("GetWhereMultipageRows(), err: %s", err)
+	}
+	total = len(rows)
+	pageCount = whereCountPage(total, pageSize)
+	if startPage <= 0 {
+		startPage = 1
+	}
+	if startPage > pageCount {
+		startPage = pageCount
+	}
+	start := (startPage - 1) * pageSize
+	result = rowMapsBetween(rows, start, start+pageNum*pageSize)
+	return result, total, pageCount, nil
+}
+
+//根据where条件分页,获取一页数据.返回数据,符合条件的总行数及总页数.page参数是页码,pageSize参数是每页行数大小
+func (v *SortView) GetWhereOnePageRows(where string, page int, pageSize int) (result []map[string]string, total int, pageCount int, err error) {
+	result, total, pageCount, err = v.GetWhereMultipageRows(where, page, 1, pageSize)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("GetWhereOnePageRows(), err: %s", err)
+	}
+	return result, total, pageCount, nil
+}
+
+//按视图的排序顺序,获取符合where条件的所有行.
+func (v *SortView) getWhereRowMaps(where string) (rows []*sync.Map, err error) {
+	isAnd, isOr, whereCondition, err := v.d.parseWhere(where)
+	if err != nil {
+		return nil, err
+	}
+	v.src.rangeRows(func(key pageKey, rowMap *sync.Map) bool {
+		if v.d.isWhereMatch(isAnd, isOr, whereCondition, rowMap) {
+			rows = append(rows, rowMap)
+		}
+		return true
+	})
+	return rows, nil
+}
+
+//按分页缓存的排序顺序遍历每一行,f返回false时停止遍历.key是该行的所有排序列值和主键值
+func (d *DBcache) rangeSortRows(f func(key pageKey, rowMap *sync.Map) bool) {
+	d.pageStore.Range(func(row *SliceCache) bool {
+		return f(pageKey{row.SortValues, row.Pkey}, row.RowMap)
+	})
+}
+
+//获取rows中开始行到结束行(不包括结束行)的数据.
+func rowMapsBetween(rows []*sync.Map, start int, end int) (result []map[string]string) {
+	if start < 0 {
+		start = 0
+	}
+	if end > len(rows) {
+		end = len(rows)
+	}
+	for i := start; i < end; i++ {
+		result = append(result, rowMapToMap(rows[i]))
+	}
+	return result
+}
+
+//把一行缓存数据(sync.Map)转换为map
+func rowMapToMap(rowMap *sync.Map) (row map[string]string) {
+	row = make(map[string]string)
+	rowMap.Range(func(column, value interface{}) bool {
+		row[column.(string)] = value.(string)
+		return true
+	})
+	return row
+}
+
+//根据总行数和每页行数,计算总页数.
+func whereCountPage(total int, pageSize int) int {
+	return int(math.Ceil(float64(total) / float64(pageSize)))
+}
@@ -0,0 +1,136 @@
+package cache
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+//GetPage返回的数据和分页信息
+func TestGetPage(t *testing.T) {
+	tests := []struct {
+		page     int
+		wantPage int
+		wantRows string
+		hasPrev  bool
+		hasNext  bool
+	}{
+		{1, 1, "1,2,3,4", false, true},
+		{2, 2, "5,6,7,8", true, true},
+		{3, 3, "9", true, false},
+		{0, 1, "1,2,3,4", false, true},
+		{100, 3, "9", true, false},
+	}
+	for _, cacheType := range []string{"slice", "sliceNotDel", "link", "tree"} {
+		d := newTestCache(cacheType, "order by age asc")
+		for i := 1; i <= 10; i++ {
+			insertTestRow(d, map[string]string{"id": fmt.Sprint(i), "age": fmt.Sprint(i)})
+		}
+		d.pageStore.Delete(map[string]bool{"10": true})
+		for _, test := range tests {
+			result, err := d.GetPage(test.page, 4)
+			if err != nil {
+				t.Fatalf("%s GetPage(%d): %s", cacheType, test.page, err)
+			}
+			var ids []string
+			for _, row := range result.Rows {
+				ids = append(ids, row["id"])
+			}
+			got := strings.Join(ids, ",")
+			if got != test.wantRows || result.Total != 9 || result.PageCount != 3 || result.Page != test.wantPage ||
+				result.HasPrev != test.hasPrev || result.HasNext != test.hasNext {
+				t.Errorf("%s GetPage(%d) = rows %s %+v, want rows %s page %d", cacheType, test.page, got, *result, test.wantRows, test.wantPage)
+			}
+		}
+		result, err := d.GetWherePage("age=6 or age=7 or age=8 or age=9", 2, 2)
+		if err != nil || result.Total != 4 || result.PageCount != 2 || len(result.Rows) != 2 || result.Rows[0]["id"] != "8" || result.HasNext {
+			t.Errorf("%s GetWherePage(age=6..9, 2, 2) = %+v, %v", cacheType, result, err)
+		}
+	}
+	d := newTestCache("slice", "")
+	result, err := d.GetPage(3, 10)
+	if err != nil || result.Total != 0 || result.PageCount != 0 || result.Page != 1 || result.HasPrev || result.HasNext {
+		t.Errorf("empty GetPage(3, 10) = %+v, %v", result, err)
+	}
+	if _, err := d.GetPage(1, 0); err == nil {
+		t.Errorf("GetPage(1, 0): want error")
+	}
+}
+
+//取得行的主键值,以逗号分隔
+func rowIds(rows []map[string]string) string {
+	var ids []string
+	for _, row := range rows {
+		ids = append(ids, row["id"])
+	}
+	return strings.Join(ids, ",")
+}
+
+//按where条件分页:按排序顺序返回符合条件的行,总行数及总页数,开始页超出范围时取边界页
+func TestGetWherePaging(t *testing.T) {
+	for _, cacheType := range []string{"slice", "sliceNotDel", "link", "tree"} {
+		d := newTestCache(cacheType, "order by age desc")
+		//按排序顺序插入(sliceNotDel插入的行追加在最后)
+		for i := 10; i >= 1; i-- {
+			name := "a"
+			if i%2 == 0 {
+				name = "b"
+			}
+			insertTestRow(d, map[string]string{"id": fmt.Sprint(i), "age": fmt.Sprint(i), "name": name})
+		}
+		//符合条件的行:9,7,5,3,1
+		rows, total, err := d.GetWhereRowBetween("name=a", 1, 3)
+		if got := rowIds(rows); err != nil || got != "7,5" || total != 5 {
+			t.Errorf("%s GetWhereRowBetween(1, 3) = %s, %d, %v, want 7,5, 5", cacheType, got, total, err)
+		}
+		rows, total, err = d.GetWhereRowBetween("name=a", -1, 100)
+		if got := rowIds(rows); err != nil || got != "9,7,5,3,1" || total != 5 {
+			t.Errorf("%s GetWhereRowBetween(-1, 100) = %s, %d, %v, want 9,7,5,3,1, 5", cacheType, got, total, err)
+		}
+
+		pageCount, total, err := d.GetWherePageCount("name=a", 2)
+		if err != nil || pageCount != 3 || total != 5 {
+			t.Errorf("%s GetWherePageCount(2) = %d, %d, %v, want 3, 5", cacheType, pageCount, total, err)
+		}
+		pageCount, total, err = d.GetWherePageCount("name=z", 2)
+		if err != nil || pageCount != 0 || total != 0 {
+			t.Errorf("%s GetWherePageCount(name=z) = %d, %d, %v, want 0, 0", cacheType, pageCount, total, err)
+		}
+
+		tests := []struct {
+			startPage int
+			pageNum   int
+			want      string
+		}{
+			{1, 1, "9,7"},
+			{2, 2, "5,3,1"},
+			{0, 1, "9,7"},
+			{10, 2, "1"},
+		}
+		for _, test := range tests {
+			rows, total, pageCount, err := d.GetWhereMultipageRows("name=a", test.startPage, test.pageNum, 2)
+			if got := rowIds(rows); err != nil || got != test.want || total != 5 || pageCount != 3 {
+				t.Errorf("%s GetWhereMultipageRows(%d, %d, 2) = %s, %d, %d, %v, want %s, 5, 3",
+					cacheType, test.startPage, test.pageNum, got, total, pageCount, err, test.want)
+			}
+		}
+		rows, total, pageCount, err = d.GetWhereOnePageRows("age=4 or age=8", 2, 1)
+		if got := rowIds(rows); err != nil || got != "4" || total != 2 || pageCount != 2 {
+			t.Errorf("%s GetWhereOnePageRows(2, 1) = %s, %d, %d, %v, want 4, 2, 2", cacheType, got, total, pageCount, err)
+		}
+		rows, total, pageCount, err = d.GetWhereOnePageRows("name=z", 1, 2)
+		if err != nil || len(rows) != 0 || total != 0 || pageCount != 0 {
+			t.Errorf("%s GetWhereOnePageRows(name=z) = %v, %d, %d, %v, want no rows", cacheType, rows, total, pageCount, err)
+		}
+	}
+	d := newTestCache("slice", "")
+	if _, _, err := d.GetWherePageCount("name=a", 0); err == nil {
+		t.Errorf("GetWherePageCount(pageSize 0): want error")
+	}
+	if _, _, _, err := d.GetWhereMultipageRows("name=a", 1, 0, 2); err == nil {
+		t.Errorf("GetWhereMultipageRows(pageNum 0): want error")
+	}
+	if _, _, err := d.GetWhereRowBetween("", 0, 10); err == nil {
+		t.Errorf("GetWhereRowBetween(empty where): want error")
+	}
+}
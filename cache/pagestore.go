@@ -0,0 +1,516 @@
+package cache
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+//用于分页查询的缓存.四种缓存类型(slice,sliceNotDel,link,tree)都实现PageStore接口,
+//DBcache根据cache_type选择一种,插入,删除,分页查询都通过该接口,不再按缓存类型判断.
+//行都用SliceCache表示,行中的排序列值(SortValues)由DBcache取出,按DBcache的排序方式排序.
+
+//分页缓存
+type PageStore interface {
+	Load(rows []*SliceCache)                  //加载数据,rows是全部的行(未排序)
+	Insert(rows []*SliceCache) (n int64)      //按排序插入多行,返回增加的行数
+	Delete(pkeys map[string]bool) (n int64)   //根据主键删除多行,返回删除的行数
+	Len() int64                               //总行数
+	Between(start int, end int) []*SliceCache //按排序获取开始行到结束行(不包括结束行)的行
+	//在一次加锁中取总行数,及按bounds(总行数)计算的开始行到结束行(不包括结束行)的行,总行数与行是一致的.
+	Page(bounds func(total int) (start int, end int)) (rows []*SliceCache, total int)
+	Rank(pkey string) int                     //获取行的排序位置(从0开始),不存在返回-1
+	Range(f func(row *SliceCache) bool)       //按排序遍历每一行,f返回false时停止遍历
+	//游标分页:isAfter为true时取key之后最前面的limit行,否则取key之前最后面的limit行(都按正常顺序).
+	//key为nil时从第一行或最后一行开始.hasOther表示另一侧(包括key)是否还有行.
+	Seek(key *SliceCache, isAfter bool, limit int) (rows []*SliceCache, hasOther bool)
+	//更新排序列值:在一次加锁中,用update(原行)返回的新行替换原行,并移到新的排序位置.
+	//update返回nil时不做改变.返回是否移动了该行.
+	Update(pkey string, update func(old *SliceCache) *SliceCache) bool
+}
+
+//根据缓存类型新建分页缓存.
+func (d *DBcache) newPageStore(cacheType string) PageStore {
+	switch cacheType {
+	case "slice": //数据保存于切片
+		return slicePage{d}
+	case "sliceNotDel": //数据保存于切片,但删除记录未真的删除,只是记录.
+		return sliceNotDelPage{d}
+	case "link": //数据保存于链表
+		return linkPage{d}
+	case "tree": //数据保存于顺序统计树
+		d.TreeDbCache = NewTreeCache(d.sortOrder.less)
+		return d.TreeDbCache
+	}
+	return nonePage{d}
+}
+
+//slice切片:数据保存于DBcache.SliceDbCache,有序.插入删除需移动切片,O(n).
+type slicePage struct {
+	d *DBcache
+}
+
+func (p slicePage) Load(rows []*SliceCache) {
+	p.d.RwMutex.Lock()
+	defer p.d.RwMutex.Unlock()
+	p.d.SliceDbCache = p.d.sortOrder.sortSlice(rows)
+}
+
+func (p slicePage) Insert(rows []*SliceCache) (n int64) {
+	d := p.d
+	d.RwMutex.Lock()
+	defer d.RwMutex.Unlock()
+	//只有一行时,二分查找插入位置.多行时,先对插入的行排序,再与原切片归并.
+	if len(rows) == 1 {
+		d.SliceDbCache = d.sortOrder.insertSlice(d.SliceDbCache, rows[0])
+		return 1
+	}
+	sorted := d.sortOrder.sortSlice(append([]*SliceCache(nil), rows...))
+	d.SliceDbCache = d.sortOrder.mergeSlice(d.SliceDbCache, sorted)
+	return int64(len(rows))
+}
+
+func (p slicePage) Delete(pkeys map[string]bool) (n int64) {
+	d := p.d
+	d.RwMutex.Lock()
+	defer d.RwMutex.Unlock()
+	//过滤掉删除的行.
+	result := d.SliceDbCache[:0]
+	for _, sliceData := range d.SliceDbCache {
+		if pkeys[sliceData.Pkey] {
+			n++
+			continue
+		}
+		result = append(result, sliceData)
+	}
+	//清除尾部引用,便于回收.
+	for i := len(result); i < len(d.SliceDbCache); i++ {
+		d.SliceDbCache[i] = nil
+	}
+	d.SliceDbCache = result
+	return n
+}
+
+func (p slicePage) Update(pkey string, update func(old *SliceCache) *SliceCache) bool {
+	d := p.d
+	d.RwMutex.Lock()
+	defer d.RwMutex.Unlock()
+	for i, sliceData := range d.SliceDbCache {
+		if sliceData.Pkey != pkey {
+			continue
+		}
+		row := update(sliceData)
+		if row == nil {
+			return false
+		}
+		//删除原行,再按新的排序列值二分查找插入位置.
+		copy(d.SliceDbCache[i:], d.SliceDbCache[i+1:])
+		d.SliceDbCache[len(d.SliceDbCache)-1] = nil
+		d.SliceDbCache = d.sortOrder.insertSlice(d.SliceDbCache[:len(d.SliceDbCache)-1], row)
+		return true
+	}
+	return false
+}
+
+func (p slicePage) Len() int64 {
+	p.d.RwMutex.RLock()
+	defer p.d.RwMutex.RUnlock()
+	return int64(len(p.d.SliceDbCache))
+}
+
+func (p slicePage) Between(start int, end int) (rows []*SliceCache) {
+	p.d.RwMutex.RLock()
+	defer p.d.RwMutex.RUnlock()
+	return sliceBetween(p.d.SliceDbCache, start, end)
+}
+
+func (p slicePage) Page(bounds func(total int) (start int, end int)) (rows []*SliceCache, total int) {
+	p.d.RwMutex.RLock()
+	defer p.d.RwMutex.RUnlock()
+	total = len(p.d.SliceDbCache)
+	start, end := bounds(total)
+	return sliceBetween(p.d.SliceDbCache, start, end), total
+}
+
+//获取切片中开始行到结束行(不包括结束行)的行.调用者需持有读锁.
+func sliceBetween(data []*SliceCache, start int, end int) (rows []*SliceCache) {
+	if start < 0 {
+		start = 0
+	}
+	if end > len(data) {
+		end = len(data)
+	}
+	if start >= end {
+		return nil
+	}
+	return append(rows, data[start:end]...)
+}
+
+func (p slicePage) Rank(pkey string) int {
+	p.d.RwMutex.RLock()
+	defer p.d.RwMutex.RUnlock()
+	return p.d.GetRowNum(pkey)
+}
+
+func (p slicePage) Range(f func(row *SliceCache) bool) {
+	p.d.RwMutex.RLock()
+	defer p.d.RwMutex.RUnlock()
+	for _, row := range p.d.SliceDbCache {
+		if !f(row) {
+			return
+		}
+	}
+}
+
+func (p slicePage) Seek(key *SliceCache, isAfter bool, limit int) (rows []*SliceCache, hasOther bool) {
+	p.d.RwMutex.RLock()
+	defer p.d.RwMutex.RUnlock()
+	return p.d.sortOrder.seekSlice(p.d.SliceDbCache, key, isAfter, limit)
+}
+
+//sliceNotDel切片:数据保存于DBcache.SliceDbCache,删除时只在DelRowNum中记录行号,插入的行追加到最后(未排序).
+type sliceNotDelPage struct {
+	d *DBcache
+}
+
+func (p sliceNotDelPage) Load(rows []*SliceCache) {
+	p.d.RwMutex.Lock()
+	defer p.d.RwMutex.Unlock()
+	p.d.SliceDbCache = p.d.sortOrder.sortSlice(rows)
+	p.d.sortedRows = len(rows)
+}
+
+func (p sliceNotDelPage) Insert(rows []*SliceCache) (n int64) {
+	p.d.RwMutex.Lock()
+	defer p.d.RwMutex.Unlock()
+	//只是追加,保证切片的行号不变.
+	p.d.SliceDbCache = append(p.d.SliceDbCache, rows...)
+	return int64(len(rows))
+}
+
+func (p sliceNotDelPage) Delete(pkeys map[string]bool) (n int64) {
+	d := p.d
+	d.RwMutex.Lock()
+	defer d.RwMutex.Unlock()
+	for i, sliceData := range d.SliceDbCache {
+		if !d.DelRowNum[i] && pkeys[sliceData.Pkey] {
+			d.DelRowNum[i] = true
+			n++
+		}
+	}
+	return n
+}
+
+func (p sliceNotDelPage) Update(pkey string, update func(old *SliceCache) *SliceCache) bool {
+	d := p.d
+	d.RwMutex.Lock()
+	defer d.RwMutex.Unlock()
+	for i, sliceData := range d.SliceDbCache {
+		if d.DelRowNum[i] || sliceData.Pkey != pkey {
+			continue
+		}
+		row := update(sliceData)
+		if row == nil {
+			return false
+		}
+		//与插入一样:原行只记录为删除,新行追加到最后,保证其它行的行号不变.
+		d.DelRowNum[i] = true
+		d.SliceDbCache = append(d.SliceDbCache, row)
+		return true
+	}
+	return false
+}
+
+func (p sliceNotDelPage) Len() int64 {
+	p.d.RwMutex.RLock()
+	defer p.d.RwMutex.RUnlock()
+	return int64(len(p.d.SliceDbCache) - len(p.d.DelRowNum))
+}
+
+func (p sliceNotDelPage) Between(start int, end int) (rows []*SliceCache) {
+	p.d.RwMutex.RLock()
+	defer p.d.RwMutex.RUnlock()
+	return p.between(start, end)
+}
+
+func (p sliceNotDelPage) Page(bounds func(total int) (start int, end int)) (rows []*SliceCache, total int) {
+	p.d.RwMutex.RLock()
+	defer p.d.RwMutex.RUnlock()
+	total = len(p.d.SliceDbCache) - len(p.d.DelRowNum)
+	start, end := bounds(total)
+	return p.between(start, end), total
+}
+
+//获取开始行到结束行(不包括结束行)的行,跳过已删除的行.调用者需持有读锁.
+func (p sliceNotDelPage) between(start int, end int) (rows []*SliceCache) {
+	d := p.d
+	if start < 0 {
+		start = 0
+	}
+	//没有删除的行时,直接按行号取.
+	if len(d.DelRowNum) == 0 {
+		return sliceBetween(d.SliceDbCache, start, end)
+	}
+	//跳过已删除的行
+	index := 0
+	for i, row := range d.SliceDbCache {
+		if index >= end {
+			break
+		}
+		if d.DelRowNum[i] {
+			continue
+		}
+		if index >= start {
+			rows = append(rows, row)
+		}
+		index++
+	}
+	return rows
+}
+
+func (p sliceNotDelPage) Rank(pkey string) int {
+	d := p.d
+	d.RwMutex.RLock()
+	defer d.RwMutex.RUnlock()
+	n := d.GetRowNumRecord(pkey)
+	if n == -1 {
+		return -1
+	}
+	//减去前面已删除的行
+	rank := n
+	for i := range d.DelRowNum {
+		if i < n {
+			rank--
+		}
+	}
+	return rank
+}
+
+func (p sliceNotDelPage) Range(f func(row *SliceCache) bool) {
+	p.d.RwMutex.RLock()
+	defer p.d.RwMutex.RUnlock()
+	for i, row := range p.d.SliceDbCache {
+		if p.d.DelRowNum[i] {
+			continue
+		}
+		if !f(row) {
+			return
+		}
+	}
+}
+
+//已排序部分二分查找游标位置,跳过已删除的行.追加的行(未排序)逐行比较,再与已排序部分的结果归并.
+func (p sliceNotDelPage) Seek(key *SliceCache, isAfter bool, limit int) (rows []*SliceCache, hasOther bool) {
+	d := p.d
+	d.RwMutex.RLock()
+	defer d.RwMutex.RUnlock()
+	o := d.sortOrder
+	sorted := d.SliceDbCache[:d.sortedRows]
+	if isAfter {
+		start := 0
+		if key != nil {
+			start = sort.Search(len(sorted), func(i int) bool { return o.less(key, sorted[i]) })
+		}
+		for i := start; i < len(sorted) && len(rows) < limit; i++ {
+			if !d.DelRowNum[i] {
+				rows = append(rows, sorted[i])
+			}
+		}
+		for i := start - 1; i >= 0 && !hasOther; i-- {
+			hasOther = !d.DelRowNum[i]
+		}
+	} else {
+		end := len(sorted)
+		if key != nil {
+			end = o.searchSlice(sorted, key)
+		}
+		for i := end - 1; i >= 0 && len(rows) < limit; i-- {
+			if !d.DelRowNum[i] {
+				rows = append(rows, sorted[i])
+			}
+		}
+		//反转为正常顺序
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+		for i := end; i < len(sorted) && !hasOther; i++ {
+			hasOther = !d.DelRowNum[i]
+		}
+	}
+	//追加的行
+	if len(sorted) == len(d.SliceDbCache) {
+		return rows, hasOther
+	}
+	tail, tailOther := o.seekUnsorted(func(f func(row *SliceCache) bool) {
+		for i := len(sorted); i < len(d.SliceDbCache); i++ {
+			if !d.DelRowNum[i] && !f(d.SliceDbCache[i]) {
+				return
+			}
+		}
+	}, key, isAfter, limit)
+	rows = o.mergeSlice(rows, tail)
+	if isAfter {
+		rows = sliceBetween(rows, 0, limit)
+	} else {
+		rows = sliceBetween(rows, len(rows)-limit, len(rows))
+	}
+	return rows, hasOther || tailOther
+}
+
+//link链表:数据保存于DBcache.LinkDbCache,有序.插入,删除,按位置取行都要遍历链表,O(n).
+type linkPage struct {
+	d *DBcache
+}
+
+//行转为链表节点
+func (p linkPage) newNodes(rows []*SliceCache) []*Node {
+	nodes := make([]*Node, 0, len(rows))
+	length := p.d.LinkDbCache.GetLength()
+	for i, row := range rows {
+		node := NewNode(length+int64(i)+1, row.Pkey, row.SortColumn, row.RowMap)
+		node.sortValues = row.SortValues
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+//链表节点转为行
+func nodeRow(node *Node) *SliceCache {
+	return &SliceCache{Pkey: node.pkey, SortColumn: node.sortColumn, SortValues: node.sortValues, RowMap: node.row}
+}
+
+func (p linkPage) Load(rows []*SliceCache) {
+	nodes := p.newNodes(rows)
+	p.d.sortOrder.sortNodes(nodes)
+	for _, node := range nodes {
+		p.d.LinkDbCache.InsertTail(node)
+	}
+}
+
+func (p linkPage) Insert(rows []*SliceCache) (n int64) {
+	nodes := p.newNodes(rows)
+	if len(nodes) == 1 {
+		p.d.LinkDbCache.InsertNodeOrder(nodes[0], p.d.sortOrder.lessNode)
+	} else {
+		p.d.LinkDbCache.InsertNodesOrder(nodes, p.d.sortOrder.lessNode)
+	}
+	return int64(len(nodes))
+}
+
+func (p linkPage) Delete(pkeys map[string]bool) (n int64) {
+	return p.d.LinkDbCache.DeleteNodesPkey(pkeys)
+}
+
+func (p linkPage) Update(pkey string, update func(old *SliceCache) *SliceCache) bool {
+	return p.d.LinkDbCache.UpdateNodeOrder(pkey, func(node *Node) bool {
+		row := update(nodeRow(node))
+		if row == nil {
+			return false
+		}
+		node.sortColumn, node.sortValues = row.SortColumn, row.SortValues
+		return true
+	}, p.d.sortOrder.lessNode)
+}
+
+func (p linkPage) Len() int64 {
+	return p.d.LinkDbCache.GetLength()
+}
+
+func (p linkPage) Between(start int, end int) (rows []*SliceCache) {
+	index := 0
+	p.d.LinkDbCache.RangeNode(func(node *Node) bool {
+		if index >= end {
+			return false
+		}
+		if index >= start {
+			rows = append(rows, nodeRow(node))
+		}
+		index++
+		return true
+	})
+	return rows
+}
+
+func (p linkPage) Page(bounds func(total int) (start int, end int)) (rows []*SliceCache, total int) {
+	nodes, length := p.d.LinkDbCache.GetNodePage(func(length int64) (int64, int64) {
+		start, end := bounds(int(length))
+		return int64(start), int64(end)
+	})
+	for _, node := range nodes {
+		rows = append(rows, nodeRow(node))
+	}
+	return rows, int(length)
+}
+
+func (p linkPage) Rank(pkey string) int {
+	index, rank := 0, -1
+	p.d.LinkDbCache.RangeNode(func(node *Node) bool {
+		if node.pkey == pkey {
+			rank = index
+			return false
+		}
+		index++
+		return true
+	})
+	return rank
+}
+
+func (p linkPage) Range(f func(row *SliceCache) bool) {
+	p.d.LinkDbCache.RangeNode(func(node *Node) bool {
+		return f(nodeRow(node))
+	})
+}
+
+//链表只能从头遍历:遍历到游标位置之后取够limit行即停止,不再遍历后面的行.
+func (p linkPage) Seek(key *SliceCache, isAfter bool, limit int) (rows []*SliceCache, hasOther bool) {
+	o := p.d.sortOrder
+	p.d.LinkDbCache.RangeNode(func(node *Node) bool {
+		row := &SliceCache{Pkey: node.pkey, SortValues: node.sortValues}
+		if isAfter {
+			if !o.isSeekRow(row, key, true) {
+				hasOther = true
+				return true
+			}
+			rows = append(rows, nodeRow(node))
+			return len(rows) < limit
+		}
+		//取游标之前的行:遇到第一个不排在key前面的行时停止,只保留最后limit行.
+		if !o.isSeekRow(row, key, false) {
+			hasOther = true
+			return false
+		}
+		rows = append(rows, nodeRow(node))
+		if len(rows) > limit {
+			rows = append(rows[:0], rows[1:]...)
+		}
+		return true
+	})
+	return rows, hasOther
+}
+
+//没有配置缓存类型:没有分页缓存,只能遍历主缓存(无序).
+type nonePage struct {
+	d *DBcache
+}
+
+func (p nonePage) Load(rows []*SliceCache)                  {}
+func (p nonePage) Insert(rows []*SliceCache) (n int64)      { return 0 }
+func (p nonePage) Delete(pkeys map[string]bool) (n int64)   { return 0 }
+func (p nonePage) Len() int64                               { return atomic.LoadInt64(&p.d.RowCount) }
+func (p nonePage) Update(pkey string, update func(old *SliceCache) *SliceCache) bool {
+	return false
+}
+func (p nonePage) Between(start int, end int) []*SliceCache { return nil }
+func (p nonePage) Page(bounds func(total int) (start int, end int)) ([]*SliceCache, int) {
+	return nil, int(atomic.LoadInt64(&p.d.RowCount))
+}
+func (p nonePage) Rank(pkey string) int                     { return -1 }
+func (p nonePage) Seek(key *SliceCache, isAfter bool, limit int) ([]*SliceCache, bool) {
+	return p.d.sortOrder.seekUnsorted(p.Range, key, isAfter, limit)
+}
+func (p nonePage) Range(f func(row *SliceCache) bool) {
+	p.d.DbCache.Range(func(k, v interface{}) bool {
+		rowMap := v.(*sync.Map)
+		return f(&SliceCache{Pkey: k.(string), SortValues: p.d.sortOrder.values(rowMap), RowMap: rowMap})
+	})
+}
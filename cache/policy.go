@@ -0,0 +1,220 @@
+package cache
+
+import (
+	"dbcache/conf"
+	"fmt"
+	"strings"
+)
+
+//列的访问策略,在cache.conf中按表配置:
+//一. hidden_columns隐藏的列,不返回给rpc和grpc客户端,where条件中也不能使用(避免按条件逐个猜测值).
+//二. masked_columns脱敏的列,只显示末尾几个字符,其它字符替换为*,where条件中也不能使用.
+//三. readonly_columns只读的列,不能更新(UpdateColumn,UpdateColumns,UpdateWhere,Upsert),插入时可以写入.
+//rpcserver和grpcserver按策略过滤返回的行,检查where条件和更新的列.Go API直接调用缓存对象的方法不受限制,
+//需要时通过Policy()获取策略,调用FilterRow(),FilterRows(),CheckWrite(),及CheckWhere(),CheckChanges().
+//策略可热加载(见reload.go).
+
+const MASK_CHAR = "*" //脱敏时替换的字符
+
+//列的访问策略,创建后不再修改,热加载时整体替换.
+type ColumnPolicy struct {
+	hidden   map[string]bool //隐藏的列,列名小写
+	masked   map[string]int  //脱敏的列,列名小写 -> 显示末尾字符数
+	readonly map[string]bool //只读的列,列名小写
+}
+
+//根据表配置创建列的访问策略.
+func newColumnPolicy(table conf.CacheTable) (p *ColumnPolicy, err error) {
+	masks, err := table.GetMaskedColumns()
+	if err != nil {
+		return nil, fmt.Errorf("newColumnPolicy(),表[%s]的masked_columns配置错误, err: %s", table.GetTableName(), err)
+	}
+	p = &ColumnPolicy{
+		hidden:   make(map[string]bool),
+		masked:   make(map[string]int, len(masks)),
+		readonly: make(map[string]bool),
+	}
+	for _, column := range table.GetHiddenColumns() {
+		p.hidden[strings.ToLower(column)] = true
+	}
+	for column, keep := range masks {
+		p.masked[strings.ToLower(column)] = keep
+	}
+	for _, column := range table.GetReadonlyColumns() {
+		p.readonly[strings.ToLower(column)] = true
+	}
+	if err = p.checkKeyColumns(table); err != nil {
+		return nil, fmt.Errorf("newColumnPolicy(),表[%s]的列访问策略配置错误, err: %s", table.GetTableName(), err)
+	}
+	return p, nil
+}
+
+//隐藏和脱敏的列不能是主键,不能用于排序(other,sort_views):分页游标中包含主键值和排序列的值,
+//返回给客户端时是未隐藏,未脱敏的原值.
+func (p *ColumnPolicy) checkKeyColumns(table conf.CacheTable) error {
+	views, err := table.GetSortViews()
+	if err != nil {
+		return err
+	}
+	keys := table.GetSortKeys()
+	for _, view := range views {
+		keys = append(keys, view.Keys...)
+	}
+	if p.IsHidden(table.GetPkey()) || p.IsMasked(table.GetPkey()) {
+		return fmt.Errorf("隐藏或脱敏的列不能是主键: %s", table.GetPkey())
+	}
+	for _, key := range keys {
+		if p.IsHidden(key.Column) || p.IsMasked(key.Column) {
+			return fmt.Errorf("隐藏或脱敏的列不能用于排序(other,sort_views): %s", key.Column)
+		}
+	}
+	return nil
+}
+
+//是否没有隐藏和脱敏的列,没有时返回的行不需过滤.
+func (p *ColumnPolicy) isOpen() bool {
+	return p == nil || (len(p.hidden) == 0 && len(p.masked) == 0)
+}
+
+//是否是隐藏的列
+func (p *ColumnPolicy) IsHidden(column string) bool {
+	return p != nil && p.hidden[strings.ToLower(column)]
+}
+
+//是否是脱敏的列
+func (p *ColumnPolicy) IsMasked(column string) bool {
+	if p == nil {
+		return false
+	}
+	_, ok := p.masked[strings.ToLower(column)]
+	return ok
+}
+
+//是否是只读的列
+func (p *ColumnPolicy) IsReadonly(column string) bool {
+	return p != nil && p.readonly[strings.ToLower(column)]
+}
+
+//按策略过滤一行:去掉隐藏的列,脱敏的列只显示末尾几个字符.返回新的map,不修改row.
+func (p *ColumnPolicy) FilterRow(row map[string]string) map[string]string {
+	if row == nil || p.isOpen() {
+		return row
+	}
+	result := make(map[string]string, len(row))
+	for column, value := range row {
+		if p.IsHidden(column) {
+			continue
+		}
+		if keep, ok := p.masked[strings.ToLower(column)]; ok {
+			value = maskValue(value, keep)
+		}
+		result[column] = value
+	}
+	return result
+}
+
+//按策略过滤多行
+func (p *ColumnPolicy) FilterRows(rows []map[string]string) []map[string]string {
+	if p.isOpen() {
+		return rows
+	}
+	result := make([]map[string]string, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, p.FilterRow(row))
+	}
+	return result
+}
+
+//按策略过滤一列的值,隐藏的列返回错误.
+func (p *ColumnPolicy) FilterColumn(column string, value string) (string, error) {
+	if p.IsHidden(column) {
+		return "", fmt.Errorf("FilterColumn(),列[%s]已隐藏", column)
+	}
+	if p == nil {
+		return value, nil
+	}
+	if keep, ok := p.masked[strings.ToLower(column)]; ok {
+		return maskValue(value, keep), nil
+	}
+	return value, nil
+}
+
+//检查条件中使用的列,隐藏和脱敏的列不能使用.
+func (p *ColumnPolicy) CheckRead(columns ...string) error {
+	for _, column := range columns {
+		if p.IsHidden(column) || p.IsMasked(column) {
+			return fmt.Errorf("CheckRead(),列[%s]已隐藏或脱敏,不能在条件中使用", column)
+		}
+	}
+	return nil
+}
+
+//检查更新的列,只读的列不能更新.
+func (p *ColumnPolicy) CheckWrite(columns ...string) error {
+	for _, column := range columns {
+		if p.IsReadonly(column) {
+			return fmt.Errorf("CheckWrite(),列[%s]是只读的,不能更新", column)
+		}
+	}
+	return nil
+}
+
+//脱敏:只显示末尾keep个字符(按字符,不按字节),其它替换为*.字符数不大于keep时全部替换.
+func maskValue(value string, keep int) string {
+	runes := []rune(value)
+	if len(runes) <= keep {
+		return strings.Repeat(MASK_CHAR, len(runes))
+	}
+	return strings.Repeat(MASK_CHAR, len(runes)-keep) + string(runes[len(runes)-keep:])
+}
+
+//获取列的访问策略.
+func (d *DBcache) Policy() *ColumnPolicy {
+	d.configMutex.RLock()
+	defer d.configMutex.RUnlock()
+	return d.policy
+}
+
+//检查where条件(格式与GetWhere()相同)中使用的列,隐藏和脱敏的列不能使用.where为空时不检查.
+func (d *DBcache) CheckWhere(where string) error {
+	if strings.TrimSpace(where) == "" {
+		return nil
+	}
+	_, _, whereCondition, err := d.parseWhere(where)
+	if err != nil {
+		return fmt.Errorf("CheckWhere(), err: %s", err)
+	}
+	for _, condition := range whereCondition {
+		if err = d.Policy().CheckRead(condition[0]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//检查多列表达式(格式与UpdateColumns()相同)中更新的列,只读的列不能更新.主键用于定位行,不检查.
+func (d *DBcache) CheckChanges(changes string) error {
+	changeCondition, err := d.GetCondition(changes, ",")
+	if err != nil {
+		return fmt.Errorf("CheckChanges(),条件错误: %s. err: %s", changes, err)
+	}
+	for _, condition := range changeCondition {
+		if condition[0] == d.TableConfig.GetPkey() {
+			continue
+		}
+		if err = d.Policy().CheckWrite(condition[0]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//获取视图所属表的列的访问策略.
+func (v *SortView) Policy() *ColumnPolicy {
+	return v.d.Policy()
+}
+
+//检查where条件中使用的列,见DBcache.CheckWhere().
+func (v *SortView) CheckWhere(where string) error {
+	return v.d.CheckWhere(where)
+}
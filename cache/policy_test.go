@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"dbcache/conf"
+	"reflect"
+	"testing"
+)
+
+//隐藏,脱敏和只读的列
+func TestColumnPolicy(t *testing.T) {
+	d := newTestCache("slice", "order by age asc")
+	table := d.TableConfig
+	table.HiddenColumns = "score"
+	table.MaskedColumns = "name:2,create_date"
+	table.ReadonlyColumns = "price"
+	if err := d.applyLiveConfig(table); err != nil {
+		t.Fatal(err)
+	}
+	p := d.Policy()
+	row := map[string]string{"id": "1", "age": "20", "price": "9.5", "score": "88", "name": "张小明", "create_date": "2020"}
+	want := map[string]string{"id": "1", "age": "20", "price": "9.5", "name": "*小明", "create_date": "****"}
+	if got := p.FilterRow(row); !reflect.DeepEqual(got, want) {
+		t.Errorf("FilterRow() = %v, want %v", got, want)
+	}
+	if row["score"] != "88" || row["name"] != "张小明" {
+		t.Errorf("FilterRow() modified the row: %v", row)
+	}
+	if got := p.FilterRows([]map[string]string{row}); len(got) != 1 || !reflect.DeepEqual(got[0], want) {
+		t.Errorf("FilterRows() = %v", got)
+	}
+	if _, err := p.FilterColumn("SCORE", "88"); err == nil {
+		t.Errorf("FilterColumn(SCORE) want error")
+	}
+	if got, err := p.FilterColumn("name", "ab"); err != nil || got != "**" {
+		t.Errorf("FilterColumn(name) = %q, %v", got, err)
+	}
+	//where条件中不能使用隐藏和脱敏的列
+	for where, ok := range map[string]bool{
+		"":                    true,
+		"age=20":              true,
+		"age=20 and score=88": false,
+		"age=20 or name=张小明":  false,
+		"create_date!=2020":   false,
+	} {
+		if err := d.CheckWhere(where); (err == nil) != ok {
+			t.Errorf("CheckWhere(%q) = %v, want ok %t", where, err, ok)
+		}
+	}
+	//只读的列不能更新,主键不检查
+	if err := d.CheckChanges("id=1,age=21"); err != nil {
+		t.Errorf("CheckChanges() = %v", err)
+	}
+	if err := d.CheckChanges("age=21,price=10"); err == nil {
+		t.Errorf("CheckChanges(price) want error")
+	}
+	//脱敏配置错误时不修改
+	table.MaskedColumns = "name:x"
+	if err := d.applyLiveConfig(table); err == nil || d.Policy() != p || d.liveConfig().MaskedColumns != "name:2,create_date" {
+		t.Errorf("applyLiveConfig() with bad masked_columns = %v", err)
+	}
+	//隐藏和脱敏的列不能是主键,不能用于排序,热加载时也检查
+	for _, bad := range []conf.CacheTable{
+		{HiddenColumns: "id"},
+		{MaskedColumns: "age"},
+		{HiddenColumns: "name", SortViews: "namev:name desc"},
+		{MaskedColumns: "create_date:2", SortViews: "datev:age asc create_date desc"},
+	} {
+		table := d.TableConfig
+		table.HiddenColumns, table.MaskedColumns, table.SortViews = bad.HiddenColumns, bad.MaskedColumns, bad.SortViews
+		if _, err := newColumnPolicy(table); err == nil {
+			t.Errorf("newColumnPolicy(hidden %q, masked %q, sort_views %q) want error", bad.HiddenColumns, bad.MaskedColumns, bad.SortViews)
+		}
+		if err := d.applyLiveConfig(table); err == nil || d.Policy() != p {
+			t.Errorf("applyLiveConfig(hidden %q, masked %q) = %v, want error", bad.HiddenColumns, bad.MaskedColumns, err)
+		}
+	}
+	//未配置时不过滤
+	var open *ColumnPolicy
+	if got := open.FilterRow(row); !reflect.DeepEqual(got, row) || open.CheckWrite("price") != nil {
+		t.Errorf("nil policy FilterRow() = %v", got)
+	}
+}
@@ -0,0 +1,202 @@
+package cache
+
+import (
+	"context"
+	"database/sql"
+	"dbcache/conf"
+	"dbcache/logs"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+//缓存实例:一组缓存表,有自己的数据库连接,config.conf,cache.conf,数据目录和注册表(rpc,grpc按名称查找缓存表).
+//缓存表的名称是cache.conf中的分段名,不区分大小写,同一个表可以配置多个分段(别名,见alias.go).
+//同一进程中可以运行多个实例(见server包),配置互不影响.包级函数(NewDBcache,GetCacheObj,AddTable等)使用默认实例,
+//默认实例的config.conf是conf.CONFIG_FILE,cache.conf是conf.TABLES_CONF,数据目录为空.
+//热加载cache.conf时(见reload.go)会在运行中增加,删除和替换表,所以注册表的读写都需加锁.
+
+//缓存实例
+type Instance struct {
+	db         *sql.DB //数据库对象,用于加载新增的表
+	configFile string  //config.conf,热加载日志配置时读取,为空时是conf.CONFIG_FILE
+	tablesConf string  //cache.conf,为空时是conf.TABLES_CONF
+	dataDir    string  //数据目录,异步sql文件的相对路径(async_file_path)相对于此目录,为空时相对于当前目录
+
+	cacheObj      map[string]*DBcache //缓存表对象,名称(小写) -> 缓存表
+	cacheObjMutex sync.RWMutex        //保护cacheObj
+	tableMutex    sync.Mutex          //重新读取配置文件和运行中增加表(AddTable)需串行
+}
+
+var defaultInstance = &Instance{cacheObj: map[string]*DBcache{}} //默认实例
+
+//新建缓存实例.configFile为空时是conf.CONFIG_FILE,tablesConf为空时是conf.TABLES_CONF,dataDir为空时相对于当前目录.
+//调用LoadTables()加载cache.conf中的表.
+func NewInstance(db *sql.DB, configFile string, tablesConf string, dataDir string) *Instance {
+	return &Instance{
+		db:         db,
+		configFile: configFile,
+		tablesConf: tablesConf,
+		dataDir:    dataDir,
+		cacheObj:   map[string]*DBcache{},
+	}
+}
+
+//获取默认实例
+func DefaultInstance() *Instance {
+	return defaultInstance
+}
+
+//获取实例的config.conf
+func (in *Instance) ConfigFile() string {
+	if in.configFile == "" {
+		return conf.CONFIG_FILE
+	}
+	return in.configFile
+}
+
+//获取实例的cache.conf
+func (in *Instance) TablesConf() string {
+	if in.tablesConf == "" {
+		return conf.TABLES_CONF
+	}
+	return in.tablesConf
+}
+
+//获取实例的数据目录
+func (in *Instance) DataDir() string {
+	return in.dataDir
+}
+
+//获取实例的数据库对象
+func (in *Instance) DB() *sql.DB {
+	return in.db
+}
+
+//按名称(cache.conf中的分段名)新建缓存表,并注册到实例.
+func (in *Instance) NewDBcache(name string) (dbCache *DBcache, err error) {
+	return newDBcache(in, in.db, name)
+}
+
+//加载cache.conf中所有的表.有表加载失败时,关闭已加载的表,返回错误.
+func (in *Instance) LoadTables() (caches []*DBcache, err error) {
+	names, err := conf.GetCacheTableSections(in.TablesConf())
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		dbCache, err := in.NewDBcache(name)
+		if err != nil {
+			for _, c := range caches {
+				retireCacheObj(c)
+			}
+			return nil, fmt.Errorf("LoadTables(),加载缓存表[%s]失败, err: %s", name, err)
+		}
+		caches = append(caches, dbCache)
+	}
+	return caches, nil
+}
+
+//关闭实例中所有的缓存表(同步完异步管道中剩余的sql).数据库对象由调用者关闭.
+func (in *Instance) Close(ctx context.Context) (err error) {
+	for _, dbCache := range in.GetCacheObjs() {
+		in.unregister(dbCache)
+		if closeErr := dbCache.Close(ctx); closeErr != nil {
+			err = fmt.Errorf("Close(),关闭缓存表[%s]失败, err: %s", dbCache.Name(), closeErr)
+			logs.Error("a", "%s", err)
+		}
+	}
+	return err
+}
+
+//根据名称(cache.conf中的分段名,不区分大小写)获取缓存表对象.
+func (in *Instance) GetCacheObj(name string) (dbCache *DBcache, ok bool) {
+	in.cacheObjMutex.RLock()
+	defer in.cacheObjMutex.RUnlock()
+	dbCache, ok = in.cacheObj[strings.ToLower(name)]
+	return dbCache, ok
+}
+
+//获取所有缓存表对象,按名称排序.
+func (in *Instance) GetCacheObjs() (caches []*DBcache) {
+	in.cacheObjMutex.RLock()
+	defer in.cacheObjMutex.RUnlock()
+	names := make([]string, 0, len(in.cacheObj))
+	for name := range in.cacheObj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		caches = append(caches, in.cacheObj[name])
+	}
+	return caches
+}
+
+//注册缓存表对象,名称已存在时替换.
+func (in *Instance) register(dbCache *DBcache) {
+	in.cacheObjMutex.Lock()
+	defer in.cacheObjMutex.Unlock()
+	in.cacheObj[strings.ToLower(dbCache.Name())] = dbCache
+}
+
+//从注册表中删除缓存表对象.只有注册的是dbCache本身时才删除,防止删除已替换的新对象.
+func (in *Instance) unregister(dbCache *DBcache) {
+	in.cacheObjMutex.Lock()
+	defer in.cacheObjMutex.Unlock()
+	name := strings.ToLower(dbCache.Name())
+	if in.cacheObj[name] == dbCache {
+		delete(in.cacheObj, name)
+	}
+}
+
+//运行中增加缓存表,使用实例的数据库对象.见AddTable().
+func (in *Instance) AddTable(name string, table conf.CacheTable) (dbCache *DBcache, err error) {
+	return in.addTable(in.db, name, table, nil)
+}
+
+//运行中增加缓存表:先写入cache.conf(见conf.AddCacheTable()),再从数据库加载并注册.加载失败时从cache.conf中删除.
+//name是缓存表的名称(分段名),为空时是表名.check不为nil时,在写入cache.conf前检查表配置(与增加,热加载表串行).
+func (in *Instance) addTable(db *sql.DB, name string, table conf.CacheTable, check func(table conf.CacheTable) error) (dbCache *DBcache, err error) {
+	if name == "" {
+		name = table.TableName
+	}
+	in.tableMutex.Lock()
+	defer in.tableMutex.Unlock()
+	if _, ok := in.GetCacheObj(name); ok {
+		return nil, fmt.Errorf("AddTable(),表[%s]已缓存", name)
+	}
+	if check != nil {
+		if err = check(table); err != nil {
+			return nil, fmt.Errorf("AddTable(),表[%s]配置错误, err: %s", name, err)
+		}
+	}
+	err = conf.AddCacheTable(in.TablesConf(), name, table)
+	if err != nil {
+		return nil, err
+	}
+	dbCache, err = newDBcache(in, db, name)
+	if err != nil {
+		if _, removeErr := conf.RemoveCacheTable(in.TablesConf(), name); removeErr != nil {
+			logs.Error("a", "AddTable(),从cache.conf中删除表[%s]失败, err: %s", name, removeErr)
+		}
+		return nil, fmt.Errorf("AddTable(),加载表[%s]失败, err: %s", name, err)
+	}
+	logs.Info("a", "增加缓存表[%s], 总行数: %d", name, dbCache.GetRowCount())
+	return dbCache, nil
+}
+
+//根据名称获取默认实例中的缓存表对象.
+func GetCacheObj(name string) (dbCache *DBcache, ok bool) {
+	return defaultInstance.GetCacheObj(name)
+}
+
+//获取默认实例中所有缓存表对象,按名称排序.
+func GetCacheObjs() (caches []*DBcache) {
+	return defaultInstance.GetCacheObjs()
+}
+
+//在默认实例中运行中增加缓存表.见Instance.AddTable().
+func AddTable(db *sql.DB, name string, table conf.CacheTable) (dbCache *DBcache, err error) {
+	return defaultInstance.addTable(db, name, table, nil)
+}
@@ -0,0 +1,327 @@
+package cache
+
+import (
+	"context"
+	"database/sql"
+	"dbcache/conf"
+	"dbcache/logs"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+//热加载配置文件.定时检查cache.conf和config.conf的修改时间,改变后重新读取:
+//一. cache.conf中新增的表,加载到缓存.
+//二. cache.conf中删除的表,从注册表中删除,并关闭(同步完异步管道中剩余的sql).
+//三. 表的配置改变时,可热加载的配置项(liveConfKeys)立即生效,其它配置项改变时,从数据库重新加载,成功后替换并关闭原缓存表.
+//加载失败的表(新增或重新加载)不记录新的配置,原缓存表继续使用,下次检查时重试.
+//四. config.conf中日志的是否启用和日志等级立即生效(见logs.Reload()).
+//所有改变都记录到日志.
+
+const RELOAD_CLOSE_TIMEOUT = time.Second * 30 //热加载时,关闭删除或重新加载的缓存表的超时时间
+
+//可热加载的配置项(conf标签名),改变后不需重新加载缓存表.
+var liveConfKeys = map[string]bool{
+	"is_wait_result":      true,
+	"compact_hours":       true,
+	"compact_max_deleted": true,
+	"compact_ratio":       true,
+	"hidden_columns":      true,
+	"masked_columns":      true,
+	"readonly_columns":    true,
+}
+
+//配置文件监视对象
+type ConfWatcher struct {
+	instance *Instance                  //监视的缓存实例,检查实例的cache.conf和config.conf(日志的配置)
+	db       *sql.DB                    //数据库对象,用于加载新增的表
+	interval time.Duration              //检查配置文件的间隔
+	tables   map[string]conf.CacheTable //上一次读取的cache.conf中表的配置,表名 -> 配置
+	modTimes map[string]time.Time       //上一次读取时配置文件的修改时间
+
+	stopChan  chan struct{}  //通知后台协程退出的管道
+	wg        sync.WaitGroup //等待后台协程退出
+	closeOnce sync.Once
+}
+
+//新建默认实例的配置文件监视对象,记录当前的配置,interval小于1秒时默认5秒.调用Start()开始监视.
+func NewConfWatcher(db *sql.DB, interval time.Duration) (w *ConfWatcher, err error) {
+	return newConfWatcher(defaultInstance, db, interval)
+}
+
+//新建实例的配置文件监视对象,使用实例的数据库对象.见NewConfWatcher().
+func (in *Instance) NewConfWatcher(interval time.Duration) (w *ConfWatcher, err error) {
+	return newConfWatcher(in, in.db, interval)
+}
+
+func newConfWatcher(in *Instance, db *sql.DB, interval time.Duration) (w *ConfWatcher, err error) {
+	if interval < time.Second {
+		interval = time.Second * 5
+	}
+	tables, err := conf.GetCacheTables(in.TablesConf())
+	if err != nil {
+		return nil, fmt.Errorf("NewConfWatcher(),读取缓存表配置失败, err: %s", err)
+	}
+	w = &ConfWatcher{
+		instance: in,
+		db:       db,
+		interval: interval,
+		tables:   tables,
+		modTimes: make(map[string]time.Time),
+		stopChan: make(chan struct{}),
+	}
+	for _, fileName := range w.files() {
+		w.modTimes[fileName], _ = getModTime(fileName)
+	}
+	return w, nil
+}
+
+//监视的配置文件:实例的cache.conf和config.conf.
+func (w *ConfWatcher) files() []string {
+	return []string{w.instance.TablesConf(), w.instance.ConfigFile()}
+}
+
+//获取文件的修改时间
+func getModTime(fileName string) (modTime time.Time, err error) {
+	info, err := os.Stat(fileName)
+	if err != nil {
+		return modTime, err
+	}
+	return info.ModTime(), nil
+}
+
+//开始后台监视配置文件.
+func (w *ConfWatcher) Start() {
+	w.wg.Add(1)
+	go w.backCheckConf()
+}
+
+//停止监视,等待正在执行的重新读取完成.可重复调用.
+func (w *ConfWatcher) Close() {
+	w.closeOnce.Do(func() {
+		close(w.stopChan)
+	})
+	w.wg.Wait()
+}
+
+//后台按间隔检查配置文件是否修改.
+func (w *ConfWatcher) backCheckConf() {
+	defer w.wg.Done()
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-w.stopChan:
+			return
+		}
+		w.check()
+	}
+}
+
+//检查配置文件的修改时间,改变时重新读取.
+func (w *ConfWatcher) check() {
+	w.instance.tableMutex.Lock()
+	defer w.instance.tableMutex.Unlock()
+	for _, fileName := range w.files() {
+		modTime, err := getModTime(fileName)
+		if err != nil {
+			logs.Error("a", "check(),获取配置文件[%s]的修改时间失败, err: %s", fileName, err)
+			continue
+		}
+		if modTime.Equal(w.modTimes[fileName]) {
+			continue
+		}
+		if fileName == w.instance.TablesConf() {
+			err = w.reloadTables()
+		} else {
+			err = reloadLogs(fileName)
+		}
+		//读取失败时(例如文件正在写入),不记录修改时间,下次再读取.
+		if err != nil {
+			logs.Error("a", "check(),重新读取配置文件[%s]失败, err: %s", fileName, err)
+			continue
+		}
+		w.modTimes[fileName] = modTime
+	}
+}
+
+//立即重新读取cache.conf和config.conf,不检查修改时间.
+func (w *ConfWatcher) Reload() (err error) {
+	w.instance.tableMutex.Lock()
+	defer w.instance.tableMutex.Unlock()
+	err = w.reloadTables()
+	if err != nil {
+		return err
+	}
+	return reloadLogs(w.instance.ConfigFile())
+}
+
+//重新读取configFile中的日志配置,记录改变.
+func reloadLogs(configFile string) error {
+	changes, err := logs.Reload(configFile)
+	if err != nil {
+		return err
+	}
+	for _, change := range changes {
+		logs.Info("a", "热加载config.conf, 日志配置改变: %s", change)
+	}
+	return nil
+}
+
+//重新读取cache.conf,与上一次读取的配置比较,加载新增的表,关闭删除的表,应用改变的配置.
+//有表加载失败时返回错误(不记录文件的修改时间,下次检查时重试),加载失败的表记录为上一次的配置.
+func (w *ConfWatcher) reloadTables() error {
+	tables, err := conf.GetCacheTables(w.instance.TablesConf())
+	if err != nil {
+		return fmt.Errorf("reloadTables(),读取缓存表配置失败, err: %s", err)
+	}
+	failed := make([]string, 0)
+	//删除的表
+	for _, name := range sortedTableNames(w.tables) {
+		if _, ok := tables[name]; ok {
+			continue
+		}
+		logs.Info("a", "热加载cache.conf, 删除缓存表[%s]", name)
+		if dbCache, ok := w.instance.GetCacheObj(name); ok {
+			retireCacheObj(dbCache)
+		}
+	}
+	for _, name := range sortedTableNames(tables) {
+		table := tables[name]
+		old, ok := w.tables[name]
+		//新增的表
+		if !ok {
+			//已由AddTable()加载
+			if _, ok = w.instance.GetCacheObj(name); ok {
+				continue
+			}
+			logs.Info("a", "热加载cache.conf, 新增缓存表[%s]", name)
+			if _, err = newDBcache(w.instance, w.db, name); err != nil {
+				logs.Error("a", "reloadTables(),加载缓存表[%s]失败, err: %s", name, err)
+				delete(tables, name)
+				failed = append(failed, name)
+			}
+			continue
+		}
+		keys := conf.DiffCacheTable(old, table)
+		if len(keys) == 0 {
+			continue
+		}
+		isLive := true
+		for _, key := range keys {
+			logs.Info("a", "热加载cache.conf, 缓存表[%s]配置[%s]改变: %s -> %s", name, key, old.GetConfValue(key), table.GetConfValue(key))
+			if !liveConfKeys[key] {
+				isLive = false
+			}
+		}
+		dbCache, ok := w.instance.GetCacheObj(name)
+		if !ok {
+			continue
+		}
+		if isLive {
+			if err = dbCache.applyLiveConfig(table); err != nil {
+				logs.Error("a", "reloadTables(),缓存表[%s]的配置未生效, err: %s", name, err)
+			}
+			continue
+		}
+		//有不能热加载的配置项改变,从数据库重新加载.
+		logs.Info("a", "热加载cache.conf, 重新加载缓存表[%s]", name)
+		if err = w.reloadCacheObj(dbCache); err != nil {
+			logs.Error("a", "reloadTables(),重新加载缓存表[%s]失败,继续使用原缓存表, err: %s", name, err)
+			tables[name] = old
+			failed = append(failed, name)
+		}
+	}
+	w.tables = tables
+	if len(failed) > 0 {
+		return fmt.Errorf("reloadTables(),缓存表%v加载失败,下次检查时重试", failed)
+	}
+	return nil
+}
+
+//从数据库重新加载缓存表,成功后替换并关闭原缓存表,失败时原缓存表继续使用.
+//加载前暂停原缓存表的写操作(等待),并同步完异步管道中的sql,使加载的数据包含之前的写入.
+func (w *ConfWatcher) reloadCacheObj(dbCache *DBcache) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), RELOAD_CLOSE_TIMEOUT)
+	defer cancel()
+	dbCache.closeMutex.Lock()
+	if dbCache.isClosed {
+		dbCache.closeMutex.Unlock()
+		return fmt.Errorf("reloadCacheObj(),缓存表[%s]已关闭", dbCache.Name())
+	}
+	if !dbCache.TableConfig.GetIsRealtime() {
+		if err = dbCache.dataAsync.flush(ctx); err != nil {
+			dbCache.closeMutex.Unlock()
+			return fmt.Errorf("reloadCacheObj(),err: %s", err)
+		}
+	}
+	//加载成功时注册新的缓存表(替换原缓存表)
+	if _, err = newDBcache(w.instance, w.db, dbCache.Name()); err != nil {
+		dbCache.closeMutex.Unlock()
+		return fmt.Errorf("reloadCacheObj(),err: %s", err)
+	}
+	//等待的写操作在原缓存表上返回错误,不写入数据库
+	dbCache.isClosed = true
+	dbCache.closeMutex.Unlock()
+	dbCache.instance.unregister(dbCache)
+	if err = dbCache.shutdown(ctx); err != nil {
+		logs.Error("a", "reloadCacheObj(),关闭原缓存表[%s]失败, err: %s", dbCache.Name(), err)
+	}
+	return nil
+}
+
+//按名称排序
+func sortedTableNames(tables map[string]conf.CacheTable) (names []string) {
+	for name := range tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+//从注册表中删除缓存表,并关闭(同步完异步管道中剩余的sql).
+func retireCacheObj(dbCache *DBcache) {
+	dbCache.instance.unregister(dbCache)
+	ctx, cancel := context.WithTimeout(context.Background(), RELOAD_CLOSE_TIMEOUT)
+	defer cancel()
+	err := dbCache.Close(ctx)
+	if err != nil {
+		logs.Error("a", "retireCacheObj(),关闭缓存表[%s]失败, err: %s", dbCache.Name(), err)
+	}
+}
+
+//获取表配置的副本.可热加载的配置项在运行中会被修改,需通过此函数读取.
+func (d *DBcache) liveConfig() conf.CacheTable {
+	d.configMutex.RLock()
+	defer d.configMutex.RUnlock()
+	return d.TableConfig
+}
+
+//异步更新时,是否等待返回结果.
+func (d *DBcache) isWaitResult() bool {
+	d.configMutex.RLock()
+	defer d.configMutex.RUnlock()
+	return d.TableConfig.GetIsWaitResult()
+}
+
+//应用可热加载的配置项.列的访问策略配置错误时,不修改任何配置项.
+func (d *DBcache) applyLiveConfig(table conf.CacheTable) error {
+	policy, err := newColumnPolicy(table)
+	if err != nil {
+		return err
+	}
+	d.configMutex.Lock()
+	defer d.configMutex.Unlock()
+	d.TableConfig.IsWaitResult = table.IsWaitResult
+	d.TableConfig.CompactHours = table.CompactHours
+	d.TableConfig.CompactMaxDeleted = table.CompactMaxDeleted
+	d.TableConfig.CompactRatio = table.CompactRatio
+	d.TableConfig.HiddenColumns = table.HiddenColumns
+	d.TableConfig.MaskedColumns = table.MaskedColumns
+	d.TableConfig.ReadonlyColumns = table.ReadonlyColumns
+	d.policy = policy
+	return nil
+}
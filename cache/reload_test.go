@@ -0,0 +1,153 @@
+package cache
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"dbcache/conf"
+	"dbcache/logs"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+//配置改变时,区分可热加载的配置项,只应用可热加载的配置项
+func TestApplyLiveConfig(t *testing.T) {
+	d := newTestCache("sliceNotDel", "order by age asc")
+	old := d.TableConfig
+	table := old
+	table.IsWaitResult = true
+	table.CompactHours = "22-4"
+	table.CompactRatio = 0.5
+	keys := conf.DiffCacheTable(old, table)
+	if want := []string{"is_wait_result", "compact_hours", "compact_ratio"}; !reflect.DeepEqual(keys, want) {
+		t.Fatalf("DiffCacheTable() = %v, want %v", keys, want)
+	}
+	for _, key := range keys {
+		if !liveConfKeys[key] {
+			t.Errorf("%s should be live", key)
+		}
+	}
+	if err := d.applyLiveConfig(table); err != nil {
+		t.Fatal(err)
+	}
+	if !d.isWaitResult() || !d.isCompactHour(23) || d.isCompactHour(12) {
+		t.Errorf("applyLiveConfig() not applied: %+v", d.liveConfig())
+	}
+	table.Other = "order by age desc"
+	keys = conf.DiffCacheTable(old, table)
+	if keys[0] != "other" || liveConfKeys[keys[0]] {
+		t.Errorf("DiffCacheTable() = %v, other should not be live", keys)
+	}
+}
+
+//注销缓存表时,不删除已替换的新对象
+func TestUnregisterCacheObj(t *testing.T) {
+	old := newTestCache("slice", "")
+	defaultInstance.register(old)
+	d := newTestCache("slice", "")
+	defaultInstance.register(d)
+	defaultInstance.unregister(old)
+	if got, ok := GetCacheObj("test"); !ok || got != d {
+		t.Errorf("GetCacheObj(test) = %v, %t, want new cache", got, ok)
+	}
+	defaultInstance.unregister(d)
+	if _, ok := GetCacheObj("test"); ok {
+		t.Errorf("GetCacheObj(test) found after unregister")
+	}
+}
+
+//多个实例的配置和注册表互不影响
+func TestInstance(t *testing.T) {
+	a := NewInstance(nil, "", "", "")
+	b := NewInstance(nil, "b/config.conf", "b/cache.conf", "b")
+	if a.TablesConf() != conf.TABLES_CONF || b.TablesConf() != "b/cache.conf" || b.DataDir() != "b" {
+		t.Errorf("TablesConf() = %s, %s", a.TablesConf(), b.TablesConf())
+	}
+	if a.ConfigFile() != conf.CONFIG_FILE || b.ConfigFile() != "b/config.conf" {
+		t.Errorf("ConfigFile() = %s, %s", a.ConfigFile(), b.ConfigFile())
+	}
+	//热加载监视实例自己的配置文件
+	if files := (&ConfWatcher{instance: b}).files(); files[0] != "b/cache.conf" || files[1] != "b/config.conf" {
+		t.Errorf("ConfWatcher.files() = %v", files)
+	}
+	da := newTestCache("slice", "")
+	da.instance = a
+	a.register(da)
+	db := newTestCache("map", "")
+	db.instance = b
+	b.register(db)
+	if got, ok := a.GetCacheObj("test"); !ok || got != da {
+		t.Errorf("a.GetCacheObj(test) = %v, %t", got, ok)
+	}
+	if got, ok := b.GetCacheObj("test"); !ok || got != db {
+		t.Errorf("b.GetCacheObj(test) = %v, %t", got, ok)
+	}
+	if _, ok := GetCacheObj("test"); ok {
+		t.Errorf("default instance should not have test")
+	}
+	da.instance.unregister(da)
+	if _, ok := a.GetCacheObj("test"); ok || len(b.GetCacheObjs()) != 1 {
+		t.Errorf("unregister() should only remove from its instance")
+	}
+}
+
+//连接总是失败的数据库驱动,用于测试加载失败
+type failDriver struct{}
+
+func (failDriver) Open(name string) (driver.Conn, error) {
+	return nil, errors.New("connection refused")
+}
+
+func init() {
+	sql.Register("dbcache_fail", failDriver{})
+}
+
+//重新加载或新增的表加载失败时,原缓存表继续使用,不记录新的配置,下次检查时重试
+func TestReloadFailedLoad(t *testing.T) {
+	//不输出日志
+	logs.Flog, logs.Slog, logs.Elog = &logs.FileLog{}, &logs.StdoutLog{}, &logs.EmailLog{}
+	db, err := sql.Open("dbcache_fail", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fileName := filepath.Join(t.TempDir(), "cache.conf")
+	table := "[test]\ntable_name = test\ncolumns = id,age,price,score,name,create_date\npkey = id\ncache_type = slice\n"
+	if err = os.WriteFile(fileName, []byte(table+"other = order by age asc\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	in := NewInstance(db, "", fileName, t.TempDir())
+	old := newTestCache("slice", "order by age asc")
+	old.instance = in
+	old.dataAsync = NewDatAsync()
+	in.register(old)
+	w, err := in.NewConfWatcher(time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content := table + "other = order by age desc\n\n[test2]\ntable_name = test2\ncolumns = id\npkey = id\n"
+	if err = os.WriteFile(fileName, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err = w.reloadTables(); err == nil {
+			t.Fatalf("reloadTables() want error")
+		}
+		if got, ok := in.GetCacheObj("test"); !ok || got != old {
+			t.Fatalf("GetCacheObj(test) = %v, %t, want old cache", got, ok)
+		}
+		if err = old.beginWrite(); err != nil {
+			t.Fatalf("old cache closed: %s", err)
+		}
+		old.endWrite()
+		if w.tables["test"].Other != "order by age asc" {
+			t.Errorf("tables[test] = %+v, want old config", w.tables["test"])
+		}
+		if _, ok := w.tables["test2"]; ok {
+			t.Errorf("failed new table recorded")
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package cache
+
+import (
+	"dbcache/conf"
+	"sort"
+	"sync"
+)
+
+//多列排序.按排序键依次比较,每列有自己的排序方式,所有排序列都相同时按主键升序,保证排序是确定的.
+//每列按在数据库中的类型比较(见compare.go),类型未知时按字节比较.
+
+//排序方式
+type sortOrder struct {
+	columns  []string      //排序列
+	desc     []bool        //每列是否降序
+	kinds    []compareKind //每列的比较方式
+	pkeyKind compareKind   //主键的比较方式
+}
+
+//根据排序键新建排序方式.没有排序键时只按主键升序.
+func newSortOrder(keys []conf.SortKey) *sortOrder {
+	o := &sortOrder{
+		columns: make([]string, 0, len(keys)),
+		desc:    make([]bool, 0, len(keys)),
+		kinds:   make([]compareKind, 0, len(keys)),
+	}
+	for _, key := range keys {
+		o.columns = append(o.columns, key.Column)
+		o.desc = append(o.desc, key.Mode == "desc")
+		o.kinds = append(o.kinds, compareBytes)
+	}
+	return o
+}
+
+//根据数据库中列的类型,设置每列和主键的比较方式.需在排序之前调用.
+func (o *sortOrder) setColumnTypes(pkey string, columnInfo map[string]*columnInfo) {
+	for i, column := range o.columns {
+		if col, ok := columnInfo[column]; ok {
+			o.kinds[i] = getCompareKind(col.databaseTypeName)
+		}
+	}
+	if col, ok := columnInfo[pkey]; ok {
+		o.pkeyKind = getCompareKind(col.databaseTypeName)
+	}
+}
+
+//从行数据中取出各排序列的值.
+func (o *sortOrder) values(rowMap *sync.Map) []string {
+	values := make([]string, len(o.columns))
+	for i, column := range o.columns {
+		v, ok := rowMap.Load(column)
+		if ok {
+			values[i], _ = v.(string)
+		}
+	}
+	return values
+}
+
+//更新的列中是否有排序列.changes是更新的列表达式(列,=,值).
+func (o *sortOrder) hasColumn(changes [][]string) bool {
+	for _, condition := range changes {
+		for _, column := range o.columns {
+			if condition[0] == column {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+//按更新的列表达式计算新的排序列值,返回新值和排序列值是否改变.原切片不变.
+func (o *sortOrder) changeValues(values []string, changes [][]string) ([]string, bool) {
+	result := append([]string(nil), values...)
+	isChange := false
+	for _, condition := range changes {
+		for i, column := range o.columns {
+			if condition[0] == column && i < len(result) && result[i] != condition[2] {
+				result[i] = condition[2]
+				isChange = true
+			}
+		}
+	}
+	return result, isChange
+}
+
+//第一个排序列的值,用于兼容SliceCache.SortColumn
+func firstValue(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+//比较二行的排序,a排在b前面返回-1,相同返回0,a排在b后面返回1.
+func (o *sortOrder) compare(aValues []string, aPkey string, bValues []string, bPkey string) int {
+	for i := range o.columns {
+		var a, b string
+		if i < len(aValues) {
+			a = aValues[i]
+		}
+		if i < len(bValues) {
+			b = bValues[i]
+		}
+		result := compareValue(o.kinds[i], a, b)
+		if result == 0 {
+			continue
+		}
+		if o.desc[i] {
+			result = -result
+		}
+		return result
+	}
+	return compareValue(o.pkeyKind, aPkey, bPkey)
+}
+
+//切片中的行a是否排在行b前面
+func (o *sortOrder) less(a, b *SliceCache) bool {
+	return o.compare(a.SortValues, a.Pkey, b.SortValues, b.Pkey) < 0
+}
+
+//链表中的节点a是否排在节点b前面
+func (o *sortOrder) lessNode(a, b *Node) bool {
+	return o.compare(a.sortValues, a.pkey, b.sortValues, b.pkey) < 0
+}
+
+//对切片排序
+func (o *sortOrder) sortSlice(data []*SliceCache) []*SliceCache {
+	sort.Slice(data, func(i, j int) bool { return o.less(data[i], data[j]) })
+	return data
+}
+
+//二分查找行在有序切片中的插入位置(第一个不排在row前面的位置).
+func (o *sortOrder) searchSlice(data []*SliceCache, row *SliceCache) int {
+	return sort.Search(len(data), func(i int) bool { return !o.less(data[i], row) })
+}
+
+//游标分页中,行row是否在游标key的一侧:isAfter为true时row排在key后面,否则row排在key前面.key为nil时所有行都符合.
+func (o *sortOrder) isSeekRow(row *SliceCache, key *SliceCache, isAfter bool) bool {
+	if key == nil {
+		return true
+	}
+	if isAfter {
+		return o.less(key, row)
+	}
+	return o.less(row, key)
+}
+
+//在有序切片中二分查找游标位置.isAfter为true时取key之后最前面的limit行,否则取key之前最后面的limit行(都按正常顺序).
+//key为nil时从第一行或最后一行开始.hasOther表示另一侧(包括key)是否还有行.
+func (o *sortOrder) seekSlice(data []*SliceCache, key *SliceCache, isAfter bool, limit int) (rows []*SliceCache, hasOther bool) {
+	if isAfter {
+		start := 0
+		if key != nil {
+			start = sort.Search(len(data), func(i int) bool { return o.less(key, data[i]) })
+		}
+		return sliceBetween(data, start, start+limit), start > 0
+	}
+	end := len(data)
+	if key != nil {
+		end = o.searchSlice(data, key)
+	}
+	return sliceBetween(data, end-limit, end), end < len(data)
+}
+
+//遍历无序的行(rangeRows),取游标key一侧的limit行(按正常顺序).只保留limit行,不需要对所有行排序.
+//hasOther表示另一侧(包括key)是否还有行.
+func (o *sortOrder) seekUnsorted(rangeRows func(f func(row *SliceCache) bool), key *SliceCache, isAfter bool, limit int) (rows []*SliceCache, hasOther bool) {
+	rows = make([]*SliceCache, 0, limit+1)
+	rangeRows(func(row *SliceCache) bool {
+		if !o.isSeekRow(row, key, isAfter) {
+			hasOther = true
+			return true
+		}
+		rows = o.insertSlice(rows, row)
+		//超出limit行时,去掉离游标最远的一行
+		if len(rows) > limit {
+			if isAfter {
+				rows = rows[:limit]
+			} else {
+				rows = append(rows[:0], rows[1:]...)
+			}
+		}
+		return true
+	})
+	return rows, hasOther
+}
+
+//按排序插入一行到有序切片.
+func (o *sortOrder) insertSlice(data []*SliceCache, row *SliceCache) []*SliceCache {
+	i := o.searchSlice(data, row)
+	data = append(data, nil)
+	copy(data[i+1:], data[i:])
+	data[i] = row
+	return data
+}
+
+//归并二个有序切片.
+func (o *sortOrder) mergeSlice(one, two []*SliceCache) []*SliceCache {
+	result := make([]*SliceCache, 0, len(one)+len(two))
+	i, j := 0, 0
+	for i < len(one) && j < len(two) {
+		if o.less(two[j], one[i]) {
+			result = append(result, two[j])
+			j++
+		} else {
+			result = append(result, one[i])
+			i++
+		}
+	}
+	result = append(result, one[i:]...)
+	result = append(result, two[j:]...)
+	return result
+}
+
+//对链表节点排序
+func (o *sortOrder) sortNodes(nodes []*Node) {
+	sort.Slice(nodes, func(i, j int) bool { return o.lessNode(nodes[i], nodes[j]) })
+}
+
+//根据数据库中列的类型,设置分页缓存和命名视图的比较方式.
+func (d *DBcache) initSortTypes() {
+	pkey := d.TableConfig.GetPkey()
+	d.sortOrder.setColumnTypes(pkey, d.ColumnInfo)
+	for _, c := range d.views {
+		c.order.setColumnTypes(pkey, d.ColumnInfo)
+	}
+}
+
+//二分查找主键在切片缓存中的行号,排序列值从主缓存中取.找不到时返回-1.
+func (d *DBcache) searchRowNum(pkeyValue string) int {
+	v, ok := d.DbCache.Load(pkeyValue)
+	if !ok {
+		return -1
+	}
+	rowMap := v.(*sync.Map)
+	row := &SliceCache{Pkey: pkeyValue, SortValues: d.sortOrder.values(rowMap)}
+	i := d.sortOrder.searchSlice(d.SliceDbCache, row)
+	if i < len(d.SliceDbCache) && d.SliceDbCache[i].Pkey == pkeyValue {
+		return i
+	}
+	return -1
+}
@@ -0,0 +1,287 @@
+package cache
+
+import (
+	"dbcache/conf"
+	"dbcache/dialect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+//按类型比较二个值
+func TestCompareValue(t *testing.T) {
+	tests := []struct {
+		kind compareKind
+		a, b string
+		want int
+	}{
+		//整型
+		{compareDecimal, "9", "100", -1},
+		{compareDecimal, "100", "9", 1},
+		{compareDecimal, "-10", "-9", -1},
+		{compareDecimal, "-1", "1", -1},
+		{compareDecimal, "007", "7", 0},
+		{compareDecimal, "18446744073709551615", "18446744073709551614", 1},
+		{compareDecimal, "", "0", -1},
+		//定点数
+		{compareDecimal, "9.99", "10.00", -1},
+		{compareDecimal, "0.5", "0.45", 1},
+		{compareDecimal, "1.50", "1.5", 0},
+		{compareDecimal, "-0.00", "0", 0},
+		{compareDecimal, "-1.5", "-1.25", -1},
+		//浮点数
+		{compareFloat, "9.5", "10", -1},
+		{compareFloat, "1e3", "999.9", 1},
+		{compareFloat, "-2.5", "-10", 1},
+		{compareFloat, "abc", "1", -1},
+		//日期,日期时间,时间戳
+		{compareDatetime, "2020-02-09", "2020-02-10", -1},
+		{compareDatetime, "2020-02-15 09:00:00", "2020-02-15 10:00:00", -1},
+		{compareDatetime, "2020-02-15 10:00:00.5", "2020-02-15 10:00:00", 1},
+		{compareDatetime, "2020-02-15T10:24:45+08:00", "2020-02-15T03:24:45+01:00", 0},
+		{compareDatetime, "2020-02-15T10:24:45+08:00", "2020-02-15T10:24:45+09:00", 1},
+		{compareDatetime, "0000-00-00 00:00:00", "2020-01-01 00:00:00", -1},
+		//时间
+		{compareTime, "9:00:00", "10:00:00", -1},
+		{compareTime, "100:00:00", "23:59:59", 1},
+		{compareTime, "-01:00:00", "00:00:00", -1},
+		{compareTime, "10:30", "10:30:00", 0},
+		//字符串,不区分大小写
+		{compareText, "apple", "Banana", -1},
+		{compareText, "ABC", "abc", 0},
+		{compareText, "abd", "ABC", 1},
+		//字节
+		{compareBytes, "B", "a", -1},
+		{compareBytes, "100", "9", -1},
+	}
+	for _, test := range tests {
+		got := compareValue(test.kind, test.a, test.b)
+		if got != test.want {
+			t.Errorf("compareValue(%d, %q, %q) = %d, want %d", test.kind, test.a, test.b, got, test.want)
+		}
+	}
+}
+
+//根据数据库类型获取比较方式
+func TestGetCompareKind(t *testing.T) {
+	tests := map[string]compareKind{
+		"INT":             compareDecimal,
+		"UNSIGNED BIGINT": compareDecimal,
+		"DECIMAL":         compareDecimal,
+		"YEAR":            compareDecimal,
+		"DOUBLE":          compareFloat,
+		"FLOAT":           compareFloat,
+		"DATETIME":        compareDatetime,
+		"TIMESTAMP":       compareDatetime,
+		"DATE":            compareDatetime,
+		"TIME":            compareTime,
+		"VARCHAR":         compareText,
+		"char":            compareText,
+		"VARBINARY":       compareBytes,
+		"":                compareBytes,
+	}
+	for typeName, want := range tests {
+		if got := getCompareKind(typeName); got != want {
+			t.Errorf("getCompareKind(%q) = %d, want %d", typeName, got, want)
+		}
+	}
+}
+
+//测试用的缓存表:id(INT),age(INT),price(DECIMAL),score(DOUBLE),name(VARCHAR),create_date(DATETIME)
+func newTestCache(cacheType string, other string) *DBcache {
+	d := &DBcache{
+		name: "test",
+		TableConfig: conf.CacheTable{
+			TableName: "test",
+			Columns:   "id,age,price,score,name,create_date",
+			Pkey:      "id",
+			Other:     other,
+			CacheType: cacheType,
+		},
+		LinkDbCache: NewLinkCache(),
+		DelRowNum:   make(map[int]bool),
+		instance:    defaultInstance,
+		dialect:     dialect.Default,
+		ColumnInfo: map[string]*columnInfo{
+			"id":          {columnName: "id", databaseTypeName: "INT"},
+			"age":         {columnName: "age", databaseTypeName: "INT"},
+			"price":       {columnName: "price", databaseTypeName: "DECIMAL"},
+			"score":       {columnName: "score", databaseTypeName: "DOUBLE"},
+			"name":        {columnName: "name", databaseTypeName: "VARCHAR"},
+			"create_date": {columnName: "create_date", databaseTypeName: "DATETIME"},
+		},
+	}
+	d.sortOrder = newSortOrder(d.TableConfig.GetSortKeys())
+	d.pageStore = d.newPageStore(cacheType)
+	d.initSortTypes()
+	return d
+}
+
+//插入一行到主缓存和分页缓存
+func insertTestRow(d *DBcache, row map[string]string) {
+	rowMap := new(sync.Map)
+	for k, v := range row {
+		rowMap.Store(k, v)
+	}
+	d.DbCache.Store(row["id"], rowMap)
+	d.insertPageCache(row["id"], rowMap)
+}
+
+//获取分页缓存中所有行的主键值
+func testPkeys(d *DBcache) string {
+	var pkeys []string
+	for _, row := range d.GetRowBetween(0, 100) {
+		pkeys = append(pkeys, row["id"])
+	}
+	return strings.Join(pkeys, ",")
+}
+
+//切片和链表,逐行插入和批量插入,都按列的类型排序
+func TestSortOrderByType(t *testing.T) {
+	rows := []map[string]string{
+		{"id": "1", "age": "9", "price": "100.00", "score": "1e2", "name": "bob", "create_date": "2020-02-15 10:00:00"},
+		{"id": "2", "age": "10", "price": "9.50", "score": "9.5", "name": "Alice", "create_date": "2020-02-09 10:00:00"},
+		{"id": "10", "age": "100", "price": "-3", "score": "-20", "name": "carl", "create_date": "2021-01-01 00:00:00"},
+		{"id": "20", "age": "9", "price": "9.5", "score": "30", "name": "ALICE", "create_date": "2020-02-15 09:00:00"},
+	}
+	tests := []struct {
+		other string
+		want  string
+	}{
+		{"order by age desc", "10,2,1,20"},
+		{"order by age asc", "1,20,2,10"},
+		{"order by price asc", "10,2,20,1"},
+		{"order by score desc", "1,20,2,10"},
+		{"order by name asc", "2,20,1,10"},
+		{"order by create_date asc", "2,20,1,10"},
+		{"order by age asc, price desc", "1,20,2,10"},
+		{"", "1,2,10,20"},
+	}
+	for _, cacheType := range []string{"slice", "link", "tree"} {
+		for _, test := range tests {
+			//逐行插入
+			d := newTestCache(cacheType, test.other)
+			for _, row := range rows {
+				insertTestRow(d, row)
+			}
+			if got := testPkeys(d); got != test.want {
+				t.Errorf("%s %q insertPageCache: got %s, want %s", cacheType, test.other, got, test.want)
+			}
+			//批量插入
+			d = newTestCache(cacheType, test.other)
+			items := make([]*SliceCache, 0, len(rows))
+			for _, row := range rows {
+				rowMap := new(sync.Map)
+				for k, v := range row {
+					rowMap.Store(k, v)
+				}
+				items = append(items, &SliceCache{Pkey: row["id"], RowMap: rowMap})
+			}
+			d.insertCaches(items)
+			if got := testPkeys(d); got != test.want {
+				t.Errorf("%s %q insertCaches: got %s, want %s", cacheType, test.other, got, test.want)
+			}
+		}
+	}
+}
+
+//链表按类型比较排序列值插入节点:数值"100"排在"9"后面,值相同时按主键.
+func TestLinkCacheOrder(t *testing.T) {
+	d := newTestCache("link", "order by price desc")
+	link := NewLinkCache()
+	node := func(pkey string, price string) *Node {
+		n := NewNode(0, pkey, price, nil)
+		n.sortValues = []string{price}
+		return n
+	}
+	pkeys := func() string {
+		var result []string
+		link.RangeNode(func(n *Node) bool {
+			result = append(result, n.pkey)
+			return true
+		})
+		return strings.Join(result, ",")
+	}
+	link.InsertNodeOrder(node("1", "9"), d.sortOrder.lessNode)
+	link.InsertNodeOrder(node("2", "100"), d.sortOrder.lessNode)
+	link.InsertNodeOrder(node("3", "9.50"), d.sortOrder.lessNode)
+	if got := pkeys(); got != "2,3,1" {
+		t.Errorf("InsertNodeOrder: got %s, want 2,3,1", got)
+	}
+	link.InsertNodesOrder([]*Node{node("5", "9"), node("4", "-1"), node("6", "1000")}, d.sortOrder.lessNode)
+	if got := pkeys(); got != "6,2,3,1,5,4" || link.GetLength() != 6 {
+		t.Errorf("InsertNodesOrder: got %s length %d, want 6,2,3,1,5,4", got, link.GetLength())
+	}
+	link.UpdateNodeOrder("4", func(n *Node) bool {
+		n.sortValues = []string{"99.9"}
+		return true
+	}, d.sortOrder.lessNode)
+	if got := pkeys(); got != "6,2,4,3,1,5" {
+		t.Errorf("UpdateNodeOrder: got %s, want 6,2,4,3,1,5", got)
+	}
+}
+
+//加载时排序,二分查找行号
+func TestSortSliceAndRowNum(t *testing.T) {
+	d := newTestCache("slice", "order by age desc")
+	for i, age := range []string{"8", "12", "9", "100", "11"} {
+		rowMap := new(sync.Map)
+		pkey := string(rune('1' + i))
+		rowMap.Store("id", pkey)
+		rowMap.Store("age", age)
+		d.DbCache.Store(pkey, rowMap)
+		sortValues := d.sortOrder.values(rowMap)
+		d.SliceDbCache = append(d.SliceDbCache, &SliceCache{Pkey: pkey, SortColumn: firstValue(sortValues), SortValues: sortValues, RowMap: rowMap})
+	}
+	d.SliceDbCache = d.sortOrder.sortSlice(d.SliceDbCache)
+	if got := testPkeys(d); got != "4,2,5,3,1" {
+		t.Errorf("sortSlice: got %s, want 4,2,5,3,1", got)
+	}
+	for i, pkey := range []string{"4", "2", "5", "3", "1"} {
+		if got := d.GetRowNum(pkey); got != i {
+			t.Errorf("GetRowNum(%s) = %d, want %d", pkey, got, i)
+		}
+	}
+	if got := d.GetRowNum("9"); got != -1 {
+		t.Errorf("GetRowNum(9) = %d, want -1", got)
+	}
+}
+
+//更新排序列后,分页缓存中的行移到新的位置
+func TestUpdatePageCache(t *testing.T) {
+	rows := []map[string]string{
+		{"id": "1", "age": "10", "price": "5.00"},
+		{"id": "2", "age": "20", "price": "9.50"},
+		{"id": "3", "age": "30", "price": "20.00"},
+		{"id": "4", "age": "40", "price": "100.00"},
+	}
+	tests := []struct {
+		changes [][]string
+		want    string
+	}{
+		{[][]string{{"price", "=", "200"}}, "2,3,4,1"},
+		{[][]string{{"age", "=", "1"}}, "2,3,4,1"},
+		{[][]string{{"price", "=", "1"}, {"age", "=", "2"}}, "1,2,3,4"},
+		{[][]string{{"price", "=", "9.5"}}, "2,1,3,4"},
+	}
+	for _, cacheType := range []string{"slice", "link", "tree"} {
+		d := newTestCache(cacheType, "order by price asc, age desc")
+		for _, row := range rows {
+			insertTestRow(d, row)
+		}
+		for _, test := range tests {
+			v, _ := d.DbCache.Load("1")
+			rowMap := v.(*sync.Map)
+			for _, condition := range test.changes {
+				rowMap.Store(condition[0], condition[2])
+			}
+			d.updatePageCache("1", test.changes)
+			if got := testPkeys(d); got != test.want {
+				t.Errorf("%s %v: got %s, want %s", cacheType, test.changes, got, test.want)
+			}
+			if got := d.GetRowRank("1"); got != strings.Index(strings.Replace(test.want, ",", "", -1), "1") {
+				t.Errorf("%s %v: GetRowRank(1) = %d", cacheType, test.changes, got)
+			}
+		}
+	}
+}
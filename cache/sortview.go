@@ -0,0 +1,347 @@
+package cache
+
+import (
+	"dbcache/conf"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+)
+
+//排序视图.所有分页查询(GetRowBetween,GetOnePageRows,GetWhereOnePageRows,GetPageAfter等)都基于排序视图.
+//默认视图是表配置的分页缓存(cache_type,按other排序).命名视图在cache.conf的sort_views中配置,
+//每个命名视图按自己的排序列保存一个有序切片,插入,更新,删除行时增量维护.
+
+//分页数据源,默认视图和命名视图都实现该接口.
+type pageSource interface {
+	getSortOrder() *sortOrder                              //排序方式
+	getRowCount() int                                      //总行数
+	rowsBetween(start int, end int) []map[string]string    //获取开始行到结束行(不包括结束行)的数据
+	page(bounds func(total int) (start int, end int)) ([]map[string]string, int) //在一次加锁中取总行数及按bounds(总行数)计算的行的数据
+	rangeRows(f func(key pageKey, rowMap *sync.Map) bool) //按排序顺序遍历每一行,f返回false时停止
+	seekRows(key *SliceCache, isAfter bool, limit int) ([]*SliceCache, bool) //游标分页,取游标一侧的limit行,见PageStore.Seek
+}
+
+//排序视图,用于分页查询.
+type SortView struct {
+	Name string //视图名,默认视图为空
+	d    *DBcache
+	src  pageSource
+}
+
+//根据视图名获取排序视图.name为空时,返回表的默认视图(cache_type).
+func (d *DBcache) View(name string) (view *SortView, err error) {
+	if name == "" {
+		return d.tableView(), nil
+	}
+	c, ok := d.views[name]
+	if !ok {
+		return nil, fmt.Errorf("View(),排序视图不存在: %s", name)
+	}
+	return &SortView{Name: name, d: d, src: c}, nil
+}
+
+//获取所有命名视图的视图名.
+func (d *DBcache) ViewNames() (names []string) {
+	for name := range d.views {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+//表的默认视图
+func (d *DBcache) tableView() *SortView {
+	return &SortView{d: d, src: tablePage{d}}
+}
+
+//(该函数仅于分页显示,提取数据)从视图中,获取指定的行,开始行-结束行.(不包括结束行)
+func (v *SortView) GetRowBetween(start int, end int) (result []map[string]string) {
+	return v.src.rowsBetween(start, end)
+}
+
+//用于分页,获取总页数.pageSize参数是每页行数大小
+func (v *SortView) GetPageCount(pageSize int) (result int) {
+	if pageSize <= 0 {
+		return 0
+	}
+	result = int(math.Ceil(float64(v.src.getRowCount()) / float64(pageSize)))
+	return result
+}
+
+//用于分页,根据指定开始页,获取多少页,每页行数.返回数据.参数说明:startPage,开始页,pageNum多少页,pageSize参数是每页行数大小
+//开始页小于1时为第1页,大于总页数时为最后一页.总行数和数据在同一次加锁中取得.
+func (v *SortView) GetMultipageRows(startPage int, pageNum int, pageSize int) (result []map[string]string) {
+	if pageSize <= 0 || pageNum <= 0 {
+		return nil
+	}
+	result, _ = v.src.page(func(total int) (start int, end int) {
+		page := startPage
+		if pageCount := whereCountPage(total, pageSize); page > pageCount {
+			page = pageCount
+		}
+		if page < 1 {
+			page = 1
+		}
+		start = (page - 1) * pageSize
+		return start, start + pageNum*pageSize
+	})
+	return result
+}
+
+//用于分页,根据页码和每页行数大小,返回数据.参数说明:page参数是页码,pageSize参数是每页行数大小
+func (v *SortView) GetOnePageRows(page int, pageSize int) (result []map[string]string) {
+	return v.GetMultipageRows(page, 1, pageSize)
+}
+
+//默认视图:表配置的分页缓存(slice,sliceNotDel,link)
+type tablePage struct {
+	d *DBcache
+}
+
+func (t tablePage) getSortOrder() *sortOrder { return t.d.sortOrder }
+func (t tablePage) getRowCount() int         { return int(t.d.pageStore.Len()) }
+func (t tablePage) rowsBetween(start int, end int) []map[string]string {
+	return t.d.GetRowBetween(start, end)
+}
+func (t tablePage) page(bounds func(total int) (start int, end int)) (result []map[string]string, total int) {
+	rows, total := t.d.pageStore.Page(bounds)
+	for _, row := range rows {
+		result = append(result, rowMapToMap(row.RowMap))
+	}
+	return result, total
+}
+func (t tablePage) rangeRows(f func(key pageKey, rowMap *sync.Map) bool) {
+	t.d.rangeSortRows(f)
+}
+func (t tablePage) seekRows(key *SliceCache, isAfter bool, limit int) ([]*SliceCache, bool) {
+	return t.d.pageStore.Seek(key, isAfter, limit)
+}
+
+//命名视图:按排序列保存的有序切片.行按(排序列值...,主键值)排序.
+type viewCache struct {
+	name  string                 //视图名
+	order *sortOrder             //排序方式
+	rows  []*SliceCache          //有序的行
+	index map[string]*SliceCache //主键值 -> 行
+	mutex sync.RWMutex           //读写锁
+}
+
+//新建命名视图
+func newViewCache(view conf.SortView) *viewCache {
+	return &viewCache{
+		name:  view.Name,
+		order: newSortOrder(view.Keys),
+		rows:  make([]*SliceCache, 0),
+		index: make(map[string]*SliceCache),
+	}
+}
+
+func (c *viewCache) getSortOrder() *sortOrder { return c.order }
+
+func (c *viewCache) getRowCount() int {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return len(c.rows)
+}
+
+func (c *viewCache) rowsBetween(start int, end int) (result []map[string]string) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	if start < 0 {
+		start = 0
+	}
+	if end > len(c.rows) {
+		end = len(c.rows)
+	}
+	for i := start; i < end; i++ {
+		result = append(result, rowMapToMap(c.rows[i].RowMap))
+	}
+	return result
+}
+
+func (c *viewCache) page(bounds func(total int) (start int, end int)) (result []map[string]string, total int) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	total = len(c.rows)
+	start, end := bounds(total)
+	if start < 0 {
+		start = 0
+	}
+	if end > total {
+		end = total
+	}
+	for i := start; i < end; i++ {
+		result = append(result, rowMapToMap(c.rows[i].RowMap))
+	}
+	return result, total
+}
+
+func (c *viewCache) rangeRows(f func(key pageKey, rowMap *sync.Map) bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	for _, row := range c.rows {
+		if !f(pageKey{row.SortValues, row.Pkey}, row.RowMap) {
+			return
+		}
+	}
+}
+
+func (c *viewCache) seekRows(key *SliceCache, isAfter bool, limit int) ([]*SliceCache, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.order.seekSlice(c.rows, key, isAfter, limit)
+}
+
+//生成该视图中的一行,排序列值从行数据中取.
+func (c *viewCache) newRow(pkey string, rowMap *sync.Map) *SliceCache {
+	sortValues := c.order.values(rowMap)
+	return &SliceCache{Pkey: pkey, SortColumn: firstValue(sortValues), SortValues: sortValues, RowMap: rowMap}
+}
+
+//加载数据时追加一行,不排序.全部加载后调用sortRows()排序.
+func (c *viewCache) appendRow(pkey string, rowMap *sync.Map) {
+	row := c.newRow(pkey, rowMap)
+	c.rows = append(c.rows, row)
+	c.index[pkey] = row
+}
+
+//对所有行排序.
+func (c *viewCache) sortRows() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.rows = c.order.sortSlice(c.rows)
+}
+
+//插入多行.主键已存在时,先删除原来的行.
+func (c *viewCache) insertRows(items []*SliceCache) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	rows := make([]*SliceCache, 0, len(items))
+	for _, item := range items {
+		if _, ok := c.index[item.Pkey]; ok {
+			c.removeRow(item.Pkey)
+		}
+		row := c.newRow(item.Pkey, item.RowMap)
+		c.index[item.Pkey] = row
+		rows = append(rows, row)
+	}
+	//只有一行时,二分查找插入位置.多行时,先排序,再与原切片归并.
+	if len(rows) == 1 {
+		c.rows = c.order.insertSlice(c.rows, rows[0])
+		return
+	}
+	c.rows = c.order.mergeSlice(c.rows, c.order.sortSlice(rows))
+}
+
+//删除多行.
+func (c *viewCache) deleteRows(pkeys map[string]bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	//只有一行时,二分查找删除位置.多行时,过滤掉删除的行.
+	if len(pkeys) == 1 {
+		for pkey := range pkeys {
+			c.removeRow(pkey)
+		}
+		return
+	}
+	result := c.rows[:0]
+	for _, row := range c.rows {
+		if pkeys[row.Pkey] {
+			delete(c.index, row.Pkey)
+			continue
+		}
+		result = append(result, row)
+	}
+	//清除尾部引用,便于回收.
+	for i := len(result); i < len(c.rows); i++ {
+		c.rows[i] = nil
+	}
+	c.rows = result
+}
+
+//排序列的值改变时,移动该行到新的位置.changes是更新的列表达式(列,=,值).
+func (c *viewCache) updateRow(pkey string, changes [][]string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	row, ok := c.index[pkey]
+	if !ok {
+		return
+	}
+	sortValues, isChange := c.order.changeValues(row.SortValues, changes)
+	if isChange == false {
+		return
+	}
+	c.removeRow(pkey)
+	row = &SliceCache{Pkey: pkey, SortColumn: firstValue(sortValues), SortValues: sortValues, RowMap: row.RowMap}
+	c.index[pkey] = row
+	c.rows = c.order.insertSlice(c.rows, row)
+}
+
+//根据主键删除一行.调用者需持有写锁.
+func (c *viewCache) removeRow(pkey string) {
+	row, ok := c.index[pkey]
+	if !ok {
+		return
+	}
+	delete(c.index, pkey)
+	i := c.order.searchSlice(c.rows, row)
+	if i < len(c.rows) && c.rows[i].Pkey == pkey {
+		copy(c.rows[i:], c.rows[i+1:])
+		c.rows[len(c.rows)-1] = nil
+		c.rows = c.rows[:len(c.rows)-1]
+	}
+}
+
+//根据配置文件,初始化命名视图.视图的排序列必须是缓存的列.
+func (d *DBcache) initViews() (err error) {
+	views, err := d.TableConfig.GetSortViews()
+	if err != nil {
+		return fmt.Errorf("initViews(),err: %s", err)
+	}
+	columns := d.TableConfig.GetColumns()
+	d.views = make(map[string]*viewCache, len(views))
+	for _, view := range views {
+		for _, key := range view.Keys {
+			isExist := false
+			for _, column := range columns {
+				if key.Column == column {
+					isExist = true
+					break
+				}
+			}
+			if isExist == false {
+				return fmt.Errorf("initViews(),排序视图[%s]的排序列未缓存: %s", view.Name, key.Column)
+			}
+		}
+		d.views[view.Name] = newViewCache(view)
+	}
+	return nil
+}
+
+//插入行到所有命名视图.
+func (d *DBcache) insertViews(items []*SliceCache) {
+	if len(items) == 0 {
+		return
+	}
+	for _, c := range d.views {
+		c.insertRows(items)
+	}
+}
+
+//从所有命名视图中删除行.
+func (d *DBcache) deleteViews(pkeys map[string]bool) {
+	if len(pkeys) == 0 {
+		return
+	}
+	for _, c := range d.views {
+		c.deleteRows(pkeys)
+	}
+}
+
+//更新行时,如果更新了视图的排序列,移动该行到新的位置.changes是更新的列表达式(列,=,值).
+func (d *DBcache) updateViews(pkey string, changes [][]string) {
+	for _, c := range d.views {
+		c.updateRow(pkey, changes)
+	}
+}
@@ -0,0 +1,122 @@
+package cache
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+//同一个表的二个命名视图,插入,更新,删除行后各自按自己的排序列保持有序.
+func TestSortViews(t *testing.T) {
+	d := newTestCache("slice", "")
+	d.TableConfig.SortViews = "agev:age asc,namev:name desc"
+	if err := d.initViews(); err != nil {
+		t.Fatalf("initViews(): %s", err)
+	}
+	d.initSortTypes()
+	if names := strings.Join(d.ViewNames(), ","); names != "agev,namev" {
+		t.Fatalf("ViewNames() = %s, want agev,namev", names)
+	}
+	if _, err := d.View("none"); err == nil {
+		t.Errorf("View(none): want error")
+	}
+	check := func(step string, wantAge string, wantName string) {
+		t.Helper()
+		for name, want := range map[string]string{"agev": wantAge, "namev": wantName} {
+			v, err := d.View(name)
+			if err != nil {
+				t.Fatalf("View(%s): %s", name, err)
+			}
+			var ids []string
+			for _, row := range v.GetRowBetween(0, 100) {
+				ids = append(ids, row["id"])
+			}
+			if got := strings.Join(ids, ","); got != want {
+				t.Errorf("%s: view %s = %s, want %s", step, name, got, want)
+			}
+		}
+	}
+	newItem := func(id string, age string, name string) *SliceCache {
+		rowMap := new(sync.Map)
+		rowMap.Store("id", id)
+		rowMap.Store("age", age)
+		rowMap.Store("name", name)
+		return &SliceCache{Pkey: id, RowMap: rowMap}
+	}
+	//逐行插入,再一次插入多行
+	insertTestRow(d, map[string]string{"id": "1", "age": "30", "name": "a"})
+	insertTestRow(d, map[string]string{"id": "2", "age": "10", "name": "d"})
+	insertTestRow(d, map[string]string{"id": "3", "age": "20", "name": "c"})
+	insertTestRow(d, map[string]string{"id": "4", "age": "20", "name": "b"})
+	d.insertViews([]*SliceCache{newItem("5", "40", "e"), newItem("6", "5", "f")})
+	check("insertViews", "6,2,3,4,1,5", "6,5,2,3,4,1")
+	//更新一个视图的排序列,只移动该视图中的行
+	d.updateViews("4", [][]string{{"age", "=", "50"}})
+	check("updateViews age", "6,2,3,1,5,4", "6,5,2,3,4,1")
+	d.updateViews("1", [][]string{{"name", "=", "z"}})
+	check("updateViews name", "6,2,3,1,5,4", "1,6,5,2,3,4")
+	d.updateViews("3", [][]string{{"score", "=", "1"}})
+	check("updateViews other column", "6,2,3,1,5,4", "1,6,5,2,3,4")
+	//插入已存在的主键,替换原来的行
+	d.insertViews([]*SliceCache{newItem("2", "60", "d")})
+	check("insertViews existing", "6,3,1,5,4,2", "1,6,5,2,3,4")
+	//删除一行,再一次删除多行
+	d.deleteViews(map[string]bool{"3": true})
+	check("deleteViews one", "6,1,5,4,2", "1,6,5,2,4")
+	d.deleteViews(map[string]bool{"6": true, "5": true})
+	check("deleteViews many", "1,4,2", "1,2,4")
+}
+
+//按页码取数据:页与页之间不重复,页码超出范围时取第1页或最后一页.
+func TestViewPageRows(t *testing.T) {
+	d := newTestCache("slice", "order by age asc")
+	d.TableConfig.SortViews = "agev:age desc"
+	if err := d.initViews(); err != nil {
+		t.Fatalf("initViews(): %s", err)
+	}
+	d.initSortTypes()
+	for i := 1; i <= 7; i++ {
+		insertTestRow(d, map[string]string{"id": fmt.Sprint(i), "age": fmt.Sprint(i)})
+	}
+	ids := func(rows []map[string]string) string {
+		var pkeys []string
+		for _, row := range rows {
+			pkeys = append(pkeys, row["id"])
+		}
+		return strings.Join(pkeys, ",")
+	}
+	tests := []struct {
+		view      string
+		startPage int
+		pageNum   int
+		want      string
+	}{
+		{"", 1, 1, "1,2,3"},
+		{"", 2, 1, "4,5,6"},
+		{"", 3, 1, "7"},
+		{"", 0, 1, "1,2,3"},
+		{"", 100, 1, "7"},
+		{"", 2, 2, "4,5,6,7"},
+		{"", 1, 0, ""},
+		{"agev", 1, 1, "7,6,5"},
+		{"agev", 2, 1, "4,3,2"},
+		{"agev", 3, 1, "1"},
+		{"agev", 1, 2, "7,6,5,4,3,2"},
+	}
+	for _, test := range tests {
+		v, err := d.View(test.view)
+		if err != nil {
+			t.Fatalf("View(%q): %s", test.view, err)
+		}
+		if got := ids(v.GetMultipageRows(test.startPage, test.pageNum, 3)); got != test.want {
+			t.Errorf("view %q GetMultipageRows(%d, %d, 3) = %s, want %s", test.view, test.startPage, test.pageNum, got, test.want)
+		}
+		if test.pageNum != 1 {
+			continue
+		}
+		if got := ids(v.GetOnePageRows(test.startPage, 3)); got != test.want {
+			t.Errorf("view %q GetOnePageRows(%d, 3) = %s, want %s", test.view, test.startPage, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,337 @@
+package cache
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+//顺序统计树缓存[用于页面分页显示].用treap(树堆)实现:按排序方式是二叉查找树,按随机优先级是堆,期望高度O(log n).
+//每个节点记录子树的行数,所以插入,删除,按排序位置取行,取行的排序位置都是O(log n).
+
+//树节点
+type treeNode struct {
+	row      *SliceCache //行数据
+	priority uint32      //随机优先级,父节点不小于子节点
+	size     int         //子树的行数
+	left     *treeNode
+	right    *treeNode
+}
+
+//顺序统计树
+type TreeCache struct {
+	root  *treeNode
+	index map[string]*SliceCache      //主键值 -> 行
+	less  func(a, b *SliceCache) bool //a是否排在b前面
+	mutex sync.RWMutex                //读写锁
+}
+
+//新建顺序统计树,less(a,b)为true时a排在b前面.
+func NewTreeCache(less func(a, b *SliceCache) bool) *TreeCache {
+	return &TreeCache{
+		index: make(map[string]*SliceCache),
+		less:  less,
+	}
+}
+
+func treeSize(n *treeNode) int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+//重新计算子树的行数
+func (n *treeNode) update() {
+	n.size = 1 + treeSize(n.left) + treeSize(n.right)
+}
+
+//把树分成二棵:左边的行都排在row前面,右边的行都不排在row前面.
+func (t *TreeCache) split(n *treeNode, row *SliceCache) (left, right *treeNode) {
+	if n == nil {
+		return nil, nil
+	}
+	if t.less(n.row, row) {
+		n.right, right = t.split(n.right, row)
+		n.update()
+		return n, right
+	}
+	left, n.left = t.split(n.left, row)
+	n.update()
+	return left, n
+}
+
+//合并二棵树,a中的行都排在b前面.
+func treeMerge(a, b *treeNode) *treeNode {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if a.priority > b.priority {
+		a.right = treeMerge(a.right, b)
+		a.update()
+		return a
+	}
+	b.left = treeMerge(a, b.left)
+	b.update()
+	return b
+}
+
+//从树中删除行row(按指针比较).
+func (t *TreeCache) remove(n *treeNode, row *SliceCache) *treeNode {
+	if n == nil {
+		return nil
+	}
+	if n.row == row {
+		return treeMerge(n.left, n.right)
+	}
+	if t.less(row, n.row) {
+		n.left = t.remove(n.left, row)
+	} else {
+		n.right = t.remove(n.right, row)
+	}
+	n.update()
+	return n
+}
+
+//加载数据,rows是全部的行(未排序).排序后用栈在O(n)内建树.
+func (t *TreeCache) Load(rows []*SliceCache) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.root = nil
+	t.index = make(map[string]*SliceCache, len(rows))
+	sort.Slice(rows, func(i, j int) bool { return t.less(rows[i], rows[j]) })
+	//按排序顺序加入节点,栈中保存最右边的一条路径
+	stack := make([]*treeNode, 0, 64)
+	for _, row := range rows {
+		node := &treeNode{row: row, priority: rand.Uint32(), size: 1}
+		var last *treeNode
+		for len(stack) > 0 && stack[len(stack)-1].priority < node.priority {
+			last = stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+		}
+		node.left = last
+		if len(stack) > 0 {
+			stack[len(stack)-1].right = node
+		}
+		stack = append(stack, node)
+		t.index[row.Pkey] = row
+	}
+	if len(stack) > 0 {
+		t.root = stack[0]
+	}
+	treeUpdateAll(t.root)
+}
+
+//重新计算所有节点的子树行数
+func treeUpdateAll(n *treeNode) {
+	if n == nil {
+		return
+	}
+	treeUpdateAll(n.left)
+	treeUpdateAll(n.right)
+	n.update()
+}
+
+//插入多行.主键已存在时,先删除原来的行.返回插入的行数.
+func (t *TreeCache) Insert(rows []*SliceCache) (n int64) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for _, row := range rows {
+		if old, ok := t.index[row.Pkey]; ok {
+			t.root = t.remove(t.root, old)
+			n--
+		}
+		left, right := t.split(t.root, row)
+		node := &treeNode{row: row, priority: rand.Uint32(), size: 1}
+		t.root = treeMerge(treeMerge(left, node), right)
+		t.index[row.Pkey] = row
+		n++
+	}
+	return n
+}
+
+//根据主键删除多行,返回删除的行数.
+func (t *TreeCache) Delete(pkeys map[string]bool) (n int64) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for pkey := range pkeys {
+		row, ok := t.index[pkey]
+		if !ok {
+			continue
+		}
+		t.root = t.remove(t.root, row)
+		delete(t.index, pkey)
+		n++
+	}
+	return n
+}
+
+//用update(原行)返回的新行替换原行,并移到新的排序位置.update返回nil时不做改变.返回是否移动了该行.
+func (t *TreeCache) Update(pkey string, update func(old *SliceCache) *SliceCache) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	old, ok := t.index[pkey]
+	if !ok {
+		return false
+	}
+	row := update(old)
+	if row == nil {
+		return false
+	}
+	t.root = t.remove(t.root, old)
+	left, right := t.split(t.root, row)
+	node := &treeNode{row: row, priority: rand.Uint32(), size: 1}
+	t.root = treeMerge(treeMerge(left, node), right)
+	t.index[pkey] = row
+	return true
+}
+
+//总行数
+func (t *TreeCache) Len() int64 {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	return int64(treeSize(t.root))
+}
+
+//获取排序位置在开始行到结束行(不包括结束行)的行.
+func (t *TreeCache) Between(start int, end int) (rows []*SliceCache) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	if start < 0 {
+		start = 0
+	}
+	if end > treeSize(t.root) {
+		end = treeSize(t.root)
+	}
+	if start >= end {
+		return nil
+	}
+	rows = make([]*SliceCache, 0, end-start)
+	treeBetween(t.root, 0, start, end, &rows)
+	return rows
+}
+
+//在一次加锁中取总行数,及按bounds(总行数)计算的开始行到结束行(不包括结束行)的行.
+func (t *TreeCache) Page(bounds func(total int) (start int, end int)) (rows []*SliceCache, total int) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	total = treeSize(t.root)
+	start, end := bounds(total)
+	if start < 0 {
+		start = 0
+	}
+	if end > total {
+		end = total
+	}
+	if start >= end {
+		return nil, total
+	}
+	rows = make([]*SliceCache, 0, end-start)
+	treeBetween(t.root, 0, start, end, &rows)
+	return rows, total
+}
+
+//中序遍历子树n,只进入与[start,end)有交集的子树.offset是子树中第一行的排序位置.
+func treeBetween(n *treeNode, offset int, start int, end int, rows *[]*SliceCache) {
+	if n == nil || offset >= end || offset+n.size <= start {
+		return
+	}
+	treeBetween(n.left, offset, start, end, rows)
+	i := offset + treeSize(n.left)
+	if i >= start && i < end {
+		*rows = append(*rows, n.row)
+	}
+	treeBetween(n.right, i+1, start, end, rows)
+}
+
+//获取行的排序位置(从0开始),不存在返回-1.
+func (t *TreeCache) Rank(pkey string) int {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	row, ok := t.index[pkey]
+	if !ok {
+		return -1
+	}
+	rank := 0
+	for n := t.root; n != nil; {
+		if n.row == row {
+			return rank + treeSize(n.left)
+		}
+		if t.less(row, n.row) {
+			n = n.left
+		} else {
+			rank += treeSize(n.left) + 1
+			n = n.right
+		}
+	}
+	return -1
+}
+
+//游标分页:isAfter为true时取key之后最前面的limit行,否则取key之前最后面的limit行(都按正常顺序).
+//key为nil时从第一行或最后一行开始.hasOther表示另一侧(包括key)是否还有行.从根向下查找游标的排序位置,O(log n).
+func (t *TreeCache) Seek(key *SliceCache, isAfter bool, limit int) (rows []*SliceCache, hasOther bool) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	total := treeSize(t.root)
+	//pos:isAfter为true时是第一个排在key后面的行的排序位置,否则是第一个不排在key前面的行的排序位置.
+	pos := total
+	if key == nil && isAfter {
+		pos = 0
+	}
+	if key != nil {
+		pos = 0
+		for n := t.root; n != nil; {
+			isLeft := !t.less(n.row, key)
+			if isAfter {
+				isLeft = t.less(key, n.row)
+			}
+			if isLeft {
+				n = n.left
+			} else {
+				pos += treeSize(n.left) + 1
+				n = n.right
+			}
+		}
+	}
+	start, end := pos, pos+limit
+	hasOther = pos > 0
+	if !isAfter {
+		start, end = pos-limit, pos
+		hasOther = pos < total
+	}
+	if start < 0 {
+		start = 0
+	}
+	if end > total {
+		end = total
+	}
+	if start >= end {
+		return nil, hasOther
+	}
+	rows = make([]*SliceCache, 0, end-start)
+	treeBetween(t.root, 0, start, end, &rows)
+	return rows, hasOther
+}
+
+//按排序遍历每一行,f返回false时停止遍历.
+func (t *TreeCache) Range(f func(row *SliceCache) bool) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	stack := make([]*treeNode, 0, 64)
+	n := t.root
+	for n != nil || len(stack) > 0 {
+		for n != nil {
+			stack = append(stack, n)
+			n = n.left
+		}
+		n = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if !f(n.row) {
+			return
+		}
+		n = n.right
+	}
+}
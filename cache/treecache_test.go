@@ -0,0 +1,113 @@
+package cache
+
+import (
+	"math/rand"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+//测试用的行,排序列是score
+func newTreeTestRow(pkey int, score int) *SliceCache {
+	values := []string{strconv.Itoa(score)}
+	return &SliceCache{Pkey: strconv.Itoa(pkey), SortColumn: values[0], SortValues: values, RowMap: new(sync.Map)}
+}
+
+//随机插入,删除,与有序切片比较排序位置和按位置取的行.
+func TestTreeCache(t *testing.T) {
+	order := &sortOrder{columns: []string{"score"}, desc: []bool{true}, kinds: []compareKind{compareDecimal}, pkeyKind: compareDecimal}
+	tree := NewTreeCache(order.less)
+	r := rand.New(rand.NewSource(1))
+
+	//加载
+	rows := make(map[string]*SliceCache)
+	load := make([]*SliceCache, 0, 500)
+	for i := 0; i < 500; i++ {
+		row := newTreeTestRow(i, r.Intn(100))
+		rows[row.Pkey] = row
+		load = append(load, row)
+	}
+	tree.Load(load)
+
+	check := func(step int) {
+		want := make([]*SliceCache, 0, len(rows))
+		for _, row := range rows {
+			want = append(want, row)
+		}
+		sort.Slice(want, func(i, j int) bool { return order.less(want[i], want[j]) })
+		if got := tree.Len(); got != int64(len(want)) {
+			t.Fatalf("step %d: Len() = %d, want %d", step, got, len(want))
+		}
+		for i, row := range want {
+			if got := tree.Rank(row.Pkey); got != i {
+				t.Fatalf("step %d: Rank(%s) = %d, want %d", step, row.Pkey, got, i)
+			}
+		}
+		start, end := r.Intn(len(want)+1), r.Intn(len(want)+10)
+		between := tree.Between(start, end)
+		var wantBetween []*SliceCache
+		if start < end && start < len(want) {
+			if end > len(want) {
+				end = len(want)
+			}
+			wantBetween = want[start:end]
+		}
+		if len(between) != len(wantBetween) {
+			t.Fatalf("step %d: Between(%d, %d) len = %d, want %d", step, start, end, len(between), len(wantBetween))
+		}
+		for i := range between {
+			if between[i] != wantBetween[i] {
+				t.Fatalf("step %d: Between(%d, %d)[%d] = %s, want %s", step, start, end, i, between[i].Pkey, wantBetween[i].Pkey)
+			}
+		}
+		i := 0
+		tree.Range(func(row *SliceCache) bool {
+			if row != want[i] {
+				t.Fatalf("step %d: Range()[%d] = %s, want %s", step, i, row.Pkey, want[i].Pkey)
+			}
+			i++
+			return true
+		})
+	}
+	check(0)
+
+	for step := 1; step <= 200; step++ {
+		switch r.Intn(3) {
+		case 0: //插入一行,主键可能已存在
+			row := newTreeTestRow(r.Intn(800), r.Intn(100))
+			_, isExist := rows[row.Pkey]
+			n := tree.Insert([]*SliceCache{row})
+			if isExist && n != 0 || !isExist && n != 1 {
+				t.Fatalf("step %d: Insert() = %d, isExist %v", step, n, isExist)
+			}
+			rows[row.Pkey] = row
+		case 1: //批量插入
+			items := make([]*SliceCache, 0, 5)
+			for i := 0; i < 5; i++ {
+				row := newTreeTestRow(800+step*5+i, r.Intn(100))
+				rows[row.Pkey] = row
+				items = append(items, row)
+			}
+			tree.Insert(items)
+		case 2: //删除几行,可能不存在
+			pkeys := make(map[string]bool)
+			var want int64
+			for i := 0; i < 3; i++ {
+				pkey := strconv.Itoa(r.Intn(1800))
+				if _, ok := rows[pkey]; ok && !pkeys[pkey] {
+					want++
+				}
+				pkeys[pkey] = true
+				delete(rows, pkey)
+			}
+			if n := tree.Delete(pkeys); n != want {
+				t.Fatalf("step %d: Delete() = %d, want %d", step, n, want)
+			}
+		}
+		check(step)
+	}
+	if tree.Rank("not exist") != -1 {
+		t.Errorf("Rank(not exist) != -1")
+	}
+}
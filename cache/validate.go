@@ -0,0 +1,109 @@
+package cache
+
+import (
+	"database/sql"
+	"dbcache/conf"
+	"dbcache/dialect"
+	"sort"
+	"strings"
+)
+
+//检查数据库中的缓存表(dbcache validate):表和columns中的列是否存在,主键是否唯一,where和other是否能执行.
+//配置文件本身的检查见conf.ValidateTables().
+
+//用实例的数据库对象检查实例的cache.conf中所有表在数据库中的结构,检查结果记录在实例的cache.conf下.
+//tables是conf.ValidateTables()返回的表配置(分段名 -> 表配置),按分段名排序检查.
+func (in *Instance) ValidateDB(r *conf.Report, tables map[string]conf.CacheTable) {
+	names := make([]string, 0, len(tables))
+	for name := range tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		table := tables[name]
+		validateTableDB(in.db, r, in.TablesConf(), name, &table)
+	}
+}
+
+//检查一个表在数据库中的结构,fileName是cache.conf,name是分段名.
+func validateTableDB(db *sql.DB, r *conf.Report, fileName string, name string, table *conf.CacheTable) {
+	errors := r.Count(conf.LEVEL_ERROR)
+	tableName := table.GetTableName()
+	dbColumns, err := getTableColumns(db, tableName)
+	if err != nil {
+		r.Error(fileName, name, "", "查询数据库表结构失败, err: %s", err)
+		return
+	}
+	if len(dbColumns) == 0 {
+		r.Error(fileName, name, "table_name", "数据库中不存在表[%s]", tableName)
+		return
+	}
+	for _, column := range table.GetColumns() {
+		if column != "" && !dbColumns[strings.ToLower(column)] {
+			r.Error(fileName, name, "columns", "数据库表中不存在列[%s]", column)
+		}
+	}
+	if !dbColumns[strings.ToLower(table.GetPkey())] {
+		r.Error(fileName, name, "pkey", "数据库表中不存在主键列[%s]", table.GetPkey())
+	} else {
+		validatePkey(db, r, fileName, name, table)
+	}
+	//where和other按加载时的sql执行,不取数据
+	where := "1=0"
+	if table.GetWhere() != "" {
+		where = "(" + table.GetWhere() + ") and 1=0"
+	}
+	rows, err := db.Query(selectColumnsSql(dialect.Of(db), table) + " where " + where + " " + table.GetOther())
+	if err != nil {
+		r.Error(fileName, name, "where", "加载数据的sql执行失败(检查where和other), err: %s", err)
+	} else {
+		rows.Close()
+	}
+	if r.Count(conf.LEVEL_ERROR) == errors {
+		r.Ok(fileName, name, "数据库表结构检查通过")
+	}
+}
+
+//获取数据库中表的所有列名(小写).表不存在时返回空.
+func getTableColumns(db *sql.DB, tableName string) (columns map[string]bool, err error) {
+	dbColumns, err := dialect.Of(db).Columns(db, tableName)
+	if err != nil {
+		return nil, err
+	}
+	columns = make(map[string]bool, len(dbColumns))
+	for _, column := range dbColumns {
+		columns[strings.ToLower(column.Name)] = true
+	}
+	return columns, nil
+}
+
+//检查主键是否唯一:有只包含主键列的唯一索引时通过;否则检查现有数据是否有重复值或空值.fileName是cache.conf,name是分段名.
+func validatePkey(db *sql.DB, r *conf.Report, fileName string, name string, table *conf.CacheTable) {
+	tableName, pkey := table.GetTableName(), table.GetPkey()
+	d := dialect.Of(db)
+	indexes, err := d.UniqueIndexes(db, tableName)
+	if err != nil {
+		r.Error(fileName, name, "pkey", "查询数据库表索引失败, err: %s", err)
+		return
+	}
+	for _, columns := range indexes {
+		if len(columns) == 1 && strings.EqualFold(columns[0], pkey) {
+			return
+		}
+	}
+	//没有唯一索引,检查现有数据
+	var count, notNull, distinct int64
+	err = db.QueryRow("select count(1), count(" + d.Quote(pkey) + "), count(distinct " + d.Quote(pkey) + ") from " + d.Quote(tableName)).Scan(&count, &notNull, &distinct)
+	if err != nil {
+		r.Error(fileName, name, "pkey", "检查主键[%s]是否唯一失败, err: %s", pkey, err)
+		return
+	}
+	switch {
+	case notNull != count:
+		r.Error(fileName, name, "pkey", "主键[%s]有%d行空值", pkey, count-notNull)
+	case distinct != count:
+		r.Error(fileName, name, "pkey", "主键[%s]有重复值(%d行,%d个不同的值)", pkey, count, distinct)
+	default:
+		r.Warning(fileName, name, "pkey", "主键[%s]没有唯一索引,现有数据没有重复值,但不能保证以后插入的数据唯一", pkey)
+	}
+}
@@ -176,14 +176,15 @@ func isRegExists(regexpStr, data string) bool {
 }
 
 //AES128解密
-func DecryptAES128(data, key []byte) ([]byte, error) {
+func DecryptAES128(data, key []byte) (result []byte, err error) {
 	if len(key) < 1 {
 		err := fmt.Errorf("Not a AES(128) Key")
 		return nil, err
 	}
 	defer func() {
-		if err := recover(); err != nil {
-			fmt.Println("DecryptAES128 panic:", err)
+		if e := recover(); e != nil {
+			fmt.Println("DecryptAES128 panic:", e)
+			result, err = nil, fmt.Errorf("DecryptAES128 panic: %v", e)
 		}
 	}()
 	block, err := aes.NewCipher(key)
@@ -191,10 +192,18 @@ func DecryptAES128(data, key []byte) ([]byte, error) {
 		return nil, err
 	}
 	blockSize := block.BlockSize()
+	//密文长度必须是块大小的整数倍
+	if len(data) == 0 || len(data)%blockSize != 0 {
+		return nil, fmt.Errorf("DecryptAES128(),密文长度错误: %d", len(data))
+	}
 
 	blockMode := cipher.NewCBCDecrypter(block, key[:blockSize])
-	result := make([]byte, len(data))
+	result = make([]byte, len(data))
 	blockMode.CryptBlocks(result, data)
+	//补码错误时,密钥不对或密文已损坏
+	if padding := int(result[len(result)-1]); padding < 1 || padding > blockSize {
+		return nil, fmt.Errorf("DecryptAES128(),解密失败,密钥错误或密文已损坏")
+	}
 	result = PKCS7UnPadding(result)
 	return result, nil
 }
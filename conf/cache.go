@@ -1,6 +1,12 @@
 package conf
 
-import "strings"
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
 
 //数据库异步同步.
 type DataAsync struct {
@@ -9,33 +15,256 @@ type DataAsync struct {
 	AsyncFileName       string `conf:"async_file_name"`        //异步保存需要更新的SQL语句文件名
 	AsyncFailedFileName string `conf:"async_failed_file_name"` //异步保存失败的需要更新的SQL语句文件名
 	MaxAsyncFileSize    int64  `conf:"max_async_file_size"`    //异步保存需要更新的SQL语句文件和失败,单个文件最大大小
+	AsyncWorkers        int    `conf:"async_workers"`          //异步同步协程数,按主键分片,同一主键按顺序执行.不配置默认1个
+}
+
+//获取异步同步协程数,未配置或小于1时,默认1个.
+func (d *DataAsync) GetAsyncWorkers() int {
+	if d.AsyncWorkers < 1 {
+		return 1
+	}
+	return d.AsyncWorkers
 }
 
 //缓存的表配置
 type CacheTable struct {
-	TableName         string `conf:"table_name"`          //缓存的表名
-	Columns           string `conf:"columns"`             //缓存的多列,以分号隔开
-	Pkey              string `conf:"pkey"`                //缓存表的主键
-	Where             string `conf:"where"`               //缓存表取数据时,加的where条件.
-	Other             string `conf:"other"`               //缓存表取数据时,按排序条件.在运行中,插入数据也是按此排序.
-	PkeyAutoIncrement bool   `conf:"pkey_auto_increment"` //缓存表主键是否为自增列
-	CacheType         string `conf:"cache_type"`          //用于分页查询,缓存类型:一.slice切片(按orther里排序),二.sliceNotDel切片(不删除,只记录,速度最快,但后插入数据未排序),三.link链表(按orther里排序)
-	IsRealtime        bool   `conf:"is_realtime"`         //缓存表是否实时同步更新,true:实时更新,false:异步更新.
-	IsWaitResult      bool   `conf:"is_wait_result"`      //缓存表在异步更新时,是否等待返回结果(上面条件是is_realtime = false时)
-}
-
-func (c *CacheTable) GetPkey() string                      { return c.Pkey }
-func (c *CacheTable) GetTableName() string                 { return c.TableName }
-func (c *CacheTable) GetWhere() string                     { return c.Where }
-func (c *CacheTable) GetOther() string                     { return c.Other }
-func (c *CacheTable) GetColumn() string                    { return c.Columns }
-func (c *CacheTable) PkeyIsIncrement() bool                { return c.PkeyAutoIncrement }
-func (c *CacheTable) GetCacheType() string                 { return c.CacheType }
-func (c *CacheTable) GetIsRealtime() bool                  { return c.IsRealtime }
-func (c *CacheTable) GetColumns() (columns []string)       { return getColumns(c.Columns) }
-func (c *CacheTable) GetSortColumn() (sortColumn string)   { return getSortColumn(c.Other, c.Pkey) }
-func (c *CacheTable) GetSortMode() (sortMode string)       { return getSortMode(c.Other) }
-func (c *CacheTable) GetIsWaitResult() (isWaitResult bool) { return c.IsWaitResult }
+	TableName         string  `conf:"table_name"`          //缓存的表名
+	Columns           string  `conf:"columns"`             //缓存的多列,以分号隔开
+	Pkey              string  `conf:"pkey"`                //缓存表的主键
+	Where             string  `conf:"where"`               //缓存表取数据时,加的where条件.
+	Other             string  `conf:"other"`               //缓存表取数据时,按排序条件.在运行中,插入数据也是按此排序.
+	PkeyAutoIncrement bool    `conf:"pkey_auto_increment"` //缓存表主键是否为自增列
+	CacheType         string  `conf:"cache_type"`          //用于分页查询,缓存类型:一.slice切片(按orther里排序),二.sliceNotDel切片(不删除,只记录,速度最快,但后插入数据未排序),三.link链表(按orther里排序),四.tree顺序统计树(按orther里排序,插入删除和分页都是O(log n))
+	IsRealtime        bool    `conf:"is_realtime"`         //缓存表是否实时同步更新,true:实时更新,false:异步更新.
+	IsWaitResult      bool    `conf:"is_wait_result"`      //缓存表在异步更新时,是否等待返回结果(上面条件是is_realtime = false时)
+	SortViews         string  `conf:"sort_views"`          //命名排序视图,多个以逗号隔开,格式:视图名:排序列 asc|desc [排序列 asc|desc].例如:price:price asc,date:create_date desc goods_id asc
+	CompactInterval   int     `conf:"compact_interval"`    //(缓存类型是sliceNotDel)后台检查是否需要整理切片的间隔(秒),不配置默认3600
+	CompactHours      string  `conf:"compact_hours"`       //(缓存类型是sliceNotDel)允许后台整理的时间段,格式:开始时-结束时,例如:1-5,22-4.不配置默认1-5
+	CompactMaxDeleted int     `conf:"compact_max_deleted"` //(缓存类型是sliceNotDel)已删除和追加(未排序)的行数超过此值时整理,不配置默认10000
+	CompactRatio      float64 `conf:"compact_ratio"`       //(缓存类型是sliceNotDel)已删除和追加(未排序)的行数占切片的比例超过此值时整理,不配置默认0.33
+	HiddenColumns     string  `conf:"hidden_columns"`      //隐藏的列,以逗号隔开.rpc和grpc不返回,where条件中不能使用
+	MaskedColumns     string  `conf:"masked_columns"`      //脱敏的列,以逗号隔开,格式:列名[:显示末尾字符数],不写默认4.例如:phone:4,id_card:6,email
+	ReadonlyColumns   string  `conf:"readonly_columns"`    //只读的列,以逗号隔开.rpc和grpc不能更新(插入时可以写入)
+}
+
+//排序键,order by中的一列及排序方式.
+type SortKey struct {
+	Column string //排序列
+	Mode   string //排序方式:asc,desc
+}
+
+//命名排序视图,用于分页查询时按不同的列排序.
+type SortView struct {
+	Name string    //视图名
+	Keys []SortKey //排序键,按顺序比较,全部相同时按主键升序
+}
+
+func (c *CacheTable) GetPkey() string                        { return c.Pkey }
+func (c *CacheTable) GetTableName() string                   { return c.TableName }
+func (c *CacheTable) GetWhere() string                       { return c.Where }
+func (c *CacheTable) GetOther() string                       { return c.Other }
+func (c *CacheTable) GetColumn() string                      { return c.Columns }
+func (c *CacheTable) PkeyIsIncrement() bool                  { return c.PkeyAutoIncrement }
+func (c *CacheTable) GetCacheType() string                   { return c.CacheType }
+func (c *CacheTable) GetIsRealtime() bool                    { return c.IsRealtime }
+func (c *CacheTable) GetColumns() (columns []string)         { return getColumns(c.Columns) }
+func (c *CacheTable) GetSortColumn() (sortColumn string)     { return getSortColumn(c.Other, c.Pkey) }
+func (c *CacheTable) GetSortMode() (sortMode string)         { return getSortMode(c.Other) }
+func (c *CacheTable) GetSortKeys() (sortKeys []SortKey)      { return getSortKeys(c.Other) }
+func (c *CacheTable) GetIsWaitResult() (isWaitResult bool)   { return c.IsWaitResult }
+func (c *CacheTable) GetHiddenColumns() (columns []string)   { return getColumns(c.HiddenColumns) }
+func (c *CacheTable) GetReadonlyColumns() (columns []string) { return getColumns(c.ReadonlyColumns) }
+
+//获取脱敏的列,列名 -> 显示末尾字符数.
+func (c *CacheTable) GetMaskedColumns() (masks map[string]int, err error) {
+	return getMaskedColumns(c.MaskedColumns)
+}
+
+//获取后台检查整理切片的间隔,未配置或小于1秒时,默认3600秒.
+func (c *CacheTable) GetCompactInterval() time.Duration {
+	if c.CompactInterval < 1 {
+		return time.Second * 3600
+	}
+	return time.Second * time.Duration(c.CompactInterval)
+}
+
+//获取已删除行数的整理阈值,未配置或小于1时,默认10000.
+func (c *CacheTable) GetCompactMaxDeleted() int {
+	if c.CompactMaxDeleted < 1 {
+		return 10000
+	}
+	return c.CompactMaxDeleted
+}
+
+//获取已删除行数占切片比例的整理阈值,未配置或不在(0,1]之间时,默认0.33.
+func (c *CacheTable) GetCompactRatio() float64 {
+	if c.CompactRatio <= 0 || c.CompactRatio > 1 {
+		return 0.33
+	}
+	return c.CompactRatio
+}
+
+//获取允许后台整理的时间段(开始时,结束时,包括结束时).未配置或格式错误时,默认1点到5点.
+//开始时大于结束时表示跨过0点,例如22-4.
+func (c *CacheTable) GetCompactHours() (start int, end int) {
+	start, end, err := getHourRange(c.CompactHours)
+	if err != nil {
+		return 1, 5
+	}
+	return start, end
+}
+
+//解析时间段,格式:开始时-结束时,小时在0到23之间.
+func getHourRange(hours string) (start int, end int, err error) {
+	i := strings.Index(hours, "-")
+	if i == -1 {
+		return 0, 0, fmt.Errorf("getHourRange(),时间段格式错误: %s", hours)
+	}
+	start, err = strconv.Atoi(strings.TrimSpace(hours[:i]))
+	if err != nil {
+		return 0, 0, fmt.Errorf("getHourRange(),时间段格式错误: %s, err: %s", hours, err)
+	}
+	end, err = strconv.Atoi(strings.TrimSpace(hours[i+1:]))
+	if err != nil {
+		return 0, 0, fmt.Errorf("getHourRange(),时间段格式错误: %s, err: %s", hours, err)
+	}
+	if start < 0 || start > 23 || end < 0 || end > 23 {
+		return 0, 0, fmt.Errorf("getHourRange(),小时需在0到23之间: %s", hours)
+	}
+	return start, end, nil
+}
+
+//读取cache.conf(fileName)中所有缓存表的配置,按分段名(缓存表的名称)保存.
+func GetCacheTables(fileName string) (tables map[string]CacheTable, err error) {
+	names, err := GetCacheTableSections(fileName)
+	if err != nil {
+		return nil, err
+	}
+	tables = make(map[string]CacheTable, len(names))
+	for _, name := range names {
+		table := CacheTable{}
+		err = ParseConfTable(fileName, name, &table)
+		if err != nil {
+			return nil, fmt.Errorf("GetCacheTables(),读取表[%s]的配置失败, err: %s", name, err)
+		}
+		tables[name] = table
+	}
+	return tables, nil
+}
+
+//在cache.conf(fileName)末尾增加表的配置,分段名是name(缓存表的名称),为空时是表名.
+//表配置有错误或分段已存在时返回错误.同一个表可以用不同的分段名增加多次(别名).只支持INI格式(见editor.go).
+func AddCacheTable(fileName string, name string, table CacheTable) (err error) {
+	if name == "" {
+		name = table.TableName
+	}
+	if err = checkCacheTable(name, &table); err != nil {
+		return fmt.Errorf("AddCacheTable(),%s", err)
+	}
+	return EditConf(fileName, func(e *ConfEditor) error {
+		if e.HasSection(name) {
+			return fmt.Errorf("AddCacheTable(),分段[%s]已存在", name)
+		}
+		return e.SetSection(name, &table)
+	})
+}
+
+//把表的配置格式化为cache.conf(INI格式)的一个分段,分段名是name,为空时是表名.表配置有错误时返回错误.
+func FormatCacheTable(name string, table CacheTable) (section string, err error) {
+	if name == "" {
+		name = table.TableName
+	}
+	if err = checkCacheTable(name, &table); err != nil {
+		return "", fmt.Errorf("FormatCacheTable(),%s", err)
+	}
+	e := &ConfEditor{newline: "\n"}
+	if err = e.SetSection(name, &table); err != nil {
+		return "", fmt.Errorf("FormatCacheTable(), err: %s", err)
+	}
+	return e.String(), nil
+}
+
+//检查表的配置,有错误时返回所有错误(不含文件名).
+func checkCacheTable(name string, table *CacheTable) error {
+	r := &Report{}
+	if validateTable(r, "", name, table) {
+		return nil
+	}
+	var errs []string
+	for _, p := range r.Problems {
+		if p.Level == LEVEL_ERROR {
+			errs = append(errs, p.Key+": "+p.Message)
+		}
+	}
+	return fmt.Errorf("表[%s]配置错误: %s", name, strings.Join(errs, "; "))
+}
+
+//从cache.conf(fileName)中删除表的配置(分段名为name的分段及其前面的注释),返回是否存在.
+func RemoveCacheTable(fileName string, name string) (ok bool, err error) {
+	err = EditConf(fileName, func(e *ConfEditor) error {
+		ok = e.DeleteSection(name)
+		return nil
+	})
+	return ok, err
+}
+
+//比较二个表配置,返回值不同的配置项(conf标签名),按结构体字段顺序.
+func DiffCacheTable(old CacheTable, new CacheTable) (keys []string) {
+	oldValue := reflect.ValueOf(old)
+	newValue := reflect.ValueOf(new)
+	t := oldValue.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if !reflect.DeepEqual(oldValue.Field(i).Interface(), newValue.Field(i).Interface()) {
+			keys = append(keys, t.Field(i).Tag.Get("conf"))
+		}
+	}
+	return keys
+}
+
+//获取配置项的值,用于记录配置的改变.key是conf标签名.
+func (c *CacheTable) GetConfValue(key string) string {
+	v := reflect.ValueOf(c).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Tag.Get("conf") == key {
+			return fmt.Sprint(v.Field(i).Interface())
+		}
+	}
+	return ""
+}
+
+//获取命名排序视图配置.
+func (c *CacheTable) GetSortViews() (views []SortView, err error) {
+	return getSortViews(c.SortViews)
+}
+
+//解析命名排序视图.格式:视图名:排序列 asc|desc [排序列 asc|desc],多个以逗号隔开.排序方式不写时默认asc.
+func getSortViews(viewStr string) (views []SortView, err error) {
+	isName := make(map[string]bool)
+	for _, v := range getColumns(viewStr) {
+		if v == "" {
+			continue
+		}
+		i := strings.Index(v, ":")
+		if i == -1 {
+			return nil, fmt.Errorf("getSortViews(),排序视图格式错误: %s", v)
+		}
+		name := strings.TrimSpace(v[:i])
+		keys, err := getSortKeyList(strings.Fields(v[i+1:]))
+		if err != nil {
+			return nil, fmt.Errorf("getSortViews(),排序视图[%s]格式错误, err: %s", name, err)
+		}
+		if name == "" || len(keys) == 0 {
+			return nil, fmt.Errorf("getSortViews(),排序视图格式错误: %s", v)
+		}
+		if isName[name] {
+			return nil, fmt.Errorf("getSortViews(),排序视图名重复: %s", name)
+		}
+		isName[name] = true
+		views = append(views, SortView{Name: name, Keys: keys})
+	}
+	return views, nil
+}
 
 //根据以逗号分割的列字符串,转换为切片.
 func getColumns(columnStr string) (columns []string) {
@@ -49,74 +278,87 @@ func getColumns(columnStr string) (columns []string) {
 	return columns
 }
 
-//获取排序字段和排序方式
-func getSortColumn(orther string, pkey string) (sortColumn string) {
-	if orther == "" {
-		return pkey
-	}
-	slices := strings.Split(orther, " ")
-	if len(slices) == 0 {
-		return pkey
-	}
-	var isOrder, isBy bool
-	for i, v := range slices {
-		v = strings.ToLower(strings.TrimSpace(v))
-		if v == "order" {
-			isOrder = true
+//解析脱敏的列,格式:列名[:显示末尾字符数],多个以逗号隔开,不写显示字符数时默认4.
+func getMaskedColumns(maskStr string) (masks map[string]int, err error) {
+	masks = make(map[string]int)
+	for _, item := range getColumns(maskStr) {
+		column, keep := item, "4"
+		if i := strings.Index(item, ":"); i != -1 {
+			column, keep = strings.TrimSpace(item[:i]), strings.TrimSpace(item[i+1:])
 		}
-		if v == "by" {
-			isBy = true
+		if column == "" {
+			return nil, fmt.Errorf("getMaskedColumns(),脱敏的列名为空: %s", maskStr)
 		}
-		if i > 0 {
-			if slices[i-1] == "by" {
-				sortColumn = v
-			}
+		n, err := strconv.Atoi(keep)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("getMaskedColumns(),列[%s]的显示字符数应为不小于0的整数: %s", column, keep)
 		}
+		masks[column] = n
 	}
-	if sortColumn == "" {
-		return pkey
-	}
-	if isOrder && isBy {
-		return sortColumn
-	} else {
+	return masks, nil
+}
+
+//获取排序字段,多列排序时返回第一列.没有order by时返回主键.
+func getSortColumn(orther string, pkey string) (sortColumn string) {
+	keys := getSortKeys(orther)
+	if len(keys) == 0 {
 		return pkey
 	}
+	return keys[0].Column
 }
 
-//获取排序字段和排序方式
+//获取排序方式,多列排序时返回第一列的排序方式.没有order by时返回空.
 func getSortMode(orther string) (sortMode string) {
-	if orther == "" {
+	keys := getSortKeys(orther)
+	if len(keys) == 0 {
 		return ""
 	}
-	slices := strings.Split(orther, " ")
-	if len(slices) == 0 {
-		return ""
-	}
-	var isOrder, isBy, isDesc, isAsc bool
-	for _, v := range slices {
-		v = strings.ToLower(strings.TrimSpace(v))
-		if v == "order" {
-			isOrder = true
-		}
-		if v == "by" {
-			isBy = true
-		}
-		if v == "asc" {
-			isAsc = true
+	return keys[0].Mode
+}
+
+//获取order by中所有的排序键.例如:order by type_id asc, price desc limit 100,返回[{type_id asc} {price desc}]
+func getSortKeys(orther string) (keys []SortKey) {
+	keys, _ = parseSortKeys(orther)
+	return keys
+}
+
+//解析order by中的排序键,格式错误时返回错误.没有order by时返回nil.
+func parseSortKeys(orther string) (keys []SortKey, err error) {
+	words := strings.Fields(strings.ToLower(strings.Replace(orther, ",", " , ", -1)))
+	for i := 0; i+1 < len(words); i++ {
+		if words[i] != "order" || words[i+1] != "by" {
+			continue
 		}
-		if v == "desc" {
-			isDesc = true
+		var list []string
+		for _, w := range words[i+2:] {
+			//order by后面的其它子句
+			if w == "limit" || w == "offset" || w == "for" || w == "lock" {
+				break
+			}
+			if w != "," {
+				list = append(list, w)
+			}
 		}
-
-	}
-	if isOrder && isBy && isAsc {
-		return "asc"
+		return getSortKeyList(list)
 	}
-	if isOrder && isBy && isDesc {
-		return "desc"
-	}
-	if isOrder && isBy && !isAsc && !isDesc {
-		return "asc"
+	return nil, nil
+}
+
+//根据单词列表解析排序键:每个排序列后面可跟asc或desc,不写时默认asc.
+func getSortKeyList(words []string) (keys []SortKey, err error) {
+	hasMode := false
+	for _, w := range words {
+		mode := strings.ToLower(w)
+		if mode == "asc" || mode == "desc" {
+			if len(keys) == 0 || hasMode {
+				return keys, fmt.Errorf("getSortKeyList(),排序方式前面没有排序列: %s", w)
+			}
+			keys[len(keys)-1].Mode = mode
+			hasMode = true
+			continue
+		}
+		keys = append(keys, SortKey{Column: w, Mode: "asc"})
+		hasMode = false
 	}
-	return ""
+	return keys, nil
 }
@@ -2,105 +2,69 @@ package conf
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
-	"os"
+	"io/ioutil"
 	"reflect"
-	"strconv"
 	"strings"
 )
 
-const (
+//默认的配置文件路径,按扩展名选择格式(见format.go),例如改为./config.yaml,./cache.toml.需在初始化日志和缓存之前修改.
+//日志,包级的缓存函数(cache.NewDBcache等)和配置检查使用默认路径.同一进程中运行多个实例时,
+//每个实例使用自己的配置文件(见server.Options),不需修改默认路径.
+var (
 	CONFIG_FILE string = `./config.conf` //配置文件
 	TABLES_CONF string = `./cache.conf`  //需要缓存的表信息
+)
+
+const (
 	TABLE_FIELD_NAME string = `table_name`   //配置表中,表名字段名
 )
 
 func GetCacheTable()(result []string,err error){
-	result, err = ParseConfField(TABLES_CONF, TABLE_FIELD_NAME)
+	return GetCacheTableNames(TABLES_CONF)
+}
+
+//获取配置文件中缓存的所有表名
+func GetCacheTableNames(fileName string)(result []string,err error){
+	result, err = ParseConfField(fileName, TABLE_FIELD_NAME)
 	return
 }
 
-//从配置文件中,取出指定指段的所有值
-func ParseConfField(fileName string,fieldName string ) (result []string,err error){
-	//打开文件
-	var index int = 0
-	f, err := os.OpenFile(fileName, os.O_RDONLY, 0)
+//获取配置文件中所有缓存表的分段名(配置了table_name的分段),按顺序.
+//分段名是缓存表对象的名称(rpc,grpc按此名称访问,不区分大小写),同一个表可以配置多个分段(别名),例如不同的where.
+func GetCacheTableSections(fileName string)(result []string,err error){
+	sections, err := LoadConf(fileName)
 	if err != nil {
-		err = fmt.Errorf("打开配置文件[%s]失败, err: %s\n", fileName, err)
-		return nil,err
+		return nil, err
 	}
-	defer f.Close()
-	fileScanner := bufio.NewScanner(f)
-	for fileScanner.Scan() {
-		index++
-		line := fileScanner.Text()
-		//去除字符串首尾的空白
-		line = strings.TrimSpace(line)
-		// 以#或;开头视为注释,空行和注释不读取
-		if line == "" {
-			continue
-		}
-		if strings.HasPrefix(line, "#") {
-			continue
-		}
-		if strings.HasPrefix(line, ";") {
-			continue
-		}
-		//检查是否前缀是[,后缀是]的分组,并取出group组名称.
-		if len(line) > 2 && line[0:1] == "[" && line[len(line)-1:] == "]" {
-			continue
-		}
-		//判断是不是具体配置项,判断是不是有等号.
-		equalIndex := strings.Index(line, "=")
-		if equalIndex == -1 {
-			err = fmt.Errorf("第%行语法错误:%w\n", index, err)
-			return nil,err
-		}
-		//按照等号=分割,左边是KEY,右边是VALUE
-		key := line[:equalIndex]
-		value := line[equalIndex+1:]
-		key = strings.TrimSpace(key)
-		value = strings.TrimSpace(value)
-		if len(key) == 0 {
-			err = fmt.Errorf("第%行语法错误:%w\n", index, err)
-			return nil,err
-		}
-		if key == fieldName{
-			result=append(result,value)
+	for _, section := range sections {
+		if _, ok := section.Values[TABLE_FIELD_NAME]; ok {
+			result = append(result, section.Name)
 		}
 	}
 	return result,nil
 }
-//从配置文件中,取出数据,保存于结构体.
-func ParseConfTable(fileName string,groupName string, result interface{}) (err error) {
-	t := reflect.TypeOf(result)
-	v := reflect.ValueOf(result)
-	//Elem()是获取引用类型指针的指向的对象,如果是值类型不需要。
-	tElem := t.Elem()
-	vElem := v.Elem()
-	//result必须是一个指针
-	if t.Kind() != reflect.Ptr {
-		fmt.Println("必须是一个指针")
-		return
-	}
-	//result必须是一个结构体,并且结构名与配置文件中分段[]名要一样.
-	if tElem.Kind() != reflect.Struct {
-		fmt.Println("必须是一个结构体")
-		return
-	}
-
-	//1.打开文件
-	var index int = 0
 
-	f, err := os.OpenFile(fileName, os.O_RDONLY, 0)
+//读取配置文件,按文件扩展名选择格式(见format.go),返回按顺序的分段.
+func LoadConf(fileName string) (sections []Section, err error) {
+	data, err := ioutil.ReadFile(fileName)
 	if err != nil {
 		err = fmt.Errorf("打开配置文件[%s]失败, err: %s\n", fileName, err)
-		return err
+		return nil, err
+	}
+	sections, err = getFormat(fileName)(data)
+	if err != nil {
+		err = fmt.Errorf("配置文件[%s]格式错误, err: %s", fileName, err)
+		return nil, err
 	}
-	defer f.Close()
-	fileScanner := bufio.NewScanner(f)
-	group := ""
-	//2.将读取的文件数据按照行读取
+	return sections, nil
+}
+
+//解析INI格式:[分段名],key = value,以#或;开头的行是注释.
+func parseIni(data []byte) (sections []Section, err error) {
+	var index int = 0
+	fileScanner := bufio.NewScanner(bytes.NewReader(data))
 	for fileScanner.Scan() {
 		index++
 		line := fileScanner.Text()
@@ -118,20 +82,16 @@ func ParseConfTable(fileName string,groupName string, result interface{}) (err e
 		}
 		//检查是否前缀是[,后缀是]的分组,并取出group组名称.
 		if len(line) > 2 && line[0:1] == "[" && line[len(line)-1:] == "]" {
-			group = line[1 : len(line)-1]
+			group := line[1 : len(line)-1]
 			group = strings.TrimSpace(group)
-			continue
-		}
-
-		//判断组名与表名是否相等.
-		if strings.ToUpper(group) != strings.ToUpper(groupName) {
+			sections = append(sections, newSection(group))
 			continue
 		}
 		//判断是不是具体配置项,判断是不是有等号.
 		equalIndex := strings.Index(line, "=")
 		if equalIndex == -1 {
-			fmt.Printf("第%行语法错误:%w\n", index, err)
-			return
+			err = fmt.Errorf("parseIni(),第%d行语法错误: %s", index, line)
+			return nil, err
 		}
 		//按照等号=分割,左边是KEY,右边是VALUE
 		key := line[:equalIndex]
@@ -139,255 +99,101 @@ func ParseConfTable(fileName string,groupName string, result interface{}) (err e
 		key = strings.TrimSpace(key)
 		value = strings.TrimSpace(value)
 		if len(key) == 0 {
-			fmt.Printf("第%行语法错误:%w\n", index, err)
-			return
+			err = fmt.Errorf("parseIni(),第%d行语法错误: %s", index, line)
+			return nil, err
 		}
-		//利用反射给result赋值
-		//遍历结构体的每一个字段和KEY比较,匹配上就赋值.
-		for i := 0; i < tElem.NumField(); i++ {
-			field := tElem.Field(i)      //取得结构体字段
-			tag := field.Tag.Get("conf") //到得该字段的Tag
-			//如果配置文件中的Key等于该结构体字段的Tag,就把value值赋给结构体对应字段.
-			if key == tag {
-				fieldType := field.Type // 拿到每个字段的类型
-				//根据字段的类型,对应赋值
-				switch fieldType.Kind() {
-				case reflect.String:
-					vElem.Field(i).SetString(value)
-					////也可这样赋值,根据(reflect.ValueOf)中用字段名找到对应的值对象.
-					//fieldValue := vElem.FieldByName(field.Name)
-					////将配置文件中的value值,赋值给对应的结构体字段
-					//fieldValue.SetString(value)
-				case reflect.Int64, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
-					valueInt64, err := strconv.ParseInt(value, 10, 64)
-					if err != nil {
-						err = fmt.Errorf("conf.ParseConf(),strconv.ParseInt(),err:%s \n", err)
-						return err
-					}
-					vElem.Field(i).SetInt(valueInt64)
-				case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-					valueUint64, err := strconv.ParseUint(value, 10, 64)
-					if err != nil {
-						err = fmt.Errorf("conf.ParseConf(),strconv.ParseUint(),err:%s \n", err)
-						return err
-					}
-					vElem.Field(i).SetUint(valueUint64)
-				case reflect.Float32, reflect.Float64:
-					valueFloat64, err := strconv.ParseFloat(value, 64)
-					if err != nil {
-						err = fmt.Errorf("conf.ParseConf(),strconv.ParseFloat(),err:%s \n", err)
-						return err
-					}
-					vElem.Field(i).SetFloat(valueFloat64)
-				case reflect.Bool:
-					valueBool, err := strconv.ParseBool(value)
-					if err != nil {
-						err = fmt.Errorf("conf.ParseConf(),strconv.ParseBool(),err:%s \n", err)
-						return err
-					}
-					vElem.Field(i).SetBool(valueBool)
-				}
-			}
+		//第一个分段之前的配置项,放在名称为空的分段中
+		if len(sections) == 0 {
+			sections = append(sections, newSection(""))
 		}
+		sections[len(sections)-1].set(key, value)
 	}
-	return
+	return sections, fileScanner.Err()
 }
 
-//从配置文件中,取出数据,保存于结构体.
-func ParseConf(fileName string, result interface{}) (err error) {
-	t := reflect.TypeOf(result)
-	v := reflect.ValueOf(result)
-	//Elem()是获取引用类型指针的指向的对象,如果是值类型不需要。
-	tElem := t.Elem()
-	vElem := v.Elem()
-	//result必须是一个指针
-	if t.Kind() != reflect.Ptr {
-		fmt.Println("必须是一个指针")
-		return
-	}
-	//result必须是一个结构体,并且结构名与配置文件中分段[]名要一样.
-	if tElem.Kind() != reflect.Struct {
-		fmt.Println("必须是一个结构体")
-		return
-	}
-
-	//1.打开文件
-	var index int = 0
-
-	f, err := os.OpenFile(fileName, os.O_RDONLY, 0)
+//从配置文件中,取出指定指段的所有值
+func ParseConfField(fileName string,fieldName string ) (result []string,err error){
+	sections, err := LoadConf(fileName)
 	if err != nil {
-		err = fmt.Errorf("打开配置文件[%s]失败, err: %s\n", fileName, err)
-		return err
+		return nil, err
 	}
-	defer f.Close()
-	fileScanner := bufio.NewScanner(f)
-	group := ""
-	//2.将读取的文件数据按照行读取
-	for fileScanner.Scan() {
-		index++
-		line := fileScanner.Text()
-		//去除字符串首尾的空白
-		line = strings.TrimSpace(line)
-		// 以#或;开头视为注释,空行和注释不读取
-		if line == "" {
+	for _, section := range sections {
+		value, ok := section.Values[fieldName]
+		if !ok {
 			continue
 		}
-		if strings.HasPrefix(line, "#") {
-			continue
+		value, err = expandValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("ParseConfField(),分段[%s]配置项[%s]: %s", section.Name, fieldName, err)
 		}
-		if strings.HasPrefix(line, ";") {
-			continue
-		}
-		//检查是否前缀是[,后缀是]的分组,并取出group组名称.
-		if len(line) > 2 && line[0:1] == "[" && line[len(line)-1:] == "]" {
-			group = line[1 : len(line)-1]
-			group = strings.TrimSpace(group)
-			continue
+		s, err := valueString(value)
+		if err != nil {
+			return nil, fmt.Errorf("ParseConfField(),分段[%s]配置项[%s]: %s", section.Name, fieldName, err)
 		}
+		result = append(result, s)
+	}
+	return result,nil
+}
 
-		//判断与传进来的结构体名称相等.
-		if strings.ToUpper(group) != strings.ToUpper(tElem.Name()) {
-			continue
-		}
-		//判断是不是具体配置项,判断是不是有等号.
-		equalIndex := strings.Index(line, "=")
-		if equalIndex == -1 {
-			fmt.Printf("第%行语法错误:%w\n", index, err)
-			return
-		}
-		//按照等号=分割,左边是KEY,右边是VALUE
-		key := line[:equalIndex]
-		value := line[equalIndex+1:]
-		key = strings.TrimSpace(key)
-		value = strings.TrimSpace(value)
-		if len(key) == 0 {
-			fmt.Printf("第%行语法错误:%w\n", index, err)
-			return
-		}
-		//利用反射给result赋值
-		//遍历结构体的每一个字段和KEY比较,匹配上就赋值.
-		for i := 0; i < tElem.NumField(); i++ {
-			field := tElem.Field(i)      //取得结构体字段
-			tag := field.Tag.Get("conf") //到得该字段的Tag
-			//如果配置文件中的Key等于该结构体字段的Tag,就把value值赋给结构体对应字段.
-			if key == tag {
-				fieldType := field.Type // 拿到每个字段的类型
-				//根据字段的类型,对应赋值
-				switch fieldType.Kind() {
-				case reflect.String:
-					vElem.Field(i).SetString(value)
-					////也可这样赋值,根据(reflect.ValueOf)中用字段名找到对应的值对象.
-					//fieldValue := vElem.FieldByName(field.Name)
-					////将配置文件中的value值,赋值给对应的结构体字段
-					//fieldValue.SetString(value)
-				case reflect.Int64, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
-					valueInt64, err := strconv.ParseInt(value, 10, 64)
-					if err != nil {
-						err = fmt.Errorf("conf.ParseConf(),strconv.ParseInt(),err:%s \n", err)
-						return err
-					}
-					vElem.Field(i).SetInt(valueInt64)
-				case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-					valueUint64, err := strconv.ParseUint(value, 10, 64)
-					if err != nil {
-						err = fmt.Errorf("conf.ParseConf(),strconv.ParseUint(),err:%s \n", err)
-						return err
-					}
-					vElem.Field(i).SetUint(valueUint64)
-				case reflect.Float32, reflect.Float64:
-					valueFloat64, err := strconv.ParseFloat(value, 64)
-					if err != nil {
-						err = fmt.Errorf("conf.ParseConf(),strconv.ParseFloat(),err:%s \n", err)
-						return err
-					}
-					vElem.Field(i).SetFloat(valueFloat64)
-				case reflect.Bool:
-					valueBool, err := strconv.ParseBool(value)
-					if err != nil {
-						err = fmt.Errorf("conf.ParseConf(),strconv.ParseBool(),err:%s \n", err)
-						return err
-					}
-					vElem.Field(i).SetBool(valueBool)
-				}
-			}
-		}
+//从配置文件中,取出数据,保存于结构体.
+func ParseConfTable(fileName string,groupName string, result interface{}) (err error) {
+	return parseConfGroup(fileName, groupName, result)
+}
+
+//从配置文件中,取出数据,保存于结构体.分段名与结构体名称相同(不区分大小写).
+func ParseConf(fileName string, result interface{}) (err error) {
+	t := reflect.TypeOf(result)
+	if t == nil || t.Kind() != reflect.Ptr {
+		return fmt.Errorf("conf.ParseConf(),必须是一个指针")
 	}
-	return
+	return parseConfGroup(fileName, t.Elem().Name(), result)
 }
 
-//修改配置文件中的值,根据组名,查找对应的key,修改v的值.
-func SetConf(groupName string, k string, v string) (err error) {
-	//1.打开文件
-	var index int = 0
-	f, err := os.OpenFile(CONFIG_FILE, os.O_RDWR, 0666)
+//从配置文件中,取出分段名为groupName(不区分大小写)的配置项,按conf标签保存于结构体.
+func parseConfGroup(fileName string, groupName string, result interface{}) (err error) {
+	t := reflect.TypeOf(result)
+	//result必须是一个指针
+	if t == nil || t.Kind() != reflect.Ptr {
+		return fmt.Errorf("conf.ParseConf(),必须是一个指针")
+	}
+	//result必须是一个结构体
+	if t.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("conf.ParseConf(),必须是一个结构体")
+	}
+	sections, err := LoadConf(fileName)
 	if err != nil {
-		fmt.Printf("打开配置文件%s失败:%w/n", CONFIG_FILE, err)
 		return err
 	}
-	defer f.Close()
-	var newConf string
-	group := ""
-	fileScanner := bufio.NewScanner(f)
-	//一行一行的查找.
-	for fileScanner.Scan() {
-		index++
-		line := fileScanner.Text()
-		// 以#或;开头视为注释,空行和注释不读取
-		if line == "" {
-			newConf = newConf + line + "\n"
-			continue
-		}
-		if strings.HasPrefix(line, "#") {
-			newConf = newConf + line + "\n"
-			continue
-		}
-		if strings.HasPrefix(line, ";") {
-			newConf = newConf + line + "\n"
-			continue
-		}
-		//line = strings.TrimSpace(line)
-		//检查是否前缀是[,后缀是]的分组,并取出group组名称.
-		if len(line) > 2 && line[0:1] == "[" && line[len(line)-1:] == "]" {
-			group = line[1 : len(line)-1]
-			group = strings.TrimSpace(group)
-			newConf = newConf + line + "\n"
+	for _, section := range sections {
+		//判断组名与传进来的名称是否相等.
+		if strings.ToUpper(section.Name) != strings.ToUpper(groupName) {
 			continue
 		}
-		group = strings.ToUpper(group)
-		groupName := strings.ToUpper(groupName)
-		if group != groupName {
-			newConf = newConf + line + "\n"
-			continue
-		}
-		//判断是不是具体配置项,判断是不是有等号.
-		index := strings.Index(line, "=")
-		if index == -1 {
-			newConf = newConf + line + "\n"
-			continue
+		//替换值中的环境变量
+		values, err := expandValues(section.Values)
+		if err != nil {
+			return fmt.Errorf("conf.ParseConf(),分段[%s]%s", section.Name, err)
 		}
-		//按照等号=分割,左边是KEY,右边是VALUE
-		key := line[:index]
-		value := line[index+1:]
-		key = strings.TrimSpace(key)
-		value = strings.TrimSpace(value)
-		if len(key) == 0 {
-			newConf = newConf + line + "\n"
-			continue
-		}
-		if key == k {
-			newConf = newConf + k + " = " + v + "\n"
-			continue
-		} else {
-			newConf = newConf + line + "\n"
+		//利用反射给result赋值
+		err = setStruct(reflect.ValueOf(result).Elem(), values)
+		if err != nil {
+			return fmt.Errorf("conf.ParseConf(),分段[%s]%s", section.Name, err)
 		}
 	}
-	//重新写入新的配置
-	f.Seek(0, 0)
-	_, err = f.Write([]byte(newConf))
+	//环境变量DBCACHE_分段名_配置项名覆盖配置文件中的值
+	overrides, err := envOverrides(groupName, t.Elem())
+	if err == nil {
+		err = setStruct(reflect.ValueOf(result).Elem(), overrides)
+	}
 	if err != nil {
-		return err
+		return fmt.Errorf("conf.ParseConf(),环境变量覆盖分段[%s]%s", groupName, err)
 	}
 	return nil
 }
 
-
+//修改配置文件config.conf中的值,根据组名,查找对应的key,修改v的值.key或组不存在时增加.只支持INI格式(见editor.go).
+func SetConf(groupName string, k string, v string) (err error) {
+	return EditConf(CONFIG_FILE, func(e *ConfEditor) error {
+		return e.Set(groupName, k, v)
+	})
+}
@@ -8,19 +8,22 @@ type DbConfig struct {
 	Port         string `conf:"ip_port"`      //端口
 	DatabaseName string `conf:"db_name"`      //数据库名
 	ConnectInfo  string `conf:"connect_info"` //连接时其它配置.
+	Driver       string `conf:"driver"`       //数据库:mysql(默认),postgres,sqlite.sqlite时db_name是数据库文件路径
 }
 
 //RPC配置
 type RpcServer struct {
-	RpcType  string `conf:"rpc_type"`   //rpc类型
-	Protocol string `conf:"protocol"`   //rpc协议
-	Ip       string `conf:"ip_address"` //rpc的IP地址
-	Port     string `conf:"ip_port"`    //rpc的端口
+	RpcType    string `conf:"rpc_type"`    //rpc类型
+	Protocol   string `conf:"protocol"`    //rpc协议
+	Ip         string `conf:"ip_address"`  //rpc的IP地址
+	Port       string `conf:"ip_port"`     //rpc的端口
+	AdminToken string `conf:"admin_token"` //管理接口(AddTable)的token,为空时管理接口关闭
 }
 
 //GRPC配置
 type GrpcServer struct {
-	Protocol string `conf:"protocol"`   //Grpc协议
-	Ip       string `conf:"ip_address"` //Grpc的IP地址
-	Port     string `conf:"ip_port"`    //Grpc的端口
+	Protocol   string `conf:"protocol"`    //Grpc协议
+	Ip         string `conf:"ip_address"`  //Grpc的IP地址
+	Port       string `conf:"ip_port"`     //Grpc的端口
+	AdminToken string `conf:"admin_token"` //管理接口(AddTable)的token,为空时管理接口关闭
 }
@@ -0,0 +1,320 @@
+package conf
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+//修改INI格式的配置文件(config.conf,cache.conf):增加,修改,删除配置项和分段,保留注释和顺序.
+//保存时先写入同目录下的临时文件,再改名替换原文件,中途失败时原文件不变.
+//分段前面紧挨着的注释行(中间没有空行)属于该分段,删除分段时一起删除.
+
+var editMutex sync.Mutex //修改配置文件需串行
+
+//配置文件编辑对象
+type ConfEditor struct {
+	fileName string
+	lines    []string //文件的每一行(不含换行符)
+	newline  string   //换行符,保持原文件的换行符
+}
+
+//读取配置文件,只支持INI格式.
+func OpenConfEditor(fileName string) (e *ConfEditor, err error) {
+	if _, ok := formats[strings.ToLower(filepath.Ext(fileName))]; ok {
+		return nil, fmt.Errorf("OpenConfEditor(),只支持修改INI格式的配置文件: %s", fileName)
+	}
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("OpenConfEditor(),打开配置文件[%s]失败, err: %s", fileName, err)
+	}
+	e = &ConfEditor{fileName: fileName, newline: "\n"}
+	content := string(data)
+	if strings.Contains(content, "\r\n") {
+		e.newline = "\r\n"
+		content = strings.Replace(content, "\r\n", "\n", -1)
+	}
+	content = strings.TrimSuffix(content, "\n")
+	if content != "" {
+		e.lines = strings.Split(content, "\n")
+	}
+	return e, nil
+}
+
+//读取配置文件,由fn修改后保存.多个修改串行执行,fn返回错误时不保存.
+func EditConf(fileName string, fn func(e *ConfEditor) error) error {
+	editMutex.Lock()
+	defer editMutex.Unlock()
+	e, err := OpenConfEditor(fileName)
+	if err != nil {
+		return err
+	}
+	if err = fn(e); err != nil {
+		return err
+	}
+	return e.Save()
+}
+
+//解析一行:是否是分段名,是否是配置项.
+func parseLine(line string) (section string, isSection bool, key string, value string, isKey bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+		return "", false, "", "", false
+	}
+	if len(line) > 2 && line[0:1] == "[" && line[len(line)-1:] == "]" {
+		return strings.TrimSpace(line[1 : len(line)-1]), true, "", "", false
+	}
+	i := strings.Index(line, "=")
+	if i == -1 {
+		return "", false, "", "", false
+	}
+	return "", false, strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:]), true
+}
+
+//是否是注释行
+func isComment(line string) bool {
+	line = strings.TrimSpace(line)
+	return strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";")
+}
+
+//查找分段(不区分大小写),返回分段名所在行,分段结束行(不包括,即下一个分段及其注释开始的行).
+func (e *ConfEditor) findSection(name string) (start int, end int, ok bool) {
+	start = -1
+	for i, line := range e.lines {
+		section, isSection, _, _, _ := parseLine(line)
+		if !isSection {
+			continue
+		}
+		if start != -1 {
+			return start, e.commentStart(i), true
+		}
+		if strings.EqualFold(section, name) {
+			start = i
+		}
+	}
+	if start == -1 {
+		return -1, -1, false
+	}
+	return start, len(e.lines), true
+}
+
+//分段名所在行前面紧挨着的注释行的开始行.
+func (e *ConfEditor) commentStart(header int) int {
+	i := header
+	for i > 0 && isComment(e.lines[i-1]) {
+		i--
+	}
+	return i
+}
+
+//查找分段中的配置项所在行,找不到返回-1.
+func (e *ConfEditor) findKey(section string, key string) int {
+	start, end, ok := e.findSection(section)
+	if !ok {
+		return -1
+	}
+	for i := start + 1; i < end; i++ {
+		if _, _, k, _, isKey := parseLine(e.lines[i]); isKey && k == key {
+			return i
+		}
+	}
+	return -1
+}
+
+//是否有分段
+func (e *ConfEditor) HasSection(section string) bool {
+	_, _, ok := e.findSection(section)
+	return ok
+}
+
+//获取配置项的值
+func (e *ConfEditor) Get(section string, key string) (value string, ok bool) {
+	i := e.findKey(section, key)
+	if i == -1 {
+		return "", false
+	}
+	_, _, _, value, _ = parseLine(e.lines[i])
+	return value, true
+}
+
+//检查配置项名和值能否写入INI格式
+func checkKeyValue(key string, value string) error {
+	if strings.TrimSpace(key) == "" || strings.ContainsAny(key, "=[]\r\n") || isComment(key) {
+		return fmt.Errorf("配置项名错误: %q", key)
+	}
+	if strings.ContainsAny(value, "\r\n") {
+		return fmt.Errorf("配置项[%s]的值不能包含换行符", key)
+	}
+	return nil
+}
+
+//修改配置项的值,配置项不存在时加在分段的最后一个配置项后面,分段不存在时加在文件末尾.
+func (e *ConfEditor) Set(section string, key string, value string) error {
+	key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+	if err := checkKeyValue(key, value); err != nil {
+		return fmt.Errorf("Set(),%s", err)
+	}
+	line := key + " = " + value
+	if i := e.findKey(section, key); i != -1 {
+		e.lines[i] = line
+		return nil
+	}
+	start, end, ok := e.findSection(section)
+	if !ok {
+		if err := e.AddSection(section); err != nil {
+			return fmt.Errorf("Set(),%s", err)
+		}
+		e.lines = append(e.lines, line)
+		return nil
+	}
+	//加在最后一个配置项后面(分段末尾的空行和注释之前)
+	i := end
+	for i > start+1 && !isKeyLine(e.lines[i-1]) {
+		i--
+	}
+	e.insert(i, line)
+	return nil
+}
+
+func isKeyLine(line string) bool {
+	_, _, _, _, isKey := parseLine(line)
+	return isKey
+}
+
+//在第i行插入
+func (e *ConfEditor) insert(i int, lines ...string) {
+	e.lines = append(e.lines[:i], append(lines, e.lines[i:]...)...)
+}
+
+//删除配置项,返回是否存在.
+func (e *ConfEditor) Delete(section string, key string) bool {
+	i := e.findKey(section, key)
+	if i == -1 {
+		return false
+	}
+	e.lines = append(e.lines[:i], e.lines[i+1:]...)
+	return true
+}
+
+//在文件末尾增加空的分段,已存在时返回错误.
+func (e *ConfEditor) AddSection(section string) error {
+	section = strings.TrimSpace(section)
+	if section == "" || strings.ContainsAny(section, "[]\r\n") {
+		return fmt.Errorf("AddSection(),分段名错误: %q", section)
+	}
+	if e.HasSection(section) {
+		return fmt.Errorf("AddSection(),分段[%s]已存在", section)
+	}
+	if len(e.lines) > 0 && strings.TrimSpace(e.lines[len(e.lines)-1]) != "" {
+		e.lines = append(e.lines, "")
+	}
+	e.lines = append(e.lines, "["+section+"]")
+	return nil
+}
+
+//删除分段及其前面紧挨着的注释,返回是否存在.
+func (e *ConfEditor) DeleteSection(section string) bool {
+	start, end, ok := e.findSection(section)
+	if !ok {
+		return false
+	}
+	e.lines = append(e.lines[:e.commentStart(start)], e.lines[end:]...)
+	return true
+}
+
+//按结构体字段的conf标签,把结构体(或指针)中不为零值的字段写入分段,分段不存在时增加.
+func (e *ConfEditor) SetSection(section string, result interface{}) error {
+	v := reflect.Indirect(reflect.ValueOf(result))
+	if v.Kind() != reflect.Struct {
+		return fmt.Errorf("SetSection(),必须是一个结构体")
+	}
+	if !e.HasSection(section) {
+		if err := e.AddSection(section); err != nil {
+			return err
+		}
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("conf")
+		if tag == "" || v.Field(i).IsZero() {
+			continue
+		}
+		value, err := formatField(v.Field(i))
+		if err != nil {
+			return fmt.Errorf("SetSection(),配置项[%s]: %s", tag, err)
+		}
+		if err = e.Set(section, tag, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//把字段的值转换为配置文件中的字符串,切片以逗号连接.
+func formatField(v reflect.Value) (string, error) {
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), nil
+	case reflect.Slice:
+		items := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			s, err := formatField(v.Index(i))
+			if err != nil {
+				return "", err
+			}
+			items = append(items, s)
+		}
+		return strings.Join(items, ","), nil
+	case reflect.Map, reflect.Struct:
+		return "", fmt.Errorf("INI格式不支持嵌套的配置项")
+	}
+	return fmt.Sprint(v.Interface()), nil
+}
+
+//文件内容
+func (e *ConfEditor) String() string {
+	if len(e.lines) == 0 {
+		return ""
+	}
+	return strings.Join(e.lines, e.newline) + e.newline
+}
+
+//保存:写入同目录下的临时文件,再改名替换原文件.保持原文件的权限.
+func (e *ConfEditor) Save() (err error) {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(e.fileName); err == nil {
+		mode = info.Mode().Perm()
+	}
+	f, err := ioutil.TempFile(filepath.Dir(e.fileName), filepath.Base(e.fileName)+".tmp")
+	if err != nil {
+		return fmt.Errorf("Save(),创建临时文件失败, err: %s", err)
+	}
+	tmpName := f.Name()
+	defer func() {
+		if err != nil {
+			os.Remove(tmpName)
+		}
+	}()
+	_, err = f.WriteString(e.String())
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("Save(),写入临时文件失败, err: %s", err)
+	}
+	if err = os.Chmod(tmpName, mode); err != nil {
+		return fmt.Errorf("Save(),修改临时文件权限失败, err: %s", err)
+	}
+	if err = os.Rename(tmpName, e.fileName); err != nil {
+		return fmt.Errorf("Save(),替换配置文件[%s]失败, err: %s", e.fileName, err)
+	}
+	return nil
+}
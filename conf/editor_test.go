@@ -0,0 +1,138 @@
+package conf
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+//修改配置项和分段,保留注释和顺序
+func TestConfEditor(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "cache.conf")
+	content := `#缓存的表
+[Users]
+table_name = users
+#主键
+pkey = uid
+
+#商品表
+[Goods]
+table_name = goods
+pkey = goods_id
+
+[DataAsync]
+async_max_chan = 1000
+`
+	if err := os.WriteFile(fileName, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	err := EditConf(fileName, func(e *ConfEditor) error {
+		if err := e.Set("users", "pkey", "id"); err != nil {
+			return err
+		}
+		if err := e.Set("Users", "columns", "id,name"); err != nil {
+			return err
+		}
+		if err := e.Set("Email", "enable", "true"); err != nil {
+			return err
+		}
+		if !e.DeleteSection("Goods") || e.Delete("DataAsync", "none") {
+			t.Errorf("DeleteSection(Goods) or Delete(none) returned wrong result")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `#缓存的表
+[Users]
+table_name = users
+#主键
+pkey = id
+columns = id,name
+
+[DataAsync]
+async_max_chan = 1000
+
+[Email]
+enable = true
+`
+	data, _ := os.ReadFile(fileName)
+	if string(data) != want {
+		t.Errorf("EditConf() wrote:\n%s\nwant:\n%s", data, want)
+	}
+	if info, _ := os.Stat(fileName); info.Mode().Perm() != 0600 {
+		t.Errorf("file mode = %v, want 0600", info.Mode().Perm())
+	}
+	//fn返回错误时不保存
+	EditConf(fileName, func(e *ConfEditor) error {
+		e.DeleteSection("Users")
+		return e.Set("Users", "bad\nkey", "1")
+	})
+	if data, _ = os.ReadFile(fileName); string(data) != want {
+		t.Errorf("EditConf() saved after error:\n%s", data)
+	}
+	//临时文件已删除
+	if files, _ := filepath.Glob(fileName + ".tmp*"); len(files) != 0 {
+		t.Errorf("temp files left: %v", files)
+	}
+}
+
+//增加和删除cache.conf中的表
+func TestAddCacheTable(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "cache.conf")
+	if err := os.WriteFile(fileName, []byte("[Users]\ntable_name = users\ncolumns = uid,name\npkey = uid\ncache_type = slice\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	table := CacheTable{TableName: "goods", Columns: "goods_id,price", Pkey: "goods_id", CacheType: "tree", Other: "order by price desc", CompactRatio: 0.5}
+	if err := AddCacheTable(fileName, "", table); err != nil {
+		t.Fatal(err)
+	}
+	got := CacheTable{}
+	if err := ParseConfTable(fileName, "goods", &got); err != nil || got != table {
+		t.Errorf("ParseConfTable() = %+v, %v, want %+v", got, err, table)
+	}
+	//重复的表,配置错误的表
+	for _, bad := range []CacheTable{
+		table,
+		{TableName: "USERS", Columns: "uid", Pkey: "uid", CacheType: "slice"},
+		{TableName: "orders", Columns: "order_id", Pkey: "id", CacheType: "slice"},
+	} {
+		if err := AddCacheTable(fileName, "", bad); err == nil {
+			t.Errorf("AddCacheTable(%s) want error", bad.TableName)
+		}
+	}
+	//同一个表用不同的分段名增加(别名)
+	cheap := table
+	cheap.Where = "price < 10"
+	if err := AddCacheTable(fileName, "CheapGoods", cheap); err != nil {
+		t.Fatal(err)
+	}
+	if names, err := GetCacheTableSections(fileName); err != nil || strings.Join(names, ",") != "Users,goods,CheapGoods" {
+		t.Errorf("GetCacheTableSections() = %v, %v", names, err)
+	}
+	if ok, err := RemoveCacheTable(fileName, "cheapgoods"); !ok || err != nil {
+		t.Errorf("RemoveCacheTable(cheapgoods) = %v, %v", ok, err)
+	}
+	if ok, err := RemoveCacheTable(fileName, "goods"); !ok || err != nil {
+		t.Errorf("RemoveCacheTable() = %v, %v", ok, err)
+	}
+	data, _ := os.ReadFile(fileName)
+	if strings.Contains(string(data), "goods") {
+		t.Errorf("goods not removed:\n%s", data)
+	}
+}
+
+func TestFormatCacheTable(t *testing.T) {
+	table := CacheTable{TableName: "goods", Columns: "goods_id,price", Pkey: "goods_id", PkeyAutoIncrement: true, CacheType: "link", IsWaitResult: true}
+	section, err := FormatCacheTable("", table)
+	want := "[goods]\ntable_name = goods\ncolumns = goods_id,price\npkey = goods_id\npkey_auto_increment = true\ncache_type = link\nis_wait_result = true\n"
+	if err != nil || section != want {
+		t.Errorf("FormatCacheTable() = %q, %v, want %q", section, err, want)
+	}
+	table.CacheType = "list"
+	if _, err = FormatCacheTable("Goods", table); err == nil {
+		t.Errorf("FormatCacheTable(cache_type=list) want error")
+	}
+}
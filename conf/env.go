@@ -0,0 +1,147 @@
+package conf
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+//配置项中的环境变量.密码等不宜写在配置文件中的值,可以从环境变量读取:
+//一. 插值:配置项的值中${变量名}替换为环境变量的值,${变量名:-默认值}在环境变量未设置或为空时使用默认值.
+//环境变量未设置且没有默认值时返回错误.$${表示字符${,不做替换.
+//二. 覆盖:环境变量DBCACHE_分段名_配置项名(大写,非字母数字替换为_)存在时,覆盖配置文件中的值,
+//例如DBCACHE_DBCONFIG_PASSWORD覆盖[DbConfig]的password,DBCACHE_USERS_IS_WAIT_RESULT覆盖[Users]的is_wait_result.
+
+const ENV_PREFIX = "DBCACHE_" //覆盖配置项的环境变量前缀
+
+//替换字符串中的${变量名}和${变量名:-默认值}.
+func expandEnv(s string) (string, error) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+	var result strings.Builder
+	for {
+		i := strings.Index(s, "${")
+		if i == -1 {
+			result.WriteString(s)
+			return result.String(), nil
+		}
+		//$${不替换
+		if i > 0 && s[i-1] == '$' {
+			result.WriteString(s[:i-1])
+			result.WriteString("${")
+			s = s[i+2:]
+			continue
+		}
+		result.WriteString(s[:i])
+		end := strings.Index(s[i:], "}")
+		if end == -1 {
+			return "", fmt.Errorf("环境变量缺少}: %s", s[i:])
+		}
+		expr := s[i+2 : i+end]
+		s = s[i+end+1:]
+		name, defaultValue, hasDefault := expr, "", false
+		if j := strings.Index(expr, ":-"); j != -1 {
+			name, defaultValue, hasDefault = expr[:j], expr[j+2:], true
+		}
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return "", fmt.Errorf("环境变量名为空: ${%s}", expr)
+		}
+		value, ok := os.LookupEnv(name)
+		switch {
+		case ok && value != "":
+			result.WriteString(value)
+		case hasDefault:
+			result.WriteString(defaultValue)
+		case ok:
+			//已设置为空,没有默认值时使用空值
+		default:
+			return "", fmt.Errorf("环境变量[%s]未设置,可设置该环境变量或使用${%s:-默认值}", name, name)
+		}
+	}
+}
+
+//替换配置项的值中的环境变量,并解密ENC(...)(见secret.go),列表和嵌套的配置项逐个替换.
+func expandValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		s, err := expandEnv(v)
+		if err != nil {
+			return nil, err
+		}
+		return decryptValue(s)
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			s, err := expandValue(item)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = s
+		}
+		return result, nil
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			s, err := expandValue(item)
+			if err != nil {
+				return nil, fmt.Errorf("[%s]: %s", k, err)
+			}
+			result[k] = s
+		}
+		return result, nil
+	}
+	return value, nil
+}
+
+//替换分段中所有配置项的值中的环境变量.
+func expandValues(values map[string]interface{}) (map[string]interface{}, error) {
+	result := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		v, err := expandValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("配置项[%s]: %s", key, err)
+		}
+		result[key] = v
+	}
+	return result, nil
+}
+
+//覆盖配置项的环境变量名:DBCACHE_分段名_配置项名,大写,非字母数字替换为_.
+func EnvName(groupName string, key string) string {
+	return ENV_PREFIX + envPart(groupName) + "_" + envPart(key)
+}
+
+func envPart(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, s)
+}
+
+//获取结构体中各conf标签对应的覆盖环境变量的值,只返回已设置的.值可以是加密值ENC(...).
+func envOverrides(groupName string, t reflect.Type) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("conf")
+		if tag == "" {
+			continue
+		}
+		name := EnvName(groupName, tag)
+		if value, ok := os.LookupEnv(name); ok {
+			value, err := decryptValue(value)
+			if err != nil {
+				return nil, fmt.Errorf("环境变量[%s]: %s", name, err)
+			}
+			values[tag] = value
+		}
+	}
+	return values, nil
+}
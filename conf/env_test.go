@@ -0,0 +1,74 @@
+package conf
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+//${变量名}和${变量名:-默认值}插值
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("DBCACHE_TEST_PWD", "secret")
+	t.Setenv("DBCACHE_TEST_EMPTY", "")
+	tests := map[string]string{
+		"${DBCACHE_TEST_PWD}":                        "secret",
+		"a${DBCACHE_TEST_PWD}b${ DBCACHE_TEST_PWD }": "asecretbsecret",
+		"${DBCACHE_TEST_UNSET:-root}":                "root",
+		"${DBCACHE_TEST_EMPTY:-root}":                "root",
+		"${DBCACHE_TEST_EMPTY}":                      "",
+		"${DBCACHE_TEST_PWD:-root}":                  "secret",
+		"$${DBCACHE_TEST_PWD}":                       "${DBCACHE_TEST_PWD}",
+		"pa$$word":                                   "pa$$word",
+	}
+	for s, want := range tests {
+		if got, err := expandEnv(s); err != nil || got != want {
+			t.Errorf("expandEnv(%q) = %q, %v, want %q", s, got, err, want)
+		}
+	}
+	for _, s := range []string{"${DBCACHE_TEST_UNSET}", "${DBCACHE_TEST_PWD", "${}"} {
+		if _, err := expandEnv(s); err == nil {
+			t.Errorf("expandEnv(%q): want error", s)
+		}
+	}
+}
+
+//插值和DBCACHE_分段名_配置项名覆盖
+func TestParseConfEnv(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "config.conf")
+	content := `
+[DbConfig]
+user_name = ${DBCACHE_TEST_USER:-root}
+password = ${DBCACHE_TEST_PWD}
+ip_port = 3306
+`
+	if err := os.WriteFile(fileName, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	//未设置必需的环境变量
+	err := ParseConf(fileName, &DbConfig{})
+	if err == nil || !strings.Contains(err.Error(), "DBCACHE_TEST_PWD") {
+		t.Errorf("ParseConf() err = %v, want unset DBCACHE_TEST_PWD", err)
+	}
+	t.Setenv("DBCACHE_TEST_PWD", "secret")
+	t.Setenv("DBCACHE_DBCONFIG_IP_PORT", "3307")
+	t.Setenv("DBCACHE_DBCONFIG_DB_NAME", "test")
+	got := DbConfig{}
+	if err = ParseConf(fileName, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := DbConfig{User: "root", Pwd: "secret", Port: "3307", DatabaseName: "test"}
+	if got != want {
+		t.Errorf("ParseConf() = %+v, want %+v", got, want)
+	}
+	//覆盖表配置
+	t.Setenv(EnvName("users", "is_wait_result"), "true")
+	table := CacheTable{}
+	if err = ParseConfTable(fileName, "users", &table); err != nil || !table.IsWaitResult {
+		t.Errorf("ParseConfTable() = %+v, %v, want is_wait_result from env", table, err)
+	}
+	t.Setenv(EnvName("users", "compact_ratio"), "abc")
+	if err = ParseConfTable(fileName, "users", &table); err == nil {
+		t.Errorf("ParseConfTable() with invalid env: want error")
+	}
+}
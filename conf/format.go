@@ -0,0 +1,339 @@
+package conf
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+//配置文件格式.按文件扩展名选择解析函数:.yaml/.yml,.toml,.json,其它扩展名(.conf,.ini等)按INI格式解析.
+//各格式都解析为按顺序的分段(Section),再按结构体字段的conf标签赋值,所以DbConfig,CacheTable,DataAsync,日志配置等结构体不需修改.
+//YAML,TOML,JSON格式中,顶层的每个键是一个分段(相当于INI中的[分段名]),分段的值是配置项.例如YAML:
+//	Users:
+//	  table_name: users
+//	  columns: [uid, age, name]
+//列表赋值给字符串字段时以逗号连接,赋值给切片字段时逐个赋值;嵌套的配置项可以赋值给结构体或map字段.
+
+//配置文件中的一个分段
+type Section struct {
+	Name   string                 //分段名
+	Keys   []string               //配置项,按文件中的顺序
+	Values map[string]interface{} //配置项的值.INI格式都是字符串,其它格式可以是数字,布尔,列表或嵌套的配置项
+}
+
+//解析配置文件内容,返回按顺序的分段.
+type FormatParser func(data []byte) (sections []Section, err error)
+
+//文件扩展名 -> 解析函数
+var formats = map[string]FormatParser{
+	".yaml": parseYaml,
+	".yml":  parseYaml,
+	".toml": parseToml,
+	".json": parseJson,
+}
+
+//注册配置文件格式,ext是文件扩展名(例如.yaml).已存在时替换.
+func RegisterFormat(ext string, parser FormatParser) {
+	formats[strings.ToLower(ext)] = parser
+}
+
+//根据文件扩展名获取解析函数,未注册的扩展名按INI格式解析.
+func getFormat(fileName string) FormatParser {
+	if parser, ok := formats[strings.ToLower(filepath.Ext(fileName))]; ok {
+		return parser
+	}
+	return parseIni
+}
+
+//新建分段
+func newSection(name string) Section {
+	return Section{Name: name, Values: make(map[string]interface{})}
+}
+
+//设置配置项,保持第一次出现的顺序.
+func (s *Section) set(key string, value interface{}) {
+	if _, ok := s.Values[key]; !ok {
+		s.Keys = append(s.Keys, key)
+	}
+	s.Values[key] = value
+}
+
+//解析YAML格式,保持分段和配置项的顺序.
+func parseYaml(data []byte) (sections []Section, err error) {
+	var doc yaml.Node
+	err = yaml.Unmarshal(data, &doc)
+	if err != nil {
+		return nil, fmt.Errorf("parseYaml(),解析失败, err: %s", err)
+	}
+	//空文件
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("parseYaml(),第%d行,顶层必须是分段名: 配置项", root.Line)
+	}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		name, body := root.Content[i], root.Content[i+1]
+		section := newSection(name.Value)
+		if body.Kind != yaml.MappingNode {
+			//空分段
+			if body.Tag == "!!null" {
+				sections = append(sections, section)
+				continue
+			}
+			return nil, fmt.Errorf("parseYaml(),第%d行,分段[%s]的值必须是配置项", body.Line, name.Value)
+		}
+		for j := 0; j+1 < len(body.Content); j += 2 {
+			var value interface{}
+			err = body.Content[j+1].Decode(&value)
+			if err != nil {
+				return nil, fmt.Errorf("parseYaml(),第%d行,分段[%s]配置项[%s]解析失败, err: %s", body.Content[j].Line, name.Value, body.Content[j].Value, err)
+			}
+			section.set(body.Content[j].Value, value)
+		}
+		sections = append(sections, section)
+	}
+	return sections, nil
+}
+
+//解析TOML格式,保持分段和配置项的顺序.
+func parseToml(data []byte) (sections []Section, err error) {
+	var doc map[string]interface{}
+	meta, err := toml.Decode(string(data), &doc)
+	if err != nil {
+		return nil, fmt.Errorf("parseToml(),解析失败, err: %s", err)
+	}
+	index := make(map[string]int)
+	for _, key := range meta.Keys() {
+		//只取分段([分段名])和分段下的第一层配置项,更深的配置项在配置项的值中
+		if len(key) == 0 || len(key) > 2 {
+			continue
+		}
+		name := key[0]
+		body, ok := doc[name].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("parseToml(),[%s]必须是分段", name)
+		}
+		i, ok := index[name]
+		if !ok {
+			i = len(sections)
+			index[name] = i
+			sections = append(sections, newSection(name))
+		}
+		if len(key) == 2 {
+			sections[i].set(key[1], body[key[1]])
+		}
+	}
+	return sections, nil
+}
+
+//解析JSON格式,保持分段和配置项的顺序.数字按json.Number保存,避免整数变为浮点数.
+func parseJson(data []byte) (sections []Section, err error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	//顶层对象
+	err = jsonDelim(decoder, '{')
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parseJson(),顶层必须是对象, err: %s", err)
+	}
+	for decoder.More() {
+		name, err := jsonKey(decoder)
+		if err != nil {
+			return nil, fmt.Errorf("parseJson(),读取分段名失败, err: %s", err)
+		}
+		section := newSection(name)
+		err = jsonDelim(decoder, '{')
+		if err != nil {
+			return nil, fmt.Errorf("parseJson(),分段[%s]的值必须是对象, err: %s", name, err)
+		}
+		for decoder.More() {
+			key, err := jsonKey(decoder)
+			if err != nil {
+				return nil, fmt.Errorf("parseJson(),分段[%s]读取配置项失败, err: %s", name, err)
+			}
+			var value interface{}
+			err = decoder.Decode(&value)
+			if err != nil {
+				return nil, fmt.Errorf("parseJson(),分段[%s]配置项[%s]解析失败, err: %s", name, key, err)
+			}
+			section.set(key, value)
+		}
+		//分段结束的}
+		if _, err = decoder.Token(); err != nil {
+			return nil, fmt.Errorf("parseJson(),分段[%s]解析失败, err: %s", name, err)
+		}
+		sections = append(sections, section)
+	}
+	return sections, nil
+}
+
+//读取JSON的分隔符,必须是delim.
+func jsonDelim(decoder *json.Decoder, delim json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := token.(json.Delim); !ok || d != delim {
+		return fmt.Errorf("应为%s, 实际为%v", delim, token)
+	}
+	return nil
+}
+
+//读取JSON对象的键
+func jsonKey(decoder *json.Decoder) (string, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return "", err
+	}
+	key, ok := token.(string)
+	if !ok {
+		return "", fmt.Errorf("应为键, 实际为%v", token)
+	}
+	return key, nil
+}
+
+//把配置项的值转换为字符串.列表以逗号连接.
+func valueString(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), nil
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			s, err := valueString(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, s)
+		}
+		return strings.Join(items, ","), nil
+	case map[string]interface{}:
+		return "", fmt.Errorf("嵌套的配置项不能转换为字符串")
+	}
+	return fmt.Sprint(value), nil
+}
+
+//按结构体字段的conf标签,把配置项的值赋给结构体.v是结构体的reflect.Value(可设置).
+func setStruct(v reflect.Value, values map[string]interface{}) (err error) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("conf")
+		if tag == "" {
+			continue
+		}
+		value, ok := values[tag]
+		if !ok {
+			continue
+		}
+		err = setField(v.Field(i), value)
+		if err != nil {
+			return fmt.Errorf("配置项[%s]: %s", tag, err)
+		}
+	}
+	return nil
+}
+
+//按字段的类型,把配置项的值赋给字段.
+func setField(field reflect.Value, value interface{}) (err error) {
+	switch field.Kind() {
+	case reflect.Slice:
+		//列表逐个赋值,字符串按逗号分隔
+		var items []interface{}
+		switch v := value.(type) {
+		case []interface{}:
+			items = v
+		case nil:
+		default:
+			s, err := valueString(v)
+			if err != nil {
+				return err
+			}
+			for _, item := range getColumns(s) {
+				items = append(items, item)
+			}
+		}
+		slice := reflect.MakeSlice(field.Type(), len(items), len(items))
+		for i, item := range items {
+			err = setField(slice.Index(i), item)
+			if err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+		return nil
+	case reflect.Map:
+		values, ok := value.(map[string]interface{})
+		if !ok || field.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("应为嵌套的配置项, 实际为%v", value)
+		}
+		m := reflect.MakeMapWithSize(field.Type(), len(values))
+		for k, item := range values {
+			elem := reflect.New(field.Type().Elem()).Elem()
+			err = setField(elem, item)
+			if err != nil {
+				return fmt.Errorf("[%s]: %s", k, err)
+			}
+			m.SetMapIndex(reflect.ValueOf(k).Convert(field.Type().Key()), elem)
+		}
+		field.Set(m)
+		return nil
+	case reflect.Struct:
+		values, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("应为嵌套的配置项, 实际为%v", value)
+		}
+		return setStruct(field, values)
+	}
+	//标量:先转换为字符串,再按字段的类型解析(与INI格式相同)
+	s, err := valueString(value)
+	if err != nil {
+		return err
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(s)
+	case reflect.Int64, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		valueInt64, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return fmt.Errorf("strconv.ParseInt(),err:%s", err)
+		}
+		field.SetInt(valueInt64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		valueUint64, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return fmt.Errorf("strconv.ParseUint(),err:%s", err)
+		}
+		field.SetUint(valueUint64)
+	case reflect.Float32, reflect.Float64:
+		valueFloat64, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("strconv.ParseFloat(),err:%s", err)
+		}
+		field.SetFloat(valueFloat64)
+	case reflect.Bool:
+		valueBool, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("strconv.ParseBool(),err:%s", err)
+		}
+		field.SetBool(valueBool)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package conf
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+//同一配置的四种格式
+var formatTests = map[string]string{
+	"cache.conf": `
+#数据库users表
+[Users]
+table_name = users
+columns = uid,age,name
+pkey = uid
+is_wait_result = true
+compact_ratio = 0.5
+
+[Goods]
+table_name = goods
+compact_max_deleted = 100
+
+[DataAsync]
+async_max_chan = 1000
+`,
+	"cache.yaml": `
+Users:
+  table_name: users
+  columns: [uid, age, name]
+  pkey: uid
+  is_wait_result: true
+  compact_ratio: 0.5
+Goods:
+  table_name: goods
+  compact_max_deleted: 100
+DataAsync:
+  async_max_chan: 1000
+`,
+	"cache.toml": `
+[Users]
+table_name = "users"
+columns = ["uid", "age", "name"]
+pkey = "uid"
+is_wait_result = true
+compact_ratio = 0.5
+
+[Goods]
+table_name = "goods"
+compact_max_deleted = 100
+
+[DataAsync]
+async_max_chan = 1000
+`,
+	"cache.json": `{
+	"Users": {"table_name": "users", "columns": ["uid", "age", "name"], "pkey": "uid", "is_wait_result": true, "compact_ratio": 0.5},
+	"Goods": {"table_name": "goods", "compact_max_deleted": 100},
+	"DataAsync": {"async_max_chan": 1000}
+}`,
+}
+
+//按扩展名选择格式,解析到同样的结构体
+func TestParseFormats(t *testing.T) {
+	dir := t.TempDir()
+	wantUsers := CacheTable{TableName: "users", Columns: "uid,age,name", Pkey: "uid", IsWaitResult: true, CompactRatio: 0.5}
+	for name, content := range formatTests {
+		fileName := filepath.Join(dir, name)
+		if err := os.WriteFile(fileName, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		names, err := ParseConfField(fileName, TABLE_FIELD_NAME)
+		if err != nil || !reflect.DeepEqual(names, []string{"users", "goods"}) {
+			t.Errorf("%s: ParseConfField() = %v, %v", name, names, err)
+		}
+		users := CacheTable{}
+		if err = ParseConfTable(fileName, "users", &users); err != nil || users != wantUsers {
+			t.Errorf("%s: ParseConfTable(users) = %+v, %v", name, users, err)
+		}
+		goods := CacheTable{}
+		if err = ParseConfTable(fileName, "Goods", &goods); err != nil || goods.GetCompactMaxDeleted() != 100 {
+			t.Errorf("%s: ParseConfTable(goods) = %+v, %v", name, goods, err)
+		}
+		dataAsync := DataAsync{}
+		if err = ParseConf(fileName, &dataAsync); err != nil || dataAsync.AsyncMaxChan != 1000 {
+			t.Errorf("%s: ParseConf(DataAsync) = %+v, %v", name, dataAsync, err)
+		}
+	}
+}
+
+//列表和嵌套的配置项赋值给切片,map和结构体字段
+func TestParseNested(t *testing.T) {
+	type column struct {
+		Width int    `conf:"width"`
+		Title string `conf:"title"`
+	}
+	type Nested struct {
+		Columns []string          `conf:"columns"`
+		Ports   []int             `conf:"ports"`
+		Titles  map[string]column `conf:"titles"`
+		Main    column            `conf:"main"`
+		Note    string            `conf:"note"`
+	}
+	fileName := filepath.Join(t.TempDir(), "nested.yaml")
+	content := `
+Nested:
+  columns: uid, age
+  ports: [8080, 8081]
+  titles:
+    uid: {width: 10, title: ID}
+  main:
+    width: 5
+  note: |
+    line1
+    line2
+`
+	if err := os.WriteFile(fileName, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got := Nested{}
+	if err := ParseConf(fileName, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := Nested{
+		Columns: []string{"uid", "age"},
+		Ports:   []int{8080, 8081},
+		Titles:  map[string]column{"uid": {Width: 10, Title: "ID"}},
+		Main:    column{Width: 5},
+		Note:    "line1\nline2\n",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseConf() = %+v, want %+v", got, want)
+	}
+	//类型错误时返回错误
+	if err := os.WriteFile(fileName, []byte("Nested:\n  ports: [a]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ParseConf(fileName, &Nested{}); err == nil {
+		t.Errorf("ParseConf() with invalid port: want error")
+	}
+}
@@ -0,0 +1,101 @@
+package conf
+
+import (
+	"crypto/rand"
+	"dbcache/comm"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+//配置项中的加密值.密码等可以加密后写在配置文件中,格式:ENC(base64密文),读取配置时(ParseConf)解密.
+//密钥从环境变量DBCACHE_CONF_KEY读取,未设置时从环境变量DBCACHE_CONF_KEY_FILE指定的文件读取(去掉首尾空白),
+//密钥长度为16,24或32字节(AES-128,192,256).加密值用命令生成: dbcache encrypt 明文
+//
+//密文 = 随机IV(16字节) + comm.EncryptAES128(标记+明文, 密钥, IV).
+//comm.DecryptAES128用密钥作为IV,CBC解密时只有第一块受IV影响,所以解密后去掉第一块(随机IV)和标记就是明文.
+//随机IV使相同的明文每次加密的结果不同,标记用于检查密钥是否正确.
+
+const (
+	ENV_CONF_KEY      = "DBCACHE_CONF_KEY"      //加密配置项的密钥
+	ENV_CONF_KEY_FILE = "DBCACHE_CONF_KEY_FILE" //加密配置项的密钥文件
+)
+
+const (
+	aesBlockSize = 16         //AES块大小
+	secretMark   = "dbcache:" //加密前加在明文前面的标记,解密后检查
+)
+
+//获取加密配置项的密钥
+func GetConfKey() (key []byte, err error) {
+	if v, ok := os.LookupEnv(ENV_CONF_KEY); ok {
+		key = []byte(v)
+	} else if fileName := os.Getenv(ENV_CONF_KEY_FILE); fileName != "" {
+		data, err := ioutil.ReadFile(fileName)
+		if err != nil {
+			return nil, fmt.Errorf("GetConfKey(),读取密钥文件[%s]失败, err: %s", fileName, err)
+		}
+		key = []byte(strings.TrimSpace(string(data)))
+	} else {
+		return nil, fmt.Errorf("GetConfKey(),未设置密钥,需设置环境变量%s或%s", ENV_CONF_KEY, ENV_CONF_KEY_FILE)
+	}
+	switch len(key) {
+	case 16, 24, 32:
+		return key, nil
+	}
+	return nil, fmt.Errorf("GetConfKey(),密钥长度应为16,24或32字节, 实际为%d", len(key))
+}
+
+//是否是加密值ENC(...)
+func isEncrypted(s string) bool {
+	s = strings.TrimSpace(s)
+	return strings.HasPrefix(s, "ENC(") && strings.HasSuffix(s, ")")
+}
+
+//加密明文,返回可写在配置文件中的ENC(base64密文).
+func EncryptValue(plain string) (value string, err error) {
+	key, err := GetConfKey()
+	if err != nil {
+		return "", err
+	}
+	iv := make([]byte, aesBlockSize)
+	if _, err = rand.Read(iv); err != nil {
+		return "", fmt.Errorf("EncryptValue(),生成IV失败, err: %s", err)
+	}
+	data, err := comm.EncryptAES128([]byte(secretMark+plain), key, iv)
+	if err != nil {
+		return "", fmt.Errorf("EncryptValue(),加密失败, err: %s", err)
+	}
+	return "ENC(" + base64.StdEncoding.EncodeToString(append(iv, data...)) + ")", nil
+}
+
+//解密ENC(base64密文),不是加密值时原样返回.
+func decryptValue(s string) (string, error) {
+	if !isEncrypted(s) {
+		return s, nil
+	}
+	s = strings.TrimSpace(s)
+	data, err := base64.StdEncoding.DecodeString(s[len("ENC(") : len(s)-1])
+	if err != nil {
+		return "", fmt.Errorf("加密值不是base64格式, err: %s", err)
+	}
+	if len(data) < aesBlockSize*2 {
+		return "", fmt.Errorf("加密值长度错误")
+	}
+	key, err := GetConfKey()
+	if err != nil {
+		return "", err
+	}
+	plain, err := comm.DecryptAES128(data, key)
+	if err != nil {
+		return "", fmt.Errorf("解密失败(密钥错误或密文已损坏), err: %s", err)
+	}
+	//第一块是随机IV解密的结果,去掉
+	plain = plain[aesBlockSize:]
+	if !strings.HasPrefix(string(plain), secretMark) {
+		return "", fmt.Errorf("解密失败,密钥错误或密文已损坏")
+	}
+	return string(plain[len(secretMark):]), nil
+}
@@ -0,0 +1,56 @@
+package conf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+//加密值ENC(...)在读取配置时解密
+func TestEncryptedValue(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(ENV_CONF_KEY, "0123456789abcdef")
+	value, err := EncryptValue("system")
+	if err != nil {
+		t.Fatal(err)
+	}
+	//相同的明文每次加密结果不同
+	if other, _ := EncryptValue("system"); other == value || !isEncrypted(value) {
+		t.Errorf("EncryptValue() = %s, %s", value, other)
+	}
+	fileName := filepath.Join(dir, "config.conf")
+	if err = os.WriteFile(fileName, []byte("[DbConfig]\npassword = "+value+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got := DbConfig{}
+	if err = ParseConf(fileName, &got); err != nil || got.Pwd != "system" {
+		t.Errorf("ParseConf() = %+v, %v, want password system", got, err)
+	}
+	//环境变量覆盖的值也可以加密
+	t.Setenv("DBCACHE_DBCONFIG_USER_NAME", value)
+	if err = ParseConf(fileName, &got); err != nil || got.User != "system" {
+		t.Errorf("ParseConf() = %+v, %v, want user_name system", got, err)
+	}
+	//密钥文件
+	keyFile := filepath.Join(dir, "dbcache.key")
+	if err = os.WriteFile(keyFile, []byte("0123456789abcdef\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	os.Unsetenv(ENV_CONF_KEY)
+	t.Setenv(ENV_CONF_KEY_FILE, keyFile)
+	if s, err := decryptValue(value); err != nil || s != "system" {
+		t.Errorf("decryptValue() with key file = %q, %v", s, err)
+	}
+	//密钥错误,密文损坏,未设置密钥
+	t.Setenv(ENV_CONF_KEY, "fedcba9876543210")
+	for _, s := range []string{value, "ENC(abc)", "ENC(" + value[4:20] + ")"} {
+		if got, err := decryptValue(s); err == nil {
+			t.Errorf("decryptValue(%s) = %q, want error", s, got)
+		}
+	}
+	os.Unsetenv(ENV_CONF_KEY)
+	t.Setenv(ENV_CONF_KEY_FILE, "")
+	if err = ParseConf(fileName, &DbConfig{}); err == nil {
+		t.Errorf("ParseConf() without key: want error")
+	}
+}
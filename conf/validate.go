@@ -0,0 +1,358 @@
+package conf
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+//检查配置文件.读取配置时,拼写错误的配置项会被忽略,主键不在columns中,cache_type错误等只在运行时才表现为异常,
+//所以启动前可以先检查(dbcache validate):配置项的类型和取值,表配置中的列和排序,及(见cache.ValidateDB)数据库中的表和列.
+
+//问题等级
+const (
+	LEVEL_OK      = "通过"
+	LEVEL_WARNING = "警告"
+	LEVEL_ERROR   = "错误"
+)
+
+//检查发现的一个问题
+type Problem struct {
+	Level   string //等级:通过,警告,错误
+	File    string //配置文件
+	Section string //分段名
+	Key     string //配置项,为空时是整个分段
+	Message string //说明
+}
+
+func (p Problem) String() string {
+	where := p.File
+	if p.Section != "" {
+		where += " [" + p.Section + "]"
+	}
+	if p.Key != "" {
+		where += " " + p.Key
+	}
+	return fmt.Sprintf("%s %s: %s", p.Level, where, p.Message)
+}
+
+//检查报告
+type Report struct {
+	Problems []Problem
+}
+
+//记录检查通过
+func (r *Report) Ok(file, section, format string, a ...interface{}) {
+	r.add(LEVEL_OK, file, section, "", format, a...)
+}
+
+//记录警告:配置可以运行,但可能不是想要的结果.
+func (r *Report) Warning(file, section, key, format string, a ...interface{}) {
+	r.add(LEVEL_WARNING, file, section, key, format, a...)
+}
+
+//记录错误:配置不能正确运行.
+func (r *Report) Error(file, section, key, format string, a ...interface{}) {
+	r.add(LEVEL_ERROR, file, section, key, format, a...)
+}
+
+func (r *Report) add(level, file, section, key, format string, a ...interface{}) {
+	r.Problems = append(r.Problems, Problem{Level: level, File: file, Section: section, Key: key, Message: fmt.Sprintf(format, a...)})
+}
+
+//指定等级的问题数
+func (r *Report) Count(level string) (n int) {
+	for _, p := range r.Problems {
+		if p.Level == level {
+			n++
+		}
+	}
+	return n
+}
+
+//是否有错误
+func (r *Report) HasError() bool {
+	return r.Count(LEVEL_ERROR) > 0
+}
+
+//按顺序输出所有问题,最后一行是错误和警告数.
+func (r *Report) String() string {
+	var b strings.Builder
+	for _, p := range r.Problems {
+		b.WriteString(p.String())
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "检查完成: %d个错误, %d个警告\n", r.Count(LEVEL_ERROR), r.Count(LEVEL_WARNING))
+	return b.String()
+}
+
+//检查配置文件中的一个分段,按conf标签读取到结构体result(指针):
+//分段不存在时,required为true记录错误;配置项不是结构体的conf标签时记录警告(可能拼写错误);类型错误记录错误.
+//返回是否读取成功.
+func CheckSection(r *Report, fileName string, sectionName string, result interface{}, required bool) bool {
+	sections, err := LoadConf(fileName)
+	if err != nil {
+		r.Error(fileName, "", "", "%s", err)
+		return false
+	}
+	tags := make(map[string]bool)
+	t := reflect.TypeOf(result).Elem()
+	for i := 0; i < t.NumField(); i++ {
+		tags[t.Field(i).Tag.Get("conf")] = true
+	}
+	found := false
+	for _, section := range sections {
+		if strings.ToUpper(section.Name) != strings.ToUpper(sectionName) {
+			continue
+		}
+		found = true
+		for _, key := range section.Keys {
+			if !tags[key] {
+				r.Warning(fileName, section.Name, key, "未知的配置项,会被忽略(是否拼写错误?)")
+			}
+		}
+	}
+	if !found {
+		if required {
+			r.Error(fileName, sectionName, "", "缺少分段")
+		}
+		return false
+	}
+	err = parseConfGroup(fileName, sectionName, result)
+	if err != nil {
+		r.Error(fileName, sectionName, "", "%s", err)
+		return false
+	}
+	return true
+}
+
+//检查config.conf(fileName)中数据库,rpc和grpc的配置.日志的配置见logs.ValidateConf().
+func ValidateConfig(r *Report, fileName string) {
+	dbConfig := DbConfig{}
+	if CheckSection(r, fileName, "DbConfig", &dbConfig, true) {
+		switch strings.ToLower(strings.TrimSpace(dbConfig.Driver)) {
+		case "", "mysql", "postgres":
+			checkRequired(r, fileName, "DbConfig", &dbConfig, "user_name", "ip_address", "ip_port", "db_name")
+			checkPort(r, fileName, "DbConfig", "ip_port", dbConfig.Port)
+		case "sqlite":
+			//只需数据库文件路径
+			checkRequired(r, fileName, "DbConfig", &dbConfig, "db_name")
+		default:
+			r.Error(fileName, "DbConfig", "driver", "应为mysql,postgres或sqlite, 实际为[%s]", dbConfig.Driver)
+		}
+	}
+	rpcServer := RpcServer{}
+	if CheckSection(r, fileName, "RpcServer", &rpcServer, true) {
+		if rpcServer.RpcType != "rpc" && rpcServer.RpcType != "jsonrpc" {
+			r.Error(fileName, "RpcServer", "rpc_type", "应为rpc或jsonrpc, 实际为[%s]", rpcServer.RpcType)
+		}
+		checkProtocol(r, fileName, "RpcServer", rpcServer.Protocol)
+		checkPort(r, fileName, "RpcServer", "ip_port", rpcServer.Port)
+		checkAdminToken(r, fileName, "RpcServer", rpcServer.AdminToken)
+	}
+	grpcServer := GrpcServer{}
+	if CheckSection(r, fileName, "GrpcServer", &grpcServer, true) {
+		checkProtocol(r, fileName, "GrpcServer", grpcServer.Protocol)
+		checkPort(r, fileName, "GrpcServer", "ip_port", grpcServer.Port)
+		checkAdminToken(r, fileName, "GrpcServer", grpcServer.AdminToken)
+	}
+}
+
+//检查管理接口的token:未配置时管理接口关闭,太短时容易被猜到.
+func checkAdminToken(r *Report, fileName string, section string, token string) {
+	if token != "" && len(token) < 16 {
+		r.Warning(fileName, section, "admin_token", "长度小于16,容易被猜到")
+	}
+}
+
+//检查字符串配置项不为空
+func checkRequired(r *Report, fileName, section string, result interface{}, keys ...string) {
+	v := reflect.ValueOf(result).Elem()
+	t := v.Type()
+	for _, key := range keys {
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).Tag.Get("conf") == key && strings.TrimSpace(v.Field(i).String()) == "" {
+				r.Error(fileName, section, key, "不能为空")
+			}
+		}
+	}
+}
+
+//检查端口在1-65535之间
+func checkPort(r *Report, fileName, section, key, port string) {
+	var n int
+	if _, err := fmt.Sscanf(port, "%d", &n); err != nil || n < 1 || n > 65535 || fmt.Sprint(n) != port {
+		r.Error(fileName, section, key, "端口应在1-65535之间, 实际为[%s]", port)
+	}
+}
+
+//检查网络协议
+func checkProtocol(r *Report, fileName, section, protocol string) {
+	switch protocol {
+	case "tcp", "tcp4", "tcp6":
+	default:
+		r.Error(fileName, section, "protocol", "应为tcp,tcp4或tcp6, 实际为[%s]", protocol)
+	}
+}
+
+//缓存类型
+var cacheTypes = map[string]bool{"slice": true, "sliceNotDel": true, "link": true, "tree": true}
+
+//检查cache.conf(fileName)中所有表和[DataAsync]的配置,返回读取成功的表配置(用于检查数据库),按分段名保存.
+func ValidateTables(r *Report, fileName string) (tables map[string]CacheTable) {
+	dataAsync := DataAsync{}
+	if CheckSection(r, fileName, "DataAsync", &dataAsync, true) {
+		if dataAsync.AsyncMaxChan < 1 {
+			r.Error(fileName, "DataAsync", "async_max_chan", "应大于0, 实际为%d", dataAsync.AsyncMaxChan)
+		}
+		if dataAsync.MaxAsyncFileSize < 1 {
+			r.Error(fileName, "DataAsync", "max_async_file_size", "应大于0, 实际为%d", dataAsync.MaxAsyncFileSize)
+		}
+		checkRequired(r, fileName, "DataAsync", &dataAsync, "async_file_name", "async_failed_file_name")
+		if info, err := os.Stat(dataAsync.AsyncFilePath); err != nil || !info.IsDir() {
+			r.Warning(fileName, "DataAsync", "async_file_path", "目录[%s]不存在", dataAsync.AsyncFilePath)
+		}
+	}
+	names, err := GetCacheTableSections(fileName)
+	if err != nil {
+		r.Error(fileName, "", "", "%s", err)
+		return nil
+	}
+	if len(names) == 0 {
+		r.Warning(fileName, "", "", "没有配置缓存表(table_name)")
+	}
+	tables = make(map[string]CacheTable, len(names))
+	isName := make(map[string]bool)
+	for _, name := range names {
+		//分段名是缓存表的名称,不区分大小写.同一个表可以配置在多个分段中(别名).
+		if isName[strings.ToUpper(name)] {
+			r.Error(fileName, name, "", "分段名重复")
+			continue
+		}
+		isName[strings.ToUpper(name)] = true
+		table := CacheTable{}
+		if !CheckSection(r, fileName, name, &table, true) {
+			continue
+		}
+		if validateTable(r, fileName, name, &table) {
+			r.Ok(fileName, name, "表配置检查通过")
+		}
+		tables[name] = table
+	}
+	return tables
+}
+
+//检查一个表的配置,fileName是cache.conf(用于检查报告),name是分段名,返回是否没有错误.
+func validateTable(r *Report, fileName string, name string, table *CacheTable) bool {
+	errors := r.Count(LEVEL_ERROR)
+	checkRequired(r, fileName, name, table, "table_name", "columns", "pkey")
+	//列名不区分大小写
+	isColumn := make(map[string]bool)
+	for _, column := range table.GetColumns() {
+		if column == "" {
+			r.Error(fileName, name, "columns", "有空的列名")
+			continue
+		}
+		if isColumn[strings.ToLower(column)] {
+			r.Error(fileName, name, "columns", "列[%s]重复", column)
+		}
+		isColumn[strings.ToLower(column)] = true
+	}
+	if table.Pkey != "" && !isColumn[strings.ToLower(table.Pkey)] {
+		r.Error(fileName, name, "pkey", "主键[%s]不在columns中", table.Pkey)
+	}
+	if !cacheTypes[table.CacheType] {
+		if table.CacheType == "" {
+			r.Warning(fileName, name, "cache_type", "未配置,不能分页查询")
+		} else {
+			r.Error(fileName, name, "cache_type", "应为slice,sliceNotDel,link或tree, 实际为[%s]", table.CacheType)
+		}
+	}
+	//排序列必须缓存,分页缓存按缓存中的值排序
+	keys, err := parseSortKeys(table.Other)
+	if err != nil {
+		r.Error(fileName, name, "other", "order by格式错误: %s", err)
+	}
+	for _, key := range keys {
+		if !isColumn[key.Column] {
+			r.Error(fileName, name, "other", "排序列[%s]不在columns中", key.Column)
+		}
+	}
+	views, err := table.GetSortViews()
+	if err != nil {
+		r.Error(fileName, name, "sort_views", "%s", err)
+	}
+	for _, view := range views {
+		for _, key := range view.Keys {
+			if !isColumn[strings.ToLower(key.Column)] {
+				r.Error(fileName, name, "sort_views", "排序视图[%s]的排序列[%s]不在columns中", view.Name, key.Column)
+			}
+		}
+	}
+	//列的访问策略
+	isSortColumn := make(map[string]bool)
+	for _, key := range keys {
+		isSortColumn[strings.ToLower(key.Column)] = true
+	}
+	for _, view := range views {
+		for _, key := range view.Keys {
+			isSortColumn[strings.ToLower(key.Column)] = true
+		}
+	}
+	isHidden := make(map[string]bool)
+	for _, column := range table.GetHiddenColumns() {
+		checkPolicyColumn(r, fileName, name, table, "hidden_columns", column, isColumn, isSortColumn)
+		isHidden[strings.ToLower(column)] = true
+	}
+	masks, err := table.GetMaskedColumns()
+	if err != nil {
+		r.Error(fileName, name, "masked_columns", "%s", err)
+	}
+	maskColumns := make([]string, 0, len(masks))
+	for column := range masks {
+		maskColumns = append(maskColumns, column)
+	}
+	sort.Strings(maskColumns)
+	for _, column := range maskColumns {
+		checkPolicyColumn(r, fileName, name, table, "masked_columns", column, isColumn, isSortColumn)
+		if isHidden[strings.ToLower(column)] {
+			r.Error(fileName, name, "masked_columns", "列[%s]已在hidden_columns中", column)
+		}
+	}
+	for _, column := range table.GetReadonlyColumns() {
+		if !isColumn[strings.ToLower(column)] {
+			r.Error(fileName, name, "readonly_columns", "列[%s]不在columns中", column)
+		}
+	}
+	if table.IsRealtime && table.IsWaitResult {
+		r.Warning(fileName, name, "is_wait_result", "实时更新(is_realtime = true)时不起作用")
+	}
+	//切片整理的配置
+	if _, _, err = getHourRange(table.CompactHours); table.CompactHours != "" && err != nil {
+		r.Error(fileName, name, "compact_hours", "%s", err)
+	}
+	if table.CompactRatio < 0 || table.CompactRatio > 1 {
+		r.Error(fileName, name, "compact_ratio", "应在0到1之间, 实际为%g", table.CompactRatio)
+	}
+	if table.CompactInterval < 0 || table.CompactMaxDeleted < 0 {
+		r.Error(fileName, name, "compact_interval", "compact_interval和compact_max_deleted不能小于0")
+	}
+	if table.CacheType != "sliceNotDel" && (table.CompactInterval != 0 || table.CompactHours != "" || table.CompactMaxDeleted != 0 || table.CompactRatio != 0) {
+		r.Warning(fileName, name, "cache_type", "compact_*只用于缓存类型sliceNotDel")
+	}
+	return r.Count(LEVEL_ERROR) == errors
+}
+
+//检查隐藏或脱敏的列:必须缓存,不能是主键,不能用于排序(分页游标中包含排序列和主键的值).
+func checkPolicyColumn(r *Report, fileName string, name string, table *CacheTable, key string, column string, isColumn map[string]bool, isSortColumn map[string]bool) {
+	switch {
+	case !isColumn[strings.ToLower(column)]:
+		r.Error(fileName, name, key, "列[%s]不在columns中", column)
+	case strings.EqualFold(column, table.Pkey):
+		r.Error(fileName, name, key, "不能是主键[%s]", column)
+	case isSortColumn[strings.ToLower(column)]:
+		r.Error(fileName, name, key, "列[%s]不能用于排序(other,sort_views),分页游标中包含排序列的值", column)
+	}
+}
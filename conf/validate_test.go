@@ -0,0 +1,99 @@
+package conf
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+//检查cache.conf中表的配置
+func TestValidateTables(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "cache.conf")
+	content := `
+[Users]
+table_name = users
+columns = uid,age,name
+pkey = id
+cache_type = lnk
+other = order by agee desc
+is_wait_reslt = true
+sort_views = name:name asc,bad:address desc
+
+[Goods]
+table_name = goods
+columns = goods_id,price
+pkey = goods_id
+cache_type = slice
+other = order by price desc
+
+[DataAsync]
+async_max_chan = 1000
+async_file_path = ./
+async_file_name = async_sql.sql
+async_failed_file_name = async_sql_failed.sql
+max_async_file_size = 512
+`
+	if err := os.WriteFile(fileName, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	r := &Report{}
+	tables := ValidateTables(r, fileName)
+	if len(tables) != 2 {
+		t.Errorf("ValidateTables() returned %d tables, want 2", len(tables))
+	}
+	report := r.String()
+	for _, want := range []string{
+		"错误 " + fileName + " [Users] pkey: 主键[id]不在columns中",
+		"错误 " + fileName + " [Users] cache_type: 应为slice,sliceNotDel,link或tree, 实际为[lnk]",
+		"错误 " + fileName + " [Users] other: 排序列[agee]不在columns中",
+		"错误 " + fileName + " [Users] sort_views: 排序视图[bad]的排序列[address]不在columns中",
+		"警告 " + fileName + " [Users] is_wait_reslt: 未知的配置项",
+		"通过 " + fileName + " [Goods]: 表配置检查通过",
+		"检查完成: 4个错误, 1个警告",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report missing %q:\n%s", want, report)
+		}
+	}
+	if !r.HasError() {
+		t.Errorf("HasError() = false")
+	}
+}
+
+//检查列的访问策略
+func TestValidateColumnPolicy(t *testing.T) {
+	table := CacheTable{
+		TableName:       "users",
+		Columns:         "uid,age,name,password,phone",
+		Pkey:            "uid",
+		CacheType:       "tree",
+		Other:           "order by age desc",
+		HiddenColumns:   "password,uid",
+		MaskedColumns:   "phone:4,age,password,email",
+		ReadonlyColumns: "name,address",
+	}
+	r := &Report{}
+	if validateTable(r, "", table.TableName, &table) {
+		t.Errorf("validateTable() = true, want errors")
+	}
+	report := r.String()
+	for _, want := range []string{
+		"[users] hidden_columns: 不能是主键[uid]",
+		"[users] masked_columns: 列[age]不能用于排序",
+		"[users] masked_columns: 列[email]不在columns中",
+		"[users] masked_columns: 列[password]已在hidden_columns中",
+		"[users] readonly_columns: 列[address]不在columns中",
+		"检查完成: 5个错误",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report missing %q:\n%s", want, report)
+		}
+	}
+	table.MaskedColumns = "phone:-1"
+	r = &Report{}
+	validateTable(r, "", table.TableName, &table)
+	if !strings.Contains(r.String(), "[users] masked_columns: getMaskedColumns(),列[phone]的显示字符数应为不小于0的整数") {
+		t.Errorf("report missing masked_columns error:\n%s", r.String())
+	}
+}
@@ -3,8 +3,10 @@ package db
 import (
 	"database/sql"
 	"dbcache/conf"
+	"dbcache/dialect"
 	"fmt"
 	_ "github.com/go-sql-driver/mysql"
+	"sync"
 	"time"
 )
 
@@ -17,6 +19,9 @@ var (
 	//stmt *sql.Stmt
 	//rows *sql.Rows
 	DbConfig = conf.DbConfig{} //[配置文件config.conf]保存数据库配置信息
+	pingStop = make(chan struct{}) //通知后台ping协程退出
+	pingWg   sync.WaitGroup        //等待后台ping协程退出
+	stopOnce sync.Once             //保证只关闭一次
 )
 
 //连接数据库
@@ -28,20 +33,72 @@ func initDB() (db *sql.DB, err error) {
 		err = fmt.Errorf("initDB(),Read the database login configuration file [%s] faild, err: %s", conf.CONFIG_FILE, err)
 		return nil, err
 	}
+	db, err = open(DbConfig)
+	if err != nil {
+		err = fmt.Errorf("initDB(), err: %s", err)
+		return nil, err
+	}
+	dbCon=db
+	return dbCon, err
+}
 
-	dataSource := DbConfig.User + ":" + DbConfig.Pwd + "@tcp(" + DbConfig.Ip + ":" + DbConfig.Port + ")/" + DbConfig.DatabaseName + "?" + DbConfig.ConnectInfo
-	db, err = sql.Open("mysql", dataSource)
+//按配置文件(config.conf)中的[DbConfig]连接数据库,返回新的数据库对象,不保存在包中.
+//用于同一进程中运行多个实例(见server包),每个实例有自己的数据库连接.sql.DB的连接池断开后会自动重连,不需后台ping.
+func Open(configFile string) (db *sql.DB, err error) {
+	dbConfig := conf.DbConfig{}
+	err = conf.ParseConf(configFile, &dbConfig)
 	if err != nil {
-		err = fmt.Errorf("initDB(),Database connection failed, err: %s", err)
+		err = fmt.Errorf("Open(),Read the database login configuration file [%s] faild, err: %s", configFile, err)
+		return nil, err
+	}
+	db, err = open(dbConfig)
+	if err != nil {
+		err = fmt.Errorf("Open(), err: %s", err)
+		return nil, err
+	}
+	return db, nil
+}
+
+//按数据库配置连接数据库,连接失败时关闭.按driver选择数据库方言,绑定到连接(cache包按方言生成sql语句).
+func open(dbConfig conf.DbConfig) (db *sql.DB, err error) {
+	d, err := dialect.Get(dbConfig.Driver)
+	if err != nil {
+		err = fmt.Errorf("open(), err: %s", err)
+		return nil, err
+	}
+	if !isDriverRegistered(d.DriverName()) {
+		err = fmt.Errorf("open(),未编译数据库[%s]的驱动,编译时需加: -tags %s", d.Name(), d.Name())
+		return nil, err
+	}
+	db, err = sql.Open(d.DriverName(), d.DataSource(dbConfig))
+	if err != nil {
+		err = fmt.Errorf("open(),Database connection failed, err: %s", err)
 		return nil, err
 	}
 	err = db.Ping()
 	if err != nil {
-		err = fmt.Errorf("initDB(),Database connection failed, err: %s", err)
+		db.Close()
+		err = fmt.Errorf("open(),Database connection failed, err: %s", err)
 		return nil, err
 	}
-	dbCon=db
-	return dbCon, err
+	dialect.Bind(db, d)
+	return db, nil
+}
+
+//database/sql中是否已注册驱动
+func isDriverRegistered(driverName string) bool {
+	for _, name := range sql.Drivers() {
+		if name == driverName {
+			return true
+		}
+	}
+	return false
+}
+
+//关闭Open()返回的数据库连接,解除方言的绑定.
+func Close(db *sql.DB) error {
+	dialect.Unbind(db)
+	return db.Close()
 }
 
 func ConnectDB() (db *sql.DB, err error) {
@@ -54,17 +111,28 @@ func ConnectDB() (db *sql.DB, err error) {
 	//DbConn.SetMaxOpenConns(100)                  // 最大打开连接数,默认值为0表示不限制。
 	//DbConn.SetMaxIdleConns(5)                  // 最大空闲连接数,默认的最大空闲连接数是2
 	//DbConn.SetConnMaxLifetime(time.Second * 60)    // 设置一个连接可以重用的最大时间量。连接过期时间如不设置 连接会被一直保持
+	pingWg.Add(1)
 	go dbPing()
 	return db, err
 }
 
+//后台定时ping数据库,断开则重连.收到退出通知时退出.
 func dbPing() {
+	defer pingWg.Done()
 	for {
-		time.Sleep(time.Second * time.Duration(PING_TIME))
+		select {
+		case <-time.After(time.Second * time.Duration(PING_TIME)):
+		case <-pingStop:
+			return
+		}
 		err := dbCon.Ping()
 		if err != nil {
 			for {
-				time.Sleep(time.Second * 60)
+				select {
+				case <-time.After(time.Second * 60):
+				case <-pingStop:
+					return
+				}
 				dbCon, err = initDB()
 				if err == nil {
 					break
@@ -123,8 +191,13 @@ func DQL(sql string,args ...interface{})(rows *sql.Rows,err error){
 	return rows,nil
 }
 
+//停止后台ping协程,关闭数据库连接.
 func CloseConn(){
+	stopOnce.Do(func() {
+		close(pingStop)
+	})
+	pingWg.Wait()
 	if dbCon!=nil{
-		dbCon.Close()
+		Close(dbCon)
 	}
 }
\ No newline at end of file
@@ -0,0 +1,7 @@
+//go:build postgres
+// +build postgres
+
+package db
+
+//PostgreSQL驱动,[DbConfig]中driver = postgres时使用.编译: go build -tags postgres
+import _ "github.com/lib/pq"
@@ -0,0 +1,7 @@
+//go:build sqlite
+// +build sqlite
+
+package db
+
+//SQLite驱动(需cgo),[DbConfig]中driver = sqlite时使用.编译: go build -tags sqlite
+import _ "github.com/mattn/go-sqlite3"
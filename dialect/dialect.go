@@ -0,0 +1,152 @@
+package dialect
+
+import (
+	"database/sql"
+	"dbcache/conf"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+//数据库方言:不同数据库的连接字符串,标识符引用,占位符,插入和插入或更新(upsert)的语法,取得自增主键值的方式,读取表结构.
+//db包按config.conf中[DbConfig]的driver选择方言连接数据库,cache包按数据库连接的方言生成sql语句.
+//支持mysql(默认),postgres,sqlite.postgres和sqlite的驱动在编译时选择(见db包driver_*.go):go build -tags "postgres sqlite"
+
+//数据库方言
+type Dialect interface {
+	Name() string                                                                  //方言名,即driver配置的值
+	DriverName() string                                                            //database/sql的驱动名
+	DataSource(dbConfig conf.DbConfig) string                                      //按[DbConfig]生成连接字符串
+	Quote(identifier string) string                                                //引用标识符(表名,列名).含.时(库名.表名)分别引用
+	Placeholder(i int) string                                                      //第i个(从1开始)参数的占位符
+	HasDefault() bool                                                              //插入多行时,VALUES中是否支持DEFAULT(没有的列用默认值)
+	Insert(table string, columns []string, rows [][]string) string                 //插入多行的INSERT语句,值是占位符或DEFAULT
+	Upsert(table string, pkey string, columns []string, values []string) string    //插入一行,主键存在时更新,值是占位符
+	UpsertIsInsert(n int64) (isInsert bool, ok bool)                               //根据upsert受影响的行数判断是否插入,ok为false时不能判断
+	Returning(pkey string) string                                                  //插入时返回自增主键值的子句,为空时用LastInsertId()
+	Exec(db *sql.DB, query string, args ...interface{}) (result Result, err error) //执行sql,返回受影响的行数和插入的行的自增主键值
+	AutoIncrementStep(db *sql.DB) (step int64, err error)                          //自增列的步长,用于按第一行的自增主键值推算其它行
+	Columns(db *sql.DB, table string) (columns []Column, err error)                //读取表的列,表不存在时为空
+	UniqueIndexes(db *sql.DB, table string) (indexes [][]string, err error)        //读取表的主键和唯一索引的列
+}
+
+//执行sql语句的结果
+type Result struct {
+	RowsAffected int64   //受影响的行数
+	InsertIds    []int64 //插入的行的自增主键值,按插入顺序.RETURNING返回所有行的值,LastInsertId()只有第一行的值,没有时为空
+}
+
+//插入的第k行(从0开始)的自增主键值,没有时为0.只有第一行的值时,按自增步长step推算(同一条INSERT语句插入的多行是连续分配的).
+func (r Result) InsertId(k int, step int64) int64 {
+	if k < len(r.InsertIds) {
+		return r.InsertIds[k]
+	}
+	if len(r.InsertIds) == 0 {
+		return 0
+	}
+	return r.InsertIds[0] + int64(k)*step
+}
+
+//表中的一列
+type Column struct {
+	Name          string //列名
+	IsPkey        bool   //是否为主键的列
+	AutoIncrement bool   //是否为自增列
+}
+
+//方言名 -> 方言
+var dialects = map[string]Dialect{
+	"mysql":    MySQL{},
+	"postgres": PostgreSQL{},
+	"sqlite":   SQLite{},
+}
+
+//默认方言
+var Default Dialect = MySQL{}
+
+//按driver配置的值获取方言,为空时是mysql.
+func Get(name string) (d Dialect, err error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return Default, nil
+	}
+	d, ok := dialects[name]
+	if !ok {
+		return nil, fmt.Errorf("Get(),不支持的数据库[%s],应为mysql,postgres或sqlite", name)
+	}
+	return d, nil
+}
+
+//数据库连接 -> 方言.db包连接数据库时绑定,cache包按连接获取方言.
+var bound sync.Map
+
+//绑定数据库连接的方言.
+func Bind(db *sql.DB, d Dialect) {
+	bound.Store(db, d)
+}
+
+//解除绑定,关闭数据库连接时调用.
+func Unbind(db *sql.DB) {
+	bound.Delete(db)
+}
+
+//获取数据库连接的方言,未绑定(不是db包连接的数据库)时是默认方言(mysql).
+func Of(db *sql.DB) Dialect {
+	if db != nil {
+		if d, ok := bound.Load(db); ok {
+			return d.(Dialect)
+		}
+	}
+	return Default
+}
+
+//用q引用标识符,含.时分别引用,标识符中的q重复一次.
+func quoteIdentifier(identifier string, q string) string {
+	parts := strings.Split(identifier, ".")
+	for i, part := range parts {
+		parts[i] = q + strings.Replace(strings.TrimSpace(part), q, q+q, -1) + q
+	}
+	return strings.Join(parts, ".")
+}
+
+//生成插入多行的INSERT语句,rows中的值是占位符或DEFAULT.
+func insertSql(d Dialect, table string, columns []string, rows [][]string) string {
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = d.Quote(column)
+	}
+	values := make([]string, len(rows))
+	for i, row := range rows {
+		values[i] = "(" + strings.Join(row, ",") + ")"
+	}
+	return "INSERT INTO " + d.Quote(table) + " (" + strings.Join(quoted, ",") + ") VALUES " + strings.Join(values, ",")
+}
+
+//ON CONFLICT语法的upsert(postgres,sqlite):主键冲突时更新其它列,只有主键时不做任何更新.
+func upsertOnConflict(d Dialect, table string, pkey string, columns []string, values []string) string {
+	updates := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if column != pkey {
+			updates = append(updates, d.Quote(column)+"=EXCLUDED."+d.Quote(column))
+		}
+	}
+	sqlString := insertSql(d, table, columns, [][]string{values}) + " ON CONFLICT (" + d.Quote(pkey) + ")"
+	if len(updates) == 0 {
+		return sqlString + " DO NOTHING"
+	}
+	return sqlString + " DO UPDATE SET " + strings.Join(updates, ",")
+}
+
+//执行sql语句,返回受影响的行数和LastInsertId()(不支持或为0时没有自增主键值).
+func execResult(db *sql.DB, query string, args []interface{}) (result Result, insertId int64, err error) {
+	rs, err := db.Exec(query, args...)
+	if err != nil {
+		return result, 0, err
+	}
+	result.RowsAffected, err = rs.RowsAffected()
+	if err != nil {
+		return result, 0, err
+	}
+	insertId, _ = rs.LastInsertId()
+	return result, insertId, nil
+}
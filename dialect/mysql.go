@@ -0,0 +1,124 @@
+package dialect
+
+import (
+	"database/sql"
+	"dbcache/conf"
+	"strings"
+)
+
+//MySQL:反引号引用标识符,?占位符,INSERT ... ON DUPLICATE KEY UPDATE,LastInsertId()是插入的第一行的自增主键值.
+type MySQL struct{}
+
+func (MySQL) Name() string       { return "mysql" }
+func (MySQL) DriverName() string { return "mysql" }
+func (MySQL) HasDefault() bool   { return true }
+
+//用户名:密码@tcp(ip:端口)/库名?connect_info
+func (MySQL) DataSource(dbConfig conf.DbConfig) string {
+	return dbConfig.User + ":" + dbConfig.Pwd + "@tcp(" + dbConfig.Ip + ":" + dbConfig.Port + ")/" + dbConfig.DatabaseName + "?" + dbConfig.ConnectInfo
+}
+
+func (MySQL) Quote(identifier string) string {
+	return quoteIdentifier(identifier, "`")
+}
+
+func (MySQL) Placeholder(i int) string {
+	return "?"
+}
+
+func (m MySQL) Insert(table string, columns []string, rows [][]string) string {
+	return insertSql(m, table, columns, rows)
+}
+
+//主键冲突时更新其它列,只有主键时更新主键为原值(不做任何更新).
+func (m MySQL) Upsert(table string, pkey string, columns []string, values []string) string {
+	updates := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if column != pkey {
+			updates = append(updates, m.Quote(column)+"=VALUES("+m.Quote(column)+")")
+		}
+	}
+	if len(updates) == 0 {
+		updates = append(updates, m.Quote(pkey)+"="+m.Quote(pkey))
+	}
+	return insertSql(m, table, columns, [][]string{values}) + " ON DUPLICATE KEY UPDATE " + strings.Join(updates, ",")
+}
+
+//受影响的行数:1插入,2更新,0数据未变化.
+func (MySQL) UpsertIsInsert(n int64) (isInsert bool, ok bool) {
+	return n == 1, true
+}
+
+func (MySQL) Returning(pkey string) string {
+	return ""
+}
+
+//同一条INSERT语句插入的多行,LastInsertId()是第一行的自增主键值.
+func (MySQL) Exec(db *sql.DB, query string, args ...interface{}) (result Result, err error) {
+	result, insertId, err := execResult(db, query, args)
+	if err == nil && insertId > 0 {
+		result.InsertIds = []int64{insertId}
+	}
+	return result, err
+}
+
+//auto_increment_increment可以大于1(例如多主复制),插入的多行的自增主键值按此步长递增.
+func (MySQL) AutoIncrementStep(db *sql.DB) (step int64, err error) {
+	err = db.QueryRow("SELECT @@auto_increment_increment").Scan(&step)
+	if err != nil {
+		return 0, err
+	}
+	if step < 1 {
+		step = 1
+	}
+	return step, nil
+}
+
+func (MySQL) Columns(db *sql.DB, table string) (columns []Column, err error) {
+	rows, err := db.Query("SELECT column_name, column_key, extra FROM information_schema.columns WHERE table_schema = DATABASE() AND table_name = ? ORDER BY ordinal_position", table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name, key, extra string
+		if err = rows.Scan(&name, &key, &extra); err != nil {
+			return nil, err
+		}
+		//column_key为PRI:主键的列(没有主键时,不为空的唯一索引的列)
+		columns = append(columns, Column{
+			Name:          name,
+			IsPkey:        strings.EqualFold(key, "PRI"),
+			AutoIncrement: strings.Contains(strings.ToLower(extra), "auto_increment"),
+		})
+	}
+	return columns, rows.Err()
+}
+
+func (MySQL) UniqueIndexes(db *sql.DB, table string) (indexes [][]string, err error) {
+	rows, err := db.Query("SELECT index_name, column_name FROM information_schema.statistics WHERE table_schema = DATABASE() AND table_name = ? AND non_unique = 0 ORDER BY index_name, seq_in_index", table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanIndexes(rows)
+}
+
+//读取(索引名,列名)的结果,按索引名合并为每个索引的列.
+func scanIndexes(rows *sql.Rows) (indexes [][]string, err error) {
+	position := make(map[string]int)
+	for rows.Next() {
+		var index, column string
+		if err = rows.Scan(&index, &column); err != nil {
+			return nil, err
+		}
+		i, ok := position[index]
+		if !ok {
+			i = len(indexes)
+			position[index] = i
+			indexes = append(indexes, nil)
+		}
+		indexes[i] = append(indexes[i], column)
+	}
+	return indexes, rows.Err()
+}
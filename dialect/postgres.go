@@ -0,0 +1,138 @@
+package dialect
+
+import (
+	"database/sql"
+	"dbcache/conf"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+//PostgreSQL:双引号引用标识符(区分大小写),$n占位符,INSERT ... ON CONFLICT,用RETURNING取得自增主键值(驱动不支持LastInsertId()).
+//驱动是github.com/lib/pq,编译时加 -tags postgres.
+type PostgreSQL struct{}
+
+func (PostgreSQL) Name() string       { return "postgres" }
+func (PostgreSQL) DriverName() string { return "postgres" }
+func (PostgreSQL) HasDefault() bool   { return true }
+
+//postgres://用户名:密码@ip:端口/库名?connect_info,例如connect_info = sslmode=disable
+func (PostgreSQL) DataSource(dbConfig conf.DbConfig) string {
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(dbConfig.User, dbConfig.Pwd),
+		Host:     net.JoinHostPort(dbConfig.Ip, dbConfig.Port),
+		Path:     "/" + dbConfig.DatabaseName,
+		RawQuery: dbConfig.ConnectInfo,
+	}
+	return u.String()
+}
+
+func (PostgreSQL) Quote(identifier string) string {
+	return quoteIdentifier(identifier, `"`)
+}
+
+func (PostgreSQL) Placeholder(i int) string {
+	return "$" + strconv.Itoa(i)
+}
+
+func (p PostgreSQL) Insert(table string, columns []string, rows [][]string) string {
+	return insertSql(p, table, columns, rows)
+}
+
+func (p PostgreSQL) Upsert(table string, pkey string, columns []string, values []string) string {
+	return upsertOnConflict(p, table, pkey, columns, values)
+}
+
+//插入和更新受影响的行数都是1,不能判断.
+func (PostgreSQL) UpsertIsInsert(n int64) (isInsert bool, ok bool) {
+	return false, false
+}
+
+func (p PostgreSQL) Returning(pkey string) string {
+	return " RETURNING " + p.Quote(pkey)
+}
+
+//Returning()生成的子句,在语句末尾
+var returningRegexp = regexp.MustCompile(`\sRETURNING\s+"(?:[^"]|"")*"$`)
+
+//带RETURNING的插入语句,按查询执行,返回的行数是插入的行数,每行是插入的一行的自增主键值.
+//不带RETURNING时,驱动不支持LastInsertId(),没有自增主键值.
+func (PostgreSQL) Exec(db *sql.DB, query string, args ...interface{}) (result Result, err error) {
+	if !returningRegexp.MatchString(query) {
+		result, _, err = execResult(db, query, args)
+		return result, err
+	}
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int64
+		if err = rows.Scan(&id); err != nil {
+			return Result{}, err
+		}
+		result.InsertIds = append(result.InsertIds, id)
+	}
+	result.RowsAffected = int64(len(result.InsertIds))
+	return result, rows.Err()
+}
+
+//RETURNING返回每行的主键值,不需要推算.
+func (PostgreSQL) AutoIncrementStep(db *sql.DB) (step int64, err error) {
+	return 1, nil
+}
+
+//表名可以带模式名(模式名.表名),不带时是当前模式.
+func (PostgreSQL) Columns(db *sql.DB, table string) (columns []Column, err error) {
+	schema, name := splitTable(table)
+	rows, err := db.Query(`SELECT c.column_name, COALESCE(c.column_default, ''), c.is_identity,
+		EXISTS (SELECT 1 FROM information_schema.table_constraints t JOIN information_schema.key_column_usage k
+			ON k.constraint_name = t.constraint_name AND k.table_schema = t.table_schema AND k.table_name = t.table_name
+			WHERE t.constraint_type = 'PRIMARY KEY' AND t.table_schema = c.table_schema AND t.table_name = c.table_name AND k.column_name = c.column_name)
+		FROM information_schema.columns c
+		WHERE c.table_schema = COALESCE(NULLIF($1, ''), current_schema()) AND c.table_name = $2 ORDER BY c.ordinal_position`, schema, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var column, columnDefault, isIdentity string
+		var isPkey bool
+		if err = rows.Scan(&column, &columnDefault, &isIdentity, &isPkey); err != nil {
+			return nil, err
+		}
+		//serial列的默认值是nextval('序列名'),或者是identity列
+		columns = append(columns, Column{
+			Name:          column,
+			IsPkey:        isPkey,
+			AutoIncrement: strings.HasPrefix(columnDefault, "nextval(") || strings.EqualFold(isIdentity, "YES"),
+		})
+	}
+	return columns, rows.Err()
+}
+
+//主键和唯一约束
+func (PostgreSQL) UniqueIndexes(db *sql.DB, table string) (indexes [][]string, err error) {
+	schema, name := splitTable(table)
+	rows, err := db.Query(`SELECT t.constraint_name, k.column_name FROM information_schema.table_constraints t JOIN information_schema.key_column_usage k
+		ON k.constraint_name = t.constraint_name AND k.table_schema = t.table_schema AND k.table_name = t.table_name
+		WHERE t.constraint_type IN ('PRIMARY KEY', 'UNIQUE') AND t.table_schema = COALESCE(NULLIF($1, ''), current_schema()) AND t.table_name = $2
+		ORDER BY t.constraint_name, k.ordinal_position`, schema, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanIndexes(rows)
+}
+
+//拆分 模式名.表名,没有模式名时为空.
+func splitTable(table string) (schema string, name string) {
+	if i := strings.LastIndex(table, "."); i != -1 {
+		return table[:i], table[i+1:]
+	}
+	return "", table
+}
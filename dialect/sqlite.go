@@ -0,0 +1,165 @@
+package dialect
+
+import (
+	"database/sql"
+	"dbcache/conf"
+	"strconv"
+	"strings"
+)
+
+//SQLite:双引号引用标识符,?占位符,INSERT ... ON CONFLICT(3.24以上),VALUES中不支持DEFAULT.
+//LastInsertId()是插入的最后一行的rowid,插入多行时按行数推算第一行.
+//驱动是github.com/mattn/go-sqlite3(需cgo),编译时加 -tags sqlite.db_name是数据库文件路径,其它连接项不使用.
+type SQLite struct{}
+
+func (SQLite) Name() string       { return "sqlite" }
+func (SQLite) DriverName() string { return "sqlite3" }
+func (SQLite) HasDefault() bool   { return false }
+
+//文件路径?connect_info,例如connect_info = _busy_timeout=5000(多个同步协程同时写入时等待锁)
+func (SQLite) DataSource(dbConfig conf.DbConfig) string {
+	if dbConfig.ConnectInfo == "" {
+		return dbConfig.DatabaseName
+	}
+	return dbConfig.DatabaseName + "?" + dbConfig.ConnectInfo
+}
+
+func (SQLite) Quote(identifier string) string {
+	return quoteIdentifier(identifier, `"`)
+}
+
+func (SQLite) Placeholder(i int) string {
+	return "?"
+}
+
+func (s SQLite) Insert(table string, columns []string, rows [][]string) string {
+	return insertSql(s, table, columns, rows)
+}
+
+func (s SQLite) Upsert(table string, pkey string, columns []string, values []string) string {
+	return upsertOnConflict(s, table, pkey, columns, values)
+}
+
+//插入和更新受影响的行数都是1,不能判断.
+func (SQLite) UpsertIsInsert(n int64) (isInsert bool, ok bool) {
+	return false, false
+}
+
+func (SQLite) Returning(pkey string) string {
+	return ""
+}
+
+//同一条INSERT语句插入的多行,rowid是连续的,第一行是LastInsertId()-行数+1.
+func (SQLite) Exec(db *sql.DB, query string, args ...interface{}) (result Result, err error) {
+	result, insertId, err := execResult(db, query, args)
+	if err == nil && insertId > 0 {
+		if result.RowsAffected > 1 {
+			insertId = insertId - result.RowsAffected + 1
+		}
+		result.InsertIds = []int64{insertId}
+	}
+	return result, err
+}
+
+//rowid的步长总是1.
+func (SQLite) AutoIncrementStep(db *sql.DB) (step int64, err error) {
+	return 1, nil
+}
+
+//主键只有一列且类型是INTEGER时,是rowid的别名,插入时不指定则自动生成.
+func (s SQLite) Columns(db *sql.DB, table string) (columns []Column, err error) {
+	result, err := queryPragma(db, s.pragma(table, "table_info"))
+	if err != nil {
+		return nil, err
+	}
+	pkeys := 0
+	for _, row := range result {
+		isPkey := row["pk"] != "" && row["pk"] != "0"
+		if isPkey {
+			pkeys++
+		}
+		columns = append(columns, Column{Name: row["name"], IsPkey: isPkey, AutoIncrement: isPkey && strings.EqualFold(row["type"], "INTEGER")})
+	}
+	if pkeys > 1 {
+		for i := range columns {
+			columns[i].AutoIncrement = false
+		}
+	}
+	return columns, nil
+}
+
+//主键(INTEGER主键没有索引)和唯一索引
+func (s SQLite) UniqueIndexes(db *sql.DB, table string) (indexes [][]string, err error) {
+	result, err := queryPragma(db, s.pragma(table, "table_info"))
+	if err != nil {
+		return nil, err
+	}
+	pkey := make([]string, 0)
+	for k := 1; k <= len(result); k++ {
+		for _, row := range result {
+			if row["pk"] == strconv.Itoa(k) {
+				pkey = append(pkey, row["name"])
+			}
+		}
+	}
+	if len(pkey) > 0 {
+		indexes = append(indexes, pkey)
+	}
+	list, err := queryPragma(db, s.pragma(table, "index_list"))
+	if err != nil {
+		return nil, err
+	}
+	for _, index := range list {
+		if index["unique"] != "1" {
+			continue
+		}
+		info, err := queryPragma(db, s.pragma(index["name"], "index_info"))
+		if err != nil {
+			return nil, err
+		}
+		columns := make([]string, 0, len(info))
+		for _, row := range info {
+			columns = append(columns, row["name"])
+		}
+		indexes = append(indexes, columns)
+	}
+	return indexes, nil
+}
+
+//生成PRAGMA语句,name可以带库名(库名.表名).
+func (s SQLite) pragma(name string, pragma string) string {
+	schema, name := splitTable(name)
+	if schema != "" {
+		return "PRAGMA " + s.Quote(schema) + "." + pragma + "(" + s.Quote(name) + ")"
+	}
+	return "PRAGMA " + pragma + "(" + s.Quote(name) + ")"
+}
+
+//执行PRAGMA,返回每行的列名 -> 值.不同版本的结果列数不同,按列名取值.
+func queryPragma(db *sql.DB, query string) (result []map[string]string, err error) {
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	names, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	values := make([]sql.NullString, len(names))
+	scanArgs := make([]interface{}, len(names))
+	for i := range values {
+		scanArgs[i] = &values[i]
+	}
+	for rows.Next() {
+		if err = rows.Scan(scanArgs...); err != nil {
+			return nil, err
+		}
+		row := make(map[string]string, len(names))
+		for i, name := range names {
+			row[strings.ToLower(name)] = values[i].String
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,518 @@
+package main
+//此文件为样例:在程序中直接使用缓存(默认实例),并启动rpc和grpc服务.
+//正式运行的服务见程序目录下的main.go(dbcache serve).
+import (
+	"context"
+	"dbcache/cache"
+	"dbcache/comm"
+	"dbcache/conf"
+	"dbcache/db"
+	"dbcache/grpcserver"
+	"dbcache/logs" //日志库
+	"dbcache/rpcserver"
+	"fmt"
+	"os/signal"
+	"syscall"
+	"time"
+)
+/*
+	使用说明:
+
+	配置程序目录下二个件:
+	一. config.conf(配置数据库连接和日志,rpc和grpc)
+	二. cache.conf(配置需要缓存的表及列,是否实时更新,还是异步更新数据库等.
+
+*/
+
+//注意:
+//目前只支持主键为一个列.
+//缓存数据可以是字符,整型,浮点,日期(datetime,timestamp).
+
+//如果实时更新,数据更新是先更新数据库,再更新缓存.
+//异步更新,会先把执行SQL语句保存于当前目录下的文件async_sql.sql,再更新数据库,如果更新失败,会把失败的sql的语句保存于async_sql_failed.sql文件.
+
+//支持日志系统: s 标准输出屏幕, f 记录到日志, e 发送邮件, a 所有(包括s,f,e)(需先在配置文件config.conf中配置),
+//等级说明:1 DEBUG,2 TRACE,3 INFO,4 WARNING,5 ERROR,6 FATAL
+//注意二个开关,enable控制是否开启,run_level运行等级,只在大于或等于设置等级才输出日志.)
+//如有什么问题,讨论可联系:38704889@qq.com
+
+/*主要函数,下面有用例:
+  1. GetRow():根据主键值,取得该行数据
+  2. GetColumn():根据主键,取得某列的数据
+  3.DelRow():根据主键,删除该行数据
+  4.GetWhere():根据where条件,查询缓存中所有符合条件的行.不用加引号
+  5.UpdateColumn():根据主键,更新一列
+  6. UpdateColumns():根据主键,更新多列
+  7.InsertRow():插入一行数据
+  7.1 Upsert():插入或更新一行数据,主键存在则更新,不存在则插入
+  7.2 InsertRows(),DelRows(),UpdateWhere(),DeleteWhere():批量插入,批量删除,按条件更新,按条件删除
+  8.GetRowBetween():从缓存中,获取指定的行,开始行-结束行.用于页面分页显示.
+  9.GetPageCount():获取总页数.用于页面分页显示.
+  10.GetMultipageRows():用于分页,根据指定开始页,获取多少页,每页行数.返回多页行数据.
+  11.GetOnePageRows():用于分页,根据页码和每页行数大小,返回单页行数据.
+  11.1 GetWhereOnePageRows(),GetWhereMultipageRows(),GetWherePageCount(),GetWhereRowBetween():按where条件分页,同时返回符合条件的总行数和总页数.
+  11.2 GetPageAfter(),GetPageBefore():游标分页,插入删除行时翻页不会重复或遗漏.
+  11.3 View():根据视图名(cache.conf中sort_views配置)获取排序视图,按其它列分页.
+  11.4 GetPage(),GetWherePage():返回当前页的行,总行数,总页数,当前页码,是否有上一页和下一页(同一次加锁中取得).
+*/
+
+/*
+    (注意数据库连接参数:&parseTime=true&loc=Local)
+    如果在连接时加上参数:&parseTime=true 返回数据带时区信息.现只是保存为字符串,如果需要转换time类型,需注意数据库和服务器的时区问题)
+    parseTime=true,不加这个参数,数据库返回时就不带时区.
+    参数: parseTime是查询结果是否自动解析为时间. loc是MySQL的时区设置.
+
+	在windows下，time.Parse()的时区和time.Format()的时区是一致的。
+	在linux环境下，time.Parse()的默认时区是UTC，time.Format()的时区默认是本地
+    在时间转换时要注意.相差8小时 (CST=UTC+8小时) (UTC=CST-8小时)
+    time.UTC():将当地时区转化为UTC时间. time.Local():将UTC时间转化为当地时间
+    解决办法:使用time.FixedZone,在init初始化时,或相关时间调用函数的代码使用之前加入如下代码)
+			timelocal := time.FixedZone("CST", 3600*8)
+			time.Local = timelocal
+
+    字符串转time.Time
+		timeStr := "2020-02-03 21:00:00"//时间字符串
+		t, err := time.ParseInLocation("2006-01-02 15:04", timeStr, time.Local) //t被转为本地时间的time.Time
+		t,err := time.Parse("2006-01-02 15:04", timeStr)                        //t被转为UTC时间的time.Time
+
+    UTC世界标准时间、世界统一时间,CST时间,CST可以同时表示美国，澳大利亚，中国，古巴四个国家的标准时间。
+    CST同时可以代表如下 4 个不同的时区：
+	Central Standard Time (USA) UT-6:00 美国
+	Central Standard Time (Australia) UT+9:30 澳大利亚
+	China Standard Time UT+8:00 中国
+	Cuba Standard Time UT-4:00 古巴
+*/
+//用于测试user表,将map数据转换为结构体,首字母要大小
+type user struct {
+	Uid string
+	Age string
+	Price string
+	Name string
+	Address string
+	Password string
+	Create_date string
+	Update_date string
+}
+
+//关闭超时时间,超过此时间未完成关闭,强制退出.
+const SHUTDOWN_TIMEOUT = time.Second * 30
+
+var (
+	rpcServer  *rpcserver.RpcServer   //rpc服务
+	grpcServer *grpcserver.GrpcServer //grpc服务
+)
+
+func main() {
+	//收到SIGINT(Ctrl+C)或SIGTERM时,ctx被取消,开始优雅关闭.
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	//初始化日志库
+	logs.InitLog()
+	defer logs.Close()
+
+	//连接数据库,初始化调用.
+	dbConn, err := db.ConnectDB()
+	if err != nil {
+		logs.Fatal("a", "连接数据库失败, err: %s", err)
+		return
+	}
+	defer db.CloseConn()
+
+	//缓存users表
+	UsersCache, err := cache.NewDBcache(dbConn, "users")
+	if err != nil {
+		logs.Fatal("a", "初始化缓存失败, err: %s", err)
+		return
+	}
+
+	//缓存Goods表
+	GoodsCache, err := cache.NewDBcache(dbConn, "goods")
+	if err != nil {
+		logs.Fatal("a", "初始化缓存失败, err: %s", err)
+		UsersCache.Close(context.Background())
+		return
+	}
+
+	//热加载配置文件:每5秒检查cache.conf和config.conf,新增,删除表及修改配置立即生效.
+	//注意:删除或重新加载的表会被关闭,原来的缓存对象不能再写入,需通过cache.GetCacheObj()重新获取.
+	watcher, err := cache.NewConfWatcher(dbConn, time.Second*5)
+	if err != nil {
+		logs.Fatal("a", "监视配置文件失败, err: %s", err)
+		shutdown(nil, UsersCache, GoodsCache)
+		return
+	}
+	watcher.Start()
+
+
+	//启动rpc
+	// 配置IP地址和端口,在config.conf配置文件中.
+	rpcConf:=conf.RpcServer{}
+	err = conf.ParseConf(conf.CONFIG_FILE, &rpcConf)
+	if err==nil{
+		rpcServer, err = rpcserver.NewRpcServer(cache.DefaultInstance(), rpcConf)
+	}
+	if err==nil{
+		err = rpcServer.Run()
+	}
+	if err!=nil{
+		fmt.Println("RPC service failed",err)
+		shutdown(watcher, UsersCache, GoodsCache)
+		return
+	}
+
+
+	//启动Grpc
+	// 配置IP地址和端口,在config.conf配置文件中.
+	grpcConf:=conf.GrpcServer{}
+	err = conf.ParseConf(conf.CONFIG_FILE, &grpcConf)
+	if err==nil{
+		grpcServer = grpcserver.NewGrpcServer(cache.DefaultInstance(), grpcConf)
+		err = grpcServer.Run()
+	}
+	if err!=nil{
+		fmt.Println("GRPC service failed",err)
+		shutdown(watcher, UsersCache, GoodsCache)
+		return
+	}
+
+
+	// 以下为users表增删改查的样例.
+	//----------------------------------------------------------------------
+	//获取总页数:
+	pageRows:=20  //每页多少行
+	fmt.Printf("users表: 总行数:%d,每页%d行,总页数:%d\n",UsersCache.GetRowCount(),pageRows,UsersCache.GetPageCount(pageRows))
+	fmt.Printf("goods表: 总行数:%d,每页%d行,总页数:%d\n",GoodsCache.GetRowCount(),pageRows,GoodsCache.GetPageCount(pageRows))
+
+	//一. GetRow:根据主键值,取得该行数据
+	fmt.Println("一. GetRow().根据主键,取得该行数据.")
+	pkey := "00YS0SW2N4NT7K8HP13E"
+	result, err := UsersCache.GetRow(pkey)
+	if err != nil {
+		fmt.Println(err)
+	}
+	for k, v := range result {
+		fmt.Printf("%s=%s, ", k, v)
+	}
+	fmt.Println()
+
+	//将map数据转换为struct.
+	tt:=&user{}
+	err = comm.MapToStruct(result, tt)
+	if err!=nil{
+		fmt.Println(err)
+	}
+    fmt.Println(*tt)
+
+	//二. GetColumn:根据主键,取得某列的数据
+	fmt.Println("二. GetColumn().根据主键,取得某列的数据")
+	v, err := UsersCache.GetColumn("00YS0SW2N4NT7K8HP13E", "name")
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println("根据主键，取得某列的数据: ", v)
+
+	//三. DelRow:根据主键,删除该行数据
+	fmt.Printf("三. DelRow().根据主键,删除该行数据\n")
+	pkey = "2222222221"
+	n, err := UsersCache.DelRow(pkey)
+	if err != nil {
+		logs.Info("a", "根据主键,删除行数据失败, err: %s", err)
+	}
+	fmt.Println("删除数据行数:", n)
+
+	//四. GetWhere:根据where条件,查询缓存中所有符合条件的行.不用加引号
+	fmt.Println("四. 根据where条件,查询缓存中所有符合条件的行.")
+	var value []map[string]string
+	//value ,_ = dbcache.GetWhere("name=AFA5Y9FB or password=Q80BJT")
+	value, err = UsersCache.GetWhere("address=重庆 and password=888888")
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Printf("查询到符合条件的有%d行:\n", len(value))
+	for _, c := range value {
+		for k, v := range c {
+			fmt.Printf("%s=%s,", k, v)
+		}
+		fmt.Println()
+	}
+
+	fmt.Println("切片map转切片struct")
+	ss:=make([]user,len(value))
+	err = comm.SliceMapToStruct(value, ss)
+	if err!=nil{
+		fmt.Println(err)
+	}
+	for _,vv:=range ss{
+		fmt.Println(vv)
+	}
+	//bytes, err = json.Marshal(value)
+	//if err!=nil{
+	//	fmt.Println(err)
+	//}
+	//fmt.Println(string(bytes))
+
+	//五. UpdateColumn:根据主键,更新一列
+	fmt.Println("五. UpdateColumn().根据主键,更新一列数据")
+	pkey = "00YS0SW2N4NT7K8HP13E"
+	col := "name"
+	val := "王明99"
+	n, err = UsersCache.UpdateColumn(pkey, col, val)
+	if err != nil {
+		logs.Error("a", "更新一列错误, err: %s", err)
+	}
+
+	//六. UpdateColumns:根据主键,更新多列
+	fmt.Printf("六. UpdateColumns().根据主键,更新多列数据:\n")
+	pkey = "00YS0SW2N4NT7K8HP13E"
+	cols := "age=111,address= 重庆111,update_date=2020-05-06 07:30:00"
+	n, err = UsersCache.UpdateColumns(pkey, cols)
+	if err != nil {
+		logs.Error("a", "更新多列错误, err:%s", err)
+	}
+
+
+	//七. InsertRow():插入一行数据
+	fmt.Printf("七. InsertRow().插入一行数据\n")
+	insert := "uid=22222211115,name=jth,address=重庆,password=888888,age=9999991,price=66.123456,create_date=2020-02-02 02:02:02,update_date=2020-01-01 01:01:01"
+	n, _, err = UsersCache.InsertRow(insert)
+	if err != nil {
+		logs.Error("a", "插入错误, err: %v", err)
+	}
+	fmt.Printf("插入%d行数据\n", n)
+
+	//七.1 Upsert():插入或更新一行数据,主键存在则更新,不存在则插入
+	fmt.Printf("七.1 Upsert().插入或更新一行数据\n")
+	upsert := "uid=22222211115,name=jth2,address=重庆,password=888888,age=9999992,price=66.123456,create_date=2020-02-02 02:02:02,update_date=2020-01-01 01:01:01"
+	n, isInsert, isKnown, err := UsersCache.Upsert(upsert)
+	if err != nil {
+		logs.Error("a", "插入或更新错误, err: %v", err)
+	}
+	fmt.Printf("影响%d行数据,是否插入:%t,是否确定:%t\n", n, isInsert, isKnown)
+
+	//七.2 批量操作:InsertRows(),UpdateWhere(),DelRows()
+	fmt.Printf("七.2 批量插入,按条件更新,批量删除\n")
+	insertRows := []string{
+		"uid=22222211116,name=jth3,address=重庆,password=888888,age=9999993,price=66.123456,create_date=2020-02-02 02:02:02,update_date=2020-01-01 01:01:01",
+		"uid=22222211117,name=jth4,address=重庆,password=888888,age=9999994,price=66.123456,create_date=2020-02-02 02:02:02,update_date=2020-01-01 01:01:01",
+	}
+	n, err = UsersCache.InsertRows(insertRows)
+	if err != nil {
+		logs.Error("a", "批量插入错误, err: %v", err)
+	}
+	fmt.Printf("批量插入%d行数据\n", n)
+	n, err = UsersCache.UpdateWhere("name=jth3 or name=jth4", "address=成都")
+	if err != nil {
+		logs.Error("a", "按条件更新错误, err: %v", err)
+	}
+	fmt.Printf("按条件更新%d行数据\n", n)
+	n, err = UsersCache.DelRows([]string{"22222211116", "22222211117"})
+	if err != nil {
+		logs.Error("a", "批量删除错误, err: %v", err)
+	}
+	fmt.Printf("批量删除%d行数据\n", n)
+
+	//八,GetRowBetween():从缓存中,获取指定的行,开始行-结束行.用于页面分页显示.
+	fmt.Printf("八,GetRowNum():获取缓存中,start行到end行之间的数据.\n")
+	rows := UsersCache.GetRowBetween(0, 15)
+	for i, rowMap := range rows {
+		fmt.Printf(" 第%d行 ", i)
+		for k, v := range rowMap {
+			fmt.Printf(" %s=%s, ", k, v)
+		}
+		fmt.Println()
+	}
+
+	//九,GetPageCount():获取总页数.用于页面分页显示.
+	fmt.Printf("九,GetPageCount():获取总页数.\n")
+	pageSize:=15  //每页15行
+	page:= UsersCache.GetPageCount(pageSize)
+	fmt.Printf("九,GetPageCount():每页%d行,获取总页数:%d\n",pageSize,page)
+
+	//十,GetMultipageRows():用于分页,根据指定开始页,获取多少页,每页行数.返回多页行数据.
+	//参数说明:startPage,开始页,pageNum多少页,pageSize参数是每页行数大小
+	fmt.Printf("十 , GetMultipageRows():用于分页,根据指定开始页,获取多少页,每页行数大小.返回数据.\n")
+	rows = UsersCache.GetMultipageRows(5, 2,10)
+	for i, rowMap := range rows {
+		fmt.Printf(" 第%d行 ", i)
+		for k, v := range rowMap {
+			fmt.Printf(" %s=%s, ", k, v)
+		}
+		fmt.Println()
+	}
+
+	//十一,GetOnePageRows():用于分页,根据页码和每页行数大小,返回单页行数据.page参数是页码,pageSize参数是每页行数大小
+	//参数说明:page参数是页码,pageSize参数是每页行数大小
+	fmt.Printf("十 , GetOnePageRows():用于分页,根据页码和每页行数大小,返回数据.\n")
+	rows  = UsersCache.GetOnePageRows(5, 10)
+	for i, rowMap := range rows {
+		fmt.Printf(" 第%d行 ", i)
+		for k, v := range rowMap {
+			fmt.Printf(" %s=%s, ", k, v)
+		}
+		fmt.Println()
+	}
+
+
+	//十一.1,GetWhereOnePageRows():按where条件分页,按表的排序返回一页数据,以及符合条件的总行数,总页数.
+	fmt.Printf("十一.1 , GetWhereOnePageRows():按where条件分页,返回数据.\n")
+	rows, total, pageCount, err := UsersCache.GetWhereOnePageRows("address=重庆", 2, 10)
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Printf("符合条件的总行数:%d,总页数:%d\n", total, pageCount)
+	for i, rowMap := range rows {
+		fmt.Printf(" 第%d行 ", i)
+		for k, v := range rowMap {
+			fmt.Printf(" %s=%s, ", k, v)
+		}
+		fmt.Println()
+	}
+
+	//十一.2,GetPageAfter():游标分页,cursor为空时从第一行开始.next传给GetPageAfter()获取下一页,prev传给GetPageBefore()获取上一页.
+	fmt.Printf("十一.2 , GetPageAfter():游标分页,返回数据.\n")
+	cursor := ""
+	for i := 0; i < 2; i++ {
+		rows, next, _, err := UsersCache.GetPageAfter(cursor, 10)
+		if err != nil {
+			fmt.Println(err)
+			break
+		}
+		fmt.Printf("第%d页,%d行\n", i+1, len(rows))
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+
+	//十一.3,View():根据视图名获取排序视图,按视图的排序列分页.视图在cache.conf的sort_views中配置.
+	fmt.Printf("十一.3 , View():按create_date降序分页,返回数据.\n")
+	view, err := GoodsCache.View("date")
+	if err != nil {
+		fmt.Println(err)
+	} else {
+		for i, rowMap := range view.GetOnePageRows(1, 10) {
+			fmt.Printf(" 第%d行 ", i)
+			for k, v := range rowMap {
+				fmt.Printf(" %s=%s, ", k, v)
+			}
+			fmt.Println()
+		}
+	}
+
+	//十一.4,GetPage():返回一页数据,以及总行数,总页数,当前页码,是否有上一页和下一页.总行数和数据是一致的.
+	fmt.Printf("十一.4 , GetPage():分页,返回数据和分页信息.\n")
+	pageResult, err := UsersCache.GetPage(2, 10)
+	if err != nil {
+		fmt.Println(err)
+	} else {
+		fmt.Printf("第%d页,共%d页,总行数:%d,本页%d行,有上一页:%t,有下一页:%t\n", pageResult.Page, pageResult.PageCount, pageResult.Total, len(pageResult.Rows), pageResult.HasPrev, pageResult.HasNext)
+	}
+
+	//Goods表操作----------------------------------------------------------
+	fmt.Println("以下是对Goods表操作.")
+	rows = GoodsCache.GetRowBetween(0, 10)
+	for i, rowMap := range rows {
+		fmt.Printf(" 第%d行 ", i)
+		for k, v := range rowMap {
+			fmt.Printf(" %s=%s, ", k, v)
+		}
+		fmt.Println()
+	}
+
+	//Goods表操作----------------------------------------------------------
+	fmt.Println()
+	pkey = "50496c578bc54cb49b21513107fcbd10"
+	result, err = GoodsCache.GetRow(pkey)
+	if err != nil {
+		fmt.Println(err)
+	}
+	for k, v := range result {
+		fmt.Printf("%s=%s, ", k, v)
+	}
+
+
+	//查看异步同步协程的状态
+	for _, stat := range UsersCache.AsyncStats() {
+		fmt.Printf("users表异步同步协程%d: 等待%d条,已执行%d条,失败%d条,延迟%s\n", stat.Id, stat.Pending, stat.Executed, stat.Failed, stat.Lag)
+	}
+
+	//查看goods表(sliceNotDel)的整理状态
+	compactStat := GoodsCache.CompactStats()
+	fmt.Printf("goods表切片: %d行,已删除%d行,未排序%d行,已整理%d次,最近整理: %s\n", compactStat.Rows, compactStat.DeletedRows, compactStat.Unsorted, compactStat.Compactions, compactStat.LastCompact)
+
+	//等待退出信号,防止退出
+	<-ctx.Done()
+	stop()
+	fmt.Println("收到退出信号,开始关闭...")
+	shutdown(watcher, UsersCache, GoodsCache)
+}
+
+//优雅关闭:先停止rpc和grpc服务,不再接收新请求,再关闭缓存(把异步管道中的sql同步到数据库).
+//数据库连接和日志由main中的defer关闭.
+func shutdown(watcher *cache.ConfWatcher, caches ...*cache.DBcache) {
+	ctx, cancel := context.WithTimeout(context.Background(), SHUTDOWN_TIMEOUT)
+	defer cancel()
+
+	//先停止热加载,再关闭服务和缓存
+	if watcher != nil {
+		watcher.Close()
+	}
+
+	var err error
+	if grpcServer != nil {
+		err = grpcServer.Stop(ctx)
+		if err != nil {
+			logs.Error("a", "关闭grpc服务失败, err: %s", err)
+		}
+	}
+	if rpcServer != nil {
+		err = rpcServer.Stop(ctx)
+		if err != nil {
+			logs.Error("a", "关闭rpc服务失败, err: %s", err)
+		}
+	}
+	//包括热加载新增的表,Close()可重复调用
+	for _, c := range append(caches, cache.GetCacheObjs()...) {
+		err = c.Close(ctx)
+		if err != nil {
+			logs.Error("a", "关闭缓存失败, err: %s", err)
+		}
+	}
+}
+
+//func prof() {
+	/*//性能分析
+	//新建性能分析文件
+	f, err := os.Create("./cpu.prof")
+	if err != nil {
+		fmt.Println("create cpu profile file failed, err:",err)
+	}
+	fm, err := os.Create("./mem.prof")
+	if err != nil {
+		fmt.Println("create mem profile file failed, err:",err)
+	}
+	fg, err := os.Create("./goroutine.prof")
+	if err != nil {
+		fmt.Println("create goroutine profile file failed, err:",err)
+	}
+	//获取系统cpu信息
+	err = pprof.StartCPUProfile(f)
+	if err != nil {
+		fmt.Println("StartCPUProfile(), err:",err)
+	}
+	defer pprof.StopCPUProfile()
+	//获取系统mem信息
+	err = pprof.WriteHeapProfile(fm)
+	if err != nil {
+		fmt.Println("WriteHeapProfile(), err:",err)
+	}
+	defer fm.Close()
+	//获取系统goroutine信息
+	lookup := pprof.Lookup("goroutine")
+	if lookup != nil {
+		fmt.Println(`Lookup("goroutine"), err:`,err)
+	}
+	lookup.WriteTo(fg,0)
+	defer fg.Close()*/
+//}
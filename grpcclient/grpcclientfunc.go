@@ -14,6 +14,7 @@ const GrpcServiceName = "DBcacheGrpc"
 type DBcacheGrpcClient struct {
 	Client   pb.GrpcDBcacheClient
 	grpcConn *grpc.ClientConn
+	View     string //分页查询使用的排序视图名,为空时按表的默认排序
 }
 
 //初始化连接Rpc服务.
@@ -26,12 +27,17 @@ func InitGrpc(address string) (grpcClient *DBcacheGrpcClient, err error) {
 	}
 	//实例化gprc客户端
 	client := pb.NewGrpcDBcacheClient(conn)
-	return &DBcacheGrpcClient{client, conn}, nil
+	return &DBcacheGrpcClient{client, conn, ""}, nil
 }
 func (d *DBcacheGrpcClient) Close() {
 	d.grpcConn.Close()
 }
 
+//返回使用指定排序视图分页查询的客户端,与原客户端共用连接.
+func (d *DBcacheGrpcClient) WithView(view string) *DBcacheGrpcClient {
+	return &DBcacheGrpcClient{d.Client, d.grpcConn, view}
+}
+
 //--------------GetRow()---------------------------------
 //参数说明:tableName,缓存的表名,pkey:主键值.
 func (d *DBcacheGrpcClient) GetRow(tableName string, pkey string) (result map[string]string, err error) {
@@ -151,7 +157,7 @@ func (d *DBcacheGrpcClient) UpdateColumns(tableName string, pkey string, where s
 
 //--------------InsertRow()---------------------------------
 //参数说明:tableName,缓存的表名,condition:多列的组合表达式
-func (d *DBcacheGrpcClient) InsertRow(tableName string, condition string) (n int64, err error) {
+func (d *DBcacheGrpcClient) InsertRow(tableName string, condition string) (n int64, lastInsertId int64, err error) {
 	//组建请求参数
 	req := pb.InsertRowRequest{
 		TableName: tableName,
@@ -161,6 +167,112 @@ func (d *DBcacheGrpcClient) InsertRow(tableName string, condition string) (n int
 	resp, err := d.Client.InsertRow(context.Background(), &req)
 	if err != nil {
 		err = fmt.Errorf("grpc InsertRow() error: %s", err)
+		return 0, 0, err
+	}
+	return resp.Result, resp.LastInsertId, nil
+}
+
+//--------------Upsert()---------------------------------
+//参数说明:tableName,缓存的表名,row:多列的组合表达式,必须包含主键.
+//返回受影响的行数,是插入还是更新,以及isInsert是否确定.
+func (d *DBcacheGrpcClient) Upsert(tableName string, row string) (n int64, isInsert bool, isKnown bool, err error) {
+	//组建请求参数
+	req := pb.UpsertRequest{
+		TableName: tableName,
+		Row:       row,
+	}
+	//调用接口
+	resp, err := d.Client.Upsert(context.Background(), &req)
+	if err != nil {
+		err = fmt.Errorf("grpc Upsert() error: %s", err)
+		return 0, false, false, err
+	}
+	return resp.Result, resp.IsInsert, resp.IsKnown, nil
+}
+
+//--------------InsertRows()---------------------------------
+//客户端流式,参数说明:tableName,缓存的表名,rows:多行数据,每行是多列的组合表达式
+func (d *DBcacheGrpcClient) InsertRows(tableName string, rows []string) (n int64, err error) {
+	stream, err := d.Client.InsertRows(context.Background())
+	if err != nil {
+		err = fmt.Errorf("grpc InsertRows() error: %s", err)
+		return 0, err
+	}
+	for _, row := range rows {
+		err = stream.Send(&pb.InsertRowsRequest{TableName: tableName, Row: row})
+		if err != nil {
+			err = fmt.Errorf("grpc InsertRows() send error: %s", err)
+			return 0, err
+		}
+	}
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		err = fmt.Errorf("grpc InsertRows() error: %s", err)
+		return 0, err
+	}
+	return resp.Result, nil
+}
+
+//--------------DelRows()---------------------------------
+//客户端流式,参数说明:tableName,缓存的表名,pkeys:多个主键值
+func (d *DBcacheGrpcClient) DelRows(tableName string, pkeys []string) (n int64, err error) {
+	stream, err := d.Client.DelRows(context.Background())
+	if err != nil {
+		err = fmt.Errorf("grpc DelRows() error: %s", err)
+		return 0, err
+	}
+	for _, pkey := range pkeys {
+		err = stream.Send(&pb.DelRowsRequest{TableName: tableName, Pkey: pkey})
+		if err != nil {
+			err = fmt.Errorf("grpc DelRows() send error: %s", err)
+			return 0, err
+		}
+	}
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		err = fmt.Errorf("grpc DelRows() error: %s", err)
+		return 0, err
+	}
+	return resp.Result, nil
+}
+
+//--------------UpdateWhere()---------------------------------
+//客户端流式,参数说明:tableName,缓存的表名,where:查询条件,changes:要更新的多列表达式
+func (d *DBcacheGrpcClient) UpdateWhere(tableName string, where string, changes string) (n int64, err error) {
+	stream, err := d.Client.UpdateWhere(context.Background())
+	if err != nil {
+		err = fmt.Errorf("grpc UpdateWhere() error: %s", err)
+		return 0, err
+	}
+	err = stream.Send(&pb.UpdateWhereRequest{TableName: tableName, Where: where, Changes: changes})
+	if err != nil {
+		err = fmt.Errorf("grpc UpdateWhere() send error: %s", err)
+		return 0, err
+	}
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		err = fmt.Errorf("grpc UpdateWhere() error: %s", err)
+		return 0, err
+	}
+	return resp.Result, nil
+}
+
+//--------------DeleteWhere()---------------------------------
+//客户端流式,参数说明:tableName,缓存的表名,where:查询条件
+func (d *DBcacheGrpcClient) DeleteWhere(tableName string, where string) (n int64, err error) {
+	stream, err := d.Client.DeleteWhere(context.Background())
+	if err != nil {
+		err = fmt.Errorf("grpc DeleteWhere() error: %s", err)
+		return 0, err
+	}
+	err = stream.Send(&pb.DeleteWhereRequest{TableName: tableName, Where: where})
+	if err != nil {
+		err = fmt.Errorf("grpc DeleteWhere() send error: %s", err)
+		return 0, err
+	}
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		err = fmt.Errorf("grpc DeleteWhere() error: %s", err)
 		return 0, err
 	}
 	return resp.Result, nil
@@ -174,6 +286,7 @@ func (d *DBcacheGrpcClient) GetRowBetween(tableName string, start int, end int)
 		TableName: tableName,
 		Start:     int64(start),
 		End:       int64(end),
+		View:      d.View,
 	}
 	//调用接口
 	stream, err := d.Client.GetRowBetween(context.Background(), &req)
@@ -202,6 +315,7 @@ func (d *DBcacheGrpcClient) GetPageCount(tableName string, pageSize int) (result
 	req := pb.GetPageCountRequest{
 		TableName: tableName,
 		PageSize:  int64(pageSize),
+		View:      d.View,
 	}
 	//调用接口
 	resp, err := d.Client.GetPageCount(context.Background(), &req)
@@ -221,6 +335,7 @@ func (d *DBcacheGrpcClient) GetMultipageRows(tableName string, startPage int, pa
 		StartPage: int64(startPage),
 		PageNum:   int64(pageNum),
 		PageSize:  int64(pageSize),
+		View:      d.View,
 	}
 	//调用接口
 	stream, err := d.Client.GetMultipageRows(context.Background(), &req)
@@ -248,8 +363,9 @@ func (d *DBcacheGrpcClient) GetOnePageRows(tableName string, page int,pageSize i
 	//组建请求参数
 	req := pb.GetOnePageRowsRequest{
 		TableName: tableName,
-		Page: int64(page),
-		PageSize:   int64(pageSize),
+		Page:      int64(page),
+		PageSize:  int64(pageSize),
+		View:      d.View,
 	}
 	//调用接口
 	stream, err := d.Client.GetOnePageRows(context.Background(), &req)
@@ -270,3 +386,185 @@ func (d *DBcacheGrpcClient) GetOnePageRows(tableName string, page int,pageSize i
 	}
 	return result, nil
 }
+
+//把GetWhereStream切片转换为多行数据
+func fromWhereStream(rows []*pb.GetWhereStream) (result []map[string]string) {
+	result = make([]map[string]string, 0, len(rows))
+	for _, v := range rows {
+		result = append(result, v.Result)
+	}
+	return result
+}
+
+//--------------GetWhereRowBetween()---------------------------------
+//参数说明:tableName,缓存的表名,where:条件表达式,start:开始行,end:结束行(不包括结束行).total:符合条件的总行数
+func (d *DBcacheGrpcClient) GetWhereRowBetween(tableName string, where string, start int, end int) (result []map[string]string, total int, err error) {
+	//组建请求参数
+	req := pb.GetWhereRowBetweenRequest{
+		TableName: tableName,
+		Where:     where,
+		Start:     int64(start),
+		End:       int64(end),
+		View:      d.View,
+	}
+	//调用接口
+	resp, err := d.Client.GetWhereRowBetween(context.Background(), &req)
+	if err != nil {
+		err = fmt.Errorf("grpc GetWhereRowBetween() error: %s", err)
+		return nil, 0, err
+	}
+	return fromWhereStream(resp.Result), int(resp.Total), nil
+}
+
+//--------------GetWherePageCount()---------------------------------
+//参数说明:tableName,缓存的表名,where:条件表达式,pageSize: 每页多少行
+func (d *DBcacheGrpcClient) GetWherePageCount(tableName string, where string, pageSize int) (pageCount int, total int, err error) {
+	//组建请求参数
+	req := pb.GetWherePageCountRequest{
+		TableName: tableName,
+		Where:     where,
+		PageSize:  int64(pageSize),
+		View:      d.View,
+	}
+	//调用接口
+	resp, err := d.Client.GetWherePageCount(context.Background(), &req)
+	if err != nil {
+		err = fmt.Errorf("grpc GetWherePageCount() error: %s", err)
+		return 0, 0, err
+	}
+	return int(resp.Result), int(resp.Total), nil
+}
+
+//--------------GetWhereMultipageRows()---------------------------------
+//参数说明:tableName,缓存的表名,where:条件表达式,startPage,开始页,pageNum多少页,pageSize参数是每页行数大小
+func (d *DBcacheGrpcClient) GetWhereMultipageRows(tableName string, where string, startPage int, pageNum int, pageSize int) (result []map[string]string, total int, pageCount int, err error) {
+	//组建请求参数
+	req := pb.GetWhereMultipageRowsRequest{
+		TableName: tableName,
+		Where:     where,
+		StartPage: int64(startPage),
+		PageNum:   int64(pageNum),
+		PageSize:  int64(pageSize),
+		View:      d.View,
+	}
+	//调用接口
+	resp, err := d.Client.GetWhereMultipageRows(context.Background(), &req)
+	if err != nil {
+		err = fmt.Errorf("grpc GetWhereMultipageRows() error: %s", err)
+		return nil, 0, 0, err
+	}
+	return fromWhereStream(resp.Result), int(resp.Total), int(resp.PageCount), nil
+}
+
+//--------------GetWhereOnePageRows()---------------------------------
+//参数说明:tableName,缓存的表名,where:条件表达式,page参数是页码,pageSize参数是每页行数大小
+func (d *DBcacheGrpcClient) GetWhereOnePageRows(tableName string, where string, page int, pageSize int) (result []map[string]string, total int, pageCount int, err error) {
+	//组建请求参数
+	req := pb.GetWhereOnePageRowsRequest{
+		TableName: tableName,
+		Where:     where,
+		Page:      int64(page),
+		PageSize:  int64(pageSize),
+		View:      d.View,
+	}
+	//调用接口
+	resp, err := d.Client.GetWhereOnePageRows(context.Background(), &req)
+	if err != nil {
+		err = fmt.Errorf("grpc GetWhereOnePageRows() error: %s", err)
+		return nil, 0, 0, err
+	}
+	return fromWhereStream(resp.Result), int(resp.Total), int(resp.PageCount), nil
+}
+
+//--------------GetPageAfter()---------------------------------
+//参数说明:tableName,缓存的表名,cursor:游标,为空时从第一行开始,pageSize参数是每页行数大小.
+//返回的next传给GetPageAfter()获取下一页,prev传给GetPageBefore()获取上一页.
+func (d *DBcacheGrpcClient) GetPageAfter(tableName string, cursor string, pageSize int) (result []map[string]string, next string, prev string, err error) {
+	//组建请求参数
+	req := pb.GetPageAfterRequest{
+		TableName: tableName,
+		Cursor:    cursor,
+		PageSize:  int64(pageSize),
+		View:      d.View,
+	}
+	//调用接口
+	resp, err := d.Client.GetPageAfter(context.Background(), &req)
+	if err != nil {
+		err = fmt.Errorf("grpc GetPageAfter() error: %s", err)
+		return nil, "", "", err
+	}
+	return fromWhereStream(resp.Result), resp.Next, resp.Prev, nil
+}
+
+//--------------GetPageBefore()---------------------------------
+//参数说明:tableName,缓存的表名,cursor:游标,为空时获取最后一页,pageSize参数是每页行数大小
+func (d *DBcacheGrpcClient) GetPageBefore(tableName string, cursor string, pageSize int) (result []map[string]string, next string, prev string, err error) {
+	//组建请求参数
+	req := pb.GetPageBeforeRequest{
+		TableName: tableName,
+		Cursor:    cursor,
+		PageSize:  int64(pageSize),
+		View:      d.View,
+	}
+	//调用接口
+	resp, err := d.Client.GetPageBefore(context.Background(), &req)
+	if err != nil {
+		err = fmt.Errorf("grpc GetPageBefore() error: %s", err)
+		return nil, "", "", err
+	}
+	return fromWhereStream(resp.Result), resp.Next, resp.Prev, nil
+}
+
+//--------------GetPage()---------------------------------
+//分页查询的结果
+type PageResult struct {
+	Rows      []map[string]string //当前页的数据
+	Total     int                 //总行数(有where条件时是符合条件的总行数)
+	PageCount int                 //总页数
+	Page      int                 //当前页码
+	PageSize  int                 //每页行数
+	HasNext   bool                //是否有下一页
+	HasPrev   bool                //是否有上一页
+}
+
+//参数说明:tableName,缓存的表名,where:条件表达式,为空时不过滤,page参数是页码,pageSize参数是每页行数大小.
+//返回当前页的行,总行数,总页数,当前页码,是否有上一页和下一页,在服务端同一次加锁中取得.
+func (d *DBcacheGrpcClient) GetPage(tableName string, where string, page int, pageSize int) (result *PageResult, err error) {
+	//组建请求参数
+	req := pb.GetPageRequest{
+		TableName: tableName,
+		Where:     where,
+		Page:      int64(page),
+		PageSize:  int64(pageSize),
+		View:      d.View,
+	}
+	//调用接口
+	resp, err := d.Client.GetPage(context.Background(), &req)
+	if err != nil {
+		err = fmt.Errorf("grpc GetPage() error: %s", err)
+		return nil, err
+	}
+	result = &PageResult{
+		Rows:      fromWhereStream(resp.Result),
+		Total:     int(resp.Total),
+		PageCount: int(resp.PageCount),
+		Page:      int(resp.Page),
+		PageSize:  int(resp.PageSize),
+		HasNext:   resp.HasNext,
+		HasPrev:   resp.HasPrev,
+	}
+	return result, nil
+}
+
+//--------------AddTable()---------------------------------
+//管理接口:运行中增加缓存表.服务端把表的配置写入cache.conf并从数据库加载,返回加载的总行数.
+//表配置错误,表已缓存或加载失败时返回错误.
+func (d *DBcacheGrpcClient) AddTable(req *pb.AddTableRequest) (rowCount int64, err error) {
+	//调用接口
+	resp, err := d.Client.AddTable(context.Background(), req)
+	if err != nil {
+		err = fmt.Errorf("grpc AddTable() error: %s", err)
+		return 0, err
+	}
+	return resp.Result, nil
+}
@@ -1,6 +1,8 @@
 package grpcserver
 
 import (
+	"context"
+	"dbcache/cache"
 	"dbcache/conf"
 	pb "dbcache/proto"
 	"fmt"
@@ -8,31 +10,63 @@ import (
 	"net"
 )
 
-var (
-	GrpcConf=conf.GrpcServer{}
-)
+//grpc服务,注册的DBcacheGrpc使用指定的缓存实例,同一进程中可以运行多个.
+type GrpcServer struct{
+	conf     conf.GrpcServer
+	instance *cache.Instance //缓存实例
+	server   *grpc.Server    //grpc服务对象
+	listen   net.Listener    //grpc监听对象
+}
 
-func GrpcRun()(err error){
-	//读取配置文件,初始化配置信息
-	err = conf.ParseConf(conf.CONFIG_FILE, &GrpcConf)
-	if err != nil {
-		return  err
+//新建grpc服务,grpcConf一般由conf.ParseConf(conf.CONFIG_FILE, &grpcConf)读取.调用Run()开始服务.
+func NewGrpcServer(instance *cache.Instance,grpcConf conf.GrpcServer)*GrpcServer{
+	return &GrpcServer{conf:grpcConf,instance:instance}
+}
+
+//监听的地址
+func (s *GrpcServer)Addr()string{
+	if s.listen != nil {
+		return s.listen.Addr().String()
 	}
+	return s.conf.Ip+":"+s.conf.Port
+}
+
+func (s *GrpcServer)Run()(err error){
 	//监听
-	grpcListen, err := net.Listen(GrpcConf.Protocol, GrpcConf.Ip+":"+GrpcConf.Port)
+	s.listen, err = net.Listen(s.conf.Protocol, s.conf.Ip+":"+s.conf.Port)
 	if err != nil {
 		return err
 	}
 	//实例化grpc服务
-	Server := grpc.NewServer()
+	s.server = grpc.NewServer()
 	//在grpc上注册服务
-	pb.RegisterGrpcDBcacheServer(Server,new(DBcacheGrpc))
+	pb.RegisterGrpcDBcacheServer(s.server,&DBcacheGrpc{instance:s.instance,adminToken:s.conf.AdminToken})
 	//启动服务器
 	//另外开一个协程处理
-	go func(rpcListen net.Listener){
-		fmt.Println("grpc server: "+GrpcConf.Ip+":"+GrpcConf.Port)
-		Server.Serve(grpcListen)
-	}(grpcListen)
+	go func(grpcListen net.Listener){
+		fmt.Println("grpc server: "+s.Addr())
+		s.server.Serve(grpcListen)
+	}(s.listen)
 
 	return nil
-}
\ No newline at end of file
+}
+
+//停止grpc服务.不再接收新的连接和请求,等待正在处理的请求完成.
+//如果ctx先超时,则强制停止.
+func (s *GrpcServer)Stop(ctx context.Context)(err error){
+	if s.server == nil {
+		return nil
+	}
+	done := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		s.server.Stop()
+		return fmt.Errorf("Stop(),关闭grpc服务超时,已强制停止, err: %s", ctx.Err())
+	}
+	return nil
+}
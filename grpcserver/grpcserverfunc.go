@@ -3,16 +3,31 @@ package grpcserver
 import (
 	"context"
 	"dbcache/cache"
+	"dbcache/conf"
 	pb "dbcache/proto"
 	"fmt"
+	"io"
 )
 
 //定义服务对象,实现pb的GrpcDBcacheServer接口
-type DBcacheGrpc struct{}
+type DBcacheGrpc struct {
+	instance   *cache.Instance //缓存实例,按表名查找缓存表
+	adminToken string          //管理接口(AddTable)的token,为空时管理接口关闭
+}
+
+//根据表名和视图名,获取用于分页查询的排序视图.视图名为空时,返回表的默认视图.
+func (d *DBcacheGrpc) getView(tableName string, viewName string) (view *cache.SortView, err error) {
+	cacheObj, ok := d.instance.GetCacheObj(tableName)
+	if !ok {
+		err = fmt.Errorf("%s,The Table is not cache.", tableName)
+		return nil, err
+	}
+	return cacheObj.View(viewName)
+}
 
 //GetRow方法
 func (d *DBcacheGrpc) GetRow(ctx context.Context, req *pb.GetRowRequest) (resp *pb.GetRowResponse, err error) {
-	cacheObj, ok := cache.CacheObj[req.TableName]
+	cacheObj, ok := d.instance.GetCacheObj(req.TableName)
 	if !ok {
 		err = fmt.Errorf("%s,The Table is not cache.", req.TableName)
 		return nil, err
@@ -22,13 +37,13 @@ func (d *DBcacheGrpc) GetRow(ctx context.Context, req *pb.GetRowRequest) (resp *
 		return nil, err
 	}
 	resp = &pb.GetRowResponse{
-		Result: result,
+		Result: cacheObj.Policy().FilterRow(result),
 	}
 	return resp, nil
 }
 //GetRow方法 GetColumn(context.Context, *GetColumnRequest) (*GetColumnResponse, error)
 func (d *DBcacheGrpc) GetColumn(ctx context.Context, req *pb.GetColumnRequest) (resp *pb.GetColumnResponse, err error) {
-	cacheObj, ok := cache.CacheObj[req.TableName]
+	cacheObj, ok := d.instance.GetCacheObj(req.TableName)
 	if !ok {
 		err = fmt.Errorf("%s,The Table is not cache.", req.TableName)
 		return nil, err
@@ -37,6 +52,10 @@ func (d *DBcacheGrpc) GetColumn(ctx context.Context, req *pb.GetColumnRequest) (
 	if err != nil {
 		return nil, err
 	}
+	result, err = cacheObj.Policy().FilterColumn(req.Column, result)
+	if err != nil {
+		return nil, err
+	}
 	resp = &pb.GetColumnResponse{
 		Result: result,
 	}
@@ -44,7 +63,7 @@ func (d *DBcacheGrpc) GetColumn(ctx context.Context, req *pb.GetColumnRequest) (
 }
 //DelRow方法
 func (d *DBcacheGrpc) DelRow(ctx context.Context, req *pb.DelRowRequest) (resp *pb.DelRowResponse, err error) {
-	cacheObj, ok := cache.CacheObj[req.TableName]
+	cacheObj, ok := d.instance.GetCacheObj(req.TableName)
 	if !ok {
 		err = fmt.Errorf("%s,The Table is not cache.", req.TableName)
 		return nil, err
@@ -61,16 +80,19 @@ func (d *DBcacheGrpc) DelRow(ctx context.Context, req *pb.DelRowRequest) (resp *
 
 //GetWhere方法
 func (d *DBcacheGrpc) GetWhere(req *pb.GetWhereRequest, stream pb.GrpcDBcache_GetWhereServer) (err error) {
-	cacheObj, ok := cache.CacheObj[req.TableName]
+	cacheObj, ok := d.instance.GetCacheObj(req.TableName)
 	if !ok {
 		err = fmt.Errorf("%s,The Table is not cache.", req.TableName)
 		return err
 	}
+	if err = cacheObj.CheckWhere(req.Where); err != nil {
+		return err
+	}
 	result, err := cacheObj.GetWhere(req.Where)
 	if err != nil {
 		return err
 	}
-	for _, v := range result {
+	for _, v := range cacheObj.Policy().FilterRows(result) {
 		err := stream.Send(&pb.GetWhereResponse{
 			Result: &pb.GetWhereStream{Result: v,},
 		})
@@ -83,11 +105,14 @@ func (d *DBcacheGrpc) GetWhere(req *pb.GetWhereRequest, stream pb.GrpcDBcache_Ge
 
 //UpdateColumn方法
 func (d *DBcacheGrpc) UpdateColumn(ctx context.Context, req *pb.UpdateColumnRequest) (resp *pb.UpdateColumnResponse, err error) {
-	cacheObj, ok := cache.CacheObj[req.TableName]
+	cacheObj, ok := d.instance.GetCacheObj(req.TableName)
 	if !ok {
 		err = fmt.Errorf("%s,The Table is not cache.", req.TableName)
 		return nil, err
 	}
+	if err = cacheObj.Policy().CheckWrite(req.Column); err != nil {
+		return nil, err
+	}
 	result, err := cacheObj.UpdateColumn(req.Pkey, req.Column, req.ColumnValue)
 	if err != nil {
 		return nil, err
@@ -100,11 +125,14 @@ func (d *DBcacheGrpc) UpdateColumn(ctx context.Context, req *pb.UpdateColumnRequ
 
 //UpdateColumns
 func (d *DBcacheGrpc) UpdateColumns(ctx context.Context, req *pb.UpdateColumnsRequest) (resp *pb.UpdateColumnsResponse, err error) {
-	cacheObj, ok := cache.CacheObj[req.TableName]
+	cacheObj, ok := d.instance.GetCacheObj(req.TableName)
 	if !ok {
 		err = fmt.Errorf("%s,The Table is not cache.", req.TableName)
 		return nil, err
 	}
+	if err = cacheObj.CheckChanges(req.Where); err != nil {
+		return nil, err
+	}
 	result, err := cacheObj.UpdateColumns(req.Pkey, req.Where)
 	if err != nil {
 		return nil, err
@@ -117,33 +145,169 @@ func (d *DBcacheGrpc) UpdateColumns(ctx context.Context, req *pb.UpdateColumnsRe
 
 //InsertRow
 func (d *DBcacheGrpc) InsertRow(ctx context.Context, req *pb.InsertRowRequest) (resp *pb.InsertRowResponse, err error) {
-	cacheObj, ok := cache.CacheObj[req.TableName]
+	cacheObj, ok := d.instance.GetCacheObj(req.TableName)
 	if !ok {
 		err = fmt.Errorf("%s,The Table is not cache.", req.TableName)
 		return nil, err
 	}
-	result, err := cacheObj.InsertRow(req.Condition)
+	result, lastInsertId, err := cacheObj.InsertRow(req.Condition)
 	if err != nil {
 		return nil, err
 	}
 	resp = &pb.InsertRowResponse{
-		Result: result,
+		Result:       result,
+		LastInsertId: lastInsertId,
 	}
 	return resp, nil
 }
 
-//GetRowBetween方法
-func (d *DBcacheGrpc) GetRowBetween(req *pb.GetRowBetweenRequest, stream pb.GrpcDBcache_GetRowBetweenServer) (err error) {
-	cacheObj, ok := cache.CacheObj[req.TableName]
+//Upsert
+func (d *DBcacheGrpc) Upsert(ctx context.Context, req *pb.UpsertRequest) (resp *pb.UpsertResponse, err error) {
+	cacheObj, ok := d.instance.GetCacheObj(req.TableName)
 	if !ok {
 		err = fmt.Errorf("%s,The Table is not cache.", req.TableName)
+		return nil, err
+	}
+	//Upsert可能更新已有的行,不能包含只读的列(插入只读的列用InsertRow)
+	if err = cacheObj.CheckChanges(req.Row); err != nil {
+		return nil, err
+	}
+	result, isInsert, isKnown, err := cacheObj.Upsert(req.Row)
+	if err != nil {
+		return nil, err
+	}
+	resp = &pb.UpsertResponse{
+		Result:   result,
+		IsInsert: isInsert,
+		IsKnown:  isKnown,
+	}
+	return resp, nil
+}
+
+//InsertRows方法,客户端流式,接收全部行后批量插入.
+func (d *DBcacheGrpc) InsertRows(stream pb.GrpcDBcache_InsertRowsServer) (err error) {
+	var tableName string
+	rows := make([]string, 0)
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		tableName = req.TableName
+		rows = append(rows, req.Row)
+	}
+	cacheObj, ok := d.instance.GetCacheObj(tableName)
+	if !ok {
+		err = fmt.Errorf("%s,The Table is not cache.", tableName)
+		return err
+	}
+	result, err := cacheObj.InsertRows(rows)
+	if err != nil {
+		return err
+	}
+	return stream.SendAndClose(&pb.InsertRowsResponse{Result: result})
+}
+
+//DelRows方法,客户端流式,接收全部主键后批量删除.
+func (d *DBcacheGrpc) DelRows(stream pb.GrpcDBcache_DelRowsServer) (err error) {
+	var tableName string
+	pkeys := make([]string, 0)
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		tableName = req.TableName
+		pkeys = append(pkeys, req.Pkey)
+	}
+	cacheObj, ok := d.instance.GetCacheObj(tableName)
+	if !ok {
+		err = fmt.Errorf("%s,The Table is not cache.", tableName)
 		return err
 	}
-	result := cacheObj.GetRowBetween(int(req.Start), int(req.End))
+	result, err := cacheObj.DelRows(pkeys)
+	if err != nil {
+		return err
+	}
+	return stream.SendAndClose(&pb.DelRowsResponse{Result: result})
+}
+
+//UpdateWhere方法,客户端流式,每条请求按where条件更新,返回更新的总行数.
+func (d *DBcacheGrpc) UpdateWhere(stream pb.GrpcDBcache_UpdateWhereServer) (err error) {
+	var result int64
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		cacheObj, ok := d.instance.GetCacheObj(req.TableName)
+		if !ok {
+			err = fmt.Errorf("%s,The Table is not cache.", req.TableName)
+			return err
+		}
+		if err = cacheObj.CheckWhere(req.Where); err != nil {
+			return err
+		}
+		if err = cacheObj.CheckChanges(req.Changes); err != nil {
+			return err
+		}
+		n, err := cacheObj.UpdateWhere(req.Where, req.Changes)
+		if err != nil {
+			return err
+		}
+		result += n
+	}
+	return stream.SendAndClose(&pb.UpdateWhereResponse{Result: result})
+}
+
+//DeleteWhere方法,客户端流式,每条请求按where条件删除,返回删除的总行数.
+func (d *DBcacheGrpc) DeleteWhere(stream pb.GrpcDBcache_DeleteWhereServer) (err error) {
+	var result int64
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		cacheObj, ok := d.instance.GetCacheObj(req.TableName)
+		if !ok {
+			err = fmt.Errorf("%s,The Table is not cache.", req.TableName)
+			return err
+		}
+		if err = cacheObj.CheckWhere(req.Where); err != nil {
+			return err
+		}
+		n, err := cacheObj.DeleteWhere(req.Where)
+		if err != nil {
+			return err
+		}
+		result += n
+	}
+	return stream.SendAndClose(&pb.DeleteWhereResponse{Result: result})
+}
+
+//GetRowBetween方法
+func (d *DBcacheGrpc) GetRowBetween(req *pb.GetRowBetweenRequest, stream pb.GrpcDBcache_GetRowBetweenServer) (err error) {
+	view, err := d.getView(req.TableName, req.View)
+	if err != nil {
+		return err
+	}
+	result := view.GetRowBetween(int(req.Start), int(req.End))
 	if len(result) == 0 {
 		return nil
 	}
-	for _, v := range result {
+	for _, v := range view.Policy().FilterRows(result) {
 		err := stream.Send(&pb.GetRowBetweenResponse{
 			Result: &pb.GetRowBetweentream{Result: v,},
 		})
@@ -155,12 +319,11 @@ func (d *DBcacheGrpc) GetRowBetween(req *pb.GetRowBetweenRequest, stream pb.Grpc
 }
 //GetPageCount方法
 func (d *DBcacheGrpc) GetPageCount(ctx context.Context,req *pb.GetPageCountRequest) (resp *pb.GetPageCountResponse,err error) {
-	cacheObj, ok := cache.CacheObj[req.TableName]
-	if !ok {
-		err = fmt.Errorf("%s,The Table is not cache.", req.TableName)
+	view, err := d.getView(req.TableName, req.View)
+	if err != nil {
 		return nil,err
 	}
-	result := cacheObj.GetPageCount(int(req.PageSize))
+	result := view.GetPageCount(int(req.PageSize))
 	resp = &pb.GetPageCountResponse{
 		Result: int64(result),
 	}
@@ -169,16 +332,15 @@ func (d *DBcacheGrpc) GetPageCount(ctx context.Context,req *pb.GetPageCountReque
 
 //GetMultipageRows方法
 func (d *DBcacheGrpc) GetMultipageRows(req *pb.GetMultipageRowsRequest, stream pb.GrpcDBcache_GetMultipageRowsServer) (err error) {
-	cacheObj, ok := cache.CacheObj[req.TableName]
-	if !ok {
-		err = fmt.Errorf("%s,The Table is not cache.", req.TableName)
+	view, err := d.getView(req.TableName, req.View)
+	if err != nil {
 		return err
 	}
-	result := cacheObj.GetMultipageRows(int(req.StartPage), int(req.PageNum),int(req.PageSize))
+	result := view.GetMultipageRows(int(req.StartPage), int(req.PageNum),int(req.PageSize))
 	if len(result) == 0 {
 		return nil
 	}
-	for _, v := range result {
+	for _, v := range view.Policy().FilterRows(result) {
 		err := stream.Send(&pb.GetMultipageRowsResponse{
 			Result: &pb.GetMultipageRowstream{Result: v,},
 		})
@@ -191,16 +353,15 @@ func (d *DBcacheGrpc) GetMultipageRows(req *pb.GetMultipageRowsRequest, stream p
 
 //GetOnePageRows方法
 func (d *DBcacheGrpc) GetOnePageRows(req *pb.GetOnePageRowsRequest, stream pb.GrpcDBcache_GetOnePageRowsServer) (err error) {
-	cacheObj, ok := cache.CacheObj[req.TableName]
-	if !ok {
-		err = fmt.Errorf("%s,The Table is not cache.", req.TableName)
+	view, err := d.getView(req.TableName, req.View)
+	if err != nil {
 		return err
 	}
-	result := cacheObj.GetOnePageRows(int(req.Page), int(req.PageSize))
+	result := view.GetOnePageRows(int(req.Page), int(req.PageSize))
 	if len(result) == 0 {
 		return nil
 	}
-	for _, v := range result {
+	for _, v := range view.Policy().FilterRows(result) {
 		err := stream.Send(&pb.GetOnePageRowsResponse{
 			Result: &pb.GetOnePageRowstream{Result: v,},
 		})
@@ -209,4 +370,192 @@ func (d *DBcacheGrpc) GetOnePageRows(req *pb.GetOnePageRowsRequest, stream pb.Gr
 		}
 	}
 	return nil
-}
\ No newline at end of file
+}
+//把多行数据转换为GetWhereStream切片
+func toWhereStream(result []map[string]string) (rows []*pb.GetWhereStream) {
+	rows = make([]*pb.GetWhereStream, 0, len(result))
+	for _, v := range result {
+		rows = append(rows, &pb.GetWhereStream{Result: v})
+	}
+	return rows
+}
+
+//GetWhereRowBetween方法
+func (d *DBcacheGrpc) GetWhereRowBetween(ctx context.Context, req *pb.GetWhereRowBetweenRequest) (resp *pb.GetWhereRowBetweenResponse, err error) {
+	view, err := d.getView(req.TableName, req.View)
+	if err != nil {
+		return nil, err
+	}
+	if err = view.CheckWhere(req.Where); err != nil {
+		return nil, err
+	}
+	result, total, err := view.GetWhereRowBetween(req.Where, int(req.Start), int(req.End))
+	if err != nil {
+		return nil, err
+	}
+	resp = &pb.GetWhereRowBetweenResponse{
+		Result: toWhereStream(view.Policy().FilterRows(result)),
+		Total:  int64(total),
+	}
+	return resp, nil
+}
+
+//GetWherePageCount方法
+func (d *DBcacheGrpc) GetWherePageCount(ctx context.Context, req *pb.GetWherePageCountRequest) (resp *pb.GetWherePageCountResponse, err error) {
+	view, err := d.getView(req.TableName, req.View)
+	if err != nil {
+		return nil, err
+	}
+	if err = view.CheckWhere(req.Where); err != nil {
+		return nil, err
+	}
+	result, total, err := view.GetWherePageCount(req.Where, int(req.PageSize))
+	if err != nil {
+		return nil, err
+	}
+	resp = &pb.GetWherePageCountResponse{
+		Result: int64(result),
+		Total:  int64(total),
+	}
+	return resp, nil
+}
+
+//GetWhereMultipageRows方法
+func (d *DBcacheGrpc) GetWhereMultipageRows(ctx context.Context, req *pb.GetWhereMultipageRowsRequest) (resp *pb.GetWhereMultipageRowsResponse, err error) {
+	view, err := d.getView(req.TableName, req.View)
+	if err != nil {
+		return nil, err
+	}
+	if err = view.CheckWhere(req.Where); err != nil {
+		return nil, err
+	}
+	result, total, pageCount, err := view.GetWhereMultipageRows(req.Where, int(req.StartPage), int(req.PageNum), int(req.PageSize))
+	if err != nil {
+		return nil, err
+	}
+	resp = &pb.GetWhereMultipageRowsResponse{
+		Result:    toWhereStream(view.Policy().FilterRows(result)),
+		Total:     int64(total),
+		PageCount: int64(pageCount),
+	}
+	return resp, nil
+}
+
+//GetWhereOnePageRows方法
+func (d *DBcacheGrpc) GetWhereOnePageRows(ctx context.Context, req *pb.GetWhereOnePageRowsRequest) (resp *pb.GetWhereOnePageRowsResponse, err error) {
+	view, err := d.getView(req.TableName, req.View)
+	if err != nil {
+		return nil, err
+	}
+	if err = view.CheckWhere(req.Where); err != nil {
+		return nil, err
+	}
+	result, total, pageCount, err := view.GetWhereOnePageRows(req.Where, int(req.Page), int(req.PageSize))
+	if err != nil {
+		return nil, err
+	}
+	resp = &pb.GetWhereOnePageRowsResponse{
+		Result:    toWhereStream(view.Policy().FilterRows(result)),
+		Total:     int64(total),
+		PageCount: int64(pageCount),
+	}
+	return resp, nil
+}
+
+//GetPageAfter方法
+func (d *DBcacheGrpc) GetPageAfter(ctx context.Context, req *pb.GetPageAfterRequest) (resp *pb.GetPageAfterResponse, err error) {
+	view, err := d.getView(req.TableName, req.View)
+	if err != nil {
+		return nil, err
+	}
+	result, next, prev, err := view.GetPageAfter(req.Cursor, int(req.PageSize))
+	if err != nil {
+		return nil, err
+	}
+	resp = &pb.GetPageAfterResponse{
+		Result: toWhereStream(view.Policy().FilterRows(result)),
+		Next:   next,
+		Prev:   prev,
+	}
+	return resp, nil
+}
+
+//GetPageBefore方法
+func (d *DBcacheGrpc) GetPageBefore(ctx context.Context, req *pb.GetPageBeforeRequest) (resp *pb.GetPageBeforeResponse, err error) {
+	view, err := d.getView(req.TableName, req.View)
+	if err != nil {
+		return nil, err
+	}
+	result, next, prev, err := view.GetPageBefore(req.Cursor, int(req.PageSize))
+	if err != nil {
+		return nil, err
+	}
+	resp = &pb.GetPageBeforeResponse{
+		Result: toWhereStream(view.Policy().FilterRows(result)),
+		Next:   next,
+		Prev:   prev,
+	}
+	return resp, nil
+}
+
+//GetPage方法
+func (d *DBcacheGrpc) GetPage(ctx context.Context, req *pb.GetPageRequest) (resp *pb.GetPageResponse, err error) {
+	view, err := d.getView(req.TableName, req.View)
+	if err != nil {
+		return nil, err
+	}
+	if err = view.CheckWhere(req.Where); err != nil {
+		return nil, err
+	}
+	var result *cache.PageResult
+	if req.Where == "" {
+		result, err = view.GetPage(int(req.Page), int(req.PageSize))
+	} else {
+		result, err = view.GetWherePage(req.Where, int(req.Page), int(req.PageSize))
+	}
+	if err != nil {
+		return nil, err
+	}
+	resp = &pb.GetPageResponse{
+		Result:    toWhereStream(view.Policy().FilterRows(result.Rows)),
+		Total:     int64(result.Total),
+		PageCount: int64(result.PageCount),
+		Page:      int64(result.Page),
+		PageSize:  int64(result.PageSize),
+		HasNext:   result.HasNext,
+		HasPrev:   result.HasPrev,
+	}
+	return resp, nil
+}
+
+//AddTable方法,管理接口:运行中增加缓存表,写入cache.conf(保留注释,原子替换)并从数据库加载.
+//需配置admin_token,请求的Token与之相同.表配置的检查见cache.Instance.AddRemoteTable().
+func (d *DBcacheGrpc) AddTable(ctx context.Context, req *pb.AddTableRequest) (resp *pb.AddTableResponse, err error) {
+	err = cache.CheckAdminToken(d.adminToken, req.Token)
+	if err != nil {
+		return nil, err
+	}
+	table := conf.CacheTable{
+		TableName:         req.TableName,
+		Columns:           req.Columns,
+		Pkey:              req.Pkey,
+		Where:             req.Where,
+		Other:             req.Other,
+		PkeyAutoIncrement: req.PkeyAutoIncrement,
+		CacheType:         req.CacheType,
+		IsRealtime:        req.IsRealtime,
+		IsWaitResult:      req.IsWaitResult,
+		SortViews:         req.SortViews,
+		HiddenColumns:     req.HiddenColumns,
+		MaskedColumns:     req.MaskedColumns,
+		ReadonlyColumns:   req.ReadonlyColumns,
+	}
+	cacheObj, err := d.instance.AddRemoteTable(req.Name, table)
+	if err != nil {
+		return nil, err
+	}
+	resp = &pb.AddTableResponse{
+		Result: cacheObj.GetRowCount(),
+	}
+	return resp, nil
+}
@@ -8,6 +8,7 @@ import (
 	"net"
 	"net/smtp"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -23,6 +24,9 @@ type EmailLog struct {
 	MaxEmailChan int      `conf:"max_email_chan"` //异步发送邮件,最大缓存邮件数
 	Mail         *gomail.Message //发邮件对象
 	EmailChan    chan *emailMsg //邮件日志存放管道
+	stopChan     chan struct{}   //通知后台发邮件协程退出
+	wg           sync.WaitGroup  //等待后台发邮件协程退出
+	closeOnce    sync.Once       //保证只关闭一次
 }
 //邮件消息对象
 type emailMsg struct {
@@ -53,6 +57,7 @@ func NewEmailLog(enable bool, levelStr, host string, port int, sendEmail, sendPa
 		EmailCC:    emailCC,
 		Mail:       gomail.NewMessage(),
 		EmailChan:  make(chan *emailMsg, maxEmailChan),
+		stopChan:   make(chan struct{}),
 	}
 
 	// 多个收件人
@@ -87,6 +92,7 @@ func NewEmailLog(enable bool, levelStr, host string, port int, sendEmail, sendPa
 
 func (e *EmailLog) initEmailLog() {
 	//后台异步写日志
+	e.wg.Add(1)
 	go e.backOutputEmail()
 
 }
@@ -115,7 +121,7 @@ func (e *EmailLog) SendEmailLog(subject, body string) {
 }
 
 func (e *EmailLog) outputEmail(level logLevel, format string, a ...interface{}) {
-	if e.isEnable(level) && e.Enable == true {
+	if e.isEnable(level) {
 		msg := fmt.Sprintf(format, a...)
 		time := time.Now().Format("2006-01-02 15:04:05")
 		funcName, fileName, line := getCallInfo(5)
@@ -134,20 +140,28 @@ func (e *EmailLog) outputEmail(level logLevel, format string, a ...interface{})
 }
 
 func (e *EmailLog) backOutputEmail() {
+	defer e.wg.Done()
 	for {
-		msg := &emailMsg{}
 		select {
-		case msg = <-e.EmailChan:
+		case msg := <-e.EmailChan:
 			e.SendEmailLog(msg.subject, msg.body)
-			time.Sleep(time.Millisecond * 500)
-		default:
-			time.Sleep(time.Millisecond * 500)
-			continue
+		case <-e.stopChan:
+			//收到退出通知,把管道中剩余的邮件发完,再退出.
+			for {
+				select {
+				case msg := <-e.EmailChan:
+					e.SendEmailLog(msg.subject, msg.body)
+				default:
+					return
+				}
+			}
 		}
 	}
 }
 func (e *EmailLog) isEnable(level logLevel) bool {
-	return level >= e.Level
+	levelMutex.RLock()
+	defer levelMutex.RUnlock()
+	return e.Enable && level >= e.Level
 }
 func (e *EmailLog) Unknown(format string, a ...interface{}) {
 	e.outputEmail(UNKNOWN, format, a...)
@@ -170,8 +184,12 @@ func (e *EmailLog) Error(format string, a ...interface{}) {
 func (e *EmailLog) Fatal(format string, a ...interface{}) {
 	e.outputEmail(FATAL, format, a...)
 }
+//停止后台发邮件协程,把管道中剩余的邮件发完.
 func (e *EmailLog) Close() {
-
+	e.closeOnce.Do(func() {
+		close(e.stopChan)
+		e.wg.Wait()
+	})
 }
 
 //以下是安全传输层协议（TLS）,来传输邮件。
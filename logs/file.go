@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"sync"
 	"time"
 )
 
@@ -18,6 +19,9 @@ type FileLog struct {
 	FileObj     *os.File     //标准日志文件对象.
 	ErrFileObj  *os.File     //单独错误日志文件对象.
 	LogChan     chan *logMsg //日志存放管道
+	stopChan    chan struct{}  //通知后台写日志协程退出
+	wg          sync.WaitGroup //等待后台写日志协程退出
+	closeOnce   sync.Once      //保证只关闭一次
 }
 
 type logMsg struct {
@@ -43,6 +47,7 @@ func NewFileLog(enable bool, levelStr, filePath, fileName string, maxFileSize in
 		MaxFileSize: maxFileSize,
 		MaxLogChan:  maxLogChan,
 		LogChan:     make(chan *logMsg, maxLogChan),
+		stopChan:    make(chan struct{}),
 	}
 	err = fileLog.initFileLog()
 	if err != nil {
@@ -65,17 +70,26 @@ func (f *FileLog) initFileLog() (err error) {
 	f.FileObj = fileObj
 	f.ErrFileObj = errFileObj
 	//后台异步写日志
+	f.wg.Add(1)
 	go f.backOutputFile()
 
 	return nil
 }
+
+//停止后台写日志协程,把管道中剩余的日志写完,再关闭文件.
 func (f *FileLog) Close() {
-	f.FileObj.Close()
-	f.ErrFileObj.Close()
+	f.closeOnce.Do(func() {
+		close(f.stopChan)
+		f.wg.Wait()
+		f.FileObj.Close()
+		f.ErrFileObj.Close()
+	})
 }
 
 func (f *FileLog) isEnable(level logLevel) bool {
-	return level >= f.Level
+	levelMutex.RLock()
+	defer levelMutex.RUnlock()
+	return f.Enable && level >= f.Level
 }
 func (f *FileLog) checkSize(file *os.File) bool {
 	fileInfo, err := file.Stat()
@@ -114,7 +128,7 @@ func (f *FileLog) splitFile(file *os.File) (newFile *os.File, err error) {
 
 //输出日志
 func (f *FileLog) outputFile(level logLevel, format string, a ...interface{}) {
-	if f.isEnable(level) && f.Enable == true {
+	if f.isEnable(level) {
 		msg := fmt.Sprintf(format, a...)
 		time := time.Now().Format("2006-01-02 15:04:05")
 		funcName, fileName, line := getCallInfo(5)
@@ -137,39 +151,47 @@ func (f *FileLog) outputFile(level logLevel, format string, a ...interface{}) {
 
 //后台异步写文件
 func (f *FileLog) backOutputFile() {
+	defer f.wg.Done()
 	for {
-		if f.checkSize(f.FileObj) {
-			newFile, err := f.splitFile(f.FileObj)
-			if err != nil {
-				fmt.Println(err)
+		select {
+		case logTmp := <-f.LogChan:
+			f.writeLog(logTmp)
+		case <-f.stopChan:
+			//收到退出通知,把管道中剩余的日志写完,再退出.
+			for {
+				select {
+				case logTmp := <-f.LogChan:
+					f.writeLog(logTmp)
+				default:
+					return
+				}
 			}
-			f.FileObj = newFile
 		}
+	}
+}
 
-		logTmp := &logMsg{}
-		select {
-		case logTmp = <-f.LogChan:
-		default:
-			time.Sleep(time.Millisecond * 500)
-			continue
+//写一条日志到文件
+func (f *FileLog) writeLog(logTmp *logMsg) {
+	if f.checkSize(f.FileObj) {
+		newFile, err := f.splitFile(f.FileObj)
+		if err != nil {
+			fmt.Println(err)
 		}
+		f.FileObj = newFile
+	}
 
-		msgInfo := fmt.Sprintf("[%s] [%s] [%s][%s:%d] %s\n", logTmp.timestamp, GetLevelStr(logTmp.level), logTmp.fileName, logTmp.funcName, logTmp.line, logTmp.msg)
-		fmt.Fprintf(f.FileObj, msgInfo)
-		time.Sleep(time.Millisecond * 500)
-		//如果日志等级大于ERROR,另外在err错误日志再记录一遍.
-		if logTmp.level >= ERROR {
-			if f.checkSize(f.ErrFileObj) {
-				newFile, err := f.splitFile(f.ErrFileObj)
-				if err != nil {
-					fmt.Println(err)
-				}
-				f.ErrFileObj = newFile
+	msgInfo := fmt.Sprintf("[%s] [%s] [%s][%s:%d] %s\n", logTmp.timestamp, GetLevelStr(logTmp.level), logTmp.fileName, logTmp.funcName, logTmp.line, logTmp.msg)
+	fmt.Fprintf(f.FileObj, msgInfo)
+	//如果日志等级大于ERROR,另外在err错误日志再记录一遍.
+	if logTmp.level >= ERROR {
+		if f.checkSize(f.ErrFileObj) {
+			newFile, err := f.splitFile(f.ErrFileObj)
+			if err != nil {
+				fmt.Println(err)
 			}
-			msgInfo = fmt.Sprintf("[%s] [%s] [%s][%s:%d] %s\n", logTmp.timestamp, GetLevelStr(logTmp.level), logTmp.fileName, logTmp.funcName, logTmp.line, logTmp.msg)
-			fmt.Fprintf(f.ErrFileObj, msgInfo)
-			time.Sleep(time.Millisecond * 500)
+			f.ErrFileObj = newFile
 		}
+		fmt.Fprintf(f.ErrFileObj, msgInfo)
 	}
 }
 
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"path"
+	"path/filepath"
 	"runtime"
 	"strings"
 )
@@ -33,6 +34,12 @@ var (
 
 //初始化日志
 func InitLog() {
+	InitLogDir("")
+}
+
+//初始化日志,dataDir不为空时,文件日志的相对路径(file_path)相对于dataDir,否则相对于当前目录.
+//日志是进程级的,同一进程中运行多个实例时共用(按conf.CONFIG_FILE中的配置).
+func InitLogDir(dataDir string) {
 	//读取配置文件中，标准屏幕输出配置
 	err := conf.ParseConf(conf.CONFIG_FILE, &sLogConf)
 	if err != nil {
@@ -51,7 +58,11 @@ func InitLog() {
 	//标准输出日志对象
 	Slog = NewStdoutLog(sLogConf.Enable, GetLevelStr(sLogConf.Level))
 	//文件日志对象
-	Flog = NewFileLog(fLogConf.Enable, GetLevelStr(fLogConf.Level), fLogConf.FilePath, fLogConf.FileName,
+	filePath := fLogConf.FilePath
+	if dataDir != "" && !filepath.IsAbs(filePath) {
+		filePath = filepath.Join(dataDir, filePath)
+	}
+	Flog = NewFileLog(fLogConf.Enable, GetLevelStr(fLogConf.Level), filePath, fLogConf.FileName,
 		fLogConf.MaxFileSize*1024*1024, fLogConf.MaxLogChan)
 	//邮件输出日志对象
 	Elog = NewEmailLog(elogConf.Enable, GetLevelStr(elogConf.Level), elogConf.Host, elogConf.Port, elogConf.SendEmail,
@@ -75,8 +86,14 @@ func Error(out, format string, a ...interface{}) {
 func Fatal(out, format string, a ...interface{}) {
 	outLog(out, "Fatal", format, a...)
 }
+//关闭日志,等待管道中剩余的日志输出完.
 func Close() {
-	Flog.Close()
+	if Flog != nil {
+		Flog.Close()
+	}
+	if Elog != nil {
+		Elog.Close()
+	}
 }
 
 //A和ALL,则文件,终端,邮件都输出,FILE和F只输出到文件,STDOUT和S只输出到终端.EMAIL和E只输出到邮件
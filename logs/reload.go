@@ -0,0 +1,51 @@
+package logs
+
+import (
+	"dbcache/conf"
+	"fmt"
+	"sync"
+)
+
+//热加载日志配置:重新读取config.conf中各日志的是否启用(enable)和日志等级(run_level),立即生效.
+//其它日志配置(文件路径,文件大小,邮件服务器等)需重启后生效.
+
+var levelMutex sync.RWMutex //保护各日志对象的Enable和Level,运行中可修改
+
+//从configFile(热加载的实例的config.conf)重新读取日志的是否启用和日志等级.返回改变的配置项说明.
+func Reload(configFile string) (changes []string, err error) {
+	stdoutConf, fileConf, emailConf := StdoutLog{}, FileLog{}, EmailLog{}
+	if err = conf.ParseConf(configFile, &stdoutConf); err != nil {
+		return nil, fmt.Errorf("Reload(),读取标准输出日志配置失败, err: %s", err)
+	}
+	if err = conf.ParseConf(configFile, &fileConf); err != nil {
+		return nil, fmt.Errorf("Reload(),读取文件日志配置失败, err: %s", err)
+	}
+	if err = conf.ParseConf(configFile, &emailConf); err != nil {
+		return nil, fmt.Errorf("Reload(),读取邮件日志配置失败, err: %s", err)
+	}
+	levelMutex.Lock()
+	defer levelMutex.Unlock()
+	if Slog != nil {
+		changes = append(changes, setLevel("StdoutLog", &Slog.Enable, &Slog.Level, stdoutConf.Enable, stdoutConf.Level)...)
+	}
+	if Flog != nil {
+		changes = append(changes, setLevel("FileLog", &Flog.Enable, &Flog.Level, fileConf.Enable, fileConf.Level)...)
+	}
+	if Elog != nil {
+		changes = append(changes, setLevel("EmailLog", &Elog.Enable, &Elog.Level, emailConf.Enable, emailConf.Level)...)
+	}
+	return changes, nil
+}
+
+//修改一个日志对象的是否启用和日志等级,返回改变的说明.调用者需持有levelMutex写锁.
+func setLevel(name string, enable *bool, level *logLevel, newEnable bool, newLevel logLevel) (changes []string) {
+	if *enable != newEnable {
+		changes = append(changes, fmt.Sprintf("[%s] enable: %t -> %t", name, *enable, newEnable))
+		*enable = newEnable
+	}
+	if *level != newLevel {
+		changes = append(changes, fmt.Sprintf("[%s] run_level: %s -> %s", name, GetLevelStr(*level), GetLevelStr(newLevel)))
+		*level = newLevel
+	}
+	return changes
+}
@@ -21,7 +21,7 @@ func NewStdoutLog(enable bool, levelStr string) *StdoutLog {
 
 func (c *StdoutLog) outputStdout(level logLevel, format string, a ...interface{}) {
 
-	if c.isEnable(level) && c.Enable == true {
+	if c.isEnable(level) {
 		msg := fmt.Sprintf(format, a...)
 		time := time.Now().Format("2006-01-02 15:04:05")
 		funcName, fileName, line := getCallInfo(5)
@@ -29,7 +29,9 @@ func (c *StdoutLog) outputStdout(level logLevel, format string, a ...interface{}
 	}
 }
 func (c *StdoutLog) isEnable(level logLevel) bool {
-	return level >= c.Level
+	levelMutex.RLock()
+	defer levelMutex.RUnlock()
+	return c.Enable && level >= c.Level
 }
 func (c *StdoutLog) Unknown(format string, a ...interface{}) {
 	c.outputStdout(UNKNOWN, format, a...)
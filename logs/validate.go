@@ -0,0 +1,38 @@
+package logs
+
+import (
+	"dbcache/conf"
+)
+
+//检查config.conf(fileName)中日志的配置(dbcache validate).
+func ValidateConf(r *conf.Report, fileName string) {
+	stdoutConf, fileConf, emailConf := StdoutLog{}, FileLog{}, EmailLog{}
+	if conf.CheckSection(r, fileName, "StdoutLog", &stdoutConf, true) {
+		checkLevel(r, fileName, "StdoutLog", stdoutConf.Level)
+	}
+	if conf.CheckSection(r, fileName, "FileLog", &fileConf, true) {
+		checkLevel(r, fileName, "FileLog", fileConf.Level)
+		if fileConf.FileName == "" {
+			r.Error(fileName, "FileLog", "file_name", "不能为空")
+		}
+		if fileConf.MaxLogChan < 1 {
+			r.Error(fileName, "FileLog", "max_log_chan", "应大于0, 实际为%d", fileConf.MaxLogChan)
+		}
+	}
+	if conf.CheckSection(r, fileName, "EmailLog", &emailConf, false) {
+		checkLevel(r, fileName, "EmailLog", emailConf.Level)
+		if emailConf.Enable && emailConf.Recipient == "" {
+			r.Warning(fileName, "EmailLog", "recipient", "未配置接收者,邮件日志不会发送")
+		}
+		if emailConf.Enable && (emailConf.Port < 1 || emailConf.Port > 65535) {
+			r.Error(fileName, "EmailLog", "port", "端口应在1-65535之间, 实际为%d", emailConf.Port)
+		}
+	}
+}
+
+//检查日志等级在1(DEBUG)到6(FATAL)之间
+func checkLevel(r *conf.Report, fileName string, section string, level logLevel) {
+	if level < DEBUG || level > FATAL {
+		r.Error(fileName, section, "run_level", "应在1(DEBUG)到6(FATAL)之间, 实际为%d", level)
+	}
+}
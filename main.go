@@ -1,341 +1,225 @@
 package main
-//此文件为样例.
+
 import (
+	"bufio"
+	"context"
 	"dbcache/cache"
-	"dbcache/comm"
+	"dbcache/conf"
 	"dbcache/db"
-	"dbcache/grpcserver"
-	"dbcache/logs" //日志库
-	"dbcache/rpcserver"
+	"dbcache/logs"
+	"dbcache/server"
+	"flag"
 	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 )
-/*
-	使用说明:
-
-	配置程序目录下二个件:
-	一. config.conf(配置数据库连接和日志,rpc和grpc)
-	二. cache.conf(配置需要缓存的表及列,是否实时更新,还是异步更新数据库等.
-
-*/
-
-//注意:
-//目前只支持主键为一个列.
-//缓存数据可以是字符,整型,浮点,日期(datetime,timestamp).
-
-//如果实时更新,数据更新是先更新数据库,再更新缓存.
-//异步更新,会先把执行SQL语句保存于当前目录下的文件async_sql.sql,再更新数据库,如果更新失败,会把失败的sql的语句保存于async_sql_failed.sql文件.
-
-//支持日志系统: s 标准输出屏幕, f 记录到日志, e 发送邮件, a 所有(包括s,f,e)(需先在配置文件config.conf中配置),
-//等级说明:1 DEBUG,2 TRACE,3 INFO,4 WARNING,5 ERROR,6 FATAL
-//注意二个开关,enable控制是否开启,run_level运行等级,只在大于或等于设置等级才输出日志.)
-//如有什么问题,讨论可联系:38704889@qq.com
-
-/*主要函数,下面有用例:
-  1. GetRow():根据主键值,取得该行数据
-  2. GetColumn():根据主键,取得某列的数据
-  3.DelRow():根据主键,删除该行数据
-  4.GetWhere():根据where条件,查询缓存中所有符合条件的行.不用加引号
-  5.UpdateColumn():根据主键,更新一列
-  6. UpdateColumns():根据主键,更新多列
-  7.InsertRow():插入一行数据
-  8.GetRowBetween():从缓存中,获取指定的行,开始行-结束行.用于页面分页显示.
-  9.GetPageCount():获取总页数.用于页面分页显示.
-  10.GetMultipageRows():用于分页,根据指定开始页,获取多少页,每页行数.返回多页行数据.
-  11.GetOnePageRows():用于分页,根据页码和每页行数大小,返回单页行数据.
-*/
 
 /*
-    (注意数据库连接参数:&parseTime=true&loc=Local)
-    如果在连接时加上参数:&parseTime=true 返回数据带时区信息.现只是保存为字符串,如果需要转换time类型,需注意数据库和服务器的时区问题)
-    parseTime=true,不加这个参数,数据库返回时就不带时区.
-    参数: parseTime是查询结果是否自动解析为时间. loc是MySQL的时区设置.
-
-	在windows下，time.Parse()的时区和time.Format()的时区是一致的。
-	在linux环境下，time.Parse()的默认时区是UTC，time.Format()的时区默认是本地
-    在时间转换时要注意.相差8小时 (CST=UTC+8小时) (UTC=CST-8小时)
-    time.UTC():将当地时区转化为UTC时间. time.Local():将UTC时间转化为当地时间
-    解决办法:使用time.FixedZone,在init初始化时,或相关时间调用函数的代码使用之前加入如下代码)
-			timelocal := time.FixedZone("CST", 3600*8)
-			time.Local = timelocal
-
-    字符串转time.Time
-		timeStr := "2020-02-03 21:00:00"//时间字符串
-		t, err := time.ParseInLocation("2006-01-02 15:04", timeStr, time.Local) //t被转为本地时间的time.Time
-		t,err := time.Parse("2006-01-02 15:04", timeStr)                        //t被转为UTC时间的time.Time
-
-    UTC世界标准时间、世界统一时间,CST时间,CST可以同时表示美国，澳大利亚，中国，古巴四个国家的标准时间。
-    CST同时可以代表如下 4 个不同的时区：
-	Central Standard Time (USA) UT-6:00 美国
-	Central Standard Time (Australia) UT+9:30 澳大利亚
-	China Standard Time UT+8:00 中国
-	Cuba Standard Time UT-4:00 古巴
+	dbcache服务程序,用法:
+	dbcache [serve] [-config config.conf] [-tables cache.conf] [-data-dir 目录] [-rpc-addr ip:port] [-grpc-addr ip:port]
+	dbcache validate [-config config.conf] [-tables cache.conf]
+	dbcache gen-config -table 表名 [-name 分段名] [-exclude 列1,列2] [-cache-type link] [-write] [-config config.conf] [-tables cache.conf]
+	dbcache encrypt [明文]
+	dbcache version
+
+	子命令:
+	一. serve(默认):加载cache.conf中的表,启动rpc和grpc服务,收到SIGINT或SIGTERM时优雅关闭.
+	二. validate:只检查配置文件和数据库中的缓存表,输出检查报告后退出,有错误时退出码为1.
+	三. gen-config:读取数据库中表的结构(列,主键,主键是否自增),生成cache.conf的分段输出到标准输出,-write时追加到-tables文件末尾.
+	四. encrypt:加密配置项的值,输出ENC(...),复制到配置文件中.不带明文时从标准输入读取(避免留在命令历史中).
+	五. version:输出版本号.
+
+	-data-dir:异步sql文件(async_file_path)和日志文件(file_path)为相对路径时,相对于此目录.
+	-rpc-addr,-grpc-addr:替换config.conf中的ip_address和ip_port,为off时不启动该服务.
+	在程序中直接使用缓存的样例见example/example.go.
 */
-//用于测试user表,将map数据转换为结构体,首字母要大小
-type user struct {
-	Uid string
-	Age string
-	Price string
-	Name string
-	Address string
-	Password string
-	Create_date string
-	Update_date string
-}
 
-func main() {
-	//初始化日志库
-	logs.InitLog()
-	defer logs.Close()
+var Version = "dev" //版本号,编译时指定: go build -ldflags "-X main.Version=1.0.0"
 
-	//连接数据库,初始化调用.
-	db, err := db.ConnectDB()
-	if err != nil {
-		logs.Fatal("a", "连接数据库失败, err: %s", err)
-		return
-	}
-	defer db.Close()
+//关闭超时时间,超过此时间未完成关闭,强制退出.
+const SHUTDOWN_TIMEOUT = time.Second * 30
 
-	//缓存users表
-	UsersCache, err := cache.NewDBcache(db, "users")
-	if err != nil {
-		logs.Fatal("a", "初始化缓存失败, err: %s", err)
-		return
-	}
-	defer UsersCache.Close()
-
-	//缓存Goods表
-	GoodsCache, err := cache.NewDBcache(db, "goods")
-	if err != nil {
-		logs.Fatal("a", "初始化缓存失败, err: %s", err)
-		return
+func main() {
+	command, args := "serve", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
+	switch command {
+	case "serve":
+		os.Exit(serve(args))
+	case "validate":
+		os.Exit(validate(args))
+	case "gen-config":
+		os.Exit(genConfig(args))
+	case "encrypt":
+		os.Exit(encrypt(args))
+	case "version":
+		fmt.Println("dbcache", Version)
+	default:
+		fmt.Fprintf(os.Stderr, "未知的子命令: %s, 应为serve,validate,gen-config,encrypt或version\n", command)
+		os.Exit(2)
 	}
-	defer GoodsCache.Close()
-
+}
 
-	//启动rpc
-	// 配置IP地址和端口,在config.conf配置文件中.
-	err = rpcserver.RpcRun()
-	if err!=nil{
-		fmt.Println("RPC service failed",err)
-		return
-	}
+//配置文件的命令行参数,设置为进程默认的配置文件(日志和validate使用).
+type fileFlags struct {
+	configFile string
+	tablesConf string
+}
 
+func (f *fileFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.configFile, "config", conf.CONFIG_FILE, "配置文件(数据库,日志,rpc和grpc)")
+	fs.StringVar(&f.tablesConf, "tables", conf.TABLES_CONF, "缓存表的配置文件")
+}
 
-	//启动Grpc
-	// 配置IP地址和端口,在config.conf配置文件中.
-	err = grpcserver.GrpcRun()
-	if err!=nil{
-		fmt.Println("GRPC service failed",err)
-		return
-	}
+func (f *fileFlags) apply() {
+	conf.CONFIG_FILE = f.configFile
+	conf.TABLES_CONF = f.tablesConf
+}
 
+//启动服务,等待退出信号.返回退出码.
+func serve(args []string) int {
+	files := fileFlags{}
+	opts := server.Options{}
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	files.register(fs)
+	fs.StringVar(&opts.DataDir, "data-dir", "", "数据目录,异步sql文件和日志文件的相对路径相对于此目录")
+	fs.StringVar(&opts.RpcAddr, "rpc-addr", "", "rpc监听地址(ip:port),为空时使用配置文件,off不启动")
+	fs.StringVar(&opts.GrpcAddr, "grpc-addr", "", "grpc监听地址(ip:port),为空时使用配置文件,off不启动")
+	fs.Parse(args)
+	files.apply()
+	opts.ConfigFile, opts.TablesConf = files.configFile, files.tablesConf
+
+	//收到SIGINT(Ctrl+C)或SIGTERM时,ctx被取消,开始优雅关闭.
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
-	// 以下为users表增删改查的样例.
-	//----------------------------------------------------------------------
-	//获取总页数:
-	pageRows:=20  //每页多少行
-	fmt.Printf("users表: 总行数:%d,每页%d行,总页数:%d\n",UsersCache.RowCount,pageRows,UsersCache.GetPageCount(pageRows))
-	fmt.Printf("goods表: 总行数:%d,每页%d行,总页数:%d\n",GoodsCache.RowCount,pageRows,GoodsCache.GetPageCount(pageRows))
+	//初始化日志库
+	logs.InitLogDir(opts.DataDir)
+	defer logs.Close()
 
-	//一. GetRow:根据主键值,取得该行数据
-	fmt.Println("一. GetRow().根据主键,取得该行数据.")
-	pkey := "00YS0SW2N4NT7K8HP13E"
-	result, err := UsersCache.GetRow(pkey)
+	s := server.New(opts)
+	err := s.Start()
 	if err != nil {
-		fmt.Println(err)
+		logs.Fatal("a", "启动服务失败, err: %s", err)
+		return 1
 	}
-	for k, v := range result {
-		fmt.Printf("%s=%s, ", k, v)
+	for _, dbCache := range s.Instance().GetCacheObjs() {
+		logs.Info("a", "缓存表[%s], 总行数: %d", dbCache.Name(), dbCache.GetRowCount())
 	}
-	fmt.Println()
 
-	//将map数据转换为struct.
-	tt:=&user{}
-	err = comm.MapToStruct(result, tt)
-	if err!=nil{
-		fmt.Println(err)
-	}
-    fmt.Println(*tt)
-
-	//二. GetColumn:根据主键,取得某列的数据
-	fmt.Println("二. GetColumn().根据主键,取得某列的数据")
-	v, err := UsersCache.GetColumn("00YS0SW2N4NT7K8HP13E", "name")
-	if err != nil {
-		fmt.Println(err)
+	//等待退出信号
+	<-ctx.Done()
+	stop()
+	fmt.Println("收到退出信号,开始关闭...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), SHUTDOWN_TIMEOUT)
+	defer cancel()
+	if err = s.Shutdown(shutdownCtx); err != nil {
+		return 1
 	}
-	fmt.Println("根据主键，取得某列的数据: ", v)
+	return 0
+}
 
-	//三. DelRow:根据主键,删除该行数据
-	fmt.Printf("三. DelRow().根据主键,删除该行数据\n")
-	pkey = "2222222221"
-	n, err := UsersCache.DelRow(pkey)
-	if err != nil {
-		logs.Info("a", "根据主键,删除行数据失败, err: %s", err)
-	}
-	fmt.Println("删除数据行数:", n)
+//检查config.conf,cache.conf的配置项,及数据库中缓存表的表,列和主键,输出检查报告.返回退出码:有错误时为1.
+func validate(args []string) int {
+	files := fileFlags{}
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	files.register(fs)
+	fs.Parse(args)
+	files.apply()
+
+	r := &conf.Report{}
+	conf.ValidateConfig(r, files.configFile)
+	logs.ValidateConf(r, files.configFile)
+	tables := conf.ValidateTables(r, files.tablesConf)
+	//连接数据库,检查表结构
+	dbConn, err := db.Open(files.configFile)
+	if err != nil {
+		r.Error(files.configFile, "DbConfig", "", "连接数据库失败, err: %s", err)
+	} else {
+		cache.NewInstance(dbConn, files.configFile, files.tablesConf, "").ValidateDB(r, tables)
+		db.Close(dbConn)
+	}
+	fmt.Print(r)
+	if r.HasError() {
+		return 1
+	}
+	return 0
+}
 
-	//四. GetWhere:根据where条件,查询缓存中所有符合条件的行.不用加引号
-	fmt.Println("四. 根据where条件,查询缓存中所有符合条件的行.")
-	var value []map[string]string
-	//value ,_ = dbcache.GetWhere("name=AFA5Y9FB or password=Q80BJT")
-	value, err = UsersCache.GetWhere("address=重庆 and password=888888")
-	if err != nil {
-		fmt.Println(err)
-	}
-	fmt.Printf("查询到符合条件的有%d行:\n", len(value))
-	for _, c := range value {
-		for k, v := range c {
-			fmt.Printf("%s=%s,", k, v)
+//读取数据库中表的结构,生成cache.conf的分段.返回退出码.
+func genConfig(args []string) int {
+	files := fileFlags{}
+	var tableName, name, exclude, cacheType string
+	var write bool
+	fs := flag.NewFlagSet("gen-config", flag.ExitOnError)
+	files.register(fs)
+	fs.StringVar(&tableName, "table", "", "数据库中的表名(必须)")
+	fs.StringVar(&name, "name", "", "分段名(缓存表的名称),为空时是表名")
+	fs.StringVar(&exclude, "exclude", "", "不缓存的列,以逗号隔开")
+	fs.StringVar(&cacheType, "cache-type", "link", "缓存类型:slice,sliceNotDel,link,tree")
+	fs.BoolVar(&write, "write", false, "追加到-tables文件末尾,不输出")
+	fs.Parse(args)
+	files.apply()
+	if tableName == "" {
+		fmt.Fprintln(os.Stderr, "必须指定-table")
+		fs.Usage()
+		return 2
+	}
+
+	dbConn, err := db.Open(conf.CONFIG_FILE)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "连接数据库失败, err:", err)
+		return 1
+	}
+	defer db.Close(dbConn)
+	table, err := cache.GenCacheTable(dbConn, tableName, strings.Split(exclude, ","))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	//与样例配置相同:异步更新,等待返回结果
+	table.CacheType = cacheType
+	table.IsWaitResult = true
+	if write {
+		if err = conf.AddCacheTable(conf.TABLES_CONF, name, table); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
 		}
-		fmt.Println()
-	}
-
-	fmt.Println("切片map转切片struct")
-	ss:=make([]user,len(value))
-	err = comm.SliceMapToStruct(value, ss)
-	if err!=nil{
-		fmt.Println(err)
-	}
-	for _,vv:=range ss{
-		fmt.Println(vv)
-	}
-	//bytes, err = json.Marshal(value)
-	//if err!=nil{
-	//	fmt.Println(err)
-	//}
-	//fmt.Println(string(bytes))
-
-	//五. UpdateColumn:根据主键,更新一列
-	fmt.Println("五. UpdateColumn().根据主键,更新一列数据")
-	pkey = "00YS0SW2N4NT7K8HP13E"
-	col := "name"
-	val := "王明99"
-	n, err = UsersCache.UpdateColumn(pkey, col, val)
-	if err != nil {
-		logs.Error("a", "更新一列错误, err: %s", err)
-	}
-
-	//六. UpdateColumns:根据主键,更新多列
-	fmt.Printf("六. UpdateColumns().根据主键,更新多列数据:\n")
-	pkey = "00YS0SW2N4NT7K8HP13E"
-	cols := "age=111,address= 重庆111,update_date=2020-05-06 07:30:00"
-	n, err = UsersCache.UpdateColumns(pkey, cols)
-	if err != nil {
-		logs.Error("a", "更新多列错误, err:%s", err)
+		fmt.Fprintf(os.Stderr, "已写入%s\n", conf.TABLES_CONF)
+		return 0
 	}
-
-
-	//七. InsertRow():插入一行数据
-	fmt.Printf("七. InsertRow().插入一行数据\n")
-	insert := "uid=22222211115,name=jth,address=重庆,password=888888,age=9999991,price=66.123456,create_date=2020-02-02 02:02:02,update_date=2020-01-01 01:01:01"
-	n, err = UsersCache.InsertRow(insert)
+	section, err := conf.FormatCacheTable(name, table)
 	if err != nil {
-		logs.Error("a", "插入错误, err: %v", err)
-	}
-	fmt.Printf("插入%d行数据\n", n)
-
-	//八,GetRowBetween():从缓存中,获取指定的行,开始行-结束行.用于页面分页显示.
-	fmt.Printf("八,GetRowNum():获取缓存中,start行到end行之间的数据.\n")
-	rows := UsersCache.GetRowBetween(0, 15)
-	for i, rowMap := range rows {
-		fmt.Printf(" 第%d行 ", i)
-		for k, v := range rowMap {
-			fmt.Printf(" %s=%s, ", k, v)
-		}
-		fmt.Println()
-	}
-
-	//九,GetPageCount():获取总页数.用于页面分页显示.
-	fmt.Printf("九,GetPageCount():获取总页数.\n")
-	pageSize:=15  //每页15行
-	page:= UsersCache.GetPageCount(pageSize)
-	fmt.Printf("九,GetPageCount():每页%d行,获取总页数:%d\n",pageSize,page)
-
-	//十,GetMultipageRows():用于分页,根据指定开始页,获取多少页,每页行数.返回多页行数据.
-	//参数说明:startPage,开始页,pageNum多少页,pageSize参数是每页行数大小
-	fmt.Printf("十 , GetMultipageRows():用于分页,根据指定开始页,获取多少页,每页行数大小.返回数据.\n")
-	rows = UsersCache.GetMultipageRows(5, 2,10)
-	for i, rowMap := range rows {
-		fmt.Printf(" 第%d行 ", i)
-		for k, v := range rowMap {
-			fmt.Printf(" %s=%s, ", k, v)
-		}
-		fmt.Println()
-	}
-
-	//十一,GetOnePageRows():用于分页,根据页码和每页行数大小,返回单页行数据.page参数是页码,pageSize参数是每页行数大小
-	//参数说明:page参数是页码,pageSize参数是每页行数大小
-	fmt.Printf("十 , GetOnePageRows():用于分页,根据页码和每页行数大小,返回数据.\n")
-	rows  = UsersCache.GetOnePageRows(5, 10)
-	for i, rowMap := range rows {
-		fmt.Printf(" 第%d行 ", i)
-		for k, v := range rowMap {
-			fmt.Printf(" %s=%s, ", k, v)
-		}
-		fmt.Println()
+		fmt.Fprintln(os.Stderr, err)
+		return 1
 	}
+	fmt.Print(section)
+	return 0
+}
 
-
-	//Goods表操作----------------------------------------------------------
-	fmt.Println("以下是对Goods表操作.")
-	rows = GoodsCache.GetRowBetween(0, 10)
-	for i, rowMap := range rows {
-		fmt.Printf(" 第%d行 ", i)
-		for k, v := range rowMap {
-			fmt.Printf(" %s=%s, ", k, v)
+//加密配置项的值,输出ENC(...).返回退出码.
+func encrypt(args []string) int {
+	var plain string
+	if len(args) > 0 {
+		plain = args[0]
+	} else {
+		fmt.Fprint(os.Stderr, "请输入要加密的值: ")
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			fmt.Fprintln(os.Stderr, "读取标准输入失败, err:", err)
+			return 1
 		}
-		fmt.Println()
+		plain = strings.TrimRight(line, "\r\n")
 	}
-
-	//Goods表操作----------------------------------------------------------
-	fmt.Println()
-	pkey = "50496c578bc54cb49b21513107fcbd10"
-	result, err = GoodsCache.GetRow(pkey)
+	value, err := conf.EncryptValue(plain)
 	if err != nil {
-		fmt.Println(err)
+		fmt.Fprintln(os.Stderr, err)
+		return 1
 	}
-	for k, v := range result {
-		fmt.Printf("%s=%s, ", k, v)
-	}
-
-
-	//测试启动RPC,防止退出
-	wait:=make(chan struct{})
-	<-wait
+	fmt.Println(value)
+	return 0
 }
-
-//func prof() {
-	/*//性能分析
-	//新建性能分析文件
-	f, err := os.Create("./cpu.prof")
-	if err != nil {
-		fmt.Println("create cpu profile file failed, err:",err)
-	}
-	fm, err := os.Create("./mem.prof")
-	if err != nil {
-		fmt.Println("create mem profile file failed, err:",err)
-	}
-	fg, err := os.Create("./goroutine.prof")
-	if err != nil {
-		fmt.Println("create goroutine profile file failed, err:",err)
-	}
-	//获取系统cpu信息
-	err = pprof.StartCPUProfile(f)
-	if err != nil {
-		fmt.Println("StartCPUProfile(), err:",err)
-	}
-	defer pprof.StopCPUProfile()
-	//获取系统mem信息
-	err = pprof.WriteHeapProfile(fm)
-	if err != nil {
-		fmt.Println("WriteHeapProfile(), err:",err)
-	}
-	defer fm.Close()
-	//获取系统goroutine信息
-	lookup := pprof.Lookup("goroutine")
-	if lookup != nil {
-		fmt.Println(`Lookup("goroutine"), err:`,err)
-	}
-	lookup.WriteTo(fg,0)
-	defer fg.Close()*/
-//}
@@ -673,6 +673,7 @@ func (m *InsertRowRequest) GetCondition() string {
 
 type InsertRowResponse struct {
 	Result               int64    `protobuf:"varint,1,opt,name=Result,proto3" json:"Result,omitempty"`
+	LastInsertId         int64    `protobuf:"varint,2,opt,name=LastInsertId,proto3" json:"LastInsertId,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -710,504 +711,2086 @@ func (m *InsertRowResponse) GetResult() int64 {
 	return 0
 }
 
-//--------------GetRowBetween()---------------------------------
-type GetRowBetweenRequest struct {
+func (m *InsertRowResponse) GetLastInsertId() int64 {
+	if m != nil {
+		return m.LastInsertId
+	}
+	return 0
+}
+
+//--------------Upsert()---------------------------------
+type UpsertRequest struct {
 	TableName            string   `protobuf:"bytes,1,opt,name=TableName,proto3" json:"TableName,omitempty"`
-	Start                int64    `protobuf:"varint,2,opt,name=Start,proto3" json:"Start,omitempty"`
-	End                  int64    `protobuf:"varint,3,opt,name=End,proto3" json:"End,omitempty"`
+	Row                  string   `protobuf:"bytes,2,opt,name=Row,proto3" json:"Row,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *GetRowBetweenRequest) Reset()         { *m = GetRowBetweenRequest{} }
-func (m *GetRowBetweenRequest) String() string { return proto.CompactTextString(m) }
-func (*GetRowBetweenRequest) ProtoMessage()    {}
-func (*GetRowBetweenRequest) Descriptor() ([]byte, []int) {
+func (m *UpsertRequest) Reset()         { *m = UpsertRequest{} }
+func (m *UpsertRequest) String() string { return proto.CompactTextString(m) }
+func (*UpsertRequest) ProtoMessage()    {}
+func (*UpsertRequest) Descriptor() ([]byte, []int) {
 	return fileDescriptor_bedfbfc9b54e5600, []int{15}
 }
 
-func (m *GetRowBetweenRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_GetRowBetweenRequest.Unmarshal(m, b)
+func (m *UpsertRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpsertRequest.Unmarshal(m, b)
 }
-func (m *GetRowBetweenRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_GetRowBetweenRequest.Marshal(b, m, deterministic)
+func (m *UpsertRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpsertRequest.Marshal(b, m, deterministic)
 }
-func (m *GetRowBetweenRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_GetRowBetweenRequest.Merge(m, src)
+func (m *UpsertRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpsertRequest.Merge(m, src)
 }
-func (m *GetRowBetweenRequest) XXX_Size() int {
-	return xxx_messageInfo_GetRowBetweenRequest.Size(m)
+func (m *UpsertRequest) XXX_Size() int {
+	return xxx_messageInfo_UpsertRequest.Size(m)
 }
-func (m *GetRowBetweenRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_GetRowBetweenRequest.DiscardUnknown(m)
+func (m *UpsertRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpsertRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_GetRowBetweenRequest proto.InternalMessageInfo
+var xxx_messageInfo_UpsertRequest proto.InternalMessageInfo
 
-func (m *GetRowBetweenRequest) GetTableName() string {
+func (m *UpsertRequest) GetTableName() string {
 	if m != nil {
 		return m.TableName
 	}
 	return ""
 }
 
-func (m *GetRowBetweenRequest) GetStart() int64 {
+func (m *UpsertRequest) GetRow() string {
 	if m != nil {
-		return m.Start
+		return m.Row
 	}
-	return 0
+	return ""
 }
 
-func (m *GetRowBetweenRequest) GetEnd() int64 {
+type UpsertResponse struct {
+	Result               int64    `protobuf:"varint,1,opt,name=Result,proto3" json:"Result,omitempty"`
+	IsInsert             bool     `protobuf:"varint,2,opt,name=IsInsert,proto3" json:"IsInsert,omitempty"`
+	IsKnown              bool     `protobuf:"varint,3,opt,name=IsKnown,proto3" json:"IsKnown,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpsertResponse) Reset()         { *m = UpsertResponse{} }
+func (m *UpsertResponse) String() string { return proto.CompactTextString(m) }
+func (*UpsertResponse) ProtoMessage()    {}
+func (*UpsertResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{16}
+}
+
+func (m *UpsertResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpsertResponse.Unmarshal(m, b)
+}
+func (m *UpsertResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpsertResponse.Marshal(b, m, deterministic)
+}
+func (m *UpsertResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpsertResponse.Merge(m, src)
+}
+func (m *UpsertResponse) XXX_Size() int {
+	return xxx_messageInfo_UpsertResponse.Size(m)
+}
+func (m *UpsertResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpsertResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpsertResponse proto.InternalMessageInfo
+
+func (m *UpsertResponse) GetResult() int64 {
 	if m != nil {
-		return m.End
+		return m.Result
 	}
 	return 0
 }
 
-//服务器端流式 RPC
-type GetRowBetweenResponse struct {
-	Result               *GetRowBetweentream `protobuf:"bytes,1,opt,name=Result,proto3" json:"Result,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
-	XXX_unrecognized     []byte              `json:"-"`
-	XXX_sizecache        int32               `json:"-"`
+func (m *UpsertResponse) GetIsInsert() bool {
+	if m != nil {
+		return m.IsInsert
+	}
+	return false
 }
 
-func (m *GetRowBetweenResponse) Reset()         { *m = GetRowBetweenResponse{} }
-func (m *GetRowBetweenResponse) String() string { return proto.CompactTextString(m) }
-func (*GetRowBetweenResponse) ProtoMessage()    {}
-func (*GetRowBetweenResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_bedfbfc9b54e5600, []int{16}
+func (m *UpsertResponse) GetIsKnown() bool {
+	if m != nil {
+		return m.IsKnown
+	}
+	return false
 }
 
-func (m *GetRowBetweenResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_GetRowBetweenResponse.Unmarshal(m, b)
+//--------------InsertRows()---------------------------------
+//客户端流式 RPC,每条请求是一行数据
+type InsertRowsRequest struct {
+	TableName            string   `protobuf:"bytes,1,opt,name=TableName,proto3" json:"TableName,omitempty"`
+	Row                  string   `protobuf:"bytes,2,opt,name=Row,proto3" json:"Row,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
-func (m *GetRowBetweenResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_GetRowBetweenResponse.Marshal(b, m, deterministic)
+
+func (m *InsertRowsRequest) Reset()         { *m = InsertRowsRequest{} }
+func (m *InsertRowsRequest) String() string { return proto.CompactTextString(m) }
+func (*InsertRowsRequest) ProtoMessage()    {}
+func (*InsertRowsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{17}
 }
-func (m *GetRowBetweenResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_GetRowBetweenResponse.Merge(m, src)
+
+func (m *InsertRowsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InsertRowsRequest.Unmarshal(m, b)
 }
-func (m *GetRowBetweenResponse) XXX_Size() int {
-	return xxx_messageInfo_GetRowBetweenResponse.Size(m)
+func (m *InsertRowsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InsertRowsRequest.Marshal(b, m, deterministic)
 }
-func (m *GetRowBetweenResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_GetRowBetweenResponse.DiscardUnknown(m)
+func (m *InsertRowsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InsertRowsRequest.Merge(m, src)
+}
+func (m *InsertRowsRequest) XXX_Size() int {
+	return xxx_messageInfo_InsertRowsRequest.Size(m)
+}
+func (m *InsertRowsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_InsertRowsRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_GetRowBetweenResponse proto.InternalMessageInfo
+var xxx_messageInfo_InsertRowsRequest proto.InternalMessageInfo
 
-func (m *GetRowBetweenResponse) GetResult() *GetRowBetweentream {
+func (m *InsertRowsRequest) GetTableName() string {
 	if m != nil {
-		return m.Result
+		return m.TableName
 	}
-	return nil
+	return ""
 }
 
-type GetRowBetweentream struct {
-	Result               map[string]string `protobuf:"bytes,1,rep,name=Result,proto3" json:"Result,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
-	XXX_unrecognized     []byte            `json:"-"`
-	XXX_sizecache        int32             `json:"-"`
+func (m *InsertRowsRequest) GetRow() string {
+	if m != nil {
+		return m.Row
+	}
+	return ""
 }
 
-func (m *GetRowBetweentream) Reset()         { *m = GetRowBetweentream{} }
-func (m *GetRowBetweentream) String() string { return proto.CompactTextString(m) }
-func (*GetRowBetweentream) ProtoMessage()    {}
-func (*GetRowBetweentream) Descriptor() ([]byte, []int) {
-	return fileDescriptor_bedfbfc9b54e5600, []int{17}
+type InsertRowsResponse struct {
+	Result               int64    `protobuf:"varint,1,opt,name=Result,proto3" json:"Result,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *GetRowBetweentream) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_GetRowBetweentream.Unmarshal(m, b)
+func (m *InsertRowsResponse) Reset()         { *m = InsertRowsResponse{} }
+func (m *InsertRowsResponse) String() string { return proto.CompactTextString(m) }
+func (*InsertRowsResponse) ProtoMessage()    {}
+func (*InsertRowsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{18}
 }
-func (m *GetRowBetweentream) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_GetRowBetweentream.Marshal(b, m, deterministic)
+
+func (m *InsertRowsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InsertRowsResponse.Unmarshal(m, b)
 }
-func (m *GetRowBetweentream) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_GetRowBetweentream.Merge(m, src)
+func (m *InsertRowsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InsertRowsResponse.Marshal(b, m, deterministic)
 }
-func (m *GetRowBetweentream) XXX_Size() int {
-	return xxx_messageInfo_GetRowBetweentream.Size(m)
+func (m *InsertRowsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InsertRowsResponse.Merge(m, src)
 }
-func (m *GetRowBetweentream) XXX_DiscardUnknown() {
-	xxx_messageInfo_GetRowBetweentream.DiscardUnknown(m)
+func (m *InsertRowsResponse) XXX_Size() int {
+	return xxx_messageInfo_InsertRowsResponse.Size(m)
+}
+func (m *InsertRowsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_InsertRowsResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_GetRowBetweentream proto.InternalMessageInfo
+var xxx_messageInfo_InsertRowsResponse proto.InternalMessageInfo
 
-func (m *GetRowBetweentream) GetResult() map[string]string {
+func (m *InsertRowsResponse) GetResult() int64 {
 	if m != nil {
 		return m.Result
 	}
-	return nil
+	return 0
 }
 
-//--------------GetPageCount()---------------------------------
-type GetPageCountRequest struct {
+//--------------DelRows()---------------------------------
+//客户端流式 RPC,每条请求是一个主键值
+type DelRowsRequest struct {
 	TableName            string   `protobuf:"bytes,1,opt,name=TableName,proto3" json:"TableName,omitempty"`
-	PageSize             int64    `protobuf:"varint,2,opt,name=PageSize,proto3" json:"PageSize,omitempty"`
+	Pkey                 string   `protobuf:"bytes,2,opt,name=Pkey,proto3" json:"Pkey,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *GetPageCountRequest) Reset()         { *m = GetPageCountRequest{} }
-func (m *GetPageCountRequest) String() string { return proto.CompactTextString(m) }
-func (*GetPageCountRequest) ProtoMessage()    {}
-func (*GetPageCountRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_bedfbfc9b54e5600, []int{18}
+func (m *DelRowsRequest) Reset()         { *m = DelRowsRequest{} }
+func (m *DelRowsRequest) String() string { return proto.CompactTextString(m) }
+func (*DelRowsRequest) ProtoMessage()    {}
+func (*DelRowsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{19}
 }
 
-func (m *GetPageCountRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_GetPageCountRequest.Unmarshal(m, b)
+func (m *DelRowsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DelRowsRequest.Unmarshal(m, b)
 }
-func (m *GetPageCountRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_GetPageCountRequest.Marshal(b, m, deterministic)
+func (m *DelRowsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DelRowsRequest.Marshal(b, m, deterministic)
 }
-func (m *GetPageCountRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_GetPageCountRequest.Merge(m, src)
+func (m *DelRowsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DelRowsRequest.Merge(m, src)
 }
-func (m *GetPageCountRequest) XXX_Size() int {
-	return xxx_messageInfo_GetPageCountRequest.Size(m)
+func (m *DelRowsRequest) XXX_Size() int {
+	return xxx_messageInfo_DelRowsRequest.Size(m)
 }
-func (m *GetPageCountRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_GetPageCountRequest.DiscardUnknown(m)
+func (m *DelRowsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DelRowsRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_GetPageCountRequest proto.InternalMessageInfo
+var xxx_messageInfo_DelRowsRequest proto.InternalMessageInfo
 
-func (m *GetPageCountRequest) GetTableName() string {
+func (m *DelRowsRequest) GetTableName() string {
 	if m != nil {
 		return m.TableName
 	}
 	return ""
 }
 
-func (m *GetPageCountRequest) GetPageSize() int64 {
+func (m *DelRowsRequest) GetPkey() string {
 	if m != nil {
-		return m.PageSize
+		return m.Pkey
 	}
-	return 0
+	return ""
 }
 
-type GetPageCountResponse struct {
+type DelRowsResponse struct {
 	Result               int64    `protobuf:"varint,1,opt,name=Result,proto3" json:"Result,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *GetPageCountResponse) Reset()         { *m = GetPageCountResponse{} }
-func (m *GetPageCountResponse) String() string { return proto.CompactTextString(m) }
-func (*GetPageCountResponse) ProtoMessage()    {}
-func (*GetPageCountResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_bedfbfc9b54e5600, []int{19}
+func (m *DelRowsResponse) Reset()         { *m = DelRowsResponse{} }
+func (m *DelRowsResponse) String() string { return proto.CompactTextString(m) }
+func (*DelRowsResponse) ProtoMessage()    {}
+func (*DelRowsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{20}
 }
 
-func (m *GetPageCountResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_GetPageCountResponse.Unmarshal(m, b)
+func (m *DelRowsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DelRowsResponse.Unmarshal(m, b)
 }
-func (m *GetPageCountResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_GetPageCountResponse.Marshal(b, m, deterministic)
+func (m *DelRowsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DelRowsResponse.Marshal(b, m, deterministic)
 }
-func (m *GetPageCountResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_GetPageCountResponse.Merge(m, src)
+func (m *DelRowsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DelRowsResponse.Merge(m, src)
 }
-func (m *GetPageCountResponse) XXX_Size() int {
-	return xxx_messageInfo_GetPageCountResponse.Size(m)
+func (m *DelRowsResponse) XXX_Size() int {
+	return xxx_messageInfo_DelRowsResponse.Size(m)
 }
-func (m *GetPageCountResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_GetPageCountResponse.DiscardUnknown(m)
+func (m *DelRowsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DelRowsResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_GetPageCountResponse proto.InternalMessageInfo
+var xxx_messageInfo_DelRowsResponse proto.InternalMessageInfo
 
-func (m *GetPageCountResponse) GetResult() int64 {
+func (m *DelRowsResponse) GetResult() int64 {
 	if m != nil {
 		return m.Result
 	}
 	return 0
 }
 
-//--------------GetMultipageRows()---------------------------------
-type GetMultipageRowsRequest struct {
+//--------------UpdateWhere()---------------------------------
+//客户端流式 RPC,每条请求是一个where条件及要更新的列
+type UpdateWhereRequest struct {
 	TableName            string   `protobuf:"bytes,1,opt,name=TableName,proto3" json:"TableName,omitempty"`
-	StartPage            int64    `protobuf:"varint,2,opt,name=StartPage,proto3" json:"StartPage,omitempty"`
-	PageNum              int64    `protobuf:"varint,3,opt,name=PageNum,proto3" json:"PageNum,omitempty"`
-	PageSize             int64    `protobuf:"varint,4,opt,name=pageSize,proto3" json:"pageSize,omitempty"`
+	Where                string   `protobuf:"bytes,2,opt,name=Where,proto3" json:"Where,omitempty"`
+	Changes              string   `protobuf:"bytes,3,opt,name=Changes,proto3" json:"Changes,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *GetMultipageRowsRequest) Reset()         { *m = GetMultipageRowsRequest{} }
-func (m *GetMultipageRowsRequest) String() string { return proto.CompactTextString(m) }
-func (*GetMultipageRowsRequest) ProtoMessage()    {}
-func (*GetMultipageRowsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_bedfbfc9b54e5600, []int{20}
+func (m *UpdateWhereRequest) Reset()         { *m = UpdateWhereRequest{} }
+func (m *UpdateWhereRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateWhereRequest) ProtoMessage()    {}
+func (*UpdateWhereRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{21}
 }
 
-func (m *GetMultipageRowsRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_GetMultipageRowsRequest.Unmarshal(m, b)
+func (m *UpdateWhereRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateWhereRequest.Unmarshal(m, b)
 }
-func (m *GetMultipageRowsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_GetMultipageRowsRequest.Marshal(b, m, deterministic)
+func (m *UpdateWhereRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateWhereRequest.Marshal(b, m, deterministic)
 }
-func (m *GetMultipageRowsRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_GetMultipageRowsRequest.Merge(m, src)
+func (m *UpdateWhereRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateWhereRequest.Merge(m, src)
 }
-func (m *GetMultipageRowsRequest) XXX_Size() int {
-	return xxx_messageInfo_GetMultipageRowsRequest.Size(m)
+func (m *UpdateWhereRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdateWhereRequest.Size(m)
 }
-func (m *GetMultipageRowsRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_GetMultipageRowsRequest.DiscardUnknown(m)
+func (m *UpdateWhereRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateWhereRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_GetMultipageRowsRequest proto.InternalMessageInfo
+var xxx_messageInfo_UpdateWhereRequest proto.InternalMessageInfo
 
-func (m *GetMultipageRowsRequest) GetTableName() string {
+func (m *UpdateWhereRequest) GetTableName() string {
 	if m != nil {
 		return m.TableName
 	}
 	return ""
 }
 
-func (m *GetMultipageRowsRequest) GetStartPage() int64 {
-	if m != nil {
-		return m.StartPage
-	}
-	return 0
-}
-
-func (m *GetMultipageRowsRequest) GetPageNum() int64 {
+func (m *UpdateWhereRequest) GetWhere() string {
 	if m != nil {
-		return m.PageNum
+		return m.Where
 	}
-	return 0
+	return ""
 }
 
-func (m *GetMultipageRowsRequest) GetPageSize() int64 {
+func (m *UpdateWhereRequest) GetChanges() string {
 	if m != nil {
-		return m.PageSize
+		return m.Changes
 	}
-	return 0
+	return ""
 }
 
-//服务器端流式 RPC
-type GetMultipageRowsResponse struct {
-	Result               *GetMultipageRowstream `protobuf:"bytes,1,opt,name=Result,proto3" json:"Result,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
-	XXX_unrecognized     []byte                 `json:"-"`
-	XXX_sizecache        int32                  `json:"-"`
+type UpdateWhereResponse struct {
+	Result               int64    `protobuf:"varint,1,opt,name=Result,proto3" json:"Result,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *GetMultipageRowsResponse) Reset()         { *m = GetMultipageRowsResponse{} }
-func (m *GetMultipageRowsResponse) String() string { return proto.CompactTextString(m) }
-func (*GetMultipageRowsResponse) ProtoMessage()    {}
-func (*GetMultipageRowsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_bedfbfc9b54e5600, []int{21}
+func (m *UpdateWhereResponse) Reset()         { *m = UpdateWhereResponse{} }
+func (m *UpdateWhereResponse) String() string { return proto.CompactTextString(m) }
+func (*UpdateWhereResponse) ProtoMessage()    {}
+func (*UpdateWhereResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{22}
 }
 
-func (m *GetMultipageRowsResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_GetMultipageRowsResponse.Unmarshal(m, b)
+func (m *UpdateWhereResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateWhereResponse.Unmarshal(m, b)
 }
-func (m *GetMultipageRowsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_GetMultipageRowsResponse.Marshal(b, m, deterministic)
+func (m *UpdateWhereResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateWhereResponse.Marshal(b, m, deterministic)
 }
-func (m *GetMultipageRowsResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_GetMultipageRowsResponse.Merge(m, src)
+func (m *UpdateWhereResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateWhereResponse.Merge(m, src)
 }
-func (m *GetMultipageRowsResponse) XXX_Size() int {
-	return xxx_messageInfo_GetMultipageRowsResponse.Size(m)
+func (m *UpdateWhereResponse) XXX_Size() int {
+	return xxx_messageInfo_UpdateWhereResponse.Size(m)
 }
-func (m *GetMultipageRowsResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_GetMultipageRowsResponse.DiscardUnknown(m)
+func (m *UpdateWhereResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateWhereResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_GetMultipageRowsResponse proto.InternalMessageInfo
+var xxx_messageInfo_UpdateWhereResponse proto.InternalMessageInfo
 
-func (m *GetMultipageRowsResponse) GetResult() *GetMultipageRowstream {
+func (m *UpdateWhereResponse) GetResult() int64 {
 	if m != nil {
 		return m.Result
 	}
-	return nil
+	return 0
 }
 
-type GetMultipageRowstream struct {
-	Result               map[string]string `protobuf:"bytes,1,rep,name=Result,proto3" json:"Result,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
-	XXX_unrecognized     []byte            `json:"-"`
-	XXX_sizecache        int32             `json:"-"`
+//--------------DeleteWhere()---------------------------------
+//客户端流式 RPC,每条请求是一个where条件
+type DeleteWhereRequest struct {
+	TableName            string   `protobuf:"bytes,1,opt,name=TableName,proto3" json:"TableName,omitempty"`
+	Where                string   `protobuf:"bytes,2,opt,name=Where,proto3" json:"Where,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *GetMultipageRowstream) Reset()         { *m = GetMultipageRowstream{} }
-func (m *GetMultipageRowstream) String() string { return proto.CompactTextString(m) }
-func (*GetMultipageRowstream) ProtoMessage()    {}
-func (*GetMultipageRowstream) Descriptor() ([]byte, []int) {
-	return fileDescriptor_bedfbfc9b54e5600, []int{22}
+func (m *DeleteWhereRequest) Reset()         { *m = DeleteWhereRequest{} }
+func (m *DeleteWhereRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteWhereRequest) ProtoMessage()    {}
+func (*DeleteWhereRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{23}
 }
 
-func (m *GetMultipageRowstream) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_GetMultipageRowstream.Unmarshal(m, b)
+func (m *DeleteWhereRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteWhereRequest.Unmarshal(m, b)
+}
+func (m *DeleteWhereRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteWhereRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteWhereRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteWhereRequest.Merge(m, src)
+}
+func (m *DeleteWhereRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteWhereRequest.Size(m)
+}
+func (m *DeleteWhereRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteWhereRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteWhereRequest proto.InternalMessageInfo
+
+func (m *DeleteWhereRequest) GetTableName() string {
+	if m != nil {
+		return m.TableName
+	}
+	return ""
+}
+
+func (m *DeleteWhereRequest) GetWhere() string {
+	if m != nil {
+		return m.Where
+	}
+	return ""
+}
+
+type DeleteWhereResponse struct {
+	Result               int64    `protobuf:"varint,1,opt,name=Result,proto3" json:"Result,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteWhereResponse) Reset()         { *m = DeleteWhereResponse{} }
+func (m *DeleteWhereResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteWhereResponse) ProtoMessage()    {}
+func (*DeleteWhereResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{24}
+}
+
+func (m *DeleteWhereResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteWhereResponse.Unmarshal(m, b)
+}
+func (m *DeleteWhereResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteWhereResponse.Marshal(b, m, deterministic)
+}
+func (m *DeleteWhereResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteWhereResponse.Merge(m, src)
+}
+func (m *DeleteWhereResponse) XXX_Size() int {
+	return xxx_messageInfo_DeleteWhereResponse.Size(m)
+}
+func (m *DeleteWhereResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteWhereResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteWhereResponse proto.InternalMessageInfo
+
+func (m *DeleteWhereResponse) GetResult() int64 {
+	if m != nil {
+		return m.Result
+	}
+	return 0
+}
+
+//--------------GetRowBetween()---------------------------------
+type GetRowBetweenRequest struct {
+	TableName            string   `protobuf:"bytes,1,opt,name=TableName,proto3" json:"TableName,omitempty"`
+	Start                int64    `protobuf:"varint,2,opt,name=Start,proto3" json:"Start,omitempty"`
+	End                  int64    `protobuf:"varint,3,opt,name=End,proto3" json:"End,omitempty"`
+	View                 string   `protobuf:"bytes,4,opt,name=View,proto3" json:"View,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetRowBetweenRequest) Reset()         { *m = GetRowBetweenRequest{} }
+func (m *GetRowBetweenRequest) String() string { return proto.CompactTextString(m) }
+func (*GetRowBetweenRequest) ProtoMessage()    {}
+func (*GetRowBetweenRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{25}
+}
+
+func (m *GetRowBetweenRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetRowBetweenRequest.Unmarshal(m, b)
+}
+func (m *GetRowBetweenRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetRowBetweenRequest.Marshal(b, m, deterministic)
+}
+func (m *GetRowBetweenRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetRowBetweenRequest.Merge(m, src)
+}
+func (m *GetRowBetweenRequest) XXX_Size() int {
+	return xxx_messageInfo_GetRowBetweenRequest.Size(m)
+}
+func (m *GetRowBetweenRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetRowBetweenRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetRowBetweenRequest proto.InternalMessageInfo
+
+func (m *GetRowBetweenRequest) GetTableName() string {
+	if m != nil {
+		return m.TableName
+	}
+	return ""
+}
+
+func (m *GetRowBetweenRequest) GetStart() int64 {
+	if m != nil {
+		return m.Start
+	}
+	return 0
+}
+
+func (m *GetRowBetweenRequest) GetEnd() int64 {
+	if m != nil {
+		return m.End
+	}
+	return 0
+}
+
+func (m *GetRowBetweenRequest) GetView() string {
+	if m != nil {
+		return m.View
+	}
+	return ""
+}
+
+//服务器端流式 RPC
+type GetRowBetweenResponse struct {
+	Result               *GetRowBetweentream `protobuf:"bytes,1,opt,name=Result,proto3" json:"Result,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *GetRowBetweenResponse) Reset()         { *m = GetRowBetweenResponse{} }
+func (m *GetRowBetweenResponse) String() string { return proto.CompactTextString(m) }
+func (*GetRowBetweenResponse) ProtoMessage()    {}
+func (*GetRowBetweenResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{26}
+}
+
+func (m *GetRowBetweenResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetRowBetweenResponse.Unmarshal(m, b)
+}
+func (m *GetRowBetweenResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetRowBetweenResponse.Marshal(b, m, deterministic)
+}
+func (m *GetRowBetweenResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetRowBetweenResponse.Merge(m, src)
+}
+func (m *GetRowBetweenResponse) XXX_Size() int {
+	return xxx_messageInfo_GetRowBetweenResponse.Size(m)
+}
+func (m *GetRowBetweenResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetRowBetweenResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetRowBetweenResponse proto.InternalMessageInfo
+
+func (m *GetRowBetweenResponse) GetResult() *GetRowBetweentream {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+type GetRowBetweentream struct {
+	Result               map[string]string `protobuf:"bytes,1,rep,name=Result,proto3" json:"Result,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *GetRowBetweentream) Reset()         { *m = GetRowBetweentream{} }
+func (m *GetRowBetweentream) String() string { return proto.CompactTextString(m) }
+func (*GetRowBetweentream) ProtoMessage()    {}
+func (*GetRowBetweentream) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{27}
+}
+
+func (m *GetRowBetweentream) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetRowBetweentream.Unmarshal(m, b)
+}
+func (m *GetRowBetweentream) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetRowBetweentream.Marshal(b, m, deterministic)
+}
+func (m *GetRowBetweentream) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetRowBetweentream.Merge(m, src)
+}
+func (m *GetRowBetweentream) XXX_Size() int {
+	return xxx_messageInfo_GetRowBetweentream.Size(m)
+}
+func (m *GetRowBetweentream) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetRowBetweentream.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetRowBetweentream proto.InternalMessageInfo
+
+func (m *GetRowBetweentream) GetResult() map[string]string {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+//--------------GetPageCount()---------------------------------
+type GetPageCountRequest struct {
+	TableName            string   `protobuf:"bytes,1,opt,name=TableName,proto3" json:"TableName,omitempty"`
+	PageSize             int64    `protobuf:"varint,2,opt,name=PageSize,proto3" json:"PageSize,omitempty"`
+	View                 string   `protobuf:"bytes,3,opt,name=View,proto3" json:"View,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetPageCountRequest) Reset()         { *m = GetPageCountRequest{} }
+func (m *GetPageCountRequest) String() string { return proto.CompactTextString(m) }
+func (*GetPageCountRequest) ProtoMessage()    {}
+func (*GetPageCountRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{28}
+}
+
+func (m *GetPageCountRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetPageCountRequest.Unmarshal(m, b)
+}
+func (m *GetPageCountRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetPageCountRequest.Marshal(b, m, deterministic)
+}
+func (m *GetPageCountRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetPageCountRequest.Merge(m, src)
+}
+func (m *GetPageCountRequest) XXX_Size() int {
+	return xxx_messageInfo_GetPageCountRequest.Size(m)
+}
+func (m *GetPageCountRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetPageCountRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetPageCountRequest proto.InternalMessageInfo
+
+func (m *GetPageCountRequest) GetTableName() string {
+	if m != nil {
+		return m.TableName
+	}
+	return ""
+}
+
+func (m *GetPageCountRequest) GetPageSize() int64 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *GetPageCountRequest) GetView() string {
+	if m != nil {
+		return m.View
+	}
+	return ""
+}
+
+type GetPageCountResponse struct {
+	Result               int64    `protobuf:"varint,1,opt,name=Result,proto3" json:"Result,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetPageCountResponse) Reset()         { *m = GetPageCountResponse{} }
+func (m *GetPageCountResponse) String() string { return proto.CompactTextString(m) }
+func (*GetPageCountResponse) ProtoMessage()    {}
+func (*GetPageCountResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{29}
+}
+
+func (m *GetPageCountResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetPageCountResponse.Unmarshal(m, b)
+}
+func (m *GetPageCountResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetPageCountResponse.Marshal(b, m, deterministic)
+}
+func (m *GetPageCountResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetPageCountResponse.Merge(m, src)
+}
+func (m *GetPageCountResponse) XXX_Size() int {
+	return xxx_messageInfo_GetPageCountResponse.Size(m)
+}
+func (m *GetPageCountResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetPageCountResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetPageCountResponse proto.InternalMessageInfo
+
+func (m *GetPageCountResponse) GetResult() int64 {
+	if m != nil {
+		return m.Result
+	}
+	return 0
+}
+
+//--------------GetMultipageRows()---------------------------------
+type GetMultipageRowsRequest struct {
+	TableName            string   `protobuf:"bytes,1,opt,name=TableName,proto3" json:"TableName,omitempty"`
+	StartPage            int64    `protobuf:"varint,2,opt,name=StartPage,proto3" json:"StartPage,omitempty"`
+	PageNum              int64    `protobuf:"varint,3,opt,name=PageNum,proto3" json:"PageNum,omitempty"`
+	PageSize             int64    `protobuf:"varint,4,opt,name=pageSize,proto3" json:"pageSize,omitempty"`
+	View                 string   `protobuf:"bytes,5,opt,name=View,proto3" json:"View,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetMultipageRowsRequest) Reset()         { *m = GetMultipageRowsRequest{} }
+func (m *GetMultipageRowsRequest) String() string { return proto.CompactTextString(m) }
+func (*GetMultipageRowsRequest) ProtoMessage()    {}
+func (*GetMultipageRowsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{30}
+}
+
+func (m *GetMultipageRowsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetMultipageRowsRequest.Unmarshal(m, b)
+}
+func (m *GetMultipageRowsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetMultipageRowsRequest.Marshal(b, m, deterministic)
+}
+func (m *GetMultipageRowsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetMultipageRowsRequest.Merge(m, src)
+}
+func (m *GetMultipageRowsRequest) XXX_Size() int {
+	return xxx_messageInfo_GetMultipageRowsRequest.Size(m)
+}
+func (m *GetMultipageRowsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetMultipageRowsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetMultipageRowsRequest proto.InternalMessageInfo
+
+func (m *GetMultipageRowsRequest) GetTableName() string {
+	if m != nil {
+		return m.TableName
+	}
+	return ""
+}
+
+func (m *GetMultipageRowsRequest) GetStartPage() int64 {
+	if m != nil {
+		return m.StartPage
+	}
+	return 0
+}
+
+func (m *GetMultipageRowsRequest) GetPageNum() int64 {
+	if m != nil {
+		return m.PageNum
+	}
+	return 0
+}
+
+func (m *GetMultipageRowsRequest) GetPageSize() int64 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *GetMultipageRowsRequest) GetView() string {
+	if m != nil {
+		return m.View
+	}
+	return ""
+}
+
+//服务器端流式 RPC
+type GetMultipageRowsResponse struct {
+	Result               *GetMultipageRowstream `protobuf:"bytes,1,opt,name=Result,proto3" json:"Result,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *GetMultipageRowsResponse) Reset()         { *m = GetMultipageRowsResponse{} }
+func (m *GetMultipageRowsResponse) String() string { return proto.CompactTextString(m) }
+func (*GetMultipageRowsResponse) ProtoMessage()    {}
+func (*GetMultipageRowsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{31}
+}
+
+func (m *GetMultipageRowsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetMultipageRowsResponse.Unmarshal(m, b)
+}
+func (m *GetMultipageRowsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetMultipageRowsResponse.Marshal(b, m, deterministic)
+}
+func (m *GetMultipageRowsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetMultipageRowsResponse.Merge(m, src)
+}
+func (m *GetMultipageRowsResponse) XXX_Size() int {
+	return xxx_messageInfo_GetMultipageRowsResponse.Size(m)
+}
+func (m *GetMultipageRowsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetMultipageRowsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetMultipageRowsResponse proto.InternalMessageInfo
+
+func (m *GetMultipageRowsResponse) GetResult() *GetMultipageRowstream {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+type GetMultipageRowstream struct {
+	Result               map[string]string `protobuf:"bytes,1,rep,name=Result,proto3" json:"Result,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *GetMultipageRowstream) Reset()         { *m = GetMultipageRowstream{} }
+func (m *GetMultipageRowstream) String() string { return proto.CompactTextString(m) }
+func (*GetMultipageRowstream) ProtoMessage()    {}
+func (*GetMultipageRowstream) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{32}
+}
+
+func (m *GetMultipageRowstream) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetMultipageRowstream.Unmarshal(m, b)
 }
 func (m *GetMultipageRowstream) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_GetMultipageRowstream.Marshal(b, m, deterministic)
 }
-func (m *GetMultipageRowstream) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_GetMultipageRowstream.Merge(m, src)
+func (m *GetMultipageRowstream) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetMultipageRowstream.Merge(m, src)
+}
+func (m *GetMultipageRowstream) XXX_Size() int {
+	return xxx_messageInfo_GetMultipageRowstream.Size(m)
+}
+func (m *GetMultipageRowstream) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetMultipageRowstream.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetMultipageRowstream proto.InternalMessageInfo
+
+func (m *GetMultipageRowstream) GetResult() map[string]string {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+//--------------GetOnePageRows()---------------------------------
+type GetOnePageRowsRequest struct {
+	TableName            string   `protobuf:"bytes,1,opt,name=TableName,proto3" json:"TableName,omitempty"`
+	Page                 int64    `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize             int64    `protobuf:"varint,3,opt,name=pageSize,proto3" json:"pageSize,omitempty"`
+	View                 string   `protobuf:"bytes,4,opt,name=View,proto3" json:"View,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetOnePageRowsRequest) Reset()         { *m = GetOnePageRowsRequest{} }
+func (m *GetOnePageRowsRequest) String() string { return proto.CompactTextString(m) }
+func (*GetOnePageRowsRequest) ProtoMessage()    {}
+func (*GetOnePageRowsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{33}
+}
+
+func (m *GetOnePageRowsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetOnePageRowsRequest.Unmarshal(m, b)
+}
+func (m *GetOnePageRowsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetOnePageRowsRequest.Marshal(b, m, deterministic)
+}
+func (m *GetOnePageRowsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetOnePageRowsRequest.Merge(m, src)
+}
+func (m *GetOnePageRowsRequest) XXX_Size() int {
+	return xxx_messageInfo_GetOnePageRowsRequest.Size(m)
+}
+func (m *GetOnePageRowsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetOnePageRowsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetOnePageRowsRequest proto.InternalMessageInfo
+
+func (m *GetOnePageRowsRequest) GetTableName() string {
+	if m != nil {
+		return m.TableName
+	}
+	return ""
+}
+
+func (m *GetOnePageRowsRequest) GetPage() int64 {
+	if m != nil {
+		return m.Page
+	}
+	return 0
+}
+
+func (m *GetOnePageRowsRequest) GetPageSize() int64 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *GetOnePageRowsRequest) GetView() string {
+	if m != nil {
+		return m.View
+	}
+	return ""
+}
+
+//服务器端流式 RPC
+type GetOnePageRowsResponse struct {
+	Result               *GetOnePageRowstream `protobuf:"bytes,1,opt,name=Result,proto3" json:"Result,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *GetOnePageRowsResponse) Reset()         { *m = GetOnePageRowsResponse{} }
+func (m *GetOnePageRowsResponse) String() string { return proto.CompactTextString(m) }
+func (*GetOnePageRowsResponse) ProtoMessage()    {}
+func (*GetOnePageRowsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{34}
+}
+
+func (m *GetOnePageRowsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetOnePageRowsResponse.Unmarshal(m, b)
+}
+func (m *GetOnePageRowsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetOnePageRowsResponse.Marshal(b, m, deterministic)
+}
+func (m *GetOnePageRowsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetOnePageRowsResponse.Merge(m, src)
+}
+func (m *GetOnePageRowsResponse) XXX_Size() int {
+	return xxx_messageInfo_GetOnePageRowsResponse.Size(m)
+}
+func (m *GetOnePageRowsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetOnePageRowsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetOnePageRowsResponse proto.InternalMessageInfo
+
+func (m *GetOnePageRowsResponse) GetResult() *GetOnePageRowstream {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+type GetOnePageRowstream struct {
+	Result               map[string]string `protobuf:"bytes,1,rep,name=Result,proto3" json:"Result,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *GetOnePageRowstream) Reset()         { *m = GetOnePageRowstream{} }
+func (m *GetOnePageRowstream) String() string { return proto.CompactTextString(m) }
+func (*GetOnePageRowstream) ProtoMessage()    {}
+func (*GetOnePageRowstream) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{35}
+}
+
+func (m *GetOnePageRowstream) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetOnePageRowstream.Unmarshal(m, b)
+}
+func (m *GetOnePageRowstream) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetOnePageRowstream.Marshal(b, m, deterministic)
+}
+func (m *GetOnePageRowstream) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetOnePageRowstream.Merge(m, src)
+}
+func (m *GetOnePageRowstream) XXX_Size() int {
+	return xxx_messageInfo_GetOnePageRowstream.Size(m)
+}
+func (m *GetOnePageRowstream) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetOnePageRowstream.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetOnePageRowstream proto.InternalMessageInfo
+
+func (m *GetOnePageRowstream) GetResult() map[string]string {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+//--------------GetWhereRowBetween()---------------------------------
+type GetWhereRowBetweenRequest struct {
+	TableName            string   `protobuf:"bytes,1,opt,name=TableName,proto3" json:"TableName,omitempty"`
+	Where                string   `protobuf:"bytes,2,opt,name=Where,proto3" json:"Where,omitempty"`
+	Start                int64    `protobuf:"varint,3,opt,name=Start,proto3" json:"Start,omitempty"`
+	End                  int64    `protobuf:"varint,4,opt,name=End,proto3" json:"End,omitempty"`
+	View                 string   `protobuf:"bytes,5,opt,name=View,proto3" json:"View,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetWhereRowBetweenRequest) Reset()         { *m = GetWhereRowBetweenRequest{} }
+func (m *GetWhereRowBetweenRequest) String() string { return proto.CompactTextString(m) }
+func (*GetWhereRowBetweenRequest) ProtoMessage()    {}
+func (*GetWhereRowBetweenRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{36}
+}
+
+func (m *GetWhereRowBetweenRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetWhereRowBetweenRequest.Unmarshal(m, b)
+}
+func (m *GetWhereRowBetweenRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetWhereRowBetweenRequest.Marshal(b, m, deterministic)
+}
+func (m *GetWhereRowBetweenRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetWhereRowBetweenRequest.Merge(m, src)
+}
+func (m *GetWhereRowBetweenRequest) XXX_Size() int {
+	return xxx_messageInfo_GetWhereRowBetweenRequest.Size(m)
+}
+func (m *GetWhereRowBetweenRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetWhereRowBetweenRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetWhereRowBetweenRequest proto.InternalMessageInfo
+
+func (m *GetWhereRowBetweenRequest) GetTableName() string {
+	if m != nil {
+		return m.TableName
+	}
+	return ""
+}
+
+func (m *GetWhereRowBetweenRequest) GetWhere() string {
+	if m != nil {
+		return m.Where
+	}
+	return ""
+}
+
+func (m *GetWhereRowBetweenRequest) GetStart() int64 {
+	if m != nil {
+		return m.Start
+	}
+	return 0
+}
+
+func (m *GetWhereRowBetweenRequest) GetEnd() int64 {
+	if m != nil {
+		return m.End
+	}
+	return 0
+}
+
+func (m *GetWhereRowBetweenRequest) GetView() string {
+	if m != nil {
+		return m.View
+	}
+	return ""
+}
+
+type GetWhereRowBetweenResponse struct {
+	Result               []*GetWhereStream `protobuf:"bytes,1,rep,name=Result,proto3" json:"Result,omitempty"`
+	Total                int64             `protobuf:"varint,2,opt,name=Total,proto3" json:"Total,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *GetWhereRowBetweenResponse) Reset()         { *m = GetWhereRowBetweenResponse{} }
+func (m *GetWhereRowBetweenResponse) String() string { return proto.CompactTextString(m) }
+func (*GetWhereRowBetweenResponse) ProtoMessage()    {}
+func (*GetWhereRowBetweenResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{37}
+}
+
+func (m *GetWhereRowBetweenResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetWhereRowBetweenResponse.Unmarshal(m, b)
+}
+func (m *GetWhereRowBetweenResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetWhereRowBetweenResponse.Marshal(b, m, deterministic)
+}
+func (m *GetWhereRowBetweenResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetWhereRowBetweenResponse.Merge(m, src)
+}
+func (m *GetWhereRowBetweenResponse) XXX_Size() int {
+	return xxx_messageInfo_GetWhereRowBetweenResponse.Size(m)
+}
+func (m *GetWhereRowBetweenResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetWhereRowBetweenResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetWhereRowBetweenResponse proto.InternalMessageInfo
+
+func (m *GetWhereRowBetweenResponse) GetResult() []*GetWhereStream {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+func (m *GetWhereRowBetweenResponse) GetTotal() int64 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
+//--------------GetWherePageCount()---------------------------------
+type GetWherePageCountRequest struct {
+	TableName            string   `protobuf:"bytes,1,opt,name=TableName,proto3" json:"TableName,omitempty"`
+	Where                string   `protobuf:"bytes,2,opt,name=Where,proto3" json:"Where,omitempty"`
+	PageSize             int64    `protobuf:"varint,3,opt,name=PageSize,proto3" json:"PageSize,omitempty"`
+	View                 string   `protobuf:"bytes,4,opt,name=View,proto3" json:"View,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetWherePageCountRequest) Reset()         { *m = GetWherePageCountRequest{} }
+func (m *GetWherePageCountRequest) String() string { return proto.CompactTextString(m) }
+func (*GetWherePageCountRequest) ProtoMessage()    {}
+func (*GetWherePageCountRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{38}
+}
+
+func (m *GetWherePageCountRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetWherePageCountRequest.Unmarshal(m, b)
+}
+func (m *GetWherePageCountRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetWherePageCountRequest.Marshal(b, m, deterministic)
+}
+func (m *GetWherePageCountRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetWherePageCountRequest.Merge(m, src)
+}
+func (m *GetWherePageCountRequest) XXX_Size() int {
+	return xxx_messageInfo_GetWherePageCountRequest.Size(m)
+}
+func (m *GetWherePageCountRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetWherePageCountRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetWherePageCountRequest proto.InternalMessageInfo
+
+func (m *GetWherePageCountRequest) GetTableName() string {
+	if m != nil {
+		return m.TableName
+	}
+	return ""
+}
+
+func (m *GetWherePageCountRequest) GetWhere() string {
+	if m != nil {
+		return m.Where
+	}
+	return ""
+}
+
+func (m *GetWherePageCountRequest) GetPageSize() int64 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *GetWherePageCountRequest) GetView() string {
+	if m != nil {
+		return m.View
+	}
+	return ""
+}
+
+type GetWherePageCountResponse struct {
+	Result               int64    `protobuf:"varint,1,opt,name=Result,proto3" json:"Result,omitempty"`
+	Total                int64    `protobuf:"varint,2,opt,name=Total,proto3" json:"Total,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetWherePageCountResponse) Reset()         { *m = GetWherePageCountResponse{} }
+func (m *GetWherePageCountResponse) String() string { return proto.CompactTextString(m) }
+func (*GetWherePageCountResponse) ProtoMessage()    {}
+func (*GetWherePageCountResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{39}
+}
+
+func (m *GetWherePageCountResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetWherePageCountResponse.Unmarshal(m, b)
+}
+func (m *GetWherePageCountResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetWherePageCountResponse.Marshal(b, m, deterministic)
+}
+func (m *GetWherePageCountResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetWherePageCountResponse.Merge(m, src)
+}
+func (m *GetWherePageCountResponse) XXX_Size() int {
+	return xxx_messageInfo_GetWherePageCountResponse.Size(m)
+}
+func (m *GetWherePageCountResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetWherePageCountResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetWherePageCountResponse proto.InternalMessageInfo
+
+func (m *GetWherePageCountResponse) GetResult() int64 {
+	if m != nil {
+		return m.Result
+	}
+	return 0
+}
+
+func (m *GetWherePageCountResponse) GetTotal() int64 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
+//--------------GetWhereMultipageRows()---------------------------------
+type GetWhereMultipageRowsRequest struct {
+	TableName            string   `protobuf:"bytes,1,opt,name=TableName,proto3" json:"TableName,omitempty"`
+	Where                string   `protobuf:"bytes,2,opt,name=Where,proto3" json:"Where,omitempty"`
+	StartPage            int64    `protobuf:"varint,3,opt,name=StartPage,proto3" json:"StartPage,omitempty"`
+	PageNum              int64    `protobuf:"varint,4,opt,name=PageNum,proto3" json:"PageNum,omitempty"`
+	PageSize             int64    `protobuf:"varint,5,opt,name=PageSize,proto3" json:"PageSize,omitempty"`
+	View                 string   `protobuf:"bytes,6,opt,name=View,proto3" json:"View,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetWhereMultipageRowsRequest) Reset()         { *m = GetWhereMultipageRowsRequest{} }
+func (m *GetWhereMultipageRowsRequest) String() string { return proto.CompactTextString(m) }
+func (*GetWhereMultipageRowsRequest) ProtoMessage()    {}
+func (*GetWhereMultipageRowsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{40}
+}
+
+func (m *GetWhereMultipageRowsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetWhereMultipageRowsRequest.Unmarshal(m, b)
+}
+func (m *GetWhereMultipageRowsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetWhereMultipageRowsRequest.Marshal(b, m, deterministic)
+}
+func (m *GetWhereMultipageRowsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetWhereMultipageRowsRequest.Merge(m, src)
+}
+func (m *GetWhereMultipageRowsRequest) XXX_Size() int {
+	return xxx_messageInfo_GetWhereMultipageRowsRequest.Size(m)
+}
+func (m *GetWhereMultipageRowsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetWhereMultipageRowsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetWhereMultipageRowsRequest proto.InternalMessageInfo
+
+func (m *GetWhereMultipageRowsRequest) GetTableName() string {
+	if m != nil {
+		return m.TableName
+	}
+	return ""
+}
+
+func (m *GetWhereMultipageRowsRequest) GetWhere() string {
+	if m != nil {
+		return m.Where
+	}
+	return ""
+}
+
+func (m *GetWhereMultipageRowsRequest) GetStartPage() int64 {
+	if m != nil {
+		return m.StartPage
+	}
+	return 0
+}
+
+func (m *GetWhereMultipageRowsRequest) GetPageNum() int64 {
+	if m != nil {
+		return m.PageNum
+	}
+	return 0
+}
+
+func (m *GetWhereMultipageRowsRequest) GetPageSize() int64 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *GetWhereMultipageRowsRequest) GetView() string {
+	if m != nil {
+		return m.View
+	}
+	return ""
+}
+
+type GetWhereMultipageRowsResponse struct {
+	Result               []*GetWhereStream `protobuf:"bytes,1,rep,name=Result,proto3" json:"Result,omitempty"`
+	Total                int64             `protobuf:"varint,2,opt,name=Total,proto3" json:"Total,omitempty"`
+	PageCount            int64             `protobuf:"varint,3,opt,name=PageCount,proto3" json:"PageCount,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *GetWhereMultipageRowsResponse) Reset()         { *m = GetWhereMultipageRowsResponse{} }
+func (m *GetWhereMultipageRowsResponse) String() string { return proto.CompactTextString(m) }
+func (*GetWhereMultipageRowsResponse) ProtoMessage()    {}
+func (*GetWhereMultipageRowsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{41}
+}
+
+func (m *GetWhereMultipageRowsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetWhereMultipageRowsResponse.Unmarshal(m, b)
+}
+func (m *GetWhereMultipageRowsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetWhereMultipageRowsResponse.Marshal(b, m, deterministic)
+}
+func (m *GetWhereMultipageRowsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetWhereMultipageRowsResponse.Merge(m, src)
+}
+func (m *GetWhereMultipageRowsResponse) XXX_Size() int {
+	return xxx_messageInfo_GetWhereMultipageRowsResponse.Size(m)
+}
+func (m *GetWhereMultipageRowsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetWhereMultipageRowsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetWhereMultipageRowsResponse proto.InternalMessageInfo
+
+func (m *GetWhereMultipageRowsResponse) GetResult() []*GetWhereStream {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+func (m *GetWhereMultipageRowsResponse) GetTotal() int64 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
+func (m *GetWhereMultipageRowsResponse) GetPageCount() int64 {
+	if m != nil {
+		return m.PageCount
+	}
+	return 0
+}
+
+//--------------GetWhereOnePageRows()---------------------------------
+type GetWhereOnePageRowsRequest struct {
+	TableName            string   `protobuf:"bytes,1,opt,name=TableName,proto3" json:"TableName,omitempty"`
+	Where                string   `protobuf:"bytes,2,opt,name=Where,proto3" json:"Where,omitempty"`
+	Page                 int64    `protobuf:"varint,3,opt,name=Page,proto3" json:"Page,omitempty"`
+	PageSize             int64    `protobuf:"varint,4,opt,name=PageSize,proto3" json:"PageSize,omitempty"`
+	View                 string   `protobuf:"bytes,5,opt,name=View,proto3" json:"View,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetWhereOnePageRowsRequest) Reset()         { *m = GetWhereOnePageRowsRequest{} }
+func (m *GetWhereOnePageRowsRequest) String() string { return proto.CompactTextString(m) }
+func (*GetWhereOnePageRowsRequest) ProtoMessage()    {}
+func (*GetWhereOnePageRowsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{42}
+}
+
+func (m *GetWhereOnePageRowsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetWhereOnePageRowsRequest.Unmarshal(m, b)
+}
+func (m *GetWhereOnePageRowsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetWhereOnePageRowsRequest.Marshal(b, m, deterministic)
+}
+func (m *GetWhereOnePageRowsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetWhereOnePageRowsRequest.Merge(m, src)
+}
+func (m *GetWhereOnePageRowsRequest) XXX_Size() int {
+	return xxx_messageInfo_GetWhereOnePageRowsRequest.Size(m)
+}
+func (m *GetWhereOnePageRowsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetWhereOnePageRowsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetWhereOnePageRowsRequest proto.InternalMessageInfo
+
+func (m *GetWhereOnePageRowsRequest) GetTableName() string {
+	if m != nil {
+		return m.TableName
+	}
+	return ""
+}
+
+func (m *GetWhereOnePageRowsRequest) GetWhere() string {
+	if m != nil {
+		return m.Where
+	}
+	return ""
+}
+
+func (m *GetWhereOnePageRowsRequest) GetPage() int64 {
+	if m != nil {
+		return m.Page
+	}
+	return 0
+}
+
+func (m *GetWhereOnePageRowsRequest) GetPageSize() int64 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *GetWhereOnePageRowsRequest) GetView() string {
+	if m != nil {
+		return m.View
+	}
+	return ""
+}
+
+type GetWhereOnePageRowsResponse struct {
+	Result               []*GetWhereStream `protobuf:"bytes,1,rep,name=Result,proto3" json:"Result,omitempty"`
+	Total                int64             `protobuf:"varint,2,opt,name=Total,proto3" json:"Total,omitempty"`
+	PageCount            int64             `protobuf:"varint,3,opt,name=PageCount,proto3" json:"PageCount,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *GetWhereOnePageRowsResponse) Reset()         { *m = GetWhereOnePageRowsResponse{} }
+func (m *GetWhereOnePageRowsResponse) String() string { return proto.CompactTextString(m) }
+func (*GetWhereOnePageRowsResponse) ProtoMessage()    {}
+func (*GetWhereOnePageRowsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{43}
+}
+
+func (m *GetWhereOnePageRowsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetWhereOnePageRowsResponse.Unmarshal(m, b)
+}
+func (m *GetWhereOnePageRowsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetWhereOnePageRowsResponse.Marshal(b, m, deterministic)
+}
+func (m *GetWhereOnePageRowsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetWhereOnePageRowsResponse.Merge(m, src)
+}
+func (m *GetWhereOnePageRowsResponse) XXX_Size() int {
+	return xxx_messageInfo_GetWhereOnePageRowsResponse.Size(m)
 }
-func (m *GetMultipageRowstream) XXX_Size() int {
-	return xxx_messageInfo_GetMultipageRowstream.Size(m)
+func (m *GetWhereOnePageRowsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetWhereOnePageRowsResponse.DiscardUnknown(m)
 }
-func (m *GetMultipageRowstream) XXX_DiscardUnknown() {
-	xxx_messageInfo_GetMultipageRowstream.DiscardUnknown(m)
+
+var xxx_messageInfo_GetWhereOnePageRowsResponse proto.InternalMessageInfo
+
+func (m *GetWhereOnePageRowsResponse) GetResult() []*GetWhereStream {
+	if m != nil {
+		return m.Result
+	}
+	return nil
 }
 
-var xxx_messageInfo_GetMultipageRowstream proto.InternalMessageInfo
+func (m *GetWhereOnePageRowsResponse) GetTotal() int64 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
 
-func (m *GetMultipageRowstream) GetResult() map[string]string {
+func (m *GetWhereOnePageRowsResponse) GetPageCount() int64 {
+	if m != nil {
+		return m.PageCount
+	}
+	return 0
+}
+
+//--------------GetPageAfter()---------------------------------
+type GetPageAfterRequest struct {
+	TableName            string   `protobuf:"bytes,1,opt,name=TableName,proto3" json:"TableName,omitempty"`
+	Cursor               string   `protobuf:"bytes,2,opt,name=Cursor,proto3" json:"Cursor,omitempty"`
+	PageSize             int64    `protobuf:"varint,3,opt,name=PageSize,proto3" json:"PageSize,omitempty"`
+	View                 string   `protobuf:"bytes,4,opt,name=View,proto3" json:"View,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetPageAfterRequest) Reset()         { *m = GetPageAfterRequest{} }
+func (m *GetPageAfterRequest) String() string { return proto.CompactTextString(m) }
+func (*GetPageAfterRequest) ProtoMessage()    {}
+func (*GetPageAfterRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{44}
+}
+
+func (m *GetPageAfterRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetPageAfterRequest.Unmarshal(m, b)
+}
+func (m *GetPageAfterRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetPageAfterRequest.Marshal(b, m, deterministic)
+}
+func (m *GetPageAfterRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetPageAfterRequest.Merge(m, src)
+}
+func (m *GetPageAfterRequest) XXX_Size() int {
+	return xxx_messageInfo_GetPageAfterRequest.Size(m)
+}
+func (m *GetPageAfterRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetPageAfterRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetPageAfterRequest proto.InternalMessageInfo
+
+func (m *GetPageAfterRequest) GetTableName() string {
+	if m != nil {
+		return m.TableName
+	}
+	return ""
+}
+
+func (m *GetPageAfterRequest) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
+func (m *GetPageAfterRequest) GetPageSize() int64 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *GetPageAfterRequest) GetView() string {
+	if m != nil {
+		return m.View
+	}
+	return ""
+}
+
+type GetPageAfterResponse struct {
+	Result               []*GetWhereStream `protobuf:"bytes,1,rep,name=Result,proto3" json:"Result,omitempty"`
+	Next                 string            `protobuf:"bytes,2,opt,name=Next,proto3" json:"Next,omitempty"`
+	Prev                 string            `protobuf:"bytes,3,opt,name=Prev,proto3" json:"Prev,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *GetPageAfterResponse) Reset()         { *m = GetPageAfterResponse{} }
+func (m *GetPageAfterResponse) String() string { return proto.CompactTextString(m) }
+func (*GetPageAfterResponse) ProtoMessage()    {}
+func (*GetPageAfterResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{45}
+}
+
+func (m *GetPageAfterResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetPageAfterResponse.Unmarshal(m, b)
+}
+func (m *GetPageAfterResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetPageAfterResponse.Marshal(b, m, deterministic)
+}
+func (m *GetPageAfterResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetPageAfterResponse.Merge(m, src)
+}
+func (m *GetPageAfterResponse) XXX_Size() int {
+	return xxx_messageInfo_GetPageAfterResponse.Size(m)
+}
+func (m *GetPageAfterResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetPageAfterResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetPageAfterResponse proto.InternalMessageInfo
+
+func (m *GetPageAfterResponse) GetResult() []*GetWhereStream {
 	if m != nil {
 		return m.Result
 	}
 	return nil
 }
 
-//--------------GetOnePageRows()---------------------------------
-type GetOnePageRowsRequest struct {
+func (m *GetPageAfterResponse) GetNext() string {
+	if m != nil {
+		return m.Next
+	}
+	return ""
+}
+
+func (m *GetPageAfterResponse) GetPrev() string {
+	if m != nil {
+		return m.Prev
+	}
+	return ""
+}
+
+//--------------GetPageBefore()---------------------------------
+type GetPageBeforeRequest struct {
 	TableName            string   `protobuf:"bytes,1,opt,name=TableName,proto3" json:"TableName,omitempty"`
-	Page                 int64    `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
-	PageSize             int64    `protobuf:"varint,3,opt,name=pageSize,proto3" json:"pageSize,omitempty"`
+	Cursor               string   `protobuf:"bytes,2,opt,name=Cursor,proto3" json:"Cursor,omitempty"`
+	PageSize             int64    `protobuf:"varint,3,opt,name=PageSize,proto3" json:"PageSize,omitempty"`
+	View                 string   `protobuf:"bytes,4,opt,name=View,proto3" json:"View,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *GetOnePageRowsRequest) Reset()         { *m = GetOnePageRowsRequest{} }
-func (m *GetOnePageRowsRequest) String() string { return proto.CompactTextString(m) }
-func (*GetOnePageRowsRequest) ProtoMessage()    {}
-func (*GetOnePageRowsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_bedfbfc9b54e5600, []int{23}
+func (m *GetPageBeforeRequest) Reset()         { *m = GetPageBeforeRequest{} }
+func (m *GetPageBeforeRequest) String() string { return proto.CompactTextString(m) }
+func (*GetPageBeforeRequest) ProtoMessage()    {}
+func (*GetPageBeforeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{46}
 }
 
-func (m *GetOnePageRowsRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_GetOnePageRowsRequest.Unmarshal(m, b)
+func (m *GetPageBeforeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetPageBeforeRequest.Unmarshal(m, b)
+}
+func (m *GetPageBeforeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetPageBeforeRequest.Marshal(b, m, deterministic)
+}
+func (m *GetPageBeforeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetPageBeforeRequest.Merge(m, src)
+}
+func (m *GetPageBeforeRequest) XXX_Size() int {
+	return xxx_messageInfo_GetPageBeforeRequest.Size(m)
+}
+func (m *GetPageBeforeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetPageBeforeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetPageBeforeRequest proto.InternalMessageInfo
+
+func (m *GetPageBeforeRequest) GetTableName() string {
+	if m != nil {
+		return m.TableName
+	}
+	return ""
+}
+
+func (m *GetPageBeforeRequest) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
+func (m *GetPageBeforeRequest) GetPageSize() int64 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *GetPageBeforeRequest) GetView() string {
+	if m != nil {
+		return m.View
+	}
+	return ""
+}
+
+type GetPageBeforeResponse struct {
+	Result               []*GetWhereStream `protobuf:"bytes,1,rep,name=Result,proto3" json:"Result,omitempty"`
+	Next                 string            `protobuf:"bytes,2,opt,name=Next,proto3" json:"Next,omitempty"`
+	Prev                 string            `protobuf:"bytes,3,opt,name=Prev,proto3" json:"Prev,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *GetPageBeforeResponse) Reset()         { *m = GetPageBeforeResponse{} }
+func (m *GetPageBeforeResponse) String() string { return proto.CompactTextString(m) }
+func (*GetPageBeforeResponse) ProtoMessage()    {}
+func (*GetPageBeforeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{47}
+}
+
+func (m *GetPageBeforeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetPageBeforeResponse.Unmarshal(m, b)
+}
+func (m *GetPageBeforeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetPageBeforeResponse.Marshal(b, m, deterministic)
+}
+func (m *GetPageBeforeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetPageBeforeResponse.Merge(m, src)
+}
+func (m *GetPageBeforeResponse) XXX_Size() int {
+	return xxx_messageInfo_GetPageBeforeResponse.Size(m)
+}
+func (m *GetPageBeforeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetPageBeforeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetPageBeforeResponse proto.InternalMessageInfo
+
+func (m *GetPageBeforeResponse) GetResult() []*GetWhereStream {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+func (m *GetPageBeforeResponse) GetNext() string {
+	if m != nil {
+		return m.Next
+	}
+	return ""
+}
+
+func (m *GetPageBeforeResponse) GetPrev() string {
+	if m != nil {
+		return m.Prev
+	}
+	return ""
+}
+
+//--------------GetPage()---------------------------------
+type GetPageRequest struct {
+	TableName            string   `protobuf:"bytes,1,opt,name=TableName,proto3" json:"TableName,omitempty"`
+	Where                string   `protobuf:"bytes,2,opt,name=Where,proto3" json:"Where,omitempty"`
+	Page                 int64    `protobuf:"varint,3,opt,name=Page,proto3" json:"Page,omitempty"`
+	PageSize             int64    `protobuf:"varint,4,opt,name=PageSize,proto3" json:"PageSize,omitempty"`
+	View                 string   `protobuf:"bytes,5,opt,name=View,proto3" json:"View,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetPageRequest) Reset()         { *m = GetPageRequest{} }
+func (m *GetPageRequest) String() string { return proto.CompactTextString(m) }
+func (*GetPageRequest) ProtoMessage()    {}
+func (*GetPageRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{48}
+}
+
+func (m *GetPageRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetPageRequest.Unmarshal(m, b)
+}
+func (m *GetPageRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetPageRequest.Marshal(b, m, deterministic)
+}
+func (m *GetPageRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetPageRequest.Merge(m, src)
+}
+func (m *GetPageRequest) XXX_Size() int {
+	return xxx_messageInfo_GetPageRequest.Size(m)
+}
+func (m *GetPageRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetPageRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetPageRequest proto.InternalMessageInfo
+
+func (m *GetPageRequest) GetTableName() string {
+	if m != nil {
+		return m.TableName
+	}
+	return ""
+}
+
+func (m *GetPageRequest) GetWhere() string {
+	if m != nil {
+		return m.Where
+	}
+	return ""
+}
+
+func (m *GetPageRequest) GetPage() int64 {
+	if m != nil {
+		return m.Page
+	}
+	return 0
+}
+
+func (m *GetPageRequest) GetPageSize() int64 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *GetPageRequest) GetView() string {
+	if m != nil {
+		return m.View
+	}
+	return ""
+}
+
+type GetPageResponse struct {
+	Result               []*GetWhereStream `protobuf:"bytes,1,rep,name=Result,proto3" json:"Result,omitempty"`
+	Total                int64             `protobuf:"varint,2,opt,name=Total,proto3" json:"Total,omitempty"`
+	PageCount            int64             `protobuf:"varint,3,opt,name=PageCount,proto3" json:"PageCount,omitempty"`
+	Page                 int64             `protobuf:"varint,4,opt,name=Page,proto3" json:"Page,omitempty"`
+	PageSize             int64             `protobuf:"varint,5,opt,name=PageSize,proto3" json:"PageSize,omitempty"`
+	HasNext              bool              `protobuf:"varint,6,opt,name=HasNext,proto3" json:"HasNext,omitempty"`
+	HasPrev              bool              `protobuf:"varint,7,opt,name=HasPrev,proto3" json:"HasPrev,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *GetPageResponse) Reset()         { *m = GetPageResponse{} }
+func (m *GetPageResponse) String() string { return proto.CompactTextString(m) }
+func (*GetPageResponse) ProtoMessage()    {}
+func (*GetPageResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{49}
+}
+
+func (m *GetPageResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetPageResponse.Unmarshal(m, b)
+}
+func (m *GetPageResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetPageResponse.Marshal(b, m, deterministic)
+}
+func (m *GetPageResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetPageResponse.Merge(m, src)
+}
+func (m *GetPageResponse) XXX_Size() int {
+	return xxx_messageInfo_GetPageResponse.Size(m)
+}
+func (m *GetPageResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetPageResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetPageResponse proto.InternalMessageInfo
+
+func (m *GetPageResponse) GetResult() []*GetWhereStream {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+func (m *GetPageResponse) GetTotal() int64 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
+func (m *GetPageResponse) GetPageCount() int64 {
+	if m != nil {
+		return m.PageCount
+	}
+	return 0
+}
+
+func (m *GetPageResponse) GetPage() int64 {
+	if m != nil {
+		return m.Page
+	}
+	return 0
+}
+
+func (m *GetPageResponse) GetPageSize() int64 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *GetPageResponse) GetHasNext() bool {
+	if m != nil {
+		return m.HasNext
+	}
+	return false
+}
+
+func (m *GetPageResponse) GetHasPrev() bool {
+	if m != nil {
+		return m.HasPrev
+	}
+	return false
+}
+
+//--------------AddTable()---------------------------------
+type AddTableRequest struct {
+	TableName            string   `protobuf:"bytes,1,opt,name=TableName,proto3" json:"TableName,omitempty"`
+	Columns              string   `protobuf:"bytes,2,opt,name=Columns,proto3" json:"Columns,omitempty"`
+	Pkey                 string   `protobuf:"bytes,3,opt,name=Pkey,proto3" json:"Pkey,omitempty"`
+	Where                string   `protobuf:"bytes,4,opt,name=Where,proto3" json:"Where,omitempty"`
+	Other                string   `protobuf:"bytes,5,opt,name=Other,proto3" json:"Other,omitempty"`
+	PkeyAutoIncrement    bool     `protobuf:"varint,6,opt,name=PkeyAutoIncrement,proto3" json:"PkeyAutoIncrement,omitempty"`
+	CacheType            string   `protobuf:"bytes,7,opt,name=CacheType,proto3" json:"CacheType,omitempty"`
+	IsRealtime           bool     `protobuf:"varint,8,opt,name=IsRealtime,proto3" json:"IsRealtime,omitempty"`
+	IsWaitResult         bool     `protobuf:"varint,9,opt,name=IsWaitResult,proto3" json:"IsWaitResult,omitempty"`
+	SortViews            string   `protobuf:"bytes,10,opt,name=SortViews,proto3" json:"SortViews,omitempty"`
+	Name                 string   `protobuf:"bytes,11,opt,name=Name,proto3" json:"Name,omitempty"`
+	HiddenColumns        string   `protobuf:"bytes,12,opt,name=HiddenColumns,proto3" json:"HiddenColumns,omitempty"`
+	MaskedColumns        string   `protobuf:"bytes,13,opt,name=MaskedColumns,proto3" json:"MaskedColumns,omitempty"`
+	ReadonlyColumns      string   `protobuf:"bytes,14,opt,name=ReadonlyColumns,proto3" json:"ReadonlyColumns,omitempty"`
+	Token                string   `protobuf:"bytes,15,opt,name=Token,proto3" json:"Token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AddTableRequest) Reset()         { *m = AddTableRequest{} }
+func (m *AddTableRequest) String() string { return proto.CompactTextString(m) }
+func (*AddTableRequest) ProtoMessage()    {}
+func (*AddTableRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{50}
+}
+
+func (m *AddTableRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AddTableRequest.Unmarshal(m, b)
 }
-func (m *GetOnePageRowsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_GetOnePageRowsRequest.Marshal(b, m, deterministic)
+func (m *AddTableRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AddTableRequest.Marshal(b, m, deterministic)
 }
-func (m *GetOnePageRowsRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_GetOnePageRowsRequest.Merge(m, src)
+func (m *AddTableRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddTableRequest.Merge(m, src)
 }
-func (m *GetOnePageRowsRequest) XXX_Size() int {
-	return xxx_messageInfo_GetOnePageRowsRequest.Size(m)
+func (m *AddTableRequest) XXX_Size() int {
+	return xxx_messageInfo_AddTableRequest.Size(m)
 }
-func (m *GetOnePageRowsRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_GetOnePageRowsRequest.DiscardUnknown(m)
+func (m *AddTableRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddTableRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_GetOnePageRowsRequest proto.InternalMessageInfo
+var xxx_messageInfo_AddTableRequest proto.InternalMessageInfo
 
-func (m *GetOnePageRowsRequest) GetTableName() string {
+func (m *AddTableRequest) GetTableName() string {
 	if m != nil {
 		return m.TableName
 	}
 	return ""
 }
 
-func (m *GetOnePageRowsRequest) GetPage() int64 {
+func (m *AddTableRequest) GetColumns() string {
 	if m != nil {
-		return m.Page
+		return m.Columns
 	}
-	return 0
+	return ""
 }
 
-func (m *GetOnePageRowsRequest) GetPageSize() int64 {
+func (m *AddTableRequest) GetPkey() string {
 	if m != nil {
-		return m.PageSize
+		return m.Pkey
 	}
-	return 0
+	return ""
 }
 
-//服务器端流式 RPC
-type GetOnePageRowsResponse struct {
-	Result               *GetOnePageRowstream `protobuf:"bytes,1,opt,name=Result,proto3" json:"Result,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
-	XXX_unrecognized     []byte               `json:"-"`
-	XXX_sizecache        int32                `json:"-"`
+func (m *AddTableRequest) GetWhere() string {
+	if m != nil {
+		return m.Where
+	}
+	return ""
 }
 
-func (m *GetOnePageRowsResponse) Reset()         { *m = GetOnePageRowsResponse{} }
-func (m *GetOnePageRowsResponse) String() string { return proto.CompactTextString(m) }
-func (*GetOnePageRowsResponse) ProtoMessage()    {}
-func (*GetOnePageRowsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_bedfbfc9b54e5600, []int{24}
+func (m *AddTableRequest) GetOther() string {
+	if m != nil {
+		return m.Other
+	}
+	return ""
 }
 
-func (m *GetOnePageRowsResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_GetOnePageRowsResponse.Unmarshal(m, b)
+func (m *AddTableRequest) GetPkeyAutoIncrement() bool {
+	if m != nil {
+		return m.PkeyAutoIncrement
+	}
+	return false
 }
-func (m *GetOnePageRowsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_GetOnePageRowsResponse.Marshal(b, m, deterministic)
+
+func (m *AddTableRequest) GetCacheType() string {
+	if m != nil {
+		return m.CacheType
+	}
+	return ""
 }
-func (m *GetOnePageRowsResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_GetOnePageRowsResponse.Merge(m, src)
+
+func (m *AddTableRequest) GetIsRealtime() bool {
+	if m != nil {
+		return m.IsRealtime
+	}
+	return false
 }
-func (m *GetOnePageRowsResponse) XXX_Size() int {
-	return xxx_messageInfo_GetOnePageRowsResponse.Size(m)
+
+func (m *AddTableRequest) GetIsWaitResult() bool {
+	if m != nil {
+		return m.IsWaitResult
+	}
+	return false
 }
-func (m *GetOnePageRowsResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_GetOnePageRowsResponse.DiscardUnknown(m)
+
+func (m *AddTableRequest) GetSortViews() string {
+	if m != nil {
+		return m.SortViews
+	}
+	return ""
 }
 
-var xxx_messageInfo_GetOnePageRowsResponse proto.InternalMessageInfo
+func (m *AddTableRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
 
-func (m *GetOnePageRowsResponse) GetResult() *GetOnePageRowstream {
+func (m *AddTableRequest) GetHiddenColumns() string {
 	if m != nil {
-		return m.Result
+		return m.HiddenColumns
 	}
-	return nil
+	return ""
 }
 
-type GetOnePageRowstream struct {
-	Result               map[string]string `protobuf:"bytes,1,rep,name=Result,proto3" json:"Result,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
-	XXX_unrecognized     []byte            `json:"-"`
-	XXX_sizecache        int32             `json:"-"`
+func (m *AddTableRequest) GetMaskedColumns() string {
+	if m != nil {
+		return m.MaskedColumns
+	}
+	return ""
 }
 
-func (m *GetOnePageRowstream) Reset()         { *m = GetOnePageRowstream{} }
-func (m *GetOnePageRowstream) String() string { return proto.CompactTextString(m) }
-func (*GetOnePageRowstream) ProtoMessage()    {}
-func (*GetOnePageRowstream) Descriptor() ([]byte, []int) {
-	return fileDescriptor_bedfbfc9b54e5600, []int{25}
+func (m *AddTableRequest) GetReadonlyColumns() string {
+	if m != nil {
+		return m.ReadonlyColumns
+	}
+	return ""
 }
 
-func (m *GetOnePageRowstream) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_GetOnePageRowstream.Unmarshal(m, b)
+func (m *AddTableRequest) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
 }
-func (m *GetOnePageRowstream) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_GetOnePageRowstream.Marshal(b, m, deterministic)
+
+type AddTableResponse struct {
+	Result               int64    `protobuf:"varint,1,opt,name=Result,proto3" json:"Result,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
-func (m *GetOnePageRowstream) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_GetOnePageRowstream.Merge(m, src)
+
+func (m *AddTableResponse) Reset()         { *m = AddTableResponse{} }
+func (m *AddTableResponse) String() string { return proto.CompactTextString(m) }
+func (*AddTableResponse) ProtoMessage()    {}
+func (*AddTableResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{51}
 }
-func (m *GetOnePageRowstream) XXX_Size() int {
-	return xxx_messageInfo_GetOnePageRowstream.Size(m)
+
+func (m *AddTableResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AddTableResponse.Unmarshal(m, b)
 }
-func (m *GetOnePageRowstream) XXX_DiscardUnknown() {
-	xxx_messageInfo_GetOnePageRowstream.DiscardUnknown(m)
+func (m *AddTableResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AddTableResponse.Marshal(b, m, deterministic)
+}
+func (m *AddTableResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddTableResponse.Merge(m, src)
+}
+func (m *AddTableResponse) XXX_Size() int {
+	return xxx_messageInfo_AddTableResponse.Size(m)
+}
+func (m *AddTableResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddTableResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_GetOnePageRowstream proto.InternalMessageInfo
+var xxx_messageInfo_AddTableResponse proto.InternalMessageInfo
 
-func (m *GetOnePageRowstream) GetResult() map[string]string {
+func (m *AddTableResponse) GetResult() int64 {
 	if m != nil {
 		return m.Result
 	}
-	return nil
+	return 0
 }
 
 func init() {
@@ -1228,6 +2811,16 @@ func init() {
 	proto.RegisterType((*UpdateColumnsResponse)(nil), "pb.UpdateColumnsResponse")
 	proto.RegisterType((*InsertRowRequest)(nil), "pb.InsertRowRequest")
 	proto.RegisterType((*InsertRowResponse)(nil), "pb.InsertRowResponse")
+	proto.RegisterType((*UpsertRequest)(nil), "pb.UpsertRequest")
+	proto.RegisterType((*UpsertResponse)(nil), "pb.UpsertResponse")
+	proto.RegisterType((*InsertRowsRequest)(nil), "pb.InsertRowsRequest")
+	proto.RegisterType((*InsertRowsResponse)(nil), "pb.InsertRowsResponse")
+	proto.RegisterType((*DelRowsRequest)(nil), "pb.DelRowsRequest")
+	proto.RegisterType((*DelRowsResponse)(nil), "pb.DelRowsResponse")
+	proto.RegisterType((*UpdateWhereRequest)(nil), "pb.UpdateWhereRequest")
+	proto.RegisterType((*UpdateWhereResponse)(nil), "pb.UpdateWhereResponse")
+	proto.RegisterType((*DeleteWhereRequest)(nil), "pb.DeleteWhereRequest")
+	proto.RegisterType((*DeleteWhereResponse)(nil), "pb.DeleteWhereResponse")
 	proto.RegisterType((*GetRowBetweenRequest)(nil), "pb.GetRowBetweenRequest")
 	proto.RegisterType((*GetRowBetweenResponse)(nil), "pb.GetRowBetweenResponse")
 	proto.RegisterType((*GetRowBetweentream)(nil), "pb.GetRowBetweentream")
@@ -1242,63 +2835,130 @@ func init() {
 	proto.RegisterType((*GetOnePageRowsResponse)(nil), "pb.GetOnePageRowsResponse")
 	proto.RegisterType((*GetOnePageRowstream)(nil), "pb.GetOnePageRowstream")
 	proto.RegisterMapType((map[string]string)(nil), "pb.GetOnePageRowstream.ResultEntry")
+	proto.RegisterType((*GetWhereRowBetweenRequest)(nil), "pb.GetWhereRowBetweenRequest")
+	proto.RegisterType((*GetWhereRowBetweenResponse)(nil), "pb.GetWhereRowBetweenResponse")
+	proto.RegisterType((*GetWherePageCountRequest)(nil), "pb.GetWherePageCountRequest")
+	proto.RegisterType((*GetWherePageCountResponse)(nil), "pb.GetWherePageCountResponse")
+	proto.RegisterType((*GetWhereMultipageRowsRequest)(nil), "pb.GetWhereMultipageRowsRequest")
+	proto.RegisterType((*GetWhereMultipageRowsResponse)(nil), "pb.GetWhereMultipageRowsResponse")
+	proto.RegisterType((*GetWhereOnePageRowsRequest)(nil), "pb.GetWhereOnePageRowsRequest")
+	proto.RegisterType((*GetWhereOnePageRowsResponse)(nil), "pb.GetWhereOnePageRowsResponse")
+	proto.RegisterType((*GetPageAfterRequest)(nil), "pb.GetPageAfterRequest")
+	proto.RegisterType((*GetPageAfterResponse)(nil), "pb.GetPageAfterResponse")
+	proto.RegisterType((*GetPageBeforeRequest)(nil), "pb.GetPageBeforeRequest")
+	proto.RegisterType((*GetPageBeforeResponse)(nil), "pb.GetPageBeforeResponse")
+	proto.RegisterType((*GetPageRequest)(nil), "pb.GetPageRequest")
+	proto.RegisterType((*GetPageResponse)(nil), "pb.GetPageResponse")
+	proto.RegisterType((*AddTableRequest)(nil), "pb.AddTableRequest")
+	proto.RegisterType((*AddTableResponse)(nil), "pb.AddTableResponse")
 }
 
 func init() { proto.RegisterFile("grpc.proto", fileDescriptor_bedfbfc9b54e5600) }
 
 var fileDescriptor_bedfbfc9b54e5600 = []byte{
-	// 807 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x56, 0x5b, 0x6f, 0xd3, 0x4c,
-	0x10, 0x95, 0xeb, 0xb4, 0x5f, 0x33, 0xf9, 0x5a, 0xda, 0x6d, 0x9a, 0x3a, 0xa6, 0x42, 0x95, 0x11,
-	0x52, 0x05, 0x52, 0x0a, 0x45, 0x82, 0x52, 0x04, 0x52, 0x6f, 0x44, 0x7d, 0xe8, 0x45, 0x2e, 0x97,
-	0x97, 0x0a, 0xc9, 0x49, 0x57, 0x6d, 0x44, 0x62, 0x1b, 0x7b, 0x4d, 0x55, 0xde, 0x10, 0x8f, 0x15,
-	0x0f, 0xfc, 0x2f, 0x7e, 0x14, 0xf2, 0x5e, 0xec, 0xdd, 0xb5, 0xab, 0x84, 0x12, 0x9e, 0xb2, 0xbb,
-	0x33, 0x99, 0x39, 0x7b, 0xe6, 0x78, 0x67, 0x00, 0xce, 0xa3, 0xb0, 0xdb, 0x0a, 0xa3, 0x80, 0x04,
-	0x68, 0x22, 0xec, 0x38, 0x5b, 0x30, 0xd3, 0xc6, 0xc4, 0x0d, 0x2e, 0x5d, 0xfc, 0x39, 0xc1, 0x31,
-	0x41, 0xcb, 0x50, 0x7d, 0xeb, 0x75, 0xfa, 0xf8, 0xd0, 0x1b, 0x60, 0xcb, 0x58, 0x31, 0x56, 0xab,
-	0x6e, 0x7e, 0x80, 0x10, 0x54, 0x8e, 0x3f, 0xe1, 0x2b, 0x6b, 0x82, 0x1a, 0xe8, 0xda, 0xf9, 0x6e,
-	0xc0, 0xac, 0x88, 0x11, 0x87, 0x81, 0x1f, 0x63, 0xf4, 0x0c, 0xa6, 0x5c, 0x1c, 0x27, 0x7d, 0x62,
-	0x19, 0x2b, 0xe6, 0x6a, 0x6d, 0xfd, 0x5e, 0x2b, 0xec, 0xb4, 0x54, 0x9f, 0x16, 0x73, 0xd8, 0xf3,
-	0x49, 0x74, 0xe5, 0x72, 0x6f, 0xfb, 0x05, 0xd4, 0xa4, 0x63, 0x34, 0x07, 0x66, 0x9a, 0x8c, 0xa1,
-	0x48, 0x97, 0xa8, 0x0e, 0x93, 0x5f, 0xbc, 0x7e, 0x82, 0x39, 0x00, 0xb6, 0xd9, 0x9c, 0xd8, 0x30,
-	0x9c, 0x53, 0x98, 0x6b, 0x63, 0xb2, 0x13, 0xf4, 0x93, 0x81, 0x7f, 0xeb, 0xbb, 0xa0, 0x06, 0x4c,
-	0xb1, 0x10, 0x96, 0x49, 0x4f, 0xf9, 0xce, 0x79, 0x04, 0xf3, 0x52, 0x74, 0x7e, 0xcb, 0x86, 0x74,
-	0x4b, 0xea, 0xcc, 0x76, 0x29, 0xa7, 0xbb, 0xb8, 0xff, 0x57, 0x9c, 0xae, 0xc2, 0xac, 0x08, 0x51,
-	0x9a, 0xcc, 0xcc, 0x92, 0xed, 0xc1, 0x9d, 0x36, 0x26, 0x1f, 0x2e, 0x70, 0x84, 0x47, 0x4b, 0x57,
-	0x87, 0x49, 0xea, 0x2d, 0x28, 0xa4, 0x1b, 0xe7, 0x35, 0xa5, 0x8f, 0x87, 0xe1, 0x29, 0x1f, 0x2a,
-	0x29, 0x6b, 0xeb, 0x88, 0x57, 0x91, 0x7a, 0x9d, 0x90, 0x08, 0x7b, 0x83, 0x0c, 0x06, 0x17, 0x81,
-	0x64, 0xba, 0x51, 0x04, 0x92, 0xcf, 0xb8, 0x45, 0xf0, 0xcd, 0x80, 0x85, 0x77, 0xe1, 0x99, 0x47,
-	0xf0, 0x3f, 0x12, 0x02, 0x5a, 0x81, 0x1a, 0x5b, 0xbd, 0xa7, 0x08, 0x2a, 0xd4, 0x28, 0x1f, 0x39,
-	0x2d, 0xa8, 0xab, 0x10, 0x86, 0x14, 0xf0, 0xa3, 0xea, 0x1f, 0xdf, 0x1e, 0x73, 0x56, 0x59, 0x53,
-	0xae, 0xec, 0x1a, 0x2c, 0x6a, 0xf1, 0x87, 0x00, 0x3a, 0x84, 0xb9, 0x7d, 0x3f, 0xc6, 0xd1, 0xe8,
-	0xaf, 0xc2, 0x32, 0x54, 0x77, 0x02, 0xff, 0xac, 0x47, 0x7a, 0x81, 0xcf, 0x11, 0xe5, 0x07, 0xe9,
-	0xb7, 0x23, 0xc5, 0x1b, 0x92, 0xfc, 0x14, 0xea, 0xec, 0x9d, 0xd8, 0xc6, 0xe4, 0x12, 0x63, 0x7f,
-	0x64, 0x4d, 0x9f, 0x10, 0x2f, 0x22, 0x34, 0xb9, 0xe9, 0xb2, 0x4d, 0xaa, 0x9c, 0x3d, 0xff, 0x8c,
-	0xb2, 0x61, 0xba, 0xe9, 0xd2, 0x69, 0xc3, 0xa2, 0x16, 0x9d, 0xc3, 0x69, 0x69, 0x52, 0x6f, 0xe4,
-	0x0f, 0x16, 0x77, 0x55, 0xe5, 0x7e, 0x6d, 0x00, 0x2a, 0x9a, 0xd1, 0xa6, 0x26, 0x79, 0xa7, 0x3c,
-	0xcc, 0xb8, 0x65, 0x7f, 0x04, 0x0b, 0x6d, 0x4c, 0x8e, 0xbd, 0x73, 0xbc, 0x13, 0x24, 0x3e, 0x19,
-	0x8d, 0x33, 0x1b, 0xa6, 0xd3, 0x7f, 0x9c, 0xf4, 0xbe, 0x62, 0x4e, 0x5b, 0xb6, 0x4f, 0x35, 0xac,
-	0x06, 0x1c, 0x52, 0xb5, 0x6b, 0x03, 0x96, 0xda, 0x98, 0x1c, 0x24, 0x7d, 0xd2, 0x0b, 0xbd, 0x73,
-	0xec, 0x06, 0x97, 0xf1, 0xc8, 0xd2, 0xa1, 0xc5, 0x4a, 0x73, 0x71, 0x18, 0xf9, 0x01, 0xb2, 0xe0,
-	0xbf, 0xf4, 0xf7, 0x30, 0x19, 0xf0, 0x2a, 0x8a, 0x6d, 0x8a, 0x3e, 0x14, 0xe8, 0x2b, 0x0c, 0xbd,
-	0xd8, 0x3b, 0x07, 0x60, 0x15, 0xc1, 0xf0, 0x1b, 0x3c, 0xd1, 0x0a, 0xdd, 0xe4, 0x15, 0x52, 0xbc,
-	0xd5, 0x5a, 0xff, 0x34, 0xa8, 0x6a, 0x8a, 0x1e, 0xe8, 0x95, 0x56, 0xee, 0x07, 0x37, 0x06, 0x1b,
-	0x77, 0xc5, 0x31, 0x85, 0x74, 0xe4, 0xe3, 0xe3, 0x3f, 0x62, 0x1b, 0x41, 0x25, 0xcc, 0x89, 0xa6,
-	0x6b, 0x85, 0x49, 0x53, 0x63, 0x72, 0x1f, 0x1a, 0x7a, 0x1a, 0xce, 0xe3, 0x9a, 0xc6, 0xe3, 0x12,
-	0xbf, 0xba, 0xe4, 0xab, 0xb2, 0xf8, 0xc3, 0xa0, 0x22, 0xd5, 0xed, 0xe8, 0xa5, 0xc6, 0xe1, 0xfd,
-	0x1b, 0x02, 0x8d, 0x99, 0xc1, 0xf5, 0x5f, 0x93, 0x50, 0x6b, 0x47, 0x61, 0x77, 0x77, 0xbb, 0xeb,
-	0x75, 0x2f, 0xe8, 0x85, 0xd8, 0x87, 0x8a, 0xe6, 0xe5, 0x61, 0x85, 0xb2, 0x6a, 0xa3, 0xe2, 0xfc,
-	0x82, 0x36, 0xa0, 0x9a, 0x8d, 0x04, 0xa8, 0xce, 0x1d, 0x94, 0xb6, 0x63, 0x2f, 0x6a, 0xa7, 0x39,
-	0x77, 0xac, 0xb9, 0xb3, 0x54, 0xca, 0xac, 0xc0, 0x52, 0x69, 0xbd, 0xff, 0x39, 0x4c, 0x8b, 0xbe,
-	0x89, 0x16, 0xe4, 0x2e, 0x2a, 0xfe, 0x54, 0x57, 0x0f, 0xd9, 0xdf, 0x1e, 0x1b, 0x68, 0x0b, 0xfe,
-	0x97, 0xdf, 0x7e, 0x44, 0xab, 0x54, 0xd2, 0x20, 0x6d, 0xab, 0x68, 0xe0, 0xb9, 0x77, 0x61, 0x46,
-	0x69, 0x1f, 0xa8, 0xe0, 0x2a, 0xb4, 0x67, 0x37, 0x4b, 0x2c, 0x39, 0x59, 0x59, 0x0f, 0x60, 0x64,
-	0xe9, 0x2d, 0x86, 0x91, 0x55, 0x6c, 0x14, 0x6f, 0xc4, 0x80, 0xca, 0x1f, 0x50, 0x96, 0xbf, 0xac,
-	0x47, 0xd8, 0xcd, 0x12, 0x8b, 0x4c, 0x85, 0xfc, 0xa4, 0x21, 0x21, 0x58, 0xfd, 0xd5, 0xb4, 0xad,
-	0xa2, 0x81, 0x43, 0x39, 0xa2, 0x33, 0x92, 0xf2, 0x71, 0xa3, 0xbb, 0x65, 0x9f, 0xbc, 0x08, 0xb5,
-	0x5c, 0x6e, 0xcc, 0x30, 0xed, 0xd3, 0x99, 0x49, 0x52, 0x3a, 0x6a, 0x16, 0xd5, 0x2f, 0x82, 0xd9,
-	0x65, 0x26, 0x11, 0xaa, 0x33, 0x45, 0x47, 0xfa, 0xa7, 0xbf, 0x03, 0x00, 0x00, 0xff, 0xff, 0x0d,
-	0x2a, 0xa5, 0xb5, 0xe0, 0x0b, 0x00, 0x00,
+	// 1619 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x59, 0x5b, 0x6f, 0x1b, 0xc5,
+	0x17, 0xd7, 0x76, 0x9d, 0xc4, 0x39, 0xce, 0x75, 0xe2, 0x24, 0xeb, 0x6d, 0xda, 0x7f, 0xfe, 0x0b,
+	0x48, 0xa1, 0x14, 0x17, 0xc2, 0xad, 0x14, 0x15, 0xc8, 0xa5, 0xb8, 0x16, 0x34, 0x89, 0x9c, 0x5e,
+	0x5e, 0xaa, 0x4a, 0x9b, 0x78, 0x9a, 0x98, 0x38, 0xbb, 0xcb, 0xee, 0xba, 0x21, 0x5c, 0x85, 0x78,
+	0x41, 0xaa, 0x78, 0x40, 0xe2, 0x9d, 0xef, 0xc1, 0x17, 0xe0, 0x13, 0x20, 0xbe, 0x0e, 0x9a, 0xeb,
+	0xce, 0xec, 0x25, 0x76, 0xd2, 0x34, 0x3c, 0x79, 0xe7, 0x9c, 0x33, 0xe7, 0xf2, 0x3b, 0x67, 0xcf,
+	0x9e, 0x19, 0x03, 0xec, 0x85, 0xc1, 0x6e, 0x3d, 0x08, 0xfd, 0xd8, 0x47, 0x97, 0x82, 0x1d, 0x67,
+	0x05, 0xc6, 0x1b, 0x38, 0x6e, 0xf9, 0x47, 0x2d, 0xfc, 0x55, 0x0f, 0x47, 0x31, 0x5a, 0x80, 0xd1,
+	0xfb, 0xee, 0x4e, 0x17, 0x6f, 0xb8, 0x87, 0xd8, 0x32, 0x16, 0x8d, 0xa5, 0xd1, 0x56, 0x42, 0x40,
+	0x08, 0x4a, 0x5b, 0x07, 0xf8, 0xd8, 0xba, 0x44, 0x19, 0xf4, 0xd9, 0xf9, 0xd9, 0x80, 0x09, 0xa1,
+	0x23, 0x0a, 0x7c, 0x2f, 0xc2, 0xe8, 0x7d, 0x18, 0x6e, 0xe1, 0xa8, 0xd7, 0x8d, 0x2d, 0x63, 0xd1,
+	0x5c, 0xaa, 0x2c, 0x5f, 0xad, 0x07, 0x3b, 0x75, 0x5d, 0xa6, 0xce, 0x04, 0xee, 0x78, 0x71, 0x78,
+	0xdc, 0xe2, 0xd2, 0xf6, 0x87, 0x50, 0x51, 0xc8, 0x68, 0x0a, 0x4c, 0x62, 0x8c, 0x79, 0x41, 0x1e,
+	0x51, 0x15, 0x86, 0x9e, 0xb9, 0xdd, 0x1e, 0xe6, 0x0e, 0xb0, 0xc5, 0xad, 0x4b, 0x37, 0x0d, 0xe7,
+	0x31, 0x4c, 0x35, 0x70, 0xbc, 0xe6, 0x77, 0x7b, 0x87, 0xde, 0x99, 0x63, 0x41, 0x73, 0x30, 0xcc,
+	0x54, 0x58, 0x26, 0xa5, 0xf2, 0x95, 0xf3, 0x06, 0x4c, 0x2b, 0xda, 0x79, 0x94, 0x73, 0x4a, 0x94,
+	0x54, 0x98, 0xad, 0x08, 0xa6, 0xeb, 0xb8, 0xfb, 0x42, 0x98, 0x2e, 0xc1, 0x84, 0x50, 0x91, 0x6b,
+	0xcc, 0x94, 0xc6, 0xee, 0xc0, 0x64, 0x03, 0xc7, 0x8f, 0xf6, 0x71, 0x88, 0x07, 0x33, 0x57, 0x85,
+	0x21, 0x2a, 0x2d, 0x20, 0xa4, 0x0b, 0xe7, 0x63, 0x0a, 0x1f, 0x57, 0xc3, 0x4d, 0x5e, 0xd3, 0x4c,
+	0x56, 0x96, 0x11, 0xcf, 0x22, 0x95, 0xda, 0x8e, 0x43, 0xec, 0x1e, 0x4a, 0x37, 0x78, 0x11, 0x28,
+	0xac, 0xc2, 0x22, 0x50, 0x64, 0xce, 0xbb, 0x08, 0x7e, 0x32, 0x60, 0xe6, 0x41, 0xd0, 0x76, 0x63,
+	0xfc, 0x92, 0x0a, 0x01, 0x2d, 0x42, 0x85, 0x3d, 0x3d, 0xa4, 0x1e, 0x94, 0x28, 0x53, 0x25, 0x39,
+	0x75, 0xa8, 0xea, 0x2e, 0xf4, 0x49, 0xe0, 0x13, 0x5d, 0x3e, 0x3a, 0xbb, 0xcf, 0x32, 0xb3, 0xa6,
+	0x9a, 0xd9, 0x1b, 0x30, 0x9b, 0xd2, 0xdf, 0xc7, 0xa1, 0x0d, 0x98, 0x6a, 0x7a, 0x11, 0x0e, 0x07,
+	0xef, 0x0a, 0x0b, 0x30, 0xba, 0xe6, 0x7b, 0xed, 0x4e, 0xdc, 0xf1, 0x3d, 0xee, 0x51, 0x42, 0x70,
+	0x36, 0x61, 0x5a, 0xd1, 0x77, 0xb2, 0x71, 0xe4, 0xc0, 0xd8, 0x17, 0x6e, 0x14, 0xb3, 0x0d, 0xcd,
+	0x36, 0xd5, 0x66, 0xb6, 0x34, 0x9a, 0xf3, 0x09, 0x8c, 0x3f, 0x08, 0xa8, 0xc2, 0x81, 0xbc, 0x9b,
+	0x02, 0xb3, 0xe5, 0x1f, 0x71, 0xbf, 0xc8, 0xa3, 0xf3, 0x04, 0x26, 0x84, 0x82, 0x3e, 0xee, 0xd8,
+	0x50, 0x6e, 0x46, 0xcc, 0x30, 0x55, 0x50, 0x6e, 0xc9, 0x35, 0xb2, 0x60, 0xa4, 0x19, 0x7d, 0xee,
+	0xf9, 0x47, 0xac, 0x46, 0xca, 0x2d, 0xb1, 0x74, 0xd6, 0x94, 0x88, 0xa3, 0xb3, 0x3a, 0x79, 0x1d,
+	0x90, 0xaa, 0xa4, 0x4f, 0xd2, 0x56, 0x45, 0xc3, 0x38, 0x7b, 0xfd, 0x38, 0xaf, 0xc3, 0xa4, 0xd4,
+	0xd1, 0xc7, 0xdc, 0x0e, 0x20, 0x56, 0x54, 0x2f, 0xda, 0x78, 0x08, 0x8a, 0x6b, 0xfb, 0xae, 0xb7,
+	0x87, 0x23, 0x5e, 0xb6, 0x62, 0xe9, 0xbc, 0x29, 0xde, 0x65, 0xbd, 0x2b, 0x15, 0xb9, 0x74, 0x17,
+	0xd0, 0x3a, 0xee, 0xe2, 0x17, 0x77, 0x89, 0x18, 0xd6, 0x34, 0xf5, 0x31, 0x1c, 0x40, 0x95, 0x7d,
+	0xda, 0x56, 0x71, 0x7c, 0x84, 0xb1, 0x37, 0xb0, 0xe9, 0xed, 0xd8, 0xe5, 0x65, 0x65, 0xb6, 0xd8,
+	0x82, 0x94, 0xc1, 0x1d, 0xaf, 0x4d, 0x91, 0x30, 0x5b, 0xe4, 0x91, 0x24, 0xea, 0x61, 0x07, 0x1f,
+	0xf1, 0x4e, 0x43, 0x9f, 0x9d, 0x06, 0xcc, 0xa6, 0x2c, 0x72, 0x17, 0xeb, 0xa9, 0x8e, 0x3d, 0x97,
+	0x7c, 0x77, 0xb9, 0xa8, 0xde, 0xb5, 0x9f, 0x1b, 0x80, 0xb2, 0x6c, 0x74, 0x2b, 0xd5, 0xb9, 0x9d,
+	0x7c, 0x35, 0xe7, 0xdd, 0xbd, 0x77, 0x61, 0xa6, 0x81, 0xe3, 0x2d, 0x77, 0x0f, 0xaf, 0xf9, 0x3d,
+	0x6f, 0xc0, 0xb7, 0xdb, 0x86, 0x32, 0xd9, 0xb1, 0xdd, 0xf9, 0x06, 0x73, 0x28, 0xe5, 0x5a, 0x62,
+	0x67, 0x2a, 0xd8, 0xd5, 0x69, 0xb6, 0x14, 0x23, 0x7d, 0xb2, 0xfb, 0x87, 0x01, 0xf3, 0x0d, 0x1c,
+	0xdf, 0xeb, 0x75, 0xe3, 0x4e, 0xe0, 0xee, 0xe1, 0xc1, 0x5f, 0xb1, 0x05, 0x18, 0xa5, 0x49, 0x25,
+	0xb6, 0xb8, 0x6b, 0x09, 0x81, 0xd4, 0x3d, 0xf9, 0xdd, 0xe8, 0x1d, 0xf2, 0x6c, 0x8b, 0x25, 0x89,
+	0x28, 0x10, 0x11, 0x95, 0x58, 0x44, 0x41, 0x3a, 0xa2, 0x21, 0x25, 0xa2, 0x7b, 0x60, 0x65, 0x1d,
+	0xe4, 0x51, 0xbd, 0x9d, 0x2a, 0x88, 0x1a, 0xcf, 0xa4, 0x26, 0xad, 0xd7, 0xc4, 0x6f, 0x06, 0xcc,
+	0xe6, 0x4a, 0xa0, 0xdb, 0xa9, 0xb2, 0x78, 0xad, 0x50, 0xd9, 0x79, 0x57, 0xc6, 0x31, 0x75, 0x69,
+	0xd3, 0xc3, 0x5b, 0xa7, 0xca, 0x00, 0x82, 0x52, 0x90, 0x80, 0x4f, 0x9f, 0x35, 0x74, 0xcd, 0x02,
+	0x74, 0xd5, 0x77, 0xad, 0x09, 0x73, 0x69, 0xd3, 0x1c, 0xdb, 0x1b, 0x29, 0x6c, 0xe7, 0x39, 0x1c,
+	0x8a, 0xac, 0x8e, 0xec, 0xaf, 0x06, 0xcc, 0xe4, 0xf0, 0xd1, 0x47, 0x29, 0x5c, 0x5f, 0x29, 0x50,
+	0x74, 0xde, 0xa8, 0x3e, 0x37, 0xa0, 0x26, 0x87, 0xbe, 0xd3, 0xb7, 0xaf, 0x9c, 0x66, 0x2e, 0x9b,
+	0x9a, 0x99, 0xd3, 0xd4, 0x4a, 0xd9, 0xa6, 0xa6, 0x96, 0xf1, 0x13, 0xb0, 0xf3, 0x9c, 0xc9, 0x99,
+	0x45, 0xcd, 0x93, 0x67, 0x51, 0xe2, 0xc5, 0x7d, 0x3f, 0x76, 0xbb, 0xa2, 0xb5, 0xd2, 0x85, 0xf3,
+	0x03, 0x58, 0x42, 0xfe, 0x94, 0x2d, 0x26, 0x3f, 0x56, 0xb5, 0xf1, 0x98, 0x05, 0x8d, 0x47, 0x2f,
+	0xa4, 0x5a, 0x8e, 0xfd, 0x3e, 0xf3, 0x47, 0x7e, 0x28, 0x7f, 0x1a, 0xb0, 0x20, 0x74, 0x9d, 0xa1,
+	0x31, 0xe5, 0xc7, 0xa3, 0xb5, 0x2b, 0xf3, 0x84, 0x76, 0x55, 0xca, 0xb4, 0x2b, 0x89, 0xc3, 0x50,
+	0x01, 0x0e, 0xc3, 0x0a, 0x0e, 0x3f, 0xc2, 0x95, 0x02, 0xdf, 0xcf, 0x2b, 0xd5, 0x24, 0x14, 0x09,
+	0xb1, 0x08, 0x45, 0x12, 0x9c, 0xdf, 0x8d, 0xa4, 0xd2, 0x4e, 0xdd, 0x52, 0xf2, 0xb1, 0x23, 0xd3,
+	0x54, 0x02, 0x1b, 0x7d, 0xd6, 0x70, 0x29, 0x15, 0xe0, 0xa2, 0xd6, 0xff, 0xf7, 0x70, 0x39, 0xd7,
+	0xab, 0x0b, 0x42, 0xe5, 0x5b, 0xf9, 0xf1, 0x5d, 0x79, 0x1a, 0xe3, 0x70, 0x30, 0x34, 0xc8, 0x29,
+	0xa9, 0x17, 0x46, 0x7e, 0xc8, 0xe1, 0xe0, 0xab, 0x53, 0xbf, 0x1b, 0x5f, 0x42, 0x55, 0x37, 0x7e,
+	0x86, 0xa0, 0x11, 0x94, 0x36, 0xf0, 0xd7, 0xb1, 0x98, 0x68, 0xc9, 0x33, 0xcd, 0x4b, 0x88, 0x9f,
+	0x89, 0x01, 0x80, 0x3c, 0x3b, 0xdf, 0x49, 0x5b, 0xab, 0xf8, 0xa9, 0x1f, 0xe2, 0x8b, 0x8d, 0xf4,
+	0x80, 0x7e, 0xc9, 0x54, 0xeb, 0x2f, 0x31, 0xd4, 0x5f, 0xd8, 0xa1, 0x9c, 0xd6, 0xd2, 0x7f, 0x5c,
+	0xdd, 0x7f, 0x1b, 0xf4, 0x9e, 0x82, 0xb9, 0x72, 0x31, 0x25, 0x2d, 0xfd, 0x2e, 0x15, 0xf8, 0x9d,
+	0xee, 0x56, 0x16, 0x8c, 0xdc, 0x75, 0x23, 0x8a, 0xec, 0x30, 0x3b, 0xd0, 0xf1, 0x25, 0xe7, 0x50,
+	0x7c, 0x47, 0x24, 0x87, 0x42, 0xfc, 0x8f, 0x09, 0x93, 0x2b, 0xed, 0x36, 0x85, 0x70, 0x30, 0x8c,
+	0xc9, 0x81, 0x87, 0x9d, 0xc4, 0x39, 0xca, 0x62, 0x29, 0xcf, 0x64, 0x66, 0xde, 0x99, 0xbe, 0x94,
+	0xfa, 0xce, 0x6e, 0xc6, 0xfb, 0x38, 0xe4, 0x10, 0xb3, 0x05, 0xba, 0x0e, 0xd3, 0x64, 0xcf, 0x4a,
+	0x2f, 0xf6, 0x9b, 0xde, 0x6e, 0x88, 0x0f, 0xb1, 0x27, 0x22, 0xc9, 0x32, 0xe8, 0xa1, 0xdd, 0xdd,
+	0xdd, 0xc7, 0xf7, 0x8f, 0x03, 0x6c, 0x8d, 0xf0, 0x43, 0xbb, 0x20, 0xa0, 0xab, 0x00, 0xcd, 0xa8,
+	0x85, 0xdd, 0x6e, 0xdc, 0x39, 0xc4, 0x56, 0x99, 0x2a, 0x51, 0x28, 0xe4, 0x9c, 0xde, 0x8c, 0x1e,
+	0xb9, 0x9d, 0x98, 0x67, 0x70, 0x94, 0x4a, 0x68, 0x34, 0xfa, 0x45, 0xf1, 0xc3, 0x98, 0xe4, 0x3f,
+	0xb2, 0x80, 0x59, 0x90, 0x04, 0x5a, 0xc4, 0x04, 0xa0, 0x0a, 0x2f, 0x62, 0x82, 0xcd, 0xab, 0x30,
+	0x7e, 0xb7, 0xd3, 0x6e, 0x63, 0x4f, 0x20, 0x34, 0x46, 0x99, 0x3a, 0x91, 0x48, 0xdd, 0x73, 0xa3,
+	0x03, 0xdc, 0x16, 0x52, 0xe3, 0x4c, 0x4a, 0x23, 0xa2, 0x25, 0x98, 0x6c, 0x61, 0xb7, 0xed, 0x7b,
+	0xdd, 0x63, 0x21, 0x37, 0x41, 0xe5, 0xd2, 0x64, 0x56, 0x5b, 0x07, 0xd8, 0xb3, 0x26, 0x19, 0x9a,
+	0x74, 0xe1, 0x5c, 0x83, 0xa9, 0x24, 0xb1, 0x27, 0x7f, 0xa6, 0x97, 0xff, 0x1a, 0x83, 0x4a, 0x23,
+	0x0c, 0x76, 0xd7, 0x57, 0x77, 0x09, 0x82, 0x64, 0x34, 0x64, 0xc7, 0x25, 0x34, 0xad, 0xde, 0x7c,
+	0xd2, 0xf2, 0xb0, 0x51, 0xf6, 0x32, 0x14, 0xdd, 0x84, 0x51, 0x79, 0xbf, 0x88, 0xaa, 0x5c, 0x40,
+	0xbb, 0xc3, 0xb2, 0x67, 0x53, 0xd4, 0x64, 0x0a, 0x65, 0x87, 0x76, 0x66, 0x4a, 0xbb, 0x78, 0xb4,
+	0x91, 0x4a, 0xe2, 0x1b, 0x3e, 0x80, 0xb2, 0x78, 0xc7, 0xd0, 0x8c, 0xfa, 0xc6, 0x89, 0x4d, 0x55,
+	0x9d, 0xc8, 0xb6, 0xbd, 0x65, 0xa0, 0x15, 0x18, 0x53, 0x2f, 0x92, 0x10, 0x9d, 0x77, 0x73, 0x6e,
+	0xdb, 0x6c, 0x2b, 0xcb, 0xe0, 0xb6, 0xd7, 0x61, 0x5c, 0xa5, 0x47, 0x28, 0x23, 0x2a, 0x3e, 0xc3,
+	0x76, 0x2d, 0x87, 0x93, 0x80, 0x25, 0x6f, 0x46, 0x18, 0x58, 0xe9, 0xfb, 0x2a, 0x7b, 0x36, 0x45,
+	0x4d, 0xc0, 0x62, 0x17, 0x3f, 0x0c, 0x2c, 0xed, 0x16, 0xc9, 0x46, 0x2a, 0x89, 0x6f, 0xb8, 0x0d,
+	0x20, 0xb5, 0x44, 0x48, 0xd7, 0x2a, 0x5d, 0x9d, 0x4b, 0x93, 0xd9, 0xe6, 0x25, 0x03, 0xbd, 0x0b,
+	0x23, 0xfc, 0x46, 0x05, 0x29, 0xa9, 0x90, 0x1b, 0x67, 0x34, 0x9a, 0xdc, 0xf5, 0x29, 0x54, 0x94,
+	0x8b, 0x0f, 0x34, 0x97, 0x20, 0xa1, 0xe5, 0x69, 0x3e, 0x43, 0x57, 0x35, 0x28, 0x37, 0x18, 0x4c,
+	0x43, 0xf6, 0x72, 0xc4, 0x9e, 0xcf, 0xd0, 0xa5, 0x86, 0xcf, 0xc4, 0xff, 0x02, 0x7c, 0x10, 0x67,
+	0x99, 0xca, 0xbb, 0xe7, 0xb0, 0x6b, 0x39, 0x1c, 0xb5, 0x68, 0xd4, 0xe3, 0x36, 0x12, 0x87, 0xa4,
+	0xf4, 0x08, 0x6e, 0x5b, 0x59, 0x06, 0xcf, 0xc1, 0x26, 0xbd, 0x9a, 0xd6, 0x66, 0x45, 0x74, 0x39,
+	0xef, 0xe8, 0x29, 0x54, 0x2d, 0xe4, 0x33, 0xa5, 0x4f, 0x4d, 0xfa, 0x55, 0x54, 0x86, 0x2c, 0x54,
+	0xcb, 0x9e, 0xb8, 0x84, 0x32, 0x3b, 0x8f, 0x25, 0x55, 0x6d, 0xd3, 0xfb, 0x93, 0xd4, 0xa1, 0x05,
+	0x5d, 0xd1, 0xde, 0xa0, 0x0c, 0x60, 0x57, 0x8b, 0xd8, 0x3c, 0xe0, 0x2d, 0x98, 0x16, 0xdc, 0x04,
+	0xb8, 0x05, 0x75, 0x53, 0x06, 0xbd, 0x2b, 0x05, 0x5c, 0xae, 0xf1, 0x31, 0xcc, 0x0a, 0xa6, 0x8e,
+	0xe3, 0xa2, 0xba, 0x2f, 0x17, 0xcc, 0xff, 0x9f, 0x20, 0xc1, 0xb5, 0x3f, 0x84, 0x19, 0x21, 0xa0,
+	0x82, 0xaa, 0x85, 0x99, 0x83, 0xec, 0xff, 0x0a, 0xf9, 0x5c, 0x6f, 0x52, 0x3b, 0x74, 0x2a, 0xd4,
+	0x6a, 0x47, 0x1d, 0x52, 0x6d, 0x2b, 0xcb, 0x48, 0x1a, 0x8e, 0x36, 0x6e, 0x21, 0x55, 0x54, 0x9b,
+	0xff, 0xec, 0x5a, 0x0e, 0x87, 0x6b, 0x59, 0x86, 0x11, 0xce, 0x40, 0x48, 0x91, 0xd2, 0x5e, 0xe3,
+	0xf4, 0x70, 0xf3, 0x1e, 0x94, 0xc5, 0xe7, 0x83, 0xb5, 0xd9, 0xd4, 0x94, 0x60, 0x57, 0x75, 0x22,
+	0xdb, 0xb6, 0x33, 0x4c, 0xff, 0x9a, 0x7b, 0xe7, 0xdf, 0x01, 0x00, 0x91, 0x59, 0xfb, 0x35, 0xa8,
+	0x1b, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1321,11 +2981,29 @@ type GrpcDBcacheClient interface {
 	UpdateColumn(ctx context.Context, in *UpdateColumnRequest, opts ...grpc.CallOption) (*UpdateColumnResponse, error)
 	UpdateColumns(ctx context.Context, in *UpdateColumnsRequest, opts ...grpc.CallOption) (*UpdateColumnsResponse, error)
 	InsertRow(ctx context.Context, in *InsertRowRequest, opts ...grpc.CallOption) (*InsertRowResponse, error)
+	Upsert(ctx context.Context, in *UpsertRequest, opts ...grpc.CallOption) (*UpsertResponse, error)
+	//客户端流式 RPC,客户端发送多条请求,服务器全部接收后批量执行,返回一个响应.
+	InsertRows(ctx context.Context, opts ...grpc.CallOption) (GrpcDBcache_InsertRowsClient, error)
+	DelRows(ctx context.Context, opts ...grpc.CallOption) (GrpcDBcache_DelRowsClient, error)
+	UpdateWhere(ctx context.Context, opts ...grpc.CallOption) (GrpcDBcache_UpdateWhereClient, error)
+	DeleteWhere(ctx context.Context, opts ...grpc.CallOption) (GrpcDBcache_DeleteWhereClient, error)
 	//服务器端流式 RPC,注意关键字 stream，声明其为一个流方法。
 	GetRowBetween(ctx context.Context, in *GetRowBetweenRequest, opts ...grpc.CallOption) (GrpcDBcache_GetRowBetweenClient, error)
 	GetPageCount(ctx context.Context, in *GetPageCountRequest, opts ...grpc.CallOption) (*GetPageCountResponse, error)
 	GetMultipageRows(ctx context.Context, in *GetMultipageRowsRequest, opts ...grpc.CallOption) (GrpcDBcache_GetMultipageRowsClient, error)
 	GetOnePageRows(ctx context.Context, in *GetOnePageRowsRequest, opts ...grpc.CallOption) (GrpcDBcache_GetOnePageRowsClient, error)
+	//按where条件分页,响应中包含当前页的行,符合条件的总行数及总页数.
+	GetWhereRowBetween(ctx context.Context, in *GetWhereRowBetweenRequest, opts ...grpc.CallOption) (*GetWhereRowBetweenResponse, error)
+	GetWherePageCount(ctx context.Context, in *GetWherePageCountRequest, opts ...grpc.CallOption) (*GetWherePageCountResponse, error)
+	GetWhereMultipageRows(ctx context.Context, in *GetWhereMultipageRowsRequest, opts ...grpc.CallOption) (*GetWhereMultipageRowsResponse, error)
+	GetWhereOnePageRows(ctx context.Context, in *GetWhereOnePageRowsRequest, opts ...grpc.CallOption) (*GetWhereOnePageRowsResponse, error)
+	//游标分页,响应中包含当前页的行,下一页和上一页的游标.
+	GetPageAfter(ctx context.Context, in *GetPageAfterRequest, opts ...grpc.CallOption) (*GetPageAfterResponse, error)
+	GetPageBefore(ctx context.Context, in *GetPageBeforeRequest, opts ...grpc.CallOption) (*GetPageBeforeResponse, error)
+	//分页,响应中包含当前页的行,总行数,总页数,当前页码,是否有上一页和下一页,在同一次加锁中取得.
+	GetPage(ctx context.Context, in *GetPageRequest, opts ...grpc.CallOption) (*GetPageResponse, error)
+	//管理接口:运行中增加缓存表
+	AddTable(ctx context.Context, in *AddTableRequest, opts ...grpc.CallOption) (*AddTableResponse, error)
 }
 
 type grpcDBcacheClient struct {
@@ -1422,8 +3100,153 @@ func (c *grpcDBcacheClient) InsertRow(ctx context.Context, in *InsertRowRequest,
 	return out, nil
 }
 
+func (c *grpcDBcacheClient) Upsert(ctx context.Context, in *UpsertRequest, opts ...grpc.CallOption) (*UpsertResponse, error) {
+	out := new(UpsertResponse)
+	err := c.cc.Invoke(ctx, "/pb.GrpcDBcache/Upsert", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *grpcDBcacheClient) InsertRows(ctx context.Context, opts ...grpc.CallOption) (GrpcDBcache_InsertRowsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_GrpcDBcache_serviceDesc.Streams[1], "/pb.GrpcDBcache/InsertRows", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpcDBcacheInsertRowsClient{stream}
+	return x, nil
+}
+
+type GrpcDBcache_InsertRowsClient interface {
+	Send(*InsertRowsRequest) error
+	CloseAndRecv() (*InsertRowsResponse, error)
+	grpc.ClientStream
+}
+
+type grpcDBcacheInsertRowsClient struct {
+	grpc.ClientStream
+}
+
+func (x *grpcDBcacheInsertRowsClient) Send(m *InsertRowsRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *grpcDBcacheInsertRowsClient) CloseAndRecv() (*InsertRowsResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(InsertRowsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *grpcDBcacheClient) DelRows(ctx context.Context, opts ...grpc.CallOption) (GrpcDBcache_DelRowsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_GrpcDBcache_serviceDesc.Streams[2], "/pb.GrpcDBcache/DelRows", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpcDBcacheDelRowsClient{stream}
+	return x, nil
+}
+
+type GrpcDBcache_DelRowsClient interface {
+	Send(*DelRowsRequest) error
+	CloseAndRecv() (*DelRowsResponse, error)
+	grpc.ClientStream
+}
+
+type grpcDBcacheDelRowsClient struct {
+	grpc.ClientStream
+}
+
+func (x *grpcDBcacheDelRowsClient) Send(m *DelRowsRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *grpcDBcacheDelRowsClient) CloseAndRecv() (*DelRowsResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(DelRowsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *grpcDBcacheClient) UpdateWhere(ctx context.Context, opts ...grpc.CallOption) (GrpcDBcache_UpdateWhereClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_GrpcDBcache_serviceDesc.Streams[3], "/pb.GrpcDBcache/UpdateWhere", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpcDBcacheUpdateWhereClient{stream}
+	return x, nil
+}
+
+type GrpcDBcache_UpdateWhereClient interface {
+	Send(*UpdateWhereRequest) error
+	CloseAndRecv() (*UpdateWhereResponse, error)
+	grpc.ClientStream
+}
+
+type grpcDBcacheUpdateWhereClient struct {
+	grpc.ClientStream
+}
+
+func (x *grpcDBcacheUpdateWhereClient) Send(m *UpdateWhereRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *grpcDBcacheUpdateWhereClient) CloseAndRecv() (*UpdateWhereResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(UpdateWhereResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *grpcDBcacheClient) DeleteWhere(ctx context.Context, opts ...grpc.CallOption) (GrpcDBcache_DeleteWhereClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_GrpcDBcache_serviceDesc.Streams[4], "/pb.GrpcDBcache/DeleteWhere", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpcDBcacheDeleteWhereClient{stream}
+	return x, nil
+}
+
+type GrpcDBcache_DeleteWhereClient interface {
+	Send(*DeleteWhereRequest) error
+	CloseAndRecv() (*DeleteWhereResponse, error)
+	grpc.ClientStream
+}
+
+type grpcDBcacheDeleteWhereClient struct {
+	grpc.ClientStream
+}
+
+func (x *grpcDBcacheDeleteWhereClient) Send(m *DeleteWhereRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *grpcDBcacheDeleteWhereClient) CloseAndRecv() (*DeleteWhereResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(DeleteWhereResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *grpcDBcacheClient) GetRowBetween(ctx context.Context, in *GetRowBetweenRequest, opts ...grpc.CallOption) (GrpcDBcache_GetRowBetweenClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_GrpcDBcache_serviceDesc.Streams[1], "/pb.GrpcDBcache/GetRowBetween", opts...)
+	stream, err := c.cc.NewStream(ctx, &_GrpcDBcache_serviceDesc.Streams[5], "/pb.GrpcDBcache/GetRowBetween", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1464,7 +3287,7 @@ func (c *grpcDBcacheClient) GetPageCount(ctx context.Context, in *GetPageCountRe
 }
 
 func (c *grpcDBcacheClient) GetMultipageRows(ctx context.Context, in *GetMultipageRowsRequest, opts ...grpc.CallOption) (GrpcDBcache_GetMultipageRowsClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_GrpcDBcache_serviceDesc.Streams[2], "/pb.GrpcDBcache/GetMultipageRows", opts...)
+	stream, err := c.cc.NewStream(ctx, &_GrpcDBcache_serviceDesc.Streams[6], "/pb.GrpcDBcache/GetMultipageRows", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1492,39 +3315,111 @@ func (x *grpcDBcacheGetMultipageRowsClient) Recv() (*GetMultipageRowsResponse, e
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
-	return m, nil
+	return m, nil
+}
+
+func (c *grpcDBcacheClient) GetOnePageRows(ctx context.Context, in *GetOnePageRowsRequest, opts ...grpc.CallOption) (GrpcDBcache_GetOnePageRowsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_GrpcDBcache_serviceDesc.Streams[7], "/pb.GrpcDBcache/GetOnePageRows", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpcDBcacheGetOnePageRowsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type GrpcDBcache_GetOnePageRowsClient interface {
+	Recv() (*GetOnePageRowsResponse, error)
+	grpc.ClientStream
+}
+
+type grpcDBcacheGetOnePageRowsClient struct {
+	grpc.ClientStream
+}
+
+func (x *grpcDBcacheGetOnePageRowsClient) Recv() (*GetOnePageRowsResponse, error) {
+	m := new(GetOnePageRowsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *grpcDBcacheClient) GetWhereRowBetween(ctx context.Context, in *GetWhereRowBetweenRequest, opts ...grpc.CallOption) (*GetWhereRowBetweenResponse, error) {
+	out := new(GetWhereRowBetweenResponse)
+	err := c.cc.Invoke(ctx, "/pb.GrpcDBcache/GetWhereRowBetween", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *grpcDBcacheClient) GetWherePageCount(ctx context.Context, in *GetWherePageCountRequest, opts ...grpc.CallOption) (*GetWherePageCountResponse, error) {
+	out := new(GetWherePageCountResponse)
+	err := c.cc.Invoke(ctx, "/pb.GrpcDBcache/GetWherePageCount", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func (c *grpcDBcacheClient) GetOnePageRows(ctx context.Context, in *GetOnePageRowsRequest, opts ...grpc.CallOption) (GrpcDBcache_GetOnePageRowsClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_GrpcDBcache_serviceDesc.Streams[3], "/pb.GrpcDBcache/GetOnePageRows", opts...)
+func (c *grpcDBcacheClient) GetWhereMultipageRows(ctx context.Context, in *GetWhereMultipageRowsRequest, opts ...grpc.CallOption) (*GetWhereMultipageRowsResponse, error) {
+	out := new(GetWhereMultipageRowsResponse)
+	err := c.cc.Invoke(ctx, "/pb.GrpcDBcache/GetWhereMultipageRows", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &grpcDBcacheGetOnePageRowsClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
+	return out, nil
+}
+
+func (c *grpcDBcacheClient) GetWhereOnePageRows(ctx context.Context, in *GetWhereOnePageRowsRequest, opts ...grpc.CallOption) (*GetWhereOnePageRowsResponse, error) {
+	out := new(GetWhereOnePageRowsResponse)
+	err := c.cc.Invoke(ctx, "/pb.GrpcDBcache/GetWhereOnePageRows", in, out, opts...)
+	if err != nil {
 		return nil, err
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
+	return out, nil
+}
+
+func (c *grpcDBcacheClient) GetPageAfter(ctx context.Context, in *GetPageAfterRequest, opts ...grpc.CallOption) (*GetPageAfterResponse, error) {
+	out := new(GetPageAfterResponse)
+	err := c.cc.Invoke(ctx, "/pb.GrpcDBcache/GetPageAfter", in, out, opts...)
+	if err != nil {
 		return nil, err
 	}
-	return x, nil
+	return out, nil
 }
 
-type GrpcDBcache_GetOnePageRowsClient interface {
-	Recv() (*GetOnePageRowsResponse, error)
-	grpc.ClientStream
+func (c *grpcDBcacheClient) GetPageBefore(ctx context.Context, in *GetPageBeforeRequest, opts ...grpc.CallOption) (*GetPageBeforeResponse, error) {
+	out := new(GetPageBeforeResponse)
+	err := c.cc.Invoke(ctx, "/pb.GrpcDBcache/GetPageBefore", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-type grpcDBcacheGetOnePageRowsClient struct {
-	grpc.ClientStream
+func (c *grpcDBcacheClient) GetPage(ctx context.Context, in *GetPageRequest, opts ...grpc.CallOption) (*GetPageResponse, error) {
+	out := new(GetPageResponse)
+	err := c.cc.Invoke(ctx, "/pb.GrpcDBcache/GetPage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func (x *grpcDBcacheGetOnePageRowsClient) Recv() (*GetOnePageRowsResponse, error) {
-	m := new(GetOnePageRowsResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
+func (c *grpcDBcacheClient) AddTable(ctx context.Context, in *AddTableRequest, opts ...grpc.CallOption) (*AddTableResponse, error) {
+	out := new(AddTableResponse)
+	err := c.cc.Invoke(ctx, "/pb.GrpcDBcache/AddTable", in, out, opts...)
+	if err != nil {
 		return nil, err
 	}
-	return m, nil
+	return out, nil
 }
 
 // GrpcDBcacheServer is the server API for GrpcDBcache service.
@@ -1537,11 +3432,29 @@ type GrpcDBcacheServer interface {
 	UpdateColumn(context.Context, *UpdateColumnRequest) (*UpdateColumnResponse, error)
 	UpdateColumns(context.Context, *UpdateColumnsRequest) (*UpdateColumnsResponse, error)
 	InsertRow(context.Context, *InsertRowRequest) (*InsertRowResponse, error)
+	Upsert(context.Context, *UpsertRequest) (*UpsertResponse, error)
+	//客户端流式 RPC,客户端发送多条请求,服务器全部接收后批量执行,返回一个响应.
+	InsertRows(GrpcDBcache_InsertRowsServer) error
+	DelRows(GrpcDBcache_DelRowsServer) error
+	UpdateWhere(GrpcDBcache_UpdateWhereServer) error
+	DeleteWhere(GrpcDBcache_DeleteWhereServer) error
 	//服务器端流式 RPC,注意关键字 stream，声明其为一个流方法。
 	GetRowBetween(*GetRowBetweenRequest, GrpcDBcache_GetRowBetweenServer) error
 	GetPageCount(context.Context, *GetPageCountRequest) (*GetPageCountResponse, error)
 	GetMultipageRows(*GetMultipageRowsRequest, GrpcDBcache_GetMultipageRowsServer) error
 	GetOnePageRows(*GetOnePageRowsRequest, GrpcDBcache_GetOnePageRowsServer) error
+	//按where条件分页,响应中包含当前页的行,符合条件的总行数及总页数.
+	GetWhereRowBetween(context.Context, *GetWhereRowBetweenRequest) (*GetWhereRowBetweenResponse, error)
+	GetWherePageCount(context.Context, *GetWherePageCountRequest) (*GetWherePageCountResponse, error)
+	GetWhereMultipageRows(context.Context, *GetWhereMultipageRowsRequest) (*GetWhereMultipageRowsResponse, error)
+	GetWhereOnePageRows(context.Context, *GetWhereOnePageRowsRequest) (*GetWhereOnePageRowsResponse, error)
+	//游标分页,响应中包含当前页的行,下一页和上一页的游标.
+	GetPageAfter(context.Context, *GetPageAfterRequest) (*GetPageAfterResponse, error)
+	GetPageBefore(context.Context, *GetPageBeforeRequest) (*GetPageBeforeResponse, error)
+	//分页,响应中包含当前页的行,总行数,总页数,当前页码,是否有上一页和下一页,在同一次加锁中取得.
+	GetPage(context.Context, *GetPageRequest) (*GetPageResponse, error)
+	//管理接口:运行中增加缓存表
+	AddTable(context.Context, *AddTableRequest) (*AddTableResponse, error)
 }
 
 // UnimplementedGrpcDBcacheServer can be embedded to have forward compatible implementations.
@@ -1569,6 +3482,21 @@ func (*UnimplementedGrpcDBcacheServer) UpdateColumns(ctx context.Context, req *U
 func (*UnimplementedGrpcDBcacheServer) InsertRow(ctx context.Context, req *InsertRowRequest) (*InsertRowResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method InsertRow not implemented")
 }
+func (*UnimplementedGrpcDBcacheServer) Upsert(ctx context.Context, req *UpsertRequest) (*UpsertResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Upsert not implemented")
+}
+func (*UnimplementedGrpcDBcacheServer) InsertRows(srv GrpcDBcache_InsertRowsServer) error {
+	return status.Errorf(codes.Unimplemented, "method InsertRows not implemented")
+}
+func (*UnimplementedGrpcDBcacheServer) DelRows(srv GrpcDBcache_DelRowsServer) error {
+	return status.Errorf(codes.Unimplemented, "method DelRows not implemented")
+}
+func (*UnimplementedGrpcDBcacheServer) UpdateWhere(srv GrpcDBcache_UpdateWhereServer) error {
+	return status.Errorf(codes.Unimplemented, "method UpdateWhere not implemented")
+}
+func (*UnimplementedGrpcDBcacheServer) DeleteWhere(srv GrpcDBcache_DeleteWhereServer) error {
+	return status.Errorf(codes.Unimplemented, "method DeleteWhere not implemented")
+}
 func (*UnimplementedGrpcDBcacheServer) GetRowBetween(req *GetRowBetweenRequest, srv GrpcDBcache_GetRowBetweenServer) error {
 	return status.Errorf(codes.Unimplemented, "method GetRowBetween not implemented")
 }
@@ -1581,6 +3509,30 @@ func (*UnimplementedGrpcDBcacheServer) GetMultipageRows(req *GetMultipageRowsReq
 func (*UnimplementedGrpcDBcacheServer) GetOnePageRows(req *GetOnePageRowsRequest, srv GrpcDBcache_GetOnePageRowsServer) error {
 	return status.Errorf(codes.Unimplemented, "method GetOnePageRows not implemented")
 }
+func (*UnimplementedGrpcDBcacheServer) GetWhereRowBetween(ctx context.Context, req *GetWhereRowBetweenRequest) (*GetWhereRowBetweenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWhereRowBetween not implemented")
+}
+func (*UnimplementedGrpcDBcacheServer) GetWherePageCount(ctx context.Context, req *GetWherePageCountRequest) (*GetWherePageCountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWherePageCount not implemented")
+}
+func (*UnimplementedGrpcDBcacheServer) GetWhereMultipageRows(ctx context.Context, req *GetWhereMultipageRowsRequest) (*GetWhereMultipageRowsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWhereMultipageRows not implemented")
+}
+func (*UnimplementedGrpcDBcacheServer) GetWhereOnePageRows(ctx context.Context, req *GetWhereOnePageRowsRequest) (*GetWhereOnePageRowsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWhereOnePageRows not implemented")
+}
+func (*UnimplementedGrpcDBcacheServer) GetPageAfter(ctx context.Context, req *GetPageAfterRequest) (*GetPageAfterResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPageAfter not implemented")
+}
+func (*UnimplementedGrpcDBcacheServer) GetPageBefore(ctx context.Context, req *GetPageBeforeRequest) (*GetPageBeforeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPageBefore not implemented")
+}
+func (*UnimplementedGrpcDBcacheServer) GetPage(ctx context.Context, req *GetPageRequest) (*GetPageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPage not implemented")
+}
+func (*UnimplementedGrpcDBcacheServer) AddTable(ctx context.Context, req *AddTableRequest) (*AddTableResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddTable not implemented")
+}
 
 func RegisterGrpcDBcacheServer(s *grpc.Server, srv GrpcDBcacheServer) {
 	s.RegisterService(&_GrpcDBcache_serviceDesc, srv)
@@ -1715,6 +3667,128 @@ func _GrpcDBcache_InsertRow_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _GrpcDBcache_Upsert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpsertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GrpcDBcacheServer).Upsert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.GrpcDBcache/Upsert",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GrpcDBcacheServer).Upsert(ctx, req.(*UpsertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GrpcDBcache_InsertRows_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(GrpcDBcacheServer).InsertRows(&grpcDBcacheInsertRowsServer{stream})
+}
+
+type GrpcDBcache_InsertRowsServer interface {
+	SendAndClose(*InsertRowsResponse) error
+	Recv() (*InsertRowsRequest, error)
+	grpc.ServerStream
+}
+
+type grpcDBcacheInsertRowsServer struct {
+	grpc.ServerStream
+}
+
+func (x *grpcDBcacheInsertRowsServer) SendAndClose(m *InsertRowsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *grpcDBcacheInsertRowsServer) Recv() (*InsertRowsRequest, error) {
+	m := new(InsertRowsRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _GrpcDBcache_DelRows_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(GrpcDBcacheServer).DelRows(&grpcDBcacheDelRowsServer{stream})
+}
+
+type GrpcDBcache_DelRowsServer interface {
+	SendAndClose(*DelRowsResponse) error
+	Recv() (*DelRowsRequest, error)
+	grpc.ServerStream
+}
+
+type grpcDBcacheDelRowsServer struct {
+	grpc.ServerStream
+}
+
+func (x *grpcDBcacheDelRowsServer) SendAndClose(m *DelRowsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *grpcDBcacheDelRowsServer) Recv() (*DelRowsRequest, error) {
+	m := new(DelRowsRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _GrpcDBcache_UpdateWhere_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(GrpcDBcacheServer).UpdateWhere(&grpcDBcacheUpdateWhereServer{stream})
+}
+
+type GrpcDBcache_UpdateWhereServer interface {
+	SendAndClose(*UpdateWhereResponse) error
+	Recv() (*UpdateWhereRequest, error)
+	grpc.ServerStream
+}
+
+type grpcDBcacheUpdateWhereServer struct {
+	grpc.ServerStream
+}
+
+func (x *grpcDBcacheUpdateWhereServer) SendAndClose(m *UpdateWhereResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *grpcDBcacheUpdateWhereServer) Recv() (*UpdateWhereRequest, error) {
+	m := new(UpdateWhereRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _GrpcDBcache_DeleteWhere_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(GrpcDBcacheServer).DeleteWhere(&grpcDBcacheDeleteWhereServer{stream})
+}
+
+type GrpcDBcache_DeleteWhereServer interface {
+	SendAndClose(*DeleteWhereResponse) error
+	Recv() (*DeleteWhereRequest, error)
+	grpc.ServerStream
+}
+
+type grpcDBcacheDeleteWhereServer struct {
+	grpc.ServerStream
+}
+
+func (x *grpcDBcacheDeleteWhereServer) SendAndClose(m *DeleteWhereResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *grpcDBcacheDeleteWhereServer) Recv() (*DeleteWhereRequest, error) {
+	m := new(DeleteWhereRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func _GrpcDBcache_GetRowBetween_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(GetRowBetweenRequest)
 	if err := stream.RecvMsg(m); err != nil {
@@ -1796,6 +3870,150 @@ func (x *grpcDBcacheGetOnePageRowsServer) Send(m *GetOnePageRowsResponse) error
 	return x.ServerStream.SendMsg(m)
 }
 
+func _GrpcDBcache_GetWhereRowBetween_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWhereRowBetweenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GrpcDBcacheServer).GetWhereRowBetween(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.GrpcDBcache/GetWhereRowBetween",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GrpcDBcacheServer).GetWhereRowBetween(ctx, req.(*GetWhereRowBetweenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GrpcDBcache_GetWherePageCount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWherePageCountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GrpcDBcacheServer).GetWherePageCount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.GrpcDBcache/GetWherePageCount",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GrpcDBcacheServer).GetWherePageCount(ctx, req.(*GetWherePageCountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GrpcDBcache_GetWhereMultipageRows_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWhereMultipageRowsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GrpcDBcacheServer).GetWhereMultipageRows(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.GrpcDBcache/GetWhereMultipageRows",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GrpcDBcacheServer).GetWhereMultipageRows(ctx, req.(*GetWhereMultipageRowsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GrpcDBcache_GetWhereOnePageRows_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWhereOnePageRowsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GrpcDBcacheServer).GetWhereOnePageRows(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.GrpcDBcache/GetWhereOnePageRows",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GrpcDBcacheServer).GetWhereOnePageRows(ctx, req.(*GetWhereOnePageRowsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GrpcDBcache_GetPageAfter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPageAfterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GrpcDBcacheServer).GetPageAfter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.GrpcDBcache/GetPageAfter",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GrpcDBcacheServer).GetPageAfter(ctx, req.(*GetPageAfterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GrpcDBcache_GetPageBefore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPageBeforeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GrpcDBcacheServer).GetPageBefore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.GrpcDBcache/GetPageBefore",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GrpcDBcacheServer).GetPageBefore(ctx, req.(*GetPageBeforeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GrpcDBcache_GetPage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GrpcDBcacheServer).GetPage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.GrpcDBcache/GetPage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GrpcDBcacheServer).GetPage(ctx, req.(*GetPageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GrpcDBcache_AddTable_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddTableRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GrpcDBcacheServer).AddTable(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.GrpcDBcache/AddTable",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GrpcDBcacheServer).AddTable(ctx, req.(*AddTableRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _GrpcDBcache_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "pb.GrpcDBcache",
 	HandlerType: (*GrpcDBcacheServer)(nil),
@@ -1824,10 +4042,46 @@ var _GrpcDBcache_serviceDesc = grpc.ServiceDesc{
 			MethodName: "InsertRow",
 			Handler:    _GrpcDBcache_InsertRow_Handler,
 		},
+		{
+			MethodName: "Upsert",
+			Handler:    _GrpcDBcache_Upsert_Handler,
+		},
 		{
 			MethodName: "GetPageCount",
 			Handler:    _GrpcDBcache_GetPageCount_Handler,
 		},
+		{
+			MethodName: "GetWhereRowBetween",
+			Handler:    _GrpcDBcache_GetWhereRowBetween_Handler,
+		},
+		{
+			MethodName: "GetWherePageCount",
+			Handler:    _GrpcDBcache_GetWherePageCount_Handler,
+		},
+		{
+			MethodName: "GetWhereMultipageRows",
+			Handler:    _GrpcDBcache_GetWhereMultipageRows_Handler,
+		},
+		{
+			MethodName: "GetWhereOnePageRows",
+			Handler:    _GrpcDBcache_GetWhereOnePageRows_Handler,
+		},
+		{
+			MethodName: "GetPageAfter",
+			Handler:    _GrpcDBcache_GetPageAfter_Handler,
+		},
+		{
+			MethodName: "GetPageBefore",
+			Handler:    _GrpcDBcache_GetPageBefore_Handler,
+		},
+		{
+			MethodName: "GetPage",
+			Handler:    _GrpcDBcache_GetPage_Handler,
+		},
+		{
+			MethodName: "AddTable",
+			Handler:    _GrpcDBcache_AddTable_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -1835,6 +4089,26 @@ var _GrpcDBcache_serviceDesc = grpc.ServiceDesc{
 			Handler:       _GrpcDBcache_GetWhere_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "InsertRows",
+			Handler:       _GrpcDBcache_InsertRows_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "DelRows",
+			Handler:       _GrpcDBcache_DelRows_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "UpdateWhere",
+			Handler:       _GrpcDBcache_UpdateWhere_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "DeleteWhere",
+			Handler:       _GrpcDBcache_DeleteWhere_Handler,
+			ClientStreams: true,
+		},
 		{
 			StreamName:    "GetRowBetween",
 			Handler:       _GrpcDBcache_GetRowBetween_Handler,
@@ -50,7 +50,7 @@ func main(){
 	fmt.Println("四. 根据where条件,查询缓存中所有符合条件的行.")
 	var value []map[string]string
 	//value ,_ = dbcache.GetWhere("name=AFA5Y9FB or password=Q80BJT")
-	value, err = rpcClient.GetWhere("users","address=重庆 and password=888888")
+	value, err = rpcClient.GetWhere("users","address=重庆 and name=jth")
 	if err != nil {
 		fmt.Println(err)
 	}
@@ -86,7 +86,7 @@ func main(){
 	//七. InsertRow():插入一行数据
 	fmt.Printf("七. InsertRow().插入一行数据\n")
 	insert := "uid=22222211117,name=jth,address=重庆,password=888888,age=9999993,price=66.123456,create_date=2020-02-02 02:02:02,update_date=2020-01-01 01:01:01"
-	n, err = rpcClient.InsertRow("users",insert)
+	n, _, err = rpcClient.InsertRow("users",insert)
 	if err != nil {
 		fmt.Println(  "插入错误, err:", err)
 	}
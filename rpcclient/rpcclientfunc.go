@@ -11,6 +11,11 @@ const RpcServiceName="DBcache"
 
 type DBcacheRpcClient struct{
 	Conn *rpc.Client
+	View string //分页查询使用的排序视图名,为空时按表的默认排序
+}
+//返回使用指定排序视图分页查询的客户端,与原客户端共用连接.
+func (d *DBcacheRpcClient)WithView(view string)(*DBcacheRpcClient){
+	return &DBcacheRpcClient{d.Conn, view}
 }
 //初始化连接Rpc服务.
 func InitRpc(rpcType string,protocol string,address string)(rpcClient *DBcacheRpcClient,err error){
@@ -20,7 +25,7 @@ func InitRpc(rpcType string,protocol string,address string)(rpcClient *DBcacheRp
 			err:=fmt.Errorf("InitRpc() rpc error:%s", err)
 			return nil,err
 		}
-		return &DBcacheRpcClient{client},nil
+		return &DBcacheRpcClient{client,""},nil
 	}else if rpcType=="jsonrpc"{
 		conn, err := net.Dial(protocol, address)
 		if err != nil {
@@ -28,7 +33,7 @@ func InitRpc(rpcType string,protocol string,address string)(rpcClient *DBcacheRp
 			return nil,err
 		}
         client := jsonrpc.NewClient(conn)
-		return &DBcacheRpcClient{client},nil
+		return &DBcacheRpcClient{client,""},nil
 	}
 	return nil,nil
 }
@@ -161,13 +166,112 @@ type InsertRowRequest struct{
 }
 type InsertRowResponse struct{
 	Result int64
+	LastInsertId int64 //自增主键值
 }
-func (d *DBcacheRpcClient)InsertRow(tableName string,condition string) (n int64, err error){
+func (d *DBcacheRpcClient)InsertRow(tableName string,condition string) (n int64, lastInsertId int64, err error){
 	req := InsertRowRequest{tableName, condition}
 	resp:= InsertRowResponse{}
 	err = d.Conn.Call(RpcServiceName+".InsertRow", req, &resp)
 	if err != nil {
 		err=fmt.Errorf("InsertRow() rpc error: %s", err)
+		return 0,0,err
+	}
+	return resp.Result,resp.LastInsertId,nil
+}
+
+//--------------Upsert()---------------------------------
+type UpsertRequest struct{
+	TableName string
+	Row string
+}
+type UpsertResponse struct{
+	Result int64
+	IsInsert bool //true:插入,false:更新
+	IsKnown bool //IsInsert是否确定
+}
+func (d *DBcacheRpcClient)Upsert(tableName string,row string) (n int64, isInsert bool, isKnown bool, err error){
+	req := UpsertRequest{tableName, row}
+	resp:= UpsertResponse{}
+	err = d.Conn.Call(RpcServiceName+".Upsert", req, &resp)
+	if err != nil {
+		err=fmt.Errorf("Upsert() rpc error: %s", err)
+		return 0,false,false,err
+	}
+	return resp.Result,resp.IsInsert,resp.IsKnown,nil
+}
+
+//--------------InsertRows()---------------------------------
+type InsertRowsRequest struct{
+	TableName string
+	Rows []string
+}
+type InsertRowsResponse struct{
+	Result int64
+}
+func (d *DBcacheRpcClient)InsertRows(tableName string,rows []string) (n int64, err error){
+	req := InsertRowsRequest{tableName, rows}
+	resp:= InsertRowsResponse{}
+	err = d.Conn.Call(RpcServiceName+".InsertRows", req, &resp)
+	if err != nil {
+		err=fmt.Errorf("InsertRows() rpc error: %s", err)
+		return 0,err
+	}
+	return resp.Result,nil
+}
+
+//--------------DelRows()---------------------------------
+type DelRowsRequest struct{
+	TableName string
+	Pkeys []string
+}
+type DelRowsResponse struct{
+	Result int64
+}
+func (d *DBcacheRpcClient)DelRows(tableName string,pkeys []string) (n int64, err error){
+	req := DelRowsRequest{tableName, pkeys}
+	resp:= DelRowsResponse{}
+	err = d.Conn.Call(RpcServiceName+".DelRows", req, &resp)
+	if err != nil {
+		err=fmt.Errorf("DelRows() rpc error: %s", err)
+		return 0,err
+	}
+	return resp.Result,nil
+}
+
+//--------------UpdateWhere()---------------------------------
+type UpdateWhereRequest struct{
+	TableName string
+	Where string
+	Changes string
+}
+type UpdateWhereResponse struct{
+	Result int64
+}
+func (d *DBcacheRpcClient)UpdateWhere(tableName string,where string,changes string) (n int64, err error){
+	req := UpdateWhereRequest{tableName, where, changes}
+	resp:= UpdateWhereResponse{}
+	err = d.Conn.Call(RpcServiceName+".UpdateWhere", req, &resp)
+	if err != nil {
+		err=fmt.Errorf("UpdateWhere() rpc error: %s", err)
+		return 0,err
+	}
+	return resp.Result,nil
+}
+
+//--------------DeleteWhere()---------------------------------
+type DeleteWhereRequest struct{
+	TableName string
+	Where string
+}
+type DeleteWhereResponse struct{
+	Result int64
+}
+func (d *DBcacheRpcClient)DeleteWhere(tableName string,where string) (n int64, err error){
+	req := DeleteWhereRequest{tableName, where}
+	resp:= DeleteWhereResponse{}
+	err = d.Conn.Call(RpcServiceName+".DeleteWhere", req, &resp)
+	if err != nil {
+		err=fmt.Errorf("DeleteWhere() rpc error: %s", err)
 		return 0,err
 	}
 	return resp.Result,nil
@@ -178,12 +282,13 @@ type GetRowBetweenRequest struct{
 	TableName string
 	Start int
 	End int
+	View string //排序视图名,为空时按表的默认排序
 }
 type GetRowBetweenResponse struct{
 	Result []map[string]string
 }
 func (d *DBcacheRpcClient)GetRowBetween(tableName string,start int,end int) (result []map[string]string, err error){
-	req := GetRowBetweenRequest{tableName, start,end}
+	req := GetRowBetweenRequest{tableName, start,end, d.View}
 	resp:= GetRowBetweenResponse{make([]map[string]string,0)}
 	err = d.Conn.Call(RpcServiceName+".GetRowBetween", req, &resp)
 	if err != nil {
@@ -197,12 +302,13 @@ func (d *DBcacheRpcClient)GetRowBetween(tableName string,start int,end int) (res
 type GetPageCountRequest struct{
 	TableName string
 	PageSize int
+	View string //排序视图名,为空时按表的默认排序
 }
 type GetPageCountResponse struct{
 	Result int
 }
 func (d *DBcacheRpcClient)GetPageCount(tableName string,pageSize int) (pageCount int,err error){
-	req := GetPageCountRequest{tableName, pageSize}
+	req := GetPageCountRequest{tableName, pageSize, d.View}
 	resp:= GetPageCountResponse{0}
 	err = d.Conn.Call(RpcServiceName+".GetPageCount", req, &resp)
 	if err != nil {
@@ -218,12 +324,13 @@ type GetMultipageRowsRequest struct{
 	StartPage int
 	PageNum int
 	PageSize int
+	View string //排序视图名,为空时按表的默认排序
 }
 type GetMultipageRowsResponse struct{
 	Result []map[string]string
 }
 func (d *DBcacheRpcClient)GetMultipageRows(tableName string,startPage int,pageNum int,pageSize int) (result []map[string]string, err error){
-	req := GetMultipageRowsRequest{tableName, startPage,pageNum,pageSize}
+	req := GetMultipageRowsRequest{tableName, startPage,pageNum,pageSize, d.View}
 	resp:= GetMultipageRowsResponse{make([]map[string]string,0)}
 	err = d.Conn.Call(RpcServiceName+".GetMultipageRows", req, &resp)
 	if err != nil {
@@ -238,12 +345,13 @@ type GetOnePageRowsRequest struct{
 	TableName string
 	Page int
 	PageSize int
+	View string //排序视图名,为空时按表的默认排序
 }
 type GetOnePageRowsResponse struct{
 	Result []map[string]string
 }
 func (d *DBcacheRpcClient)GetOnePageRows(tableName string,page int,pageSize int) (result []map[string]string, err error){
-	req := GetOnePageRowsRequest{tableName, page,pageSize}
+	req := GetOnePageRowsRequest{tableName, page,pageSize, d.View}
 	resp:= GetOnePageRowsResponse{make([]map[string]string,0)}
 	err = d.Conn.Call(RpcServiceName+".GetOnePageRows", req, &resp)
 	if err != nil {
@@ -251,4 +359,208 @@ func (d *DBcacheRpcClient)GetOnePageRows(tableName string,page int,pageSize int)
 		return nil,err
 	}
 	return resp.Result,nil
-}
\ No newline at end of file
+}
+//--------------GetWhereRowBetween()---------------------------------
+type GetWhereRowBetweenRequest struct{
+	TableName string
+	Where string
+	Start int
+	End int
+	View string //排序视图名,为空时按表的默认排序
+}
+type GetWhereRowBetweenResponse struct{
+	Result []map[string]string
+	Total int //符合条件的总行数
+}
+func (d *DBcacheRpcClient)GetWhereRowBetween(tableName string,where string,start int,end int) (result []map[string]string, total int, err error){
+	req := GetWhereRowBetweenRequest{tableName, where, start, end, d.View}
+	resp:= GetWhereRowBetweenResponse{make([]map[string]string,0),0}
+	err = d.Conn.Call(RpcServiceName+".GetWhereRowBetween", req, &resp)
+	if err != nil {
+		err=fmt.Errorf("GetWhereRowBetween() rpc error: %s", err)
+		return nil,0,err
+	}
+	return resp.Result,resp.Total,nil
+}
+
+//--------------GetWherePageCount()---------------------------------
+type GetWherePageCountRequest struct{
+	TableName string
+	Where string
+	PageSize int
+	View string //排序视图名,为空时按表的默认排序
+}
+type GetWherePageCountResponse struct{
+	Result int
+	Total int //符合条件的总行数
+}
+func (d *DBcacheRpcClient)GetWherePageCount(tableName string,where string,pageSize int) (pageCount int, total int, err error){
+	req := GetWherePageCountRequest{tableName, where, pageSize, d.View}
+	resp:= GetWherePageCountResponse{}
+	err = d.Conn.Call(RpcServiceName+".GetWherePageCount", req, &resp)
+	if err != nil {
+		err=fmt.Errorf("GetWherePageCount() rpc error: %s", err)
+		return 0,0,err
+	}
+	return resp.Result,resp.Total,nil
+}
+
+//--------------GetWhereMultipageRows()---------------------------------
+type GetWhereMultipageRowsRequest struct{
+	TableName string
+	Where string
+	StartPage int
+	PageNum int
+	PageSize int
+	View string //排序视图名,为空时按表的默认排序
+}
+type GetWhereMultipageRowsResponse struct{
+	Result []map[string]string
+	Total int //符合条件的总行数
+	PageCount int //总页数
+}
+func (d *DBcacheRpcClient)GetWhereMultipageRows(tableName string,where string,startPage int,pageNum int,pageSize int) (result []map[string]string, total int, pageCount int, err error){
+	req := GetWhereMultipageRowsRequest{tableName, where, startPage, pageNum, pageSize, d.View}
+	resp:= GetWhereMultipageRowsResponse{Result:make([]map[string]string,0)}
+	err = d.Conn.Call(RpcServiceName+".GetWhereMultipageRows", req, &resp)
+	if err != nil {
+		err=fmt.Errorf("GetWhereMultipageRows() rpc error: %s", err)
+		return nil,0,0,err
+	}
+	return resp.Result,resp.Total,resp.PageCount,nil
+}
+
+//--------------GetWhereOnePageRows()---------------------------------
+type GetWhereOnePageRowsRequest struct{
+	TableName string
+	Where string
+	Page int
+	PageSize int
+	View string //排序视图名,为空时按表的默认排序
+}
+type GetWhereOnePageRowsResponse struct{
+	Result []map[string]string
+	Total int //符合条件的总行数
+	PageCount int //总页数
+}
+func (d *DBcacheRpcClient)GetWhereOnePageRows(tableName string,where string,page int,pageSize int) (result []map[string]string, total int, pageCount int, err error){
+	req := GetWhereOnePageRowsRequest{tableName, where, page, pageSize, d.View}
+	resp:= GetWhereOnePageRowsResponse{Result:make([]map[string]string,0)}
+	err = d.Conn.Call(RpcServiceName+".GetWhereOnePageRows", req, &resp)
+	if err != nil {
+		err=fmt.Errorf("GetWhereOnePageRows() rpc error: %s", err)
+		return nil,0,0,err
+	}
+	return resp.Result,resp.Total,resp.PageCount,nil
+}
+
+//--------------GetPageAfter()---------------------------------
+type GetPageAfterRequest struct{
+	TableName string
+	Cursor string
+	PageSize int
+	View string //排序视图名,为空时按表的默认排序
+}
+type GetPageAfterResponse struct{
+	Result []map[string]string
+	Next string //下一页游标
+	Prev string //上一页游标
+}
+//参数说明:cursor:游标,为空时从第一行开始.返回的next传给GetPageAfter()获取下一页,prev传给GetPageBefore()获取上一页.
+func (d *DBcacheRpcClient)GetPageAfter(tableName string,cursor string,pageSize int) (result []map[string]string, next string, prev string, err error){
+	req := GetPageAfterRequest{tableName, cursor, pageSize, d.View}
+	resp:= GetPageAfterResponse{Result:make([]map[string]string,0)}
+	err = d.Conn.Call(RpcServiceName+".GetPageAfter", req, &resp)
+	if err != nil {
+		err=fmt.Errorf("GetPageAfter() rpc error: %s", err)
+		return nil,"","",err
+	}
+	return resp.Result,resp.Next,resp.Prev,nil
+}
+
+//--------------GetPageBefore()---------------------------------
+type GetPageBeforeRequest struct{
+	TableName string
+	Cursor string
+	PageSize int
+	View string //排序视图名,为空时按表的默认排序
+}
+type GetPageBeforeResponse struct{
+	Result []map[string]string
+	Next string //下一页游标
+	Prev string //上一页游标
+}
+//参数说明:cursor:游标,为空时获取最后一页.
+func (d *DBcacheRpcClient)GetPageBefore(tableName string,cursor string,pageSize int) (result []map[string]string, next string, prev string, err error){
+	req := GetPageBeforeRequest{tableName, cursor, pageSize, d.View}
+	resp:= GetPageBeforeResponse{Result:make([]map[string]string,0)}
+	err = d.Conn.Call(RpcServiceName+".GetPageBefore", req, &resp)
+	if err != nil {
+		err=fmt.Errorf("GetPageBefore() rpc error: %s", err)
+		return nil,"","",err
+	}
+	return resp.Result,resp.Next,resp.Prev,nil
+}
+
+//--------------GetPage()---------------------------------
+type GetPageRequest struct{
+	TableName string
+	Where string //条件表达式,为空时不过滤
+	Page int
+	PageSize int
+	View string //排序视图名,为空时按表的默认排序
+}
+type GetPageResponse struct{
+	Result []map[string]string
+	Total int //总行数(有where条件时是符合条件的总行数)
+	PageCount int //总页数
+	Page int //当前页码
+	PageSize int //每页行数
+	HasNext bool //是否有下一页
+	HasPrev bool //是否有上一页
+}
+//参数说明:where:条件表达式,为空时不过滤,page参数是页码,pageSize参数是每页行数大小.
+//返回当前页的行,总行数,总页数,当前页码,是否有上一页和下一页,在服务端同一次加锁中取得.
+func (d *DBcacheRpcClient)GetPage(tableName string,where string,page int,pageSize int) (result *GetPageResponse, err error){
+	req := GetPageRequest{tableName, where, page, pageSize, d.View}
+	resp:= GetPageResponse{Result:make([]map[string]string,0)}
+	err = d.Conn.Call(RpcServiceName+".GetPage", req, &resp)
+	if err != nil {
+		err=fmt.Errorf("GetPage() rpc error: %s", err)
+		return nil,err
+	}
+	return &resp,nil
+}
+
+//--------------AddTable()---------------------------------
+//管理接口:运行中增加缓存表.服务端需配置admin_token.
+type AddTableRequest struct{
+	Token string //管理接口的token,与服务端config.conf中[RpcServer]的admin_token相同
+	Name string //缓存表的名称(cache.conf中的分段名),为空时是表名
+	TableName string
+	Columns string //缓存的多列,以逗号隔开
+	Pkey string
+	Where string
+	Other string //排序条件,例如:order by price asc
+	PkeyAutoIncrement bool
+	CacheType string //slice,sliceNotDel,link,tree
+	IsRealtime bool
+	IsWaitResult bool
+	SortViews string
+	HiddenColumns string //隐藏的列,以逗号隔开
+	MaskedColumns string //脱敏的列,以逗号隔开,格式:列名[:显示末尾字符数]
+	ReadonlyColumns string //只读的列,以逗号隔开
+}
+type AddTableResponse struct{
+	Result int64 //加载的总行数
+}
+//服务端把表的配置写入cache.conf并从数据库加载,返回加载的总行数.表配置错误,表已缓存或加载失败时返回错误.
+func (d *DBcacheRpcClient)AddTable(req AddTableRequest) (rowCount int64, err error){
+	resp:= AddTableResponse{0}
+	err = d.Conn.Call(RpcServiceName+".AddTable", req, &resp)
+	if err != nil {
+		err=fmt.Errorf("AddTable() rpc error: %s", err)
+		return 0,err
+	}
+	return resp.Result,nil
+}
@@ -2,10 +2,23 @@ package rpcserver
 
 import (
 	"dbcache/cache"
+	"dbcache/conf"
 	"fmt"
 )
 
 type DBcache struct{
+	instance *cache.Instance //缓存实例,按表名查找缓存表
+	adminToken string //管理接口(AddTable)的token,为空时管理接口关闭
+}
+
+//根据表名和视图名,获取用于分页查询的排序视图.视图名为空时,返回表的默认视图.
+func (g *DBcache)getView(tableName string,viewName string)(view *cache.SortView,err error){
+	cacheObj,ok := g.instance.GetCacheObj(tableName)
+	if !ok{
+		err = fmt.Errorf("%s,The Table is not cache.",tableName)
+		return nil,err
+	}
+	return cacheObj.View(viewName)
 }
 
 //--------------GetRow()---------------------------------
@@ -20,7 +33,7 @@ type GetRowResponse struct{
 }
 //GetRow()
 func (g *DBcache)GetRow(req GetRowRequest,resp *GetRowResponse)(err error){
-	cacheObj,ok := cache.CacheObj[req.TableName]
+	cacheObj,ok := g.instance.GetCacheObj(req.TableName)
 	if !ok{
 		err = fmt.Errorf("%s,The Table is not cache.",req.TableName)
 		return err
@@ -29,7 +42,7 @@ func (g *DBcache)GetRow(req GetRowRequest,resp *GetRowResponse)(err error){
 	if err!=nil{
 		return err
 	}
-	resp.Result=result
+	resp.Result=cacheObj.Policy().FilterRow(result)
 	return nil
 }
 //--------------GetColumn()---------------------------------
@@ -42,7 +55,7 @@ type GetColumnResponse struct{
 	Result string
 }
 func (g *DBcache)GetColumn(req GetColumnRequest,resp *GetColumnResponse)(err error){
-	cacheObj,ok := cache.CacheObj[req.TableName]
+	cacheObj,ok := g.instance.GetCacheObj(req.TableName)
 	if !ok{
 		err = fmt.Errorf("%s,The Table is not cache.",req.TableName)
 		return err
@@ -51,6 +64,10 @@ func (g *DBcache)GetColumn(req GetColumnRequest,resp *GetColumnResponse)(err err
 	if err!=nil{
 		return err
 	}
+	result, err = cacheObj.Policy().FilterColumn(req.Column,result)
+	if err!=nil{
+		return err
+	}
 	resp.Result=result
 	return nil
 }
@@ -64,7 +81,7 @@ type DelRowResponse struct{
 	Result int64
 }
 func (g *DBcache)DelRow(req DelRowRequest,resp *DelRowResponse)(err error){
-	cacheObj,ok := cache.CacheObj[req.TableName]
+	cacheObj,ok := g.instance.GetCacheObj(req.TableName)
 	if !ok{
 		err = fmt.Errorf("%s,The Table is not cache.",req.TableName)
 		return err
@@ -86,16 +103,19 @@ type GetWhereResponse struct{
 	Result []map[string]string
 }
 func (g *DBcache)GetWhere(req GetWhereRequest,resp *GetWhereResponse)(err error){
-	cacheObj,ok := cache.CacheObj[req.TableName]
+	cacheObj,ok := g.instance.GetCacheObj(req.TableName)
 	if !ok{
 		err = fmt.Errorf("%s,The Table is not cache.",req.TableName)
 		return err
 	}
+	if err=cacheObj.CheckWhere(req.Where);err!=nil{
+		return err
+	}
 	result, err := cacheObj.GetWhere(req.Where)
 	if err!=nil{
 		return err
 	}
-	resp.Result=result
+	resp.Result=cacheObj.Policy().FilterRows(result)
 	return nil
 }
 
@@ -110,11 +130,14 @@ type UpdateColumnResponse struct{
 	Result int64
 }
 func (g *DBcache)UpdateColumn(req UpdateColumnRequest,resp *UpdateColumnResponse)(err error){
-	cacheObj,ok := cache.CacheObj[req.TableName]
+	cacheObj,ok := g.instance.GetCacheObj(req.TableName)
 	if !ok{
 		err = fmt.Errorf("%s,The Table is not cache.",req.TableName)
 		return err
 	}
+	if err=cacheObj.Policy().CheckWrite(req.Column);err!=nil{
+		return err
+	}
 	result, err := cacheObj.UpdateColumn(req.Pkey,req.Column,req.ColumnValue)
 	if err!=nil{
 		return err
@@ -133,11 +156,14 @@ type UpdateColumnsResponse struct{
 	Result int64
 }
 func (g *DBcache)UpdateColumns(req UpdateColumnsRequest,resp *UpdateColumnsResponse)(err error){
-	cacheObj,ok := cache.CacheObj[req.TableName]
+	cacheObj,ok := g.instance.GetCacheObj(req.TableName)
 	if !ok{
 		err = fmt.Errorf("%s,The Table is not cache.",req.TableName)
 		return err
 	}
+	if err=cacheObj.CheckChanges(req.Where);err!=nil{
+		return err
+	}
 	result, err := cacheObj.UpdateColumns(req.Pkey,req.Where)
 	if err!=nil{
 		return err
@@ -153,39 +179,157 @@ type InsertRowRequest struct{
 }
 type InsertRowResponse struct{
 	Result int64
+	LastInsertId int64 //自增主键值
 }
 func (g *DBcache)InsertRow(req InsertRowRequest,resp *InsertRowResponse)(err error){
-	cacheObj,ok := cache.CacheObj[req.TableName]
+	cacheObj,ok := g.instance.GetCacheObj(req.TableName)
 	if !ok{
 		err = fmt.Errorf("%s,The Table is not cache.",req.TableName)
 		return err
 	}
-	result, err := cacheObj.InsertRow(req.Condition)
+	result, lastInsertId, err := cacheObj.InsertRow(req.Condition)
 	if err!=nil{
 		return err
 	}
 	resp.Result=result
+	resp.LastInsertId=lastInsertId
 	return nil
 }
 
+//--------------Upsert()---------------------------------
+type UpsertRequest struct{
+	TableName string
+	Row string
+}
+type UpsertResponse struct{
+	Result int64
+	IsInsert bool //true:插入,false:更新
+	IsKnown bool //IsInsert是否确定.未缓存的行不能根据受影响的行数判断时(postgres,sqlite,异步不等待结果)为false
+}
+func (g *DBcache)Upsert(req UpsertRequest,resp *UpsertResponse)(err error){
+	cacheObj,ok := g.instance.GetCacheObj(req.TableName)
+	if !ok{
+		err = fmt.Errorf("%s,The Table is not cache.",req.TableName)
+		return err
+	}
+	//Upsert可能更新已有的行,不能包含只读的列(插入只读的列用InsertRow)
+	if err=cacheObj.CheckChanges(req.Row);err!=nil{
+		return err
+	}
+	result, isInsert, isKnown, err := cacheObj.Upsert(req.Row)
+	if err!=nil{
+		return err
+	}
+	resp.Result=result
+	resp.IsInsert=isInsert
+	resp.IsKnown=isKnown
+	return nil
+}
+
+//--------------InsertRows()---------------------------------
+type InsertRowsRequest struct{
+	TableName string
+	Rows []string
+}
+type InsertRowsResponse struct{
+	Result int64
+}
+func (g *DBcache)InsertRows(req InsertRowsRequest,resp *InsertRowsResponse)(err error){
+	cacheObj,ok := g.instance.GetCacheObj(req.TableName)
+	if !ok{
+		err = fmt.Errorf("%s,The Table is not cache.",req.TableName)
+		return err
+	}
+	result, err := cacheObj.InsertRows(req.Rows)
+	resp.Result=result
+	return err
+}
+
+//--------------DelRows()---------------------------------
+type DelRowsRequest struct{
+	TableName string
+	Pkeys []string
+}
+type DelRowsResponse struct{
+	Result int64
+}
+func (g *DBcache)DelRows(req DelRowsRequest,resp *DelRowsResponse)(err error){
+	cacheObj,ok := g.instance.GetCacheObj(req.TableName)
+	if !ok{
+		err = fmt.Errorf("%s,The Table is not cache.",req.TableName)
+		return err
+	}
+	result, err := cacheObj.DelRows(req.Pkeys)
+	resp.Result=result
+	return err
+}
+
+//--------------UpdateWhere()---------------------------------
+type UpdateWhereRequest struct{
+	TableName string
+	Where string
+	Changes string
+}
+type UpdateWhereResponse struct{
+	Result int64
+}
+func (g *DBcache)UpdateWhere(req UpdateWhereRequest,resp *UpdateWhereResponse)(err error){
+	cacheObj,ok := g.instance.GetCacheObj(req.TableName)
+	if !ok{
+		err = fmt.Errorf("%s,The Table is not cache.",req.TableName)
+		return err
+	}
+	if err=cacheObj.CheckWhere(req.Where);err!=nil{
+		return err
+	}
+	if err=cacheObj.CheckChanges(req.Changes);err!=nil{
+		return err
+	}
+	result, err := cacheObj.UpdateWhere(req.Where, req.Changes)
+	resp.Result=result
+	return err
+}
+
+//--------------DeleteWhere()---------------------------------
+type DeleteWhereRequest struct{
+	TableName string
+	Where string
+}
+type DeleteWhereResponse struct{
+	Result int64
+}
+func (g *DBcache)DeleteWhere(req DeleteWhereRequest,resp *DeleteWhereResponse)(err error){
+	cacheObj,ok := g.instance.GetCacheObj(req.TableName)
+	if !ok{
+		err = fmt.Errorf("%s,The Table is not cache.",req.TableName)
+		return err
+	}
+	if err=cacheObj.CheckWhere(req.Where);err!=nil{
+		return err
+	}
+	result, err := cacheObj.DeleteWhere(req.Where)
+	resp.Result=result
+	return err
+}
+
 //--------------GetRowBetween()---------------------------------
 type GetRowBetweenRequest struct{
 	TableName string
 	Start int
 	End int
+	View string //排序视图名,为空时按表的默认排序
 }
 type GetRowBetweenResponse struct{
 	Result []map[string]string
 }
 func (g *DBcache)GetRowBetween(req GetRowBetweenRequest,resp *GetRowBetweenResponse)(err error){
-	cacheObj,ok := cache.CacheObj[req.TableName]
-	if !ok{
-		err = fmt.Errorf("%s,The Table is not cache.",req.TableName)
+	view,err := g.getView(req.TableName,req.View)
+	if err!=nil{
 		return err
 	}
-	result := cacheObj.GetRowBetween(req.Start,req.End)
+	result := view.GetRowBetween(req.Start,req.End)
 
-	resp.Result=result
+	resp.Result=view.Policy().FilterRows(result)
 	return nil
 }
 
@@ -193,17 +337,17 @@ func (g *DBcache)GetRowBetween(req GetRowBetweenRequest,resp *GetRowBetweenRespo
 type GetPageCountRequest struct{
 	TableName string
 	PageSize int
+	View string //排序视图名,为空时按表的默认排序
 }
 type GetPageCountResponse struct{
 	Result int
 }
 func (g *DBcache)GetPageCount(req GetPageCountRequest,resp *GetPageCountResponse)(err error){
-	cacheObj,ok := cache.CacheObj[req.TableName]
-	if !ok{
-		err = fmt.Errorf("%s,The Table is not cache.",req.TableName)
+	view,err := g.getView(req.TableName,req.View)
+	if err!=nil{
 		return err
 	}
-	result := cacheObj.GetPageCount(req.PageSize)
+	result := view.GetPageCount(req.PageSize)
 
 	resp.Result=result
 	return nil
@@ -215,19 +359,19 @@ type GetMultipageRowsRequest struct{
 	StartPage int
 	PageNum int
 	PageSize int
+	View string //排序视图名,为空时按表的默认排序
 }
 type GetMultipageRowsResponse struct{
 	Result []map[string]string
 }
 func (g *DBcache)GetMultipageRows(req GetMultipageRowsRequest,resp *GetMultipageRowsResponse)(err error){
-	cacheObj,ok := cache.CacheObj[req.TableName]
-	if !ok{
-		err = fmt.Errorf("%s,The Table is not cache.",req.TableName)
+	view,err := g.getView(req.TableName,req.View)
+	if err!=nil{
 		return err
 	}
-	result := cacheObj.GetMultipageRows(req.StartPage,req.PageNum,req.PageSize)
+	result := view.GetMultipageRows(req.StartPage,req.PageNum,req.PageSize)
 
-	resp.Result=result
+	resp.Result=view.Policy().FilterRows(result)
 	return nil
 }
 
@@ -236,18 +380,286 @@ type GetOnePageRowsRequest struct{
 	TableName string
 	Page int
 	PageSize int
+	View string //排序视图名,为空时按表的默认排序
 }
 type GetOnePageRowsResponse struct{
 	Result []map[string]string
 }
 func (g *DBcache)GetOnePageRows(req GetOnePageRowsRequest,resp *GetOnePageRowsResponse)(err error){
-	cacheObj,ok := cache.CacheObj[req.TableName]
-	if !ok{
-		err = fmt.Errorf("%s,The Table is not cache.",req.TableName)
+	view,err := g.getView(req.TableName,req.View)
+	if err!=nil{
+		return err
+	}
+	result := view.GetOnePageRows(req.Page,req.PageSize)
+
+	resp.Result=view.Policy().FilterRows(result)
+	return nil
+}
+//--------------GetWhereRowBetween()---------------------------------
+type GetWhereRowBetweenRequest struct{
+	TableName string
+	Where string
+	Start int
+	End int
+	View string //排序视图名,为空时按表的默认排序
+}
+type GetWhereRowBetweenResponse struct{
+	Result []map[string]string
+	Total int //符合条件的总行数
+}
+func (g *DBcache)GetWhereRowBetween(req GetWhereRowBetweenRequest,resp *GetWhereRowBetweenResponse)(err error){
+	view,err := g.getView(req.TableName,req.View)
+	if err!=nil{
+		return err
+	}
+	if err=view.CheckWhere(req.Where);err!=nil{
+		return err
+	}
+	result, total, err := view.GetWhereRowBetween(req.Where,req.Start,req.End)
+	if err!=nil{
 		return err
 	}
-	result := cacheObj.GetOnePageRows(req.Page,req.PageSize)
+	resp.Result=view.Policy().FilterRows(result)
+	resp.Total=total
+	return nil
+}
 
+//--------------GetWherePageCount()---------------------------------
+type GetWherePageCountRequest struct{
+	TableName string
+	Where string
+	PageSize int
+	View string //排序视图名,为空时按表的默认排序
+}
+type GetWherePageCountResponse struct{
+	Result int
+	Total int //符合条件的总行数
+}
+func (g *DBcache)GetWherePageCount(req GetWherePageCountRequest,resp *GetWherePageCountResponse)(err error){
+	view,err := g.getView(req.TableName,req.View)
+	if err!=nil{
+		return err
+	}
+	if err=view.CheckWhere(req.Where);err!=nil{
+		return err
+	}
+	result, total, err := view.GetWherePageCount(req.Where,req.PageSize)
+	if err!=nil{
+		return err
+	}
 	resp.Result=result
+	resp.Total=total
+	return nil
+}
+
+//--------------GetWhereMultipageRows()---------------------------------
+type GetWhereMultipageRowsRequest struct{
+	TableName string
+	Where string
+	StartPage int
+	PageNum int
+	PageSize int
+	View string //排序视图名,为空时按表的默认排序
+}
+type GetWhereMultipageRowsResponse struct{
+	Result []map[string]string
+	Total int //符合条件的总行数
+	PageCount int //总页数
+}
+func (g *DBcache)GetWhereMultipageRows(req GetWhereMultipageRowsRequest,resp *GetWhereMultipageRowsResponse)(err error){
+	view,err := g.getView(req.TableName,req.View)
+	if err!=nil{
+		return err
+	}
+	if err=view.CheckWhere(req.Where);err!=nil{
+		return err
+	}
+	result, total, pageCount, err := view.GetWhereMultipageRows(req.Where,req.StartPage,req.PageNum,req.PageSize)
+	if err!=nil{
+		return err
+	}
+	resp.Result=view.Policy().FilterRows(result)
+	resp.Total=total
+	resp.PageCount=pageCount
+	return nil
+}
+
+//--------------GetWhereOnePageRows()---------------------------------
+type GetWhereOnePageRowsRequest struct{
+	TableName string
+	Where string
+	Page int
+	PageSize int
+	View string //排序视图名,为空时按表的默认排序
+}
+type GetWhereOnePageRowsResponse struct{
+	Result []map[string]string
+	Total int //符合条件的总行数
+	PageCount int //总页数
+}
+func (g *DBcache)GetWhereOnePageRows(req GetWhereOnePageRowsRequest,resp *GetWhereOnePageRowsResponse)(err error){
+	view,err := g.getView(req.TableName,req.View)
+	if err!=nil{
+		return err
+	}
+	if err=view.CheckWhere(req.Where);err!=nil{
+		return err
+	}
+	result, total, pageCount, err := view.GetWhereOnePageRows(req.Where,req.Page,req.PageSize)
+	if err!=nil{
+		return err
+	}
+	resp.Result=view.Policy().FilterRows(result)
+	resp.Total=total
+	resp.PageCount=pageCount
+	return nil
+}
+
+//--------------GetPageAfter()---------------------------------
+type GetPageAfterRequest struct{
+	TableName string
+	Cursor string
+	PageSize int
+	View string //排序视图名,为空时按表的默认排序
+}
+type GetPageAfterResponse struct{
+	Result []map[string]string
+	Next string //下一页游标
+	Prev string //上一页游标
+}
+func (g *DBcache)GetPageAfter(req GetPageAfterRequest,resp *GetPageAfterResponse)(err error){
+	view,err := g.getView(req.TableName,req.View)
+	if err!=nil{
+		return err
+	}
+	result, next, prev, err := view.GetPageAfter(req.Cursor,req.PageSize)
+	if err!=nil{
+		return err
+	}
+	resp.Result=view.Policy().FilterRows(result)
+	resp.Next=next
+	resp.Prev=prev
 	return nil
-}
\ No newline at end of file
+}
+
+//--------------GetPageBefore()---------------------------------
+type GetPageBeforeRequest struct{
+	TableName string
+	Cursor string
+	PageSize int
+	View string //排序视图名,为空时按表的默认排序
+}
+type GetPageBeforeResponse struct{
+	Result []map[string]string
+	Next string //下一页游标
+	Prev string //上一页游标
+}
+func (g *DBcache)GetPageBefore(req GetPageBeforeRequest,resp *GetPageBeforeResponse)(err error){
+	view,err := g.getView(req.TableName,req.View)
+	if err!=nil{
+		return err
+	}
+	result, next, prev, err := view.GetPageBefore(req.Cursor,req.PageSize)
+	if err!=nil{
+		return err
+	}
+	resp.Result=view.Policy().FilterRows(result)
+	resp.Next=next
+	resp.Prev=prev
+	return nil
+}
+
+//--------------GetPage()---------------------------------
+type GetPageRequest struct{
+	TableName string
+	Where string //条件表达式,为空时不过滤
+	Page int
+	PageSize int
+	View string //排序视图名,为空时按表的默认排序
+}
+type GetPageResponse struct{
+	Result []map[string]string
+	Total int //总行数(有where条件时是符合条件的总行数)
+	PageCount int //总页数
+	Page int //当前页码
+	PageSize int //每页行数
+	HasNext bool //是否有下一页
+	HasPrev bool //是否有上一页
+}
+func (g *DBcache)GetPage(req GetPageRequest,resp *GetPageResponse)(err error){
+	view,err := g.getView(req.TableName,req.View)
+	if err!=nil{
+		return err
+	}
+	if err=view.CheckWhere(req.Where);err!=nil{
+		return err
+	}
+	var result *cache.PageResult
+	if req.Where==""{
+		result, err = view.GetPage(req.Page,req.PageSize)
+	}else{
+		result, err = view.GetWherePage(req.Where,req.Page,req.PageSize)
+	}
+	if err!=nil{
+		return err
+	}
+	resp.Result=view.Policy().FilterRows(result.Rows)
+	resp.Total=result.Total
+	resp.PageCount=result.PageCount
+	resp.Page=result.Page
+	resp.PageSize=result.PageSize
+	resp.HasNext=result.HasNext
+	resp.HasPrev=result.HasPrev
+	return nil
+}
+
+//--------------AddTable()---------------------------------
+//管理接口:运行中增加缓存表,写入cache.conf(保留注释,原子替换)并从数据库加载.
+//需配置admin_token,请求的Token与之相同.表配置的检查见cache.Instance.AddRemoteTable().
+type AddTableRequest struct{
+	Token string //管理接口的token,与config.conf中[RpcServer]的admin_token相同
+	Name string //缓存表的名称(cache.conf中的分段名),为空时是表名.同一个表可以用不同的名称增加多次(别名)
+	TableName string
+	Columns string //缓存的多列,以逗号隔开
+	Pkey string
+	Where string
+	Other string //排序条件,例如:order by price asc
+	PkeyAutoIncrement bool
+	CacheType string //slice,sliceNotDel,link,tree
+	IsRealtime bool
+	IsWaitResult bool
+	SortViews string
+	HiddenColumns string //隐藏的列,以逗号隔开
+	MaskedColumns string //脱敏的列,以逗号隔开,格式:列名[:显示末尾字符数]
+	ReadonlyColumns string //只读的列,以逗号隔开
+}
+type AddTableResponse struct{
+	Result int64 //加载的总行数
+}
+func (g *DBcache)AddTable(req AddTableRequest,resp *AddTableResponse)(err error){
+	err = cache.CheckAdminToken(g.adminToken,req.Token)
+	if err!=nil{
+		return err
+	}
+	table := conf.CacheTable{
+		TableName:         req.TableName,
+		Columns:           req.Columns,
+		Pkey:              req.Pkey,
+		Where:             req.Where,
+		Other:             req.Other,
+		PkeyAutoIncrement: req.PkeyAutoIncrement,
+		CacheType:         req.CacheType,
+		IsRealtime:        req.IsRealtime,
+		IsWaitResult:      req.IsWaitResult,
+		SortViews:         req.SortViews,
+		HiddenColumns:     req.HiddenColumns,
+		MaskedColumns:     req.MaskedColumns,
+		ReadonlyColumns:   req.ReadonlyColumns,
+	}
+	cacheObj, err := g.instance.AddRemoteTable(req.Name,table)
+	if err!=nil{
+		return err
+	}
+	resp.Result=cacheObj.GetRowCount()
+	return nil
+}
@@ -1,66 +1,139 @@
 package rpcserver
 
 import (
+	"context"
+	"dbcache/cache"
 	"dbcache/conf"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
 	"net/rpc"
 	"net/rpc/jsonrpc"
-)
-var (
-	RpcConf=conf.RpcServer{}
+	"sync"
 )
 
-func RpcRun()(err error){
-	//读取配置文件,初始化配置信息
-	err = conf.ParseConf(conf.CONFIG_FILE, &RpcConf)
-	if err != nil {
-		return  err
+//rpc服务,每个服务有自己的rpc.Server,注册的DBcache使用指定的缓存实例,同一进程中可以运行多个.
+type RpcServer struct{
+	conf       conf.RpcServer
+	instance   *cache.Instance       //缓存实例
+	server     *rpc.Server           //注册服务的rpc对象
+	rpcListen  net.Listener          //rpc监听对象
+	httpServer *http.Server          //rpc采用http协议时的http服务对象
+	connMutex  sync.Mutex            //保护jsonConns
+	jsonConns  map[net.Conn]bool     //jsonrpc当前的客户端连接
+	connWg     sync.WaitGroup        //等待jsonrpc客户端连接处理完
+}
+
+//新建rpc服务,rpcConf一般由conf.ParseConf(conf.CONFIG_FILE, &rpcConf)读取.调用Run()开始服务.
+func NewRpcServer(instance *cache.Instance,rpcConf conf.RpcServer)(s *RpcServer,err error){
+	s = &RpcServer{
+		conf:      rpcConf,
+		instance:  instance,
+		server:    rpc.NewServer(),
+		jsonConns: map[net.Conn]bool{},
 	}
 	//注册服务
-	rpc.Register(new(DBcache))
+	err = s.server.RegisterName("DBcache",&DBcache{instance:instance,adminToken:rpcConf.AdminToken})
+	if err != nil {
+		return nil,fmt.Errorf("NewRpcServer(),注册服务失败, err: %s", err)
+	}
+	return s,nil
+}
 
-	if RpcConf.RpcType=="rpc"{      //rpc采用http协议作为rpc载体
-		rpc.HandleHTTP()
-		//fmt.Println("rpc http server: "+RpcConf.Ip+":"+RpcConf.Port)
-		//err = http.ListenAndServe(RpcConf.Ip+":"+RpcConf.Port, nil)
-		//if err!=nil{
-		//	return err
-		//}
+//监听的地址
+func (s *RpcServer)Addr()string{
+	if s.rpcListen != nil {
+		return s.rpcListen.Addr().String()
+	}
+	return s.conf.Ip+":"+s.conf.Port
+}
 
-		rpcListen, err := net.Listen(RpcConf.Protocol, RpcConf.Ip+":"+RpcConf.Port)
+func (s *RpcServer)Run()(err error){
+	if s.conf.RpcType=="rpc"{      //rpc采用http协议作为rpc载体
+		s.rpcListen, err = net.Listen(s.conf.Protocol, s.conf.Ip+":"+s.conf.Port)
 		if err != nil {
 			return err
 		}
+		//rpc.Server处理CONNECT请求,不使用http.DefaultServeMux,多个服务互不影响.
+		s.httpServer = &http.Server{Handler: s.server}
 	    //另外开一个协程处理
 		go func(rpcListen net.Listener){
-			fmt.Println("rpc server: "+RpcConf.Ip+":"+RpcConf.Port)
-			http.Serve(rpcListen, nil)
-		}(rpcListen)
+			fmt.Println("rpc server: "+s.Addr())
+			err := s.httpServer.Serve(rpcListen)
+			if err != nil && err != http.ErrServerClosed {
+				fmt.Println("rpc server : err:",err)
+			}
+		}(s.rpcListen)
 
-	}else if RpcConf.RpcType=="jsonrpc"{  //jsonrpc基于tcp协议,实现JSON进行数据编解码,，因而支持跨语言tcp调用。
+	}else if s.conf.RpcType=="jsonrpc"{  //jsonrpc基于tcp协议,实现JSON进行数据编解码,，因而支持跨语言tcp调用。
 
-		rpcListen, err := net.Listen(RpcConf.Protocol, RpcConf.Ip+":"+RpcConf.Port)
+		s.rpcListen, err = net.Listen(s.conf.Protocol, s.conf.Ip+":"+s.conf.Port)
 		if err != nil {
 			return err
 		}
 		//另外开一个协程并发处理
 		go func(rpcListen net.Listener){
-			fmt.Println("json rpc tcp server: "+RpcConf.Ip+":"+RpcConf.Port)
+			fmt.Println("json rpc tcp server: "+s.Addr())
 			for {
 				conn, err := rpcListen.Accept() // 接收客户端连接请求
 				if err != nil {
+					//监听已关闭,退出.
+					if errors.Is(err, net.ErrClosed) {
+						return
+					}
 					fmt.Println("json rpc tcp server : err:",err)
 					continue
 				}
+				s.connMutex.Lock()
+				s.jsonConns[conn] = true
+				s.connMutex.Unlock()
+				s.connWg.Add(1)
 				go func(conn net.Conn) { // 并发处理客户端请求
+					defer s.connWg.Done()
 					fmt.Println("json rpc,new client in coming")
-					jsonrpc.ServeConn(conn)
+					s.server.ServeCodec(jsonrpc.NewServerCodec(conn))
+					s.connMutex.Lock()
+					delete(s.jsonConns, conn)
+					s.connMutex.Unlock()
 				}(conn)
 			}
-		}(rpcListen)
+		}(s.rpcListen)
 	}
 	return nil
 }
 
+//停止rpc服务.不再接收新的连接,等待正在处理的请求完成.
+//如果ctx先超时,则强制关闭所有连接.
+func (s *RpcServer)Stop(ctx context.Context)(err error){
+	if s.rpcListen == nil {
+		return nil
+	}
+	if s.httpServer != nil {
+		err = s.httpServer.Shutdown(ctx)
+		if err != nil {
+			s.httpServer.Close()
+			return fmt.Errorf("Stop(),关闭rpc服务超时, err: %s", err)
+		}
+		return nil
+	}
+	//jsonrpc:关闭监听,再关闭客户端连接.
+	s.rpcListen.Close()
+	done := make(chan struct{})
+	go func() {
+		s.connWg.Wait()
+		close(done)
+	}()
+	//jsonrpc.ServeConn在连接关闭前不会返回,空闲连接需要主动关闭.
+	s.connMutex.Lock()
+	for conn := range s.jsonConns {
+		conn.Close()
+	}
+	s.connMutex.Unlock()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return fmt.Errorf("Stop(),关闭jsonrpc服务超时, err: %s", ctx.Err())
+	}
+	return nil
+}
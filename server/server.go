@@ -0,0 +1,157 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"dbcache/cache"
+	"dbcache/conf"
+	"dbcache/db"
+	"dbcache/grpcserver"
+	"dbcache/logs"
+	"dbcache/rpcserver"
+	"fmt"
+	"net"
+	"time"
+)
+
+//dbcache服务:一个缓存实例(数据库连接,cache.conf中的表,数据目录),及热加载,rpc和grpc服务.
+//各服务的配置都在Options中指定,同一进程中可以运行多个互不影响的服务(使用不同的cache.conf,数据目录和地址).
+//日志是进程级的,由调用者初始化(logs.InitLog(),logs.InitLogDir()).
+
+const ADDR_OFF = "off" //RpcAddr,GrpcAddr为此值时不启动该服务
+
+//服务的配置
+type Options struct {
+	ConfigFile     string        //config.conf,读取数据库,rpc和grpc的配置,为空时是conf.CONFIG_FILE
+	TablesConf     string        //cache.conf,为空时是conf.TABLES_CONF
+	DataDir        string        //数据目录,异步sql文件的相对路径相对于此目录,为空时相对于当前目录
+	RpcAddr        string        //rpc监听地址(ip:port),为空时使用config.conf中[RpcServer]的配置,off不启动
+	GrpcAddr       string        //grpc监听地址(ip:port),为空时使用config.conf中[GrpcServer]的配置,off不启动
+	ReloadInterval time.Duration //热加载检查配置文件的间隔,小于0时不热加载,0时默认5秒
+}
+
+//dbcache服务
+type Server struct {
+	opts       Options
+	db         *sql.DB
+	instance   *cache.Instance
+	watcher    *cache.ConfWatcher
+	rpcServer  *rpcserver.RpcServer
+	grpcServer *grpcserver.GrpcServer
+}
+
+//新建服务,调用Start()启动.
+func New(opts Options) *Server {
+	if opts.ConfigFile == "" {
+		opts.ConfigFile = conf.CONFIG_FILE
+	}
+	if opts.TablesConf == "" {
+		opts.TablesConf = conf.TABLES_CONF
+	}
+	return &Server{opts: opts}
+}
+
+//启动服务:连接数据库,加载cache.conf中的表,开始热加载,启动rpc和grpc服务.失败时关闭已启动的部分.
+func (s *Server) Start() (err error) {
+	defer func() {
+		if err != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), cache.RELOAD_CLOSE_TIMEOUT)
+			defer cancel()
+			s.Shutdown(ctx)
+		}
+	}()
+	s.db, err = db.Open(s.opts.ConfigFile)
+	if err != nil {
+		return fmt.Errorf("Start(),连接数据库失败, err: %s", err)
+	}
+	s.instance = cache.NewInstance(s.db, s.opts.ConfigFile, s.opts.TablesConf, s.opts.DataDir)
+	if _, err = s.instance.LoadTables(); err != nil {
+		return fmt.Errorf("Start(), err: %s", err)
+	}
+	if s.opts.ReloadInterval >= 0 {
+		s.watcher, err = s.instance.NewConfWatcher(s.opts.ReloadInterval)
+		if err != nil {
+			return fmt.Errorf("Start(), err: %s", err)
+		}
+		s.watcher.Start()
+	}
+	if s.opts.RpcAddr != ADDR_OFF {
+		rpcConf := conf.RpcServer{}
+		if err = conf.ParseConf(s.opts.ConfigFile, &rpcConf); err != nil {
+			return fmt.Errorf("Start(),读取rpc配置失败, err: %s", err)
+		}
+		if err = setAddr(s.opts.RpcAddr, &rpcConf.Ip, &rpcConf.Port); err != nil {
+			return fmt.Errorf("Start(),rpc地址错误, err: %s", err)
+		}
+		if s.rpcServer, err = rpcserver.NewRpcServer(s.instance, rpcConf); err != nil {
+			return fmt.Errorf("Start(), err: %s", err)
+		}
+		if err = s.rpcServer.Run(); err != nil {
+			return fmt.Errorf("Start(),启动rpc服务失败, err: %s", err)
+		}
+	}
+	if s.opts.GrpcAddr != ADDR_OFF {
+		grpcConf := conf.GrpcServer{}
+		if err = conf.ParseConf(s.opts.ConfigFile, &grpcConf); err != nil {
+			return fmt.Errorf("Start(),读取grpc配置失败, err: %s", err)
+		}
+		if err = setAddr(s.opts.GrpcAddr, &grpcConf.Ip, &grpcConf.Port); err != nil {
+			return fmt.Errorf("Start(),grpc地址错误, err: %s", err)
+		}
+		s.grpcServer = grpcserver.NewGrpcServer(s.instance, grpcConf)
+		if err = s.grpcServer.Run(); err != nil {
+			return fmt.Errorf("Start(),启动grpc服务失败, err: %s", err)
+		}
+	}
+	return nil
+}
+
+//用命令行指定的地址(ip:port)替换配置文件中的ip和端口,地址为空时不替换.
+func setAddr(addr string, ip *string, port *string) error {
+	if addr == "" {
+		return nil
+	}
+	host, p, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	*ip, *port = host, p
+	return nil
+}
+
+//获取服务的缓存实例,用于通过Go API访问缓存表.
+func (s *Server) Instance() *cache.Instance {
+	return s.instance
+}
+
+//优雅关闭:先停止热加载和rpc,grpc服务,不再接收新请求,再关闭缓存(把异步管道中的sql同步到数据库),最后关闭数据库连接.
+func (s *Server) Shutdown(ctx context.Context) (err error) {
+	if s.watcher != nil {
+		s.watcher.Close()
+		s.watcher = nil
+	}
+	if s.grpcServer != nil {
+		if stopErr := s.grpcServer.Stop(ctx); stopErr != nil {
+			err = stopErr
+			logs.Error("a", "关闭grpc服务失败, err: %s", stopErr)
+		}
+		s.grpcServer = nil
+	}
+	if s.rpcServer != nil {
+		if stopErr := s.rpcServer.Stop(ctx); stopErr != nil {
+			err = stopErr
+			logs.Error("a", "关闭rpc服务失败, err: %s", stopErr)
+		}
+		s.rpcServer = nil
+	}
+	if s.instance != nil {
+		if closeErr := s.instance.Close(ctx); closeErr != nil {
+			err = closeErr
+		}
+	}
+	if s.db != nil {
+		db.Close(s.db)
+		s.db = nil
+	}
+	return err
+}